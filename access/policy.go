@@ -0,0 +1,224 @@
+// Package access decides which servers a public ping service may ping and how often each client may use it,
+// so the service can't be abused to direct UDP traffic at arbitrary third parties.
+package access
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// ErrNotAllowed is returned, wrapped with the host or address, for targets a TargetPolicy doesn't allow.
+var ErrNotAllowed = errors.New("access: target not allowed")
+
+// PrivateNetworks are the networks that aren't reachable from the internet: loopback, private, link-local,
+// shared, multicast and reserved ranges. Denying them stops a public service probing the network it runs in.
+var PrivateNetworks = []string{
+	"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16", "172.16.0.0/12",
+	"192.0.0.0/24", "192.168.0.0/16", "198.18.0.0/15", "224.0.0.0/4", "240.0.0.0/4",
+	"::/128", "::1/128", "64:ff9b:1::/48", "fc00::/7", "fe80::/10", "ff00::/8",
+}
+
+// TargetPolicy decides which servers may be pinged from an allowlist and a denylist. Each entry is a hostname,
+// a wildcard such as "*.example.com" matching any subdomain, an IP address or a CIDR prefix such as "10.0.0.0/8".
+// A host must match no denied entry, and an allowed entry unless the allowlist is empty. The IP addresses
+// hostnames resolve to are also checked against denied prefixes, so a hostname can't be pointed at them.
+//
+// A nil *TargetPolicy allows every target.
+type TargetPolicy struct {
+	allow, deny hostList
+	allowAll    bool
+	// restrict are further allowlists a host must also match, see Restrict
+	restrict []hostList
+}
+
+// hostList matches hosts against a list of entries.
+type hostList struct {
+	hosts    map[string]bool
+	suffixes []string
+	prefixes []netip.Prefix
+}
+
+// NewTargetPolicy returns a TargetPolicy allowing the entries of allow, or every host if it's empty,
+// except the entries of deny. It returns an error if a CIDR prefix is invalid.
+func NewTargetPolicy(allow, deny []string) (*TargetPolicy, error) {
+	p := &TargetPolicy{allowAll: len(allow) == 0}
+	var err error
+	if p.allow, err = newHostList(allow); err != nil {
+		return nil, err
+	}
+	if p.deny, err = newHostList(deny); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Restrict returns a policy only allowing the hosts p allows that also match an entry of allow, such as to
+// narrow a shared policy down to the servers one service lists. Unlike with NewTargetPolicy an empty allow
+// allows nothing. p may be nil. It returns an error if a CIDR prefix is invalid.
+func (p *TargetPolicy) Restrict(allow ...string) (*TargetPolicy, error) {
+	l, err := newHostList(allow)
+	if err != nil {
+		return nil, err
+	}
+	r := &TargetPolicy{allowAll: true}
+	if p != nil {
+		*r = *p
+	}
+	r.restrict = append(append([]hostList(nil), r.restrict...), l)
+	return r, nil
+}
+
+func newHostList(entries []string) (hostList, error) {
+	l := hostList{hosts: make(map[string]bool)}
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.Trim(entry, "[]"))
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return hostList{}, fmt.Errorf("access: %w", err)
+			}
+			l.prefixes = append(l.prefixes, prefix.Masked())
+		} else if ip, err := netip.ParseAddr(entry); err == nil {
+			ip = ip.Unmap()
+			l.prefixes = append(l.prefixes, netip.PrefixFrom(ip, ip.BitLen()))
+		} else if suffix, ok := strings.CutPrefix(entry, "*"); ok && strings.HasPrefix(suffix, ".") {
+			l.suffixes = append(l.suffixes, suffix)
+		} else {
+			l.hosts[entry] = true
+		}
+	}
+	return l, nil
+}
+
+// matchHost reports whether host, a lowercased hostname or IP address, matches an entry.
+func (l hostList) matchHost(host string) bool {
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return l.matchIP(ip)
+	}
+	if l.hosts[host] {
+		return true
+	}
+	for _, suffix := range l.suffixes {
+		if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// coversPrefix reports whether every address of prefix, which is masked, matches an entry.
+func (l hostList) coversPrefix(prefix netip.Prefix) bool {
+	for _, allowed := range l.prefixes {
+		if allowed.Bits() <= prefix.Bits() && allowed.Contains(prefix.Addr()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l hostList) matchIP(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	for _, prefix := range l.prefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowHost returns an error wrapping ErrNotAllowed if host, a hostname or IP address, may not be pinged.
+func (p *TargetPolicy) AllowHost(host string) error {
+	if p == nil {
+		return nil
+	}
+	host = strings.ToLower(strings.Trim(host, "[]"))
+	if p.deny.matchHost(host) || (!p.allowAll && !p.allow.matchHost(host)) {
+		return fmt.Errorf("%w: %s", ErrNotAllowed, host)
+	}
+	for _, l := range p.restrict {
+		if !l.matchHost(host) {
+			return fmt.Errorf("%w: %s", ErrNotAllowed, host)
+		}
+	}
+	return nil
+}
+
+// AllowIP returns an error wrapping ErrNotAllowed if ip, which an allowed hostname resolved to, is denied.
+func (p *TargetPolicy) AllowIP(ip netip.Addr) error {
+	if p == nil {
+		return nil
+	}
+	if p.deny.matchIP(ip) {
+		return fmt.Errorf("%w: %s", ErrNotAllowed, ip.Unmap())
+	}
+	return nil
+}
+
+// AllowPrefix returns an error wrapping ErrNotAllowed if scanning prefix could ping a denied address,
+// or, unless the allowlist is empty, an address outside the allowed prefixes or those it was restricted to.
+func (p *TargetPolicy) AllowPrefix(prefix netip.Prefix) error {
+	if p == nil {
+		return nil
+	}
+	if addr := prefix.Addr(); addr.Is4In6() && prefix.Bits() >= 96 {
+		prefix = netip.PrefixFrom(addr.Unmap(), prefix.Bits()-96)
+	}
+	prefix = prefix.Masked()
+	for _, denied := range p.deny.prefixes {
+		if denied.Overlaps(prefix) {
+			return fmt.Errorf("%w: %s overlaps %s", ErrNotAllowed, prefix, denied)
+		}
+	}
+	if !p.allowAll && !p.allow.coversPrefix(prefix) {
+		return fmt.Errorf("%w: %s", ErrNotAllowed, prefix)
+	}
+	for _, l := range p.restrict {
+		if !l.coversPrefix(prefix) {
+			return fmt.Errorf("%w: %s", ErrNotAllowed, prefix)
+		}
+	}
+	return nil
+}
+
+// Ping pings the server at address with client if the policy allows it. The host is resolved first and its
+// IP address checked, then pinged directly, so a hostname can't resolve to a denied address in between.
+// Targets that aren't allowed are results with an error wrapping ErrNotAllowed.
+func (p *TargetPolicy) Ping(ctx context.Context, client *bedrockping.Client, address string) bedrockping.Result {
+	if p == nil {
+		return client.Ping(ctx, address)
+	}
+	// Addresses without a port are pinged on the default one, as they are by client.Ping
+	hostport, err := bedrockping.ParseAddress(address)
+	if err != nil {
+		return bedrockping.Result{Address: address, Err: err}
+	}
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return bedrockping.Result{Address: address, Err: err}
+	}
+	if err := p.AllowHost(host); err != nil {
+		return bedrockping.Result{Address: address, Err: err}
+	}
+	resolved, err := client.Resolve(ctx, hostport)
+	if err != nil {
+		return bedrockping.Result{Address: address, Err: err}
+	}
+	ip, err := netip.ParseAddrPort(resolved)
+	if err != nil {
+		return bedrockping.Result{Address: address, Err: err}
+	}
+	if err := p.AllowIP(ip.Addr()); err != nil {
+		return bedrockping.Result{Address: address, Resolved: resolved, Err: err}
+	}
+
+	res := client.Ping(ctx, resolved)
+	res.Address = address
+	res.Resolved = resolved
+	return res
+}
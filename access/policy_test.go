@@ -0,0 +1,178 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+func TestTargetPolicyAllowHost(t *testing.T) {
+	p, err := NewTargetPolicy([]string{"play.example.com", "*.example.net", "203.0.113.0/24", "[2001:db8::1]"},
+		[]string{"bad.example.net", "203.0.113.13"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for host, allowed := range map[string]bool{
+		"play.example.com":    true,
+		"PLAY.Example.com":    true,
+		"other.example.com":   false,
+		"a.example.net":       true,
+		"a.b.example.net":     true,
+		"example.net":         false,
+		"bad.example.net":     false,
+		"203.0.113.7":         true,
+		"203.0.113.13":        false,
+		"::ffff:203.0.113.7":  true,
+		"198.51.100.1":        false,
+		"2001:db8::1":         true,
+		"[2001:db8::1]":       true,
+		"2001:db8::2":         false,
+		"example.net.evil.co": false,
+	} {
+		if err := p.AllowHost(host); (err == nil) != allowed {
+			t.Errorf("%s: expected allowed %v, got %v", host, allowed, err)
+		} else if err != nil && !errors.Is(err, ErrNotAllowed) {
+			t.Errorf("%s: expected ErrNotAllowed, got %v", host, err)
+		}
+	}
+
+	var nilPolicy *TargetPolicy
+	if err := nilPolicy.AllowHost("anything"); err != nil {
+		t.Errorf("expected a nil policy to allow everything, got %v", err)
+	}
+}
+
+func TestTargetPolicyDenyOnly(t *testing.T) {
+	p, err := NewTargetPolicy(nil, PrivateNetworks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for host, allowed := range map[string]bool{
+		"play.example.com": true,
+		"8.8.8.8":          true,
+		"10.1.2.3":         false,
+		"127.0.0.1":        false,
+		"::1":              false,
+		"fe80::1":          false,
+		"2001:4860::8888":  true,
+	} {
+		if err := p.AllowHost(host); (err == nil) != allowed {
+			t.Errorf("%s: expected allowed %v, got %v", host, allowed, err)
+		}
+	}
+	if err := p.AllowIP(netip.MustParseAddr("192.168.1.1")); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("expected a private IP to be denied, got %v", err)
+	}
+
+	if _, err := NewTargetPolicy(nil, []string{"10.0.0.0/33"}); err == nil {
+		t.Error("expected an invalid prefix to fail")
+	}
+}
+
+func TestTargetPolicyRestrict(t *testing.T) {
+	shared, err := NewTargetPolicy(nil, []string{"bad.example.com", "10.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := shared.Restrict("*.example.com", "10.1.0.0/16", "203.0.113.0/24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for host, allowed := range map[string]bool{
+		"play.example.com": true,
+		"bad.example.com":  false,
+		"play.example.net": false,
+		"10.1.2.3":         false,
+		"203.0.113.7":      true,
+	} {
+		if err := p.AllowHost(host); (err == nil) != allowed {
+			t.Errorf("%s: expected allowed %v, got %v", host, allowed, err)
+		}
+	}
+	if err := p.AllowPrefix(netip.MustParsePrefix("203.0.113.0/25")); err != nil {
+		t.Errorf("expected a restricted prefix to be allowed, got %v", err)
+	}
+	if err := p.AllowPrefix(netip.MustParsePrefix("198.51.100.0/24")); err == nil {
+		t.Error("expected a prefix outside the restriction to be denied")
+	}
+	if err := shared.AllowHost("play.example.net"); err != nil {
+		t.Errorf("restricting changed the shared policy: %v", err)
+	}
+
+	// Restricting a nil policy, or to nothing, works too
+	var nilPolicy *TargetPolicy
+	if p, err = nilPolicy.Restrict(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AllowHost("play.example.com"); err == nil {
+		t.Error("expected an empty restriction to allow nothing")
+	}
+	if _, err := nilPolicy.Restrict("10.0.0.0/33"); err == nil {
+		t.Error("expected an error for an invalid prefix")
+	}
+}
+
+func TestTargetPolicyAllowPrefix(t *testing.T) {
+	p, err := NewTargetPolicy([]string{"203.0.113.0/24"}, []string{"203.0.113.128/28"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for prefix, allowed := range map[string]bool{
+		"203.0.113.0/25":         true,
+		"203.0.113.0/24":         false,
+		"203.0.113.128/30":       false,
+		"203.0.112.0/23":         false,
+		"198.51.100.0/24":        false,
+		"::ffff:203.0.113.0/121": true,
+	} {
+		if err := p.AllowPrefix(netip.MustParsePrefix(prefix)); (err == nil) != allowed {
+			t.Errorf("%s: expected allowed %v, got %v", prefix, allowed, err)
+		}
+	}
+}
+
+func TestTargetPolicyPing(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	go bedrockping.NewResponder(bedrockping.Response{GameID: "MCPE", ServerName: "Test"}).Serve(conn)
+	_, port, _ := net.SplitHostPort(conn.LocalAddr().String())
+	client := bedrockping.NewClient(bedrockping.WithTimeout(time.Second), bedrockping.WithNetwork("udp4"))
+
+	// localhost is allowed by name, but resolves to a denied address
+	p, err := NewTargetPolicy([]string{"localhost", "127.0.0.1"}, []string{"127.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := p.Ping(context.Background(), client, "localhost:"+port)
+	if !errors.Is(res.Err, ErrNotAllowed) || res.Resolved != "127.0.0.1:"+port {
+		t.Errorf("expected the resolved address to be denied, got %+v", res)
+	}
+	// An address without a port is checked on the default port, rather than refused
+	res = p.Ping(context.Background(), client, "localhost")
+	if !errors.Is(res.Err, ErrNotAllowed) || res.Address != "localhost" || res.Resolved != "127.0.0.1:19132" {
+		t.Errorf("expected the resolved address on the default port to be denied, got %+v", res)
+	}
+	if n := client.Stats().PingsSent; n != 0 {
+		t.Errorf("expected no pings, got %d", n)
+	}
+
+	p, err = NewTargetPolicy([]string{"localhost"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res = p.Ping(context.Background(), client, "localhost:"+port)
+	if res.Err != nil || res.Address != "localhost:"+port || res.Resolved != "127.0.0.1:"+port || res.Response.ServerName != "Test" {
+		t.Errorf("unexpected result: %+v", res)
+	}
+	if res := p.Ping(context.Background(), client, "127.0.0.1:"+port); !errors.Is(res.Err, ErrNotAllowed) {
+		t.Errorf("expected an address that isn't allowed to be refused, got %v", res.Err)
+	}
+}
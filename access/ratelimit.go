@@ -0,0 +1,94 @@
+package access
+
+import (
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// pruneInterval is how often a RateLimiter forgets clients whose buckets have refilled.
+const pruneInterval = time.Minute
+
+// RateLimiter limits the rate of requests from each client IP address with a token bucket per client,
+// allowing bursts of up to burst requests and refilling at rate requests per second. IPv6 clients are
+// limited per /64 network, as a single host usually has a whole /64 to pick addresses from.
+// A nil *RateLimiter imposes no limit. A RateLimiter is safe for concurrent use.
+type RateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu        sync.Mutex
+	buckets   map[netip.Addr]*bucket
+	lastPrune time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a RateLimiter allowing each client rate requests per second with bursts of up to
+// burst requests. If burst is less than 1 it is set to 1.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{rate: rate, burst: float64(burst), buckets: make(map[netip.Addr]*bucket), lastPrune: time.Now()}
+}
+
+// Allow reports whether a request from the client with ip may be served now, and if not how long until
+// it may be. Requests from addresses that can't be parsed share a single bucket.
+func (l *RateLimiter) Allow(ip string) (bool, time.Duration) {
+	if l == nil || l.rate <= 0 {
+		return true, 0
+	}
+	key := clientKey(ip)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastPrune) >= pruneInterval {
+		l.prune(now)
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * l.rate
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// prune forgets the clients whose buckets would be full by now, l.mu must be held.
+func (l *RateLimiter) prune(now time.Time) {
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastPrune = now
+}
+
+// clientKey returns the key of the bucket for requests from ip.
+func clientKey(ip string) netip.Addr {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return netip.Addr{}
+	}
+	addr = addr.Unmap().WithZone("")
+	if addr.Is6() {
+		prefix, _ := addr.Prefix(64)
+		return prefix.Addr()
+	}
+	return addr
+}
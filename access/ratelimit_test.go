@@ -0,0 +1,56 @@
+package access
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := NewRateLimiter(10, 2)
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("203.0.113.1"); !ok {
+			t.Fatalf("expected request %d of the burst to be allowed", i)
+		}
+	}
+	ok, wait := l.Allow("203.0.113.1")
+	if ok || wait <= 0 || wait > 100*time.Millisecond {
+		t.Errorf("expected to wait up to 100ms, got %v %v", ok, wait)
+	}
+	if ok, _ := l.Allow("203.0.113.2"); !ok {
+		t.Error("expected other clients to have their own bucket")
+	}
+
+	time.Sleep(wait)
+	if ok, _ := l.Allow("203.0.113.1"); !ok {
+		t.Error("expected the bucket to refill")
+	}
+
+	// Addresses in the same IPv6 /64 share a bucket
+	l = NewRateLimiter(1, 1)
+	if ok, _ := l.Allow("2001:db8::1"); !ok {
+		t.Fatal("expected the first request to be allowed")
+	}
+	if ok, _ := l.Allow("2001:db8::2"); ok {
+		t.Error("expected the /64 to be limited")
+	}
+	if ok, _ := l.Allow("2001:db8:0:1::1"); !ok {
+		t.Error("expected another /64 to have its own bucket")
+	}
+
+	var nilLimiter *RateLimiter
+	if ok, _ := nilLimiter.Allow("203.0.113.1"); !ok {
+		t.Error("expected a nil limiter to allow everything")
+	}
+}
+
+func TestRateLimiterPrune(t *testing.T) {
+	l := NewRateLimiter(1000, 1)
+	l.Allow("203.0.113.1")
+	l.Allow("203.0.113.2")
+	l.lastPrune = time.Now().Add(-pruneInterval)
+	time.Sleep(5 * time.Millisecond)
+	l.Allow("203.0.113.3")
+	if len(l.buckets) != 1 {
+		t.Errorf("expected refilled buckets to be forgotten, have %d", len(l.buckets))
+	}
+}
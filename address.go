@@ -0,0 +1,66 @@
+package bedrockping
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// ParseAddress returns the host:port address of a server given as a hostname or IP address with an optional
+// port, adding DefaultPort if there's none:
+//
+//	play.example.com        play.example.com:19132
+//	play.example.com:19133  play.example.com:19133
+//	192.0.2.1               192.0.2.1:19132
+//	2001:db8::1             [2001:db8::1]:19132
+//	[2001:db8::1]           [2001:db8::1]:19132
+//	[fe80::1%eth0]:19133    [fe80::1%eth0]:19133
+//
+// An IPv6 address without brackets never has a port, as 2001:db8::1:1234 is itself an IPv6 address.
+// Zones are only allowed on IPv6 addresses.
+func ParseAddress(address string) (string, error) {
+	if address == "" {
+		return "", &net.AddrError{Err: "missing address", Addr: address}
+	}
+	if ip, err := netip.ParseAddr(address); err == nil {
+		return net.JoinHostPort(ip.String(), strconv.Itoa(DefaultPort)), nil
+	}
+
+	host, port := address, strconv.Itoa(DefaultPort)
+	if strings.HasPrefix(address, "[") && strings.HasSuffix(address, "]") {
+		host = address[1 : len(address)-1]
+	} else if strings.Contains(address, ":") {
+		var err error
+		if host, port, err = net.SplitHostPort(address); err != nil {
+			return "", err
+		}
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return "", &net.AddrError{Err: "invalid port", Addr: address}
+		}
+	}
+
+	if strings.HasPrefix(address, "[") {
+		ip, err := netip.ParseAddr(host)
+		if err != nil || !ip.Is6() {
+			return "", &net.AddrError{Err: "invalid IPv6 address", Addr: address}
+		}
+		return net.JoinHostPort(ip.String(), port), nil
+	}
+	if err := checkHostname(host); err != nil {
+		return "", &net.AddrError{Err: err.Error(), Addr: address}
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// checkHostname reports an error if host can't be a hostname or IPv4 address.
+func checkHostname(host string) error {
+	switch {
+	case host == "":
+		return errors.New("missing host")
+	case strings.ContainsAny(host, " \t\r\n/[]%"):
+		return errors.New("invalid host")
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package bedrockping
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := map[string]string{
+		"play.example.com":        "play.example.com:19132",
+		"play.example.com:19133":  "play.example.com:19133",
+		"play.example.com.":       "play.example.com.:19132",
+		"localhost":               "localhost:19132",
+		"192.0.2.1":               "192.0.2.1:19132",
+		"192.0.2.1:1":             "192.0.2.1:1",
+		"2001:db8::1":             "[2001:db8::1]:19132",
+		"2001:DB8::1:1234":        "[2001:db8::1:1234]:19132",
+		"::1":                     "[::1]:19132",
+		"[::1]":                   "[::1]:19132",
+		"[::1]:19133":             "[::1]:19133",
+		"fe80::1%eth0":            "[fe80::1%eth0]:19132",
+		"[fe80::1%eth0]":          "[fe80::1%eth0]:19132",
+		"[fe80::1%eth0]:19133":    "[fe80::1%eth0]:19133",
+		"[::ffff:192.0.2.1]:1234": "[::ffff:192.0.2.1]:1234",
+	}
+	for address, expected := range tests {
+		if got, err := ParseAddress(address); err != nil || got != expected {
+			t.Errorf("ParseAddress(%q) = %q, %v, expected %q", address, got, err, expected)
+		}
+	}
+
+	for _, address := range []string{
+		"",
+		":19132",
+		"play.example.com:",
+		"play.example.com:0",
+		"play.example.com:65536",
+		"play.example.com:port",
+		"play.example.com:1:2",
+		"[::1",
+		"[::1]:",
+		"[192.0.2.1]",
+		"[play.example.com]:19132",
+		"192.0.2.1%eth0",
+		"play example.com",
+		"http://play.example.com",
+	} {
+		if got, err := ParseAddress(address); err == nil {
+			t.Errorf("ParseAddress(%q) = %q, expected an error", address, got)
+		}
+	}
+}
+
+func TestPingDefaultPort(t *testing.T) {
+	resolver := staticResolver{}
+	c := NewClient(WithResolver(resolver), WithTimeout(100*time.Millisecond))
+
+	res := c.Ping(context.Background(), "play.example.com")
+	if res.Address != "play.example.com:19132" {
+		t.Errorf("expected the default port to be added, got %+v", res)
+	}
+	if res := c.Ping(context.Background(), "play.example.com:port"); res.Err == nil {
+		t.Errorf("expected an error for an invalid address, got %+v", res)
+	}
+	if res := c.Ping(context.Background(), "::1"); res.Resolved != "[::1]:19132" {
+		t.Errorf("expected an IP address to be pinged on the default port, got %+v", res)
+	}
+}
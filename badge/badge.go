@@ -0,0 +1,140 @@
+// Package badge renders shields.io-style SVG status badges for servers, for embedding in websites and READMEs.
+package badge
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// Colors are the named colors accepted for a Badge, the same as shields.io's.
+var Colors = map[string]string{
+	"brightgreen": "#4c1",
+	"green":       "#97ca00",
+	"yellowgreen": "#a4a61d",
+	"yellow":      "#dfb317",
+	"orange":      "#fe7d37",
+	"red":         "#e05d44",
+	"blue":        "#007ec6",
+	"lightgrey":   "#9f9f9f",
+	"grey":        "#555",
+}
+
+const (
+	// DefaultLabel is the label of badges created by ForResult.
+	DefaultLabel = "bedrock"
+	// DefaultOnlineColor and DefaultOfflineColor are the colors of badges created by ForResult.
+	DefaultOnlineColor  = "brightgreen"
+	DefaultOfflineColor = "red"
+)
+
+// Badge is a two part badge, a grey label followed by a colored message.
+type Badge struct {
+	Label   string
+	Message string
+	// Color is the background of the message, a name from Colors or a hex color like "#4c1".
+	Color string
+}
+
+// ForResult returns a badge for the result of a ping, with the players if the server is online.
+func ForResult(res bedrockping.Result) Badge {
+	if res.Err != nil {
+		return Badge{Label: DefaultLabel, Message: "offline", Color: DefaultOfflineColor}
+	}
+	return Badge{
+		Label:   DefaultLabel,
+		Message: fmt.Sprintf("%d/%d players", res.Response.PlayerCount, res.Response.MaxPlayers),
+		Color:   DefaultOnlineColor,
+	}
+}
+
+// ValidColor reports whether color is a name from Colors or a hex color.
+func ValidColor(color string) bool {
+	if _, ok := Colors[color]; ok {
+		return true
+	}
+	if !strings.HasPrefix(color, "#") || (len(color) != 4 && len(color) != 7) {
+		return false
+	}
+	for _, r := range color[1:] {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// fill returns the SVG fill for color, grey if it isn't valid.
+func fill(color string) string {
+	if hex, ok := Colors[color]; ok {
+		return hex
+	}
+	if ValidColor(color) {
+		return color
+	}
+	return Colors["lightgrey"]
+}
+
+// textWidth estimates the width of s in pixels when drawn in 11px Verdana, like shields.io does.
+func textWidth(s string) int {
+	width := 0.0
+	for _, r := range s {
+		switch {
+		case strings.ContainsRune("iljI.,:;'|!", r):
+			width += 3.5
+		case strings.ContainsRune("ftr ()[]/", r):
+			width += 4.5
+		case strings.ContainsRune("mwMW", r):
+			width += 10.5
+		case r >= 'A' && r <= 'Z':
+			width += 7.5
+		default:
+			width += 7
+		}
+	}
+	return int(width + 0.5)
+}
+
+// WriteSVG writes the badge as an SVG image to w.
+// Minecraft formatting codes in the label and message are removed.
+func (b Badge) WriteSVG(w io.Writer) error {
+	label := bedrockping.StripFormatting(b.Label)
+	message := bedrockping.StripFormatting(b.Message)
+
+	// Each part has 5 pixels of padding either side of its text
+	labelWidth := textWidth(label) + 10
+	messageWidth := textWidth(message) + 10
+	width := labelWidth + messageWidth
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`,
+		width, html.EscapeString(label), html.EscapeString(message))
+	fmt.Fprintf(&buf, `<title>%s: %s</title>`, html.EscapeString(label), html.EscapeString(message))
+	buf.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	fmt.Fprintf(&buf, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`, width)
+	buf.WriteString(`<g clip-path="url(#r)">`)
+	fmt.Fprintf(&buf, `<rect width="%d" height="20" fill="#555"/>`, labelWidth)
+	fmt.Fprintf(&buf, `<rect x="%d" width="%d" height="20" fill="%s"/>`, labelWidth, messageWidth, fill(b.Color))
+	fmt.Fprintf(&buf, `<rect width="%d" height="20" fill="url(#s)"/>`, width)
+	buf.WriteString(`</g>`)
+	buf.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	for _, part := range []struct {
+		text string
+		x    int
+	}{
+		{label, labelWidth / 2},
+		{message, labelWidth + messageWidth/2},
+	} {
+		// The shadow is drawn first, a pixel below the text
+		fmt.Fprintf(&buf, `<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text>`, part.x, html.EscapeString(part.text))
+		fmt.Fprintf(&buf, `<text x="%d" y="14">%s</text>`, part.x, html.EscapeString(part.text))
+	}
+	buf.WriteString("</g></svg>\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,94 @@
+package badge
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+func TestForResult(t *testing.T) {
+	b := ForResult(bedrockping.Result{Response: bedrockping.Response{PlayerCount: 3, MaxPlayers: 20}})
+	if b != (Badge{Label: "bedrock", Message: "3/20 players", Color: "brightgreen"}) {
+		t.Errorf("unexpected badge: %+v", b)
+	}
+	b = ForResult(bedrockping.Result{Err: errors.New("timeout")})
+	if b != (Badge{Label: "bedrock", Message: "offline", Color: "red"}) {
+		t.Errorf("unexpected badge: %+v", b)
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	var buf bytes.Buffer
+	b := Badge{Label: "§aMy <Server>", Message: "3/20 players", Color: "#123abc"}
+	if err := b.WriteSVG(&buf); err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+
+	// The output must be well formed XML
+	dec := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		if _, err := dec.Token(); err != nil {
+			if !errors.Is(err, io.EOF) {
+				t.Fatalf("invalid XML: %v\n%s", err, svg)
+			}
+			break
+		}
+	}
+	for _, expect := range []string{
+		`<title>My &lt;Server&gt;: 3/20 players</title>`,
+		`fill="#123abc"`,
+		`<text x="`,
+	} {
+		if !strings.Contains(svg, expect) {
+			t.Errorf("missing %q in:\n%s", expect, svg)
+		}
+	}
+
+	// Longer messages make wider badges
+	var short, long bytes.Buffer
+	Badge{Label: "bedrock", Message: "1/20 players"}.WriteSVG(&short)
+	Badge{Label: "bedrock", Message: "100/200 players"}.WriteSVG(&long)
+	if short.Len() == 0 || svgWidth(t, short.String()) >= svgWidth(t, long.String()) {
+		t.Error("expected the longer message to be wider")
+	}
+}
+
+// svgWidth returns the width attribute of the svg element.
+func svgWidth(t *testing.T, svg string) int {
+	var root struct {
+		Width string `xml:"width,attr"`
+	}
+	if err := xml.Unmarshal([]byte(svg), &root); err != nil {
+		t.Fatal(err)
+	}
+	width, err := strconv.Atoi(root.Width)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return width
+}
+
+func TestValidColor(t *testing.T) {
+	for color, expect := range map[string]bool{
+		"brightgreen": true,
+		"#4c1":        true,
+		"#4C11AA":     true,
+		"purple":      false,
+		"#4c":         false,
+		"#ggg":        false,
+	} {
+		if got := ValidColor(color); got != expect {
+			t.Errorf("ValidColor(%q) = %t", color, got)
+		}
+	}
+	if fill("purple") != Colors["lightgrey"] || fill("red") != "#e05d44" {
+		t.Error("unexpected fill")
+	}
+}
@@ -0,0 +1,523 @@
+package bedrockping
+
+import (
+	"container/heap"
+	"context"
+	"net"
+	"net/netip"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping/internal/batchio"
+	"github.com/ZeroErrors/go-bedrockping/internal/tracker"
+)
+
+// batchResolvers is how many hosts of a batch are resolved at once.
+const batchResolvers = 16
+
+// batch is the state of a QueryMany call. A single event loop owns the query of every target, a table of
+// the queries in flight keyed by the address pongs come from and a heap of when each next needs attention.
+// Pings are sent by a sender goroutine, which waits for the rate limiters, through the writers of the
+// Multiplexer, whose readers deliver the pongs back to the loop.
+type batch struct {
+	m        *Multiplexer
+	opts     BatchOptions
+	limiter  *RateLimiter
+	ping     []byte
+	queries  []*batchQuery
+	progress *tracker.Progress
+
+	// pongs receives the pongs from the addresses in flight, sent the pings the sender is writing, failures
+	// the pings that couldn't be sent and resolved the queries whose host has been resolved
+	pongs    chan pong
+	sent     chan sentPing
+	failures chan batchFailure
+	resolved chan *batchQuery
+	// done is closed once the loop returns
+	done chan struct{}
+
+	ctx       context.Context
+	inflight  map[string][]*batchQuery
+	timers    batchTimers
+	sends     sendQueue
+	active    int
+	enriching sync.WaitGroup
+}
+
+// batchQuery is the query of one target of a batch.
+type batchQuery struct {
+	res     *Result
+	target  Target
+	timeout time.Duration
+	retries int
+
+	// addr is the resolved address, key what pongs from it are matched by and writes the queue of the socket
+	// pinging it. resolveErr is why the host couldn't be resolved.
+	addr       *net.UDPAddr
+	key        string
+	writes     chan outgoing
+	resolveErr error
+	// failed is called by the writer with the error of a ping that couldn't be sent
+	failed func(error)
+
+	// queuedAt is when the current attempt queued its first ping, start is when that ping was sent and
+	// deadline when the attempt times out, both zero until it is sent so time spent waiting for the rate
+	// limiters doesn't count. next is when the next ping is due.
+	queuedAt, start, deadline, next time.Time
+	// prevStart is when the first ping of the previous attempt was sent, which a late pong answering
+	// it is timed from while the retry's ping is still waiting
+	prevStart time.Time
+	// timer is the index of the query in the batch's timers, -1 if it isn't in them
+	timer int
+	// begun is set once the query is in the inflight table
+	begun bool
+	// queued is set while a ping is waiting to be sent, finished once the query is done
+	queued, finished atomic.Bool
+	// seq orders the queued pings of the same priority
+	seq uint64
+}
+
+// wake returns when q next needs attention, for its next ping or the end of its attempt.
+func (q *batchQuery) wake() time.Time {
+	if q.deadline.IsZero() || q.next.Before(q.deadline) {
+		return q.next
+	}
+	return q.deadline
+}
+
+// sentPing is a ping of query sent at a time.
+type sentPing struct {
+	query *batchQuery
+	at    time.Time
+}
+
+// batchFailure is a ping of query that couldn't be sent.
+type batchFailure struct {
+	query *batchQuery
+	err   error
+}
+
+// newBatch returns a batch querying targets, storing the outcome of each in results.
+func newBatch(m *Multiplexer, targets []Target, opts BatchOptions, limiter *RateLimiter, results []Result) *batch {
+	b := &batch{
+		m:        m,
+		opts:     opts,
+		limiter:  limiter,
+		ping:     AppendUnconnectedPing(nil, 0),
+		progress: tracker.New(opts.Progress, len(targets)),
+		pongs:    make(chan pong, batchio.Size),
+		sent:     make(chan sentPing),
+		failures: make(chan batchFailure),
+		resolved: make(chan *batchQuery),
+		done:     make(chan struct{}),
+		inflight: make(map[string][]*batchQuery),
+	}
+	b.sends.signal = make(chan struct{}, 1)
+
+	for i, target := range targets {
+		q := &batchQuery{res: &results[i], target: target, timeout: opts.Timeout, retries: opts.Retries, timer: -1}
+		if target.Timeout > 0 {
+			q.timeout = target.Timeout
+		}
+		if target.Retries > 0 {
+			q.retries = target.Retries
+		}
+		q.failed = func(err error) {
+			select {
+			case b.failures <- batchFailure{query: q, err: err}:
+			case <-b.done:
+			}
+		}
+		b.queries = append(b.queries, q)
+	}
+	return b
+}
+
+// run queries every target of the batch, returning once they are all done.
+func (b *batch) run(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	b.ctx = ctx
+	go b.sendLoop(ctx)
+
+	var hosts []*batchQuery
+	for _, q := range b.queries {
+		b.active++
+		b.progress.Start()
+		q.res.Address = q.target.Address()
+		if q.res.Err = b.opts.CircuitBreaker.Allow(q.res.Address); q.res.Err != nil {
+			q.finished.Store(true)
+			b.active--
+			b.progress.Finish()
+			continue
+		}
+		q.res.CheckedAt = time.Now()
+
+		// IP addresses resolve straight away, hosts are left to the resolvers
+		if host, _, err := net.SplitHostPort(q.res.Address); err == nil {
+			if _, err := netip.ParseAddr(host); err == nil {
+				b.begin(q, b.resolve(q))
+				continue
+			}
+		}
+		hosts = append(hosts, q)
+	}
+	b.startResolvers(hosts)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for b.active > 0 {
+		if len(b.timers) > 0 {
+			timer.Reset(time.Until(b.timers[0].wake()))
+		} else {
+			timer.Stop()
+		}
+
+		select {
+		case p := <-b.pongs:
+			b.answered(p, time.Now())
+		case p := <-b.sent:
+			b.sentAt(p.query, p.at)
+		case f := <-b.failures:
+			if !f.query.finished.Load() {
+				if b.m.isClosed() {
+					f.err = ErrMultiplexerClosed
+				}
+				b.finish(f.query, f.err)
+			}
+		case q := <-b.resolved:
+			b.begin(q, q.resolveErr)
+		case <-timer.C:
+			b.expire(time.Now())
+		case <-ctx.Done():
+			b.abort(ctx.Err())
+		case <-b.m.done:
+			b.abort(ErrMultiplexerClosed)
+		}
+	}
+	close(b.done)
+	b.enriching.Wait()
+}
+
+// resolve resolves the address of q, setting its key and the socket it is pinged from.
+func (b *batch) resolve(q *batchQuery) error {
+	address, err := ParseAddress(q.res.Address)
+	if err != nil {
+		return err
+	}
+	if q.addr, err = net.ResolveUDPAddr("udp", address); err != nil {
+		return err
+	}
+	q.key = q.addr.String()
+	q.writes = b.m.writes[b.m.shard(q.key)]
+	return nil
+}
+
+// startResolvers resolves the hosts of queries, batchResolvers at a time, passing each to the loop once resolved.
+func (b *batch) startResolvers(queries []*batchQuery) {
+	var next atomic.Int64
+	for range min(batchResolvers, len(queries)) {
+		go func() {
+			for {
+				i := int(next.Add(1)) - 1
+				if i >= len(queries) {
+					return
+				}
+				q := queries[i]
+				q.resolveErr = b.resolve(q)
+				select {
+				case b.resolved <- q:
+				case <-b.done:
+					return
+				}
+			}
+		}()
+	}
+}
+
+// begin starts the first attempt of q once resolving it returned err.
+func (b *batch) begin(q *batchQuery, err error) {
+	if q.finished.Load() {
+		return
+	}
+	if err == nil {
+		if _, ok := b.inflight[q.key]; !ok {
+			err = b.m.register(q.key, pongWaiter{pongs: b.pongs, done: b.done})
+		}
+	}
+	if err != nil {
+		q.res.Attempts = 1
+		b.finish(q, err)
+		return
+	}
+	b.inflight[q.key] = append(b.inflight[q.key], q)
+	q.begun = true
+	b.attempt(q, time.Now())
+}
+
+// attempt starts a new attempt of q at now, with a ping due straight away. The attempt's deadline is set
+// once the ping is sent.
+func (b *batch) attempt(q *batchQuery, now time.Time) {
+	q.res.Attempts++
+	if !q.start.IsZero() {
+		q.prevStart = q.start
+	}
+	q.queuedAt, q.start, q.deadline, q.next = now, time.Time{}, time.Time{}, now
+	if q.timer < 0 {
+		heap.Push(&b.timers, q)
+	} else {
+		heap.Fix(&b.timers, q.timer)
+	}
+}
+
+// expire handles every query needing attention by now, queuing the pings due and retrying or failing
+// the attempts that timed out.
+func (b *batch) expire(now time.Time) {
+	for len(b.timers) > 0 {
+		q := b.timers[0]
+		if q.wake().After(now) {
+			return
+		}
+		if !q.deadline.IsZero() && !now.Before(q.deadline) {
+			// Only timeouts of an attempt are worth retrying
+			if q.res.Attempts <= q.retries {
+				b.attempt(q, now)
+			} else {
+				b.finish(q, context.DeadlineExceeded)
+			}
+			continue
+		}
+
+		// A ping still waiting for the rate limiters isn't queued again
+		if !q.queued.Load() {
+			q.queued.Store(true)
+			b.sends.push(q)
+		}
+		q.next = now.Add(b.opts.Resend)
+		heap.Fix(&b.timers, q.timer)
+	}
+}
+
+// sentAt starts the clock of the current attempt of q if at is when its first ping was sent.
+func (b *batch) sentAt(q *batchQuery, at time.Time) {
+	// A ping sent before the attempt started belongs to the previous one
+	if q.finished.Load() || !q.start.IsZero() || at.Before(q.queuedAt) {
+		return
+	}
+	q.start, q.deadline = at, at.Add(q.timeout)
+	heap.Fix(&b.timers, q.timer)
+}
+
+// answered completes the queries in flight for the address of p at now.
+func (b *batch) answered(p pong, now time.Time) {
+	// The reader stops delivering pongs from an address once it has delivered one
+	queries := b.inflight[p.key]
+	delete(b.inflight, p.key)
+
+	// Targets sharing an address whose own ping is still waiting are answered by the first one sent,
+	// or if none has been by the ping of their previous attempt
+	var first time.Time
+	for _, q := range queries {
+		if !q.start.IsZero() && (first.IsZero() || q.start.Before(first)) {
+			first = q.start
+		}
+	}
+	for _, q := range queries {
+		start := q.start
+		if start.IsZero() {
+			start = first
+		}
+		if start.IsZero() {
+			start = q.prevStart
+		}
+		if start.IsZero() {
+			start = q.queuedAt
+		}
+		q.begun = false
+		q.res.Response = p.resp
+		q.res.Latency = now.Sub(start)
+		b.finish(q, nil)
+	}
+}
+
+// abort ends every query that isn't done with err.
+func (b *batch) abort(err error) {
+	for _, q := range b.queries {
+		if !q.finished.Load() {
+			if q.res.Attempts == 0 {
+				q.res.Attempts = 1
+			}
+			b.finish(q, err)
+		}
+	}
+}
+
+// finish ends the query of q with err, recording it with the circuit breaker and reporting progress once
+// a successful result is enriched.
+func (b *batch) finish(q *batchQuery, err error) {
+	q.finished.Store(true)
+	b.active--
+	if q.timer >= 0 {
+		heap.Remove(&b.timers, q.timer)
+	}
+	if q.begun {
+		q.begun = false
+		queries := b.inflight[q.key]
+		for i, other := range queries {
+			if other == q {
+				queries = append(queries[:i], queries[i+1:]...)
+				break
+			}
+		}
+		if len(queries) == 0 {
+			delete(b.inflight, q.key)
+			b.m.unregister(q.key, pongWaiter{pongs: b.pongs})
+		} else {
+			b.inflight[q.key] = queries
+		}
+	}
+
+	q.res.Err = err
+	if err != nil || len(b.opts.Enrichers) == 0 {
+		b.record(q)
+		return
+	}
+	b.enriching.Add(1)
+	go func() {
+		defer b.enriching.Done()
+		Enrich(b.ctx, q.res, b.opts.Enrichers...)
+		b.record(q)
+	}()
+}
+
+// record records the result of q with the circuit breaker and reports it done.
+func (b *batch) record(q *batchQuery) {
+	b.opts.CircuitBreaker.Record(q.res.Address, q.res.Err)
+	b.progress.Finish()
+}
+
+// sendLoop sends the pings queued by the loop, the highest priority first, once the rate limiters allow.
+func (b *batch) sendLoop(ctx context.Context) {
+	for {
+		q := b.sends.pop()
+		if q == nil {
+			select {
+			case <-b.sends.signal:
+				continue
+			case <-b.done:
+				return
+			}
+		}
+		if q.finished.Load() {
+			q.queued.Store(false)
+			continue
+		}
+
+		if b.limiter.WaitPriority(ctx, q.target.Priority) != nil || b.m.rateLimiter().WaitPriority(ctx, q.target.Priority) != nil {
+			return
+		}
+		// The loop hears of the ping before it is written, so it can't see the pong first
+		select {
+		case b.sent <- sentPing{query: q, at: time.Now()}:
+		case <-b.done:
+			return
+		}
+		select {
+		case q.writes <- outgoing{ping: b.ping, addr: q.addr, failed: q.failed}:
+		case <-b.done:
+			return
+		case <-b.m.done:
+			return
+		}
+		q.queued.Store(false)
+	}
+}
+
+// sendQueue holds the queries of a batch with a ping waiting to be sent, the highest priority first and
+// in the order they were queued within a priority.
+type sendQueue struct {
+	mu      sync.Mutex
+	queries sendHeap
+	seq     uint64
+	// signal is sent to when a query is pushed
+	signal chan struct{}
+}
+
+// push queues a ping for q.
+func (s *sendQueue) push(q *batchQuery) {
+	s.mu.Lock()
+	q.seq = s.seq
+	s.seq++
+	heap.Push(&s.queries, q)
+	s.mu.Unlock()
+
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
+
+// pop returns the next query to send a ping for, or nil if there are none.
+func (s *sendQueue) pop() *batchQuery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.queries) == 0 {
+		return nil
+	}
+	return heap.Pop(&s.queries).(*batchQuery)
+}
+
+// sendHeap is a heap of queries ordered by priority, then the order they were queued.
+type sendHeap []*batchQuery
+
+func (h sendHeap) Len() int { return len(h) }
+
+func (h sendHeap) Less(i, j int) bool {
+	if h[i].target.Priority != h[j].target.Priority {
+		return h[i].target.Priority > h[j].target.Priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h sendHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *sendHeap) Push(x any) {
+	*h = append(*h, x.(*batchQuery))
+}
+
+func (h *sendHeap) Pop() any {
+	old := *h
+	q := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return q
+}
+
+// batchTimers is a heap of queries ordered by when they next need attention.
+type batchTimers []*batchQuery
+
+func (t batchTimers) Len() int { return len(t) }
+
+func (t batchTimers) Less(i, j int) bool { return t[i].wake().Before(t[j].wake()) }
+
+func (t batchTimers) Swap(i, j int) {
+	t[i], t[j] = t[j], t[i]
+	t[i].timer = i
+	t[j].timer = j
+}
+
+func (t *batchTimers) Push(x any) {
+	q := x.(*batchQuery)
+	q.timer = len(*t)
+	*t = append(*t, q)
+}
+
+func (t *batchTimers) Pop() any {
+	old := *t
+	q := old[len(old)-1]
+	old[len(old)-1] = nil
+	q.timer = -1
+	*t = old[:len(old)-1]
+	return q
+}
@@ -0,0 +1,145 @@
+package bedrockping
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestBatchManyTargets(t *testing.T) {
+	servers := []string{
+		startTestServer(t, testResponse("A")),
+		startTestServer(t, testResponse("B")),
+	}
+	// Every server is listed many times, so the same address is in flight for several targets at once
+	var targets []Target
+	for i := 0; i < 200; i++ {
+		targets = append(targets, Target{Host: servers[i%len(servers)]})
+	}
+
+	m, err := NewMultiplexer("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	before := runtime.NumGoroutine()
+	results := m.QueryMany(context.Background(), targets, BatchOptions{Timeout: 2 * time.Second, Resend: 50 * time.Millisecond})
+	for i, res := range results {
+		if res.Err != nil {
+			t.Errorf("%s: %v", res.Address, res.Err)
+			continue
+		}
+		if name := string(rune('A' + i%len(servers))); res.Response.ServerName != name {
+			t.Errorf("result %d has server name %s, expected %s", i, res.Response.ServerName, name)
+		}
+	}
+
+	// The batch's goroutines exit with it
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("%d goroutines left running after the batch, expected at most %d", n, before)
+	}
+}
+
+func TestBatchRateLimitedTimeout(t *testing.T) {
+	var targets []Target
+	for _, name := range []string{"A", "B", "C", "D"} {
+		targets = append(targets, Target{Host: startTestServer(t, testResponse(name))})
+	}
+
+	m, err := NewMultiplexer("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	// The last target waits 3 seconds for its ping to be sent, far longer than the timeout, which only
+	// starts once it is
+	timeout := 500 * time.Millisecond
+	results := m.QueryMany(context.Background(), targets, BatchOptions{Timeout: timeout, RateLimit: 1, Burst: 1})
+	for _, res := range results {
+		if res.Err != nil {
+			t.Errorf("%s: %v", res.Address, res.Err)
+			continue
+		}
+		if res.Attempts != 1 {
+			t.Errorf("%s: made %d attempts, expected 1", res.Address, res.Attempts)
+		}
+		if res.Latency >= timeout {
+			t.Errorf("%s: latency %s includes the time spent waiting to be sent", res.Address, res.Latency)
+		}
+	}
+}
+
+func TestBatchThrottledRetryLatePong(t *testing.T) {
+	address := startSlowResponder(t, 150*time.Millisecond)
+
+	m, err := NewMultiplexer("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	// The first attempt times out before its pong arrives, which is while the retry's ping is still waiting
+	// for the rate limiter, so the pong is timed from the first attempt's ping
+	results := m.QueryMany(context.Background(), []Target{{Host: address}}, BatchOptions{Timeout: 100 * time.Millisecond, Retries: 1, RateLimit: 1, Burst: 1})
+	res := results[0]
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if res.Attempts != 2 {
+		t.Errorf("expected the pong to arrive during the retry, made %d attempts", res.Attempts)
+	}
+	if res.Latency < 150*time.Millisecond || res.Latency >= time.Second {
+		t.Errorf("expected the latency of the first attempt's ping, got %s", res.Latency)
+	}
+}
+
+func TestBatchCanceled(t *testing.T) {
+	m, err := NewMultiplexer("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	targets := make([]Target, 10)
+	for i := range targets {
+		// Nothing answers on the discard port
+		targets[i] = Target{Host: fmt.Sprintf("127.0.0.%d", i+1), Port: 9}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	results := m.QueryMany(ctx, targets, BatchOptions{Timeout: 5 * time.Second})
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("batch took %s to stop after its context ended", elapsed)
+	}
+	for _, res := range results {
+		if res.Err != context.DeadlineExceeded {
+			t.Errorf("%s: expected deadline exceeded, got: %v", res.Address, res.Err)
+		}
+		if res.Attempts != 1 {
+			t.Errorf("%s: made %d attempts, expected 1", res.Address, res.Attempts)
+		}
+	}
+}
+
+func TestBatchClosed(t *testing.T) {
+	m, err := NewMultiplexer("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.AfterFunc(50*time.Millisecond, func() { m.Close() })
+
+	results := m.QueryMany(context.Background(), []Target{{Host: "127.0.0.1", Port: 9}}, BatchOptions{Timeout: 5 * time.Second})
+	if results[0].Err != ErrMultiplexerClosed {
+		t.Errorf("expected ErrMultiplexerClosed, got: %v", results[0].Err)
+	}
+}
@@ -20,6 +20,7 @@ const (
 )
 
 // Response data returned from ReadUnconnectedPong.
+// It's encoded to JSON and YAML as a ResponseJSON, which can also be used for TOML.
 type Response struct {
 	Timestamp       uint64   `json:"timestamp"`
 	ServerID        uint64   `json:"serverId"`
@@ -65,6 +66,14 @@ func WriteUnconnectedPing(writer io.Writer, timestamp uint64) error {
 	return nil
 }
 
+// AppendUnconnectedPing appends the 'Unconnected Ping (0x01)' packet to dst and returns the extended slice,
+// the allocation free equivalent of WriteUnconnectedPing for senders that reuse a buffer.
+func AppendUnconnectedPing(dst []byte, timestamp uint64) []byte {
+	dst = append(dst, 0x01)
+	dst = binary.BigEndian.AppendUint64(dst, timestamp)
+	return append(dst, offlineMessageDataID...)
+}
+
 // ReadUTFString reads a UTF-8 string with a uint16 length header.
 func ReadUTFString(reader io.Reader) (string, error) {
 	var strLen uint16
@@ -80,6 +89,18 @@ func ReadUTFString(reader io.Reader) (string, error) {
 	return string(strBytes), nil
 }
 
+// WriteUTFString writes a UTF-8 string with a uint16 length header.
+func WriteUTFString(writer io.Writer, str string) error {
+	if len(str) > 0xffff {
+		return fmt.Errorf("string too long: %d bytes", len(str))
+	}
+	if err := binary.Write(writer, binary.BigEndian, uint16(len(str))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(writer, str)
+	return err
+}
+
 // ReadUnconnectedPong reads the 'Unconnected Pong (0x1C)' packet from a connection into a Response struct.
 // Details on the packet structure can be found:
 // https://github.com/NiclasOlofsson/MiNET/blob/5bcfbfd94cff943f31208eb8614b3ff16269fdc7/src/MiNET/MiNET/Net/MCPE%20Protocol.cs#L1154
@@ -143,12 +164,122 @@ func ReadUnconnectedPong(reader *bufio.Reader, resp *Response) error {
 	return nil
 }
 
+// DecodeUnconnectedPong decodes the 'Unconnected Pong (0x1C)' packet pkt into resp, the equivalent of
+// ReadUnconnectedPong for a packet already read into a slice. It decodes without bufio or reflection, only
+// allocating the string the payload's fields share, and the Extra slice if the payload has extra fields.
+// Every field of resp is overwritten, Extra with nil if there are no extra fields.
+func DecodeUnconnectedPong(pkt []byte, resp *Response) error {
+	resp.Extra = nil
+	return DecodeInto(pkt, resp)
+}
+
+// DecodeInto decodes the 'Unconnected Pong (0x1C)' packet pkt into resp like DecodeUnconnectedPong, but reuses
+// the backing array of resp.Extra for the extra fields if it is large enough, so a Response decoded into over
+// and over, such as by a monitor sampling a server every second, only allocates the payload string.
+// Any previous state of resp is cleared first, and Extra is nil if the payload has no extra fields. The
+// previous Extra is overwritten, so it must not be used after calling DecodeInto.
+func DecodeInto(pkt []byte, resp *Response) error {
+	extra := resp.Extra[:0]
+	*resp = Response{}
+	if len(pkt) == 0 {
+		return io.EOF
+	}
+	if pkt[0] != 0x1c {
+		return fmt.Errorf("unexpected packet id: %d", pkt[0])
+	}
+	const header = 1 + 8 + 8 + 16
+	if len(pkt) < header+2 {
+		return io.ErrUnexpectedEOF
+	}
+	if id := pkt[17:33]; !bytes.Equal(offlineMessageDataID, id) {
+		return fmt.Errorf("invalid offline message data id: %x", id)
+	}
+	size := int(binary.BigEndian.Uint16(pkt[header:]))
+	if len(pkt) < header+2+size {
+		return io.ErrUnexpectedEOF
+	}
+	payload := string(pkt[header+2 : header+2+size])
+
+	// Cut the six fields every pong has, leaving any extra ones in rest
+	var fields [6]string
+	rest, more := payload, true
+	for i := range fields {
+		if !more {
+			return fmt.Errorf("invalid payload: %s", payload)
+		}
+		fields[i], rest, more = strings.Cut(rest, ";")
+	}
+
+	var err error
+	resp.Timestamp = binary.BigEndian.Uint64(pkt[1:])
+	resp.ServerID = binary.BigEndian.Uint64(pkt[9:])
+	resp.GameID = fields[0]
+	resp.ServerName = fields[1]
+	resp.MCPEVersion = fields[3]
+	if more {
+		if n := strings.Count(rest, ";") + 1; cap(extra) < n {
+			extra = make([]string, 0, n)
+		}
+		for more {
+			var field string
+			field, rest, more = strings.Cut(rest, ";")
+			extra = append(extra, field)
+		}
+		resp.Extra = extra
+	}
+	if resp.ProtocolVersion, err = strconv.Atoi(fields[2]); err != nil {
+		return err
+	}
+	if resp.PlayerCount, err = strconv.Atoi(fields[4]); err != nil {
+		return err
+	}
+	if resp.MaxPlayers, err = strconv.Atoi(fields[5]); err != nil {
+		return err
+	}
+	return nil
+}
+
+// WriteUnconnectedPong writes the 'Unconnected Pong (0x1C)' packet described by resp to a writer,
+// the inverse of ReadUnconnectedPong.
+func WriteUnconnectedPong(writer io.Writer, resp Response) error {
+	if err := binary.Write(writer, binary.BigEndian, byte(0x1c)); err != nil {
+		return err
+	}
+	if err := binary.Write(writer, binary.BigEndian, resp.Timestamp); err != nil {
+		return err
+	}
+	if err := binary.Write(writer, binary.BigEndian, resp.ServerID); err != nil {
+		return err
+	}
+	if _, err := writer.Write(offlineMessageDataID); err != nil {
+		return err
+	}
+
+	payload := fmt.Sprintf("%s;%s;%d;%s;%d;%d",
+		resp.GameID,
+		resp.ServerName,
+		resp.ProtocolVersion,
+		resp.MCPEVersion,
+		resp.PlayerCount,
+		resp.MaxPlayers)
+	if resp.Extra != nil {
+		payload = payload + ";" + strings.Join(resp.Extra, ";")
+	}
+	return WriteUTFString(writer, payload)
+}
+
 // Query makes a query to the specified address via the Minecraft Bedrock protocol,
 // if successful it returns a Response containing data from the pong packet.
 // resend is the interval that the ping packet is sent in case there is packet loss.
+// The address is parsed with ParseAddress, so Query("play.example.com", ...) pings DefaultPort.
 func Query(address string, timeout time.Duration, resend time.Duration) (Response, error) {
 	var resp Response
 
+	address, err := ParseAddress(address)
+	if err != nil {
+		return resp, err
+	}
+
 	deadline := time.Now().Add(timeout)
 
 	conn, err := net.DialTimeout("udp", address, timeout)
@@ -156,6 +287,7 @@ func Query(address string, timeout time.Duration, resend time.Duration) (Respons
 		return resp, err
 	}
 	defer conn.Close()
+	reportPortUnreachable(conn)
 
 	if err = conn.SetDeadline(deadline); err != nil {
 		return resp, err
@@ -184,12 +316,12 @@ func Query(address string, timeout time.Duration, resend time.Duration) (Respons
 
 	reader := bufio.NewReader(conn)
 	if err = ReadUnconnectedPong(reader, &resp); err != nil {
-		return resp, err
+		return resp, portClosed(err)
 	}
 
 	select {
 	case err := <-errs:
-		return resp, err
+		return resp, portClosed(err)
 	default:
 		return resp, nil
 	}
@@ -5,7 +5,6 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
-	"io"
 	"reflect"
 	"strings"
 	"testing"
@@ -52,25 +51,12 @@ func TestWriteUnconnectedPing(t *testing.T) {
 	}
 }
 
-func writeUTFString(buf io.Writer, str string) error {
-	var strLen = uint16(len(str))
-	if err := binary.Write(buf, binary.BigEndian, &strLen); err != nil {
-		return err
-	}
-
-	if _, err := buf.Write([]byte(str)); err != nil {
-		return err
-	}
-
-	return nil
-}
-
 func TestReadUTFString(t *testing.T) {
 	var testString = "This is some test string"
 
 	buf := new(bytes.Buffer)
 
-	if err := writeUTFString(buf, testString); err != nil {
+	if err := WriteUTFString(buf, testString); err != nil {
 		t.Error(err)
 	}
 
@@ -122,7 +108,7 @@ func TestReadUnconnectedPong(t *testing.T) {
 	if expect.Extra != nil {
 		payload = payload + ";" + strings.Join(expect.Extra, ";")
 	}
-	if err := writeUTFString(buf, payload); err != nil {
+	if err := WriteUTFString(buf, payload); err != nil {
 		t.Error(err)
 	}
 
@@ -144,3 +130,134 @@ func TestQuery(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestAppendUnconnectedPing(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := WriteUnconnectedPing(buf, 1234567890); err != nil {
+		t.Fatal(err)
+	}
+	prefix := []byte{0xaa}
+	if ping := AppendUnconnectedPing(prefix, 1234567890); !bytes.Equal(ping, append([]byte{0xaa}, buf.Bytes()...)) {
+		t.Errorf("expected %x after the prefix, got %x", buf.Bytes(), ping)
+	}
+
+	dst := make([]byte, 0, 64)
+	if allocs := testing.AllocsPerRun(100, func() { AppendUnconnectedPing(dst[:0], 1) }); allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+}
+
+func TestDecodeUnconnectedPong(t *testing.T) {
+	for _, expect := range []Response{
+		{Timestamp: 1, ServerID: 2, GameID: "MCPE", ServerName: "ServerName", ProtocolVersion: 3, MCPEVersion: "1.2.3", PlayerCount: 4, MaxPlayers: 5},
+		{GameID: "MCPE", ServerName: "ServerName", MCPEVersion: "0.0.0", Extra: []string{"Extra", "Stuff"}},
+		{GameID: "MCPE", Extra: []string{""}},
+	} {
+		buf := new(bytes.Buffer)
+		if err := WriteUnconnectedPong(buf, expect); err != nil {
+			t.Fatal(err)
+		}
+		var read Response
+		if err := ReadUnconnectedPong(bufio.NewReader(bytes.NewReader(buf.Bytes())), &read); err != nil {
+			t.Fatal(err)
+		}
+
+		resp := Response{Extra: []string{"previous"}}
+		if err := DecodeUnconnectedPong(buf.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(expect, resp) || !reflect.DeepEqual(read, resp) {
+			t.Errorf("expected %+v, got %+v", expect, resp)
+		}
+	}
+}
+
+func TestDecodeUnconnectedPongInvalid(t *testing.T) {
+	valid := new(bytes.Buffer)
+	if err := WriteUnconnectedPong(valid, Response{GameID: "MCPE", ServerName: "Name"}); err != nil {
+		t.Fatal(err)
+	}
+	pong := valid.Bytes()
+	withPayload := func(payload string) []byte {
+		buf := bytes.NewBuffer(bytes.Clone(pong[:33]))
+		WriteUTFString(buf, payload)
+		return buf.Bytes()
+	}
+	badID := bytes.Clone(pong)
+	badID[18] = 0
+
+	for _, pkt := range [][]byte{
+		nil,
+		{0x01},
+		pong[:20],
+		pong[:len(pong)-1],
+		badID,
+		withPayload("MCPE;Name;0;1.0;0"),
+		withPayload("MCPE;Name;x;1.0;0;0"),
+		withPayload("MCPE;Name;0;1.0;0;x"),
+	} {
+		var resp Response
+		if err := DecodeUnconnectedPong(pkt, &resp); err == nil {
+			t.Errorf("%x: expected an error", pkt)
+		}
+	}
+}
+
+func TestDecodeUnconnectedPongAllocs(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := WriteUnconnectedPong(buf, Response{GameID: "MCPE", ServerName: "ServerName", MCPEVersion: "1.21.0", MaxPlayers: 10}); err != nil {
+		t.Fatal(err)
+	}
+	var resp Response
+	// Only the payload string is allocated
+	if allocs := testing.AllocsPerRun(100, func() { DecodeUnconnectedPong(buf.Bytes(), &resp) }); allocs != 1 {
+		t.Errorf("expected 1 allocation, got %v", allocs)
+	}
+}
+
+func TestDecodeInto(t *testing.T) {
+	encode := func(resp Response) []byte {
+		buf := new(bytes.Buffer)
+		if err := WriteUnconnectedPong(buf, resp); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	first := encode(Response{Timestamp: 1, GameID: "MCPE", ServerName: "First", PlayerCount: 5, Extra: []string{"a", "b", "c"}})
+	second := encode(Response{GameID: "MCPE", ServerName: "Second", Extra: []string{"d", "e"}})
+	plain := encode(Response{GameID: "MCPE", ServerName: "Plain"})
+
+	var resp Response
+	if err := DecodeInto(first, &resp); err != nil {
+		t.Fatal(err)
+	}
+	backing := &resp.Extra[:1][0]
+	if err := DecodeInto(second, &resp); err != nil {
+		t.Fatal(err)
+	}
+	expect := Response{GameID: "MCPE", ServerName: "Second", Extra: []string{"d", "e"}}
+	if !reflect.DeepEqual(expect, resp) {
+		t.Errorf("expected %+v, got %+v", expect, resp)
+	}
+	if &resp.Extra[0] != backing {
+		t.Error("expected the Extra backing array to be reused")
+	}
+
+	if err := DecodeInto(plain, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if expect := (Response{GameID: "MCPE", ServerName: "Plain"}); !reflect.DeepEqual(expect, resp) {
+		t.Errorf("expected %+v, got %+v", expect, resp)
+	}
+
+	// Decoding the same server over and over only allocates its payload
+	resp = Response{}
+	DecodeInto(first, &resp)
+	if allocs := testing.AllocsPerRun(100, func() { DecodeInto(first, &resp) }); allocs != 1 {
+		t.Errorf("expected 1 allocation, got %v", allocs)
+	}
+
+	if err := DecodeInto([]byte{0x01}, &resp); err == nil || !reflect.DeepEqual(Response{}, resp) {
+		t.Errorf("expected an error and a cleared response, got %v %+v", err, resp)
+	}
+}
@@ -0,0 +1,222 @@
+// Package binenc encodes results as compact MessagePack or CBOR records, for storing millions of scan
+// results where the field names repeated in every JSON record add up.
+//
+// Each record holds a Result with its Response. MessagePack records are arrays of their fields in a fixed
+// order, and CBOR records are maps keyed by small integers. New fields are only ever added at the end,
+// so records written by older versions can still be read.
+package binenc
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// record is the binary representation of a Result.
+type record struct {
+	_msgpack struct{} `msgpack:",as_array"`
+
+	Address  string `cbor:"1,keyasint,omitempty"`
+	Resolved string `cbor:"2,keyasint,omitempty"`
+	// CheckedAt is in nanoseconds since the Unix epoch, 0 if the result has no time.
+	CheckedAt int64 `cbor:"3,keyasint,omitempty"`
+	// Latency is in nanoseconds.
+	Latency  int64 `cbor:"4,keyasint,omitempty"`
+	Attempts int   `cbor:"5,keyasint,omitempty"`
+	Sent     int   `cbor:"6,keyasint,omitempty"`
+	// Response is only set if the server is online.
+	Response   *response         `cbor:"7,keyasint,omitempty"`
+	Error      string            `cbor:"8,keyasint,omitempty"`
+	ErrorKind  string            `cbor:"9,keyasint,omitempty"`
+	Enrichment map[string]string `cbor:"10,keyasint,omitempty"`
+}
+
+// response is the binary representation of a Response.
+type response struct {
+	_msgpack struct{} `msgpack:",as_array"`
+
+	Timestamp       uint64   `cbor:"1,keyasint,omitempty"`
+	ServerID        uint64   `cbor:"2,keyasint,omitempty"`
+	GameID          string   `cbor:"3,keyasint,omitempty"`
+	ServerName      string   `cbor:"4,keyasint,omitempty"`
+	ProtocolVersion int      `cbor:"5,keyasint,omitempty"`
+	MCPEVersion     string   `cbor:"6,keyasint,omitempty"`
+	PlayerCount     int      `cbor:"7,keyasint,omitempty"`
+	MaxPlayers      int      `cbor:"8,keyasint,omitempty"`
+	Extra           []string `cbor:"9,keyasint,omitempty"`
+}
+
+// DecodeMsgpack decodes r from an array, which may have fewer or more fields than r if written by another version.
+func (r *record) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeArray(dec, &r.Address, &r.Resolved, &r.CheckedAt, &r.Latency, &r.Attempts, &r.Sent, &r.Response,
+		&r.Error, &r.ErrorKind, &r.Enrichment)
+}
+
+func (r *response) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeArray(dec, &r.Timestamp, &r.ServerID, &r.GameID, &r.ServerName, &r.ProtocolVersion, &r.MCPEVersion,
+		&r.PlayerCount, &r.MaxPlayers, &r.Extra)
+}
+
+// decodeArray decodes the elements of an array into fields in order, skipping any elements beyond them.
+func decodeArray(dec *msgpack.Decoder, fields ...any) error {
+	n, err := dec.DecodeArrayLen()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if i < len(fields) {
+			err = dec.Decode(fields[i])
+		} else {
+			err = dec.Skip()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newRecord(res bedrockping.Result) *record {
+	r := &record{
+		Address:    res.Address,
+		Resolved:   res.Resolved,
+		Attempts:   res.Attempts,
+		Sent:       res.Sent,
+		Enrichment: res.Enrichment,
+	}
+	if !res.CheckedAt.IsZero() {
+		r.CheckedAt = res.CheckedAt.UnixNano()
+	}
+	if res.Err != nil {
+		r.Error = res.Err.Error()
+		r.ErrorKind = bedrockping.ErrorKind(res.Err)
+		return r
+	}
+	resp := res.Response
+	r.Latency = int64(res.Latency)
+	r.Response = &response{
+		Timestamp:       resp.Timestamp,
+		ServerID:        resp.ServerID,
+		GameID:          resp.GameID,
+		ServerName:      resp.ServerName,
+		ProtocolVersion: resp.ProtocolVersion,
+		MCPEVersion:     resp.MCPEVersion,
+		PlayerCount:     resp.PlayerCount,
+		MaxPlayers:      resp.MaxPlayers,
+		Extra:           resp.Extra,
+	}
+	return r
+}
+
+// result returns the Result r represents. The error of an offline server only keeps its message.
+func (r *record) result() bedrockping.Result {
+	res := bedrockping.Result{
+		Address:    r.Address,
+		Resolved:   r.Resolved,
+		Latency:    time.Duration(r.Latency),
+		Attempts:   r.Attempts,
+		Sent:       r.Sent,
+		Enrichment: r.Enrichment,
+	}
+	if r.CheckedAt != 0 {
+		res.CheckedAt = time.Unix(0, r.CheckedAt).UTC()
+	}
+	if r.Response == nil {
+		res.Err = errors.New(r.Error)
+		return res
+	}
+	resp := r.Response
+	res.Response = bedrockping.Response{
+		Timestamp:       resp.Timestamp,
+		ServerID:        resp.ServerID,
+		GameID:          resp.GameID,
+		ServerName:      resp.ServerName,
+		ProtocolVersion: resp.ProtocolVersion,
+		MCPEVersion:     resp.MCPEVersion,
+		PlayerCount:     resp.PlayerCount,
+		MaxPlayers:      resp.MaxPlayers,
+		Extra:           resp.Extra,
+	}
+	return res
+}
+
+// Encoder writes results to a stream one record after another.
+type Encoder struct {
+	encode func(v any) error
+}
+
+// NewMsgpackEncoder returns an Encoder writing MessagePack records to w.
+func NewMsgpackEncoder(w io.Writer) *Encoder {
+	enc := msgpack.NewEncoder(w)
+	return &Encoder{encode: enc.Encode}
+}
+
+// NewCBOREncoder returns an Encoder writing CBOR records to w.
+func NewCBOREncoder(w io.Writer) *Encoder {
+	enc := cbor.NewEncoder(w)
+	return &Encoder{encode: enc.Encode}
+}
+
+// Encode writes the record of res.
+func (e *Encoder) Encode(res bedrockping.Result) error {
+	return e.encode(newRecord(res))
+}
+
+// Decoder reads results from a stream of records written by an Encoder.
+type Decoder struct {
+	decode func(v any) error
+}
+
+// NewMsgpackDecoder returns a Decoder reading MessagePack records from r.
+func NewMsgpackDecoder(r io.Reader) *Decoder {
+	dec := msgpack.NewDecoder(r)
+	return &Decoder{decode: dec.Decode}
+}
+
+// NewCBORDecoder returns a Decoder reading CBOR records from r.
+func NewCBORDecoder(r io.Reader) *Decoder {
+	dec := cbor.NewDecoder(r)
+	return &Decoder{decode: dec.Decode}
+}
+
+// Decode reads the next record, returning io.EOF once there are no more.
+func (d *Decoder) Decode() (bedrockping.Result, error) {
+	var r record
+	if err := d.decode(&r); err != nil {
+		return bedrockping.Result{}, err
+	}
+	return r.result(), nil
+}
+
+// MarshalMsgpack returns the MessagePack record of res.
+func MarshalMsgpack(res bedrockping.Result) ([]byte, error) {
+	return msgpack.Marshal(newRecord(res))
+}
+
+// UnmarshalMsgpack returns the Result held by the MessagePack record data.
+func UnmarshalMsgpack(data []byte) (bedrockping.Result, error) {
+	var r record
+	if err := msgpack.Unmarshal(data, &r); err != nil {
+		return bedrockping.Result{}, err
+	}
+	return r.result(), nil
+}
+
+// MarshalCBOR returns the CBOR record of res.
+func MarshalCBOR(res bedrockping.Result) ([]byte, error) {
+	return cbor.Marshal(newRecord(res))
+}
+
+// UnmarshalCBOR returns the Result held by the CBOR record data.
+func UnmarshalCBOR(data []byte) (bedrockping.Result, error) {
+	var r record
+	if err := cbor.Unmarshal(data, &r); err != nil {
+		return bedrockping.Result{}, err
+	}
+	return r.result(), nil
+}
@@ -0,0 +1,132 @@
+package binenc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+var testResults = []bedrockping.Result{
+	{
+		Address:  "play.example.com:19132",
+		Resolved: "203.0.113.7:19132",
+		Response: bedrockping.Response{Timestamp: 1, ServerID: 1<<64 - 1, GameID: "MCPE", ServerName: "My Server",
+			ProtocolVersion: 390, MCPEVersion: "1.14.60", PlayerCount: 3, MaxPlayers: 20, Extra: []string{"1", "world"}},
+		Latency:    23500 * time.Microsecond,
+		Attempts:   1,
+		Sent:       1,
+		CheckedAt:  time.Date(2024, 5, 1, 12, 0, 0, 123, time.UTC),
+		Enrichment: map[string]string{"rdns": "host.example.com"},
+	},
+	{Address: "down.example.com:19132", Attempts: 2, Sent: 4, Err: context.DeadlineExceeded},
+}
+
+// checkResult fails the test unless got is want, whose error only keeps its message.
+func checkResult(t *testing.T, got, want bedrockping.Result) {
+	t.Helper()
+	if want.Err != nil {
+		if got.Err == nil || got.Err.Error() != want.Err.Error() {
+			t.Errorf("expected error %q, got %v", want.Err, got.Err)
+		}
+		got.Err = want.Err
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected result:\n%+v\nexpected:\n%+v", got, want)
+	}
+}
+
+func TestStream(t *testing.T) {
+	for name, codec := range map[string]struct {
+		enc func(io.Writer) *Encoder
+		dec func(io.Reader) *Decoder
+	}{
+		"msgpack": {NewMsgpackEncoder, NewMsgpackDecoder},
+		"cbor":    {NewCBOREncoder, NewCBORDecoder},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			enc := codec.enc(&buf)
+			for _, res := range testResults {
+				if err := enc.Encode(res); err != nil {
+					t.Fatal(err)
+				}
+			}
+			dec := codec.dec(&buf)
+			for _, want := range testResults {
+				got, err := dec.Decode()
+				if err != nil {
+					t.Fatal(err)
+				}
+				checkResult(t, got, want)
+			}
+			if _, err := dec.Decode(); err != io.EOF {
+				t.Errorf("expected io.EOF, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	res := testResults[0]
+	mp, err := MarshalMsgpack(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := UnmarshalMsgpack(mp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkResult(t, got, res)
+
+	cb, err := MarshalCBOR(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err = UnmarshalCBOR(cb); err != nil {
+		t.Fatal(err)
+	}
+	checkResult(t, got, res)
+
+	js, _ := json.Marshal(res)
+	if len(mp) >= len(js)/2 || len(cb) >= len(js)/2 {
+		t.Errorf("expected records much smaller than JSON's %d bytes, got %d and %d", len(js), len(mp), len(cb))
+	}
+}
+
+func TestOlderRecord(t *testing.T) {
+	// A record written before the later fields were added
+	data, err := msgpack.Marshal([]any{"a:1", "203.0.113.7:1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := UnmarshalMsgpack(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Address != "a:1" || res.Resolved != "203.0.113.7:1" {
+		t.Errorf("unexpected result: %+v", res)
+	}
+}
+
+func TestNewerRecord(t *testing.T) {
+	// A record written after more fields were added
+	data, err := msgpack.Marshal([]any{"a:1", "", 0, 0, 1, 1, nil, "timeout", "timeout", nil, "new field"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := UnmarshalMsgpack(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Address != "a:1" || res.Attempts != 1 || res.Err == nil || res.Err.Error() != "timeout" {
+		t.Errorf("unexpected result: %+v", res)
+	}
+}
@@ -0,0 +1,123 @@
+package bedrockping
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned instead of pinging a server that has failed repeatedly, until its cooldown ends.
+var ErrCircuitOpen = errors.New("bedrockping: circuit open")
+
+// CircuitBreaker stops servers that have failed repeatedly from being pinged for a cooldown, so batches don't
+// waste their time budget waiting for dead hosts to time out. After failures consecutive failed queries of a
+// server its circuit opens, and queries fail with ErrCircuitOpen straight away. Once the cooldown has passed
+// a single query is let through: if it succeeds the circuit closes, otherwise it opens for another cooldown.
+// Queries cancelled by their context don't count.
+//
+// A CircuitBreaker may be shared between Clients and batches, so what's learned about a server carries over.
+// A nil *CircuitBreaker never opens. A CircuitBreaker is safe for concurrent use.
+type CircuitBreaker struct {
+	failures int
+	cooldown time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*circuit
+}
+
+// circuit is the state of a server that has failed since it last succeeded.
+type circuit struct {
+	failures  int
+	openUntil time.Time
+	// trial is set while the query let through after the cooldown is in flight
+	trial bool
+}
+
+// NewCircuitBreaker returns a CircuitBreaker opening after failures consecutive failures of a server for cooldown.
+// If failures is less than 1 it is set to 1.
+func NewCircuitBreaker(failures int, cooldown time.Duration) *CircuitBreaker {
+	if failures < 1 {
+		failures = 1
+	}
+	return &CircuitBreaker{failures: failures, cooldown: cooldown, hosts: make(map[string]*circuit)}
+}
+
+// WithCircuitBreaker stops the Client pinging servers whose circuit is open in b, see CircuitBreaker.
+func WithCircuitBreaker(b *CircuitBreaker) Option {
+	return func(c *Client) {
+		c.breaker = b
+	}
+}
+
+// Allow returns ErrCircuitOpen if the server at address mustn't be queried, otherwise nil.
+// Every query allowed must be followed by a call to Record with its outcome.
+func (b *CircuitBreaker) Allow(address string) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.hosts[address]
+	if !ok || c.failures < b.failures {
+		return nil
+	}
+	if c.trial || time.Now().Before(c.openUntil) {
+		return ErrCircuitOpen
+	}
+	c.trial = true
+	return nil
+}
+
+// Record records the outcome of a query of the server at address allowed by Allow.
+func (b *CircuitBreaker) Record(address string, err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		delete(b.hosts, address)
+		return
+	}
+	c, ok := b.hosts[address]
+	if errors.Is(err, context.Canceled) {
+		if ok {
+			c.trial = false
+		}
+		return
+	}
+	if !ok {
+		c = &circuit{}
+		b.hosts[address] = c
+	}
+	c.trial = false
+	c.failures++
+	if c.failures >= b.failures {
+		c.openUntil = time.Now().Add(b.cooldown)
+	}
+}
+
+// Open reports whether the circuit of the server at address is open.
+func (b *CircuitBreaker) Open(address string) bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.hosts[address]
+	return ok && c.failures >= b.failures && time.Now().Before(c.openUntil)
+}
+
+// Reset closes the circuit of the server at address, forgetting its failures.
+func (b *CircuitBreaker) Reset(address string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	delete(b.hosts, address)
+	b.mu.Unlock()
+}
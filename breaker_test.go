@@ -0,0 +1,119 @@
+package bedrockping
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	b := NewCircuitBreaker(2, 50*time.Millisecond)
+	failed := errors.New("failed")
+
+	b.Record("a", failed)
+	if err := b.Allow("a"); err != nil {
+		t.Fatalf("expected the circuit to stay closed after 1 failure, got %v", err)
+	}
+	b.Record("a", context.Canceled)
+	b.Record("a", failed)
+	if err := b.Allow("a"); !errors.Is(err, ErrCircuitOpen) || !b.Open("a") {
+		t.Fatalf("expected the circuit to open after 2 failures, got %v", err)
+	}
+	if err := b.Allow("b"); err != nil {
+		t.Errorf("expected other servers to be allowed, got %v", err)
+	}
+
+	// After the cooldown a single trial is let through, failing reopens the circuit
+	time.Sleep(60 * time.Millisecond)
+	if err := b.Allow("a"); err != nil {
+		t.Fatalf("expected a trial after the cooldown, got %v", err)
+	}
+	if err := b.Allow("a"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected one trial at a time, got %v", err)
+	}
+	b.Record("a", failed)
+	if !b.Open("a") {
+		t.Error("expected the failed trial to reopen the circuit")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if err := b.Allow("a"); err != nil {
+		t.Fatal(err)
+	}
+	b.Record("a", nil)
+	if err := b.Allow("a"); err != nil || b.Open("a") {
+		t.Errorf("expected a successful trial to close the circuit, got %v", err)
+	}
+
+	b.Record("a", failed)
+	b.Record("a", failed)
+	b.Reset("a")
+	if err := b.Allow("a"); err != nil {
+		t.Errorf("expected Reset to close the circuit, got %v", err)
+	}
+
+	var nilBreaker *CircuitBreaker
+	nilBreaker.Record("a", failed)
+	if err := nilBreaker.Allow("a"); err != nil || nilBreaker.Open("a") {
+		t.Errorf("expected a nil breaker to allow everything, got %v", err)
+	}
+}
+
+func TestClientCircuitBreaker(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	dead := conn.LocalAddr().String()
+
+	client := NewClient(WithTimeout(20*time.Millisecond), WithCircuitBreaker(NewCircuitBreaker(2, time.Minute)))
+	for i := 0; i < 2; i++ {
+		if res := client.Ping(context.Background(), dead); !errors.Is(res.Err, context.DeadlineExceeded) {
+			t.Fatalf("expected a timeout, got %v", res.Err)
+		}
+	}
+	sent := client.Stats().PingsSent
+
+	start := time.Now()
+	res := client.Ping(context.Background(), dead)
+	if !errors.Is(res.Err, ErrCircuitOpen) || ErrorKind(res.Err) != ErrorKindCircuitOpen || time.Since(start) > 10*time.Millisecond {
+		t.Errorf("expected the circuit to be open, got %v after %v", res.Err, time.Since(start))
+	}
+	if res.Attempts != 1 || res.CheckedAt.Before(start) {
+		t.Errorf("expected the result to be stamped like any other, got %d attempts checked at %v", res.Attempts, res.CheckedAt)
+	}
+	if n := client.Stats().PingsSent; n != sent {
+		t.Errorf("expected no pings to be sent, got %d more", n-sent)
+	}
+}
+
+func TestMultiplexerQueryManyCircuitBreaker(t *testing.T) {
+	m, err := NewMultiplexer("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	dead, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dead.Close()
+	alive := startTestServer(t, testResponse("Alive"))
+	targets := []Target{{Host: alive}, {Host: dead.LocalAddr().String()}}
+
+	opts := BatchOptions{Timeout: 20 * time.Millisecond, Retries: 2, CircuitBreaker: NewCircuitBreaker(1, time.Minute)}
+	results := m.QueryMany(context.Background(), targets, opts)
+	if results[0].Err != nil || !errors.Is(results[1].Err, context.DeadlineExceeded) || results[1].Attempts != 3 {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+
+	// The dead server's retries count as one failure, which is enough to open its circuit
+	results = m.QueryMany(context.Background(), targets, opts)
+	if results[0].Err != nil || !errors.Is(results[1].Err, ErrCircuitOpen) || results[1].Attempts != 0 {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
@@ -0,0 +1,30 @@
+package bedrockping
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// bufferPool reuses the packet buffers of queries, so pinging many servers doesn't allocate new ones for each.
+// The zero value is ready to use.
+type bufferPool struct {
+	pool   sync.Pool
+	gets   atomic.Uint64
+	allocs atomic.Uint64
+}
+
+// get returns a buffer of maxPacketSize bytes from the pool, allocating one if none are free.
+func (p *bufferPool) get() *[]byte {
+	p.gets.Add(1)
+	if buf, ok := p.pool.Get().(*[]byte); ok {
+		return buf
+	}
+	p.allocs.Add(1)
+	buf := make([]byte, maxPacketSize)
+	return &buf
+}
+
+// put returns buf to the pool, it must not be used afterwards.
+func (p *bufferPool) put(buf *[]byte) {
+	p.pool.Put(buf)
+}
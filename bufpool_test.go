@@ -0,0 +1,38 @@
+package bedrockping
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBufferPool(t *testing.T) {
+	var p bufferPool
+	buf := p.get()
+	if len(*buf) != maxPacketSize {
+		t.Fatalf("expected a %d byte buffer, got %d", maxPacketSize, len(*buf))
+	}
+	p.put(buf)
+	p.put(p.get())
+	if gets, allocs := p.gets.Load(), p.allocs.Load(); gets != 2 || allocs < 1 || allocs > 2 {
+		t.Errorf("unexpected counts: %d gets, %d allocs", gets, allocs)
+	}
+}
+
+func TestClientBufferReuse(t *testing.T) {
+	address := startTestServer(t, testResponse("Server"))
+
+	c := NewClient(WithTimeout(time.Second))
+	const queries = 20
+	for range queries {
+		if res := c.Ping(context.Background(), address); res.Err != nil {
+			t.Fatal(res.Err)
+		}
+	}
+
+	// The reader of each query puts its buffer back asynchronously, so a few more may be allocated
+	stats := c.Stats()
+	if stats.BufferGets != 2*queries || stats.BufferAllocs >= stats.BufferGets/2 {
+		t.Errorf("expected buffers to be reused, got %+v", stats)
+	}
+}
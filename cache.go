@@ -0,0 +1,167 @@
+package bedrockping
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// DefaultCacheIdle is how long a Cache holds the result of a server that isn't asked for by default.
+const DefaultCacheIdle = 10 * time.Minute
+
+// Pinger pings servers, such as a Client or a function checking an access policy first.
+type Pinger interface {
+	Ping(ctx context.Context, address string) Result
+}
+
+// PingerFunc is a function used as a Pinger.
+type PingerFunc func(ctx context.Context, address string) Result
+
+func (f PingerFunc) Ping(ctx context.Context, address string) Result {
+	return f(ctx, address)
+}
+
+// CachedResult is a Result served by a Cache.
+type CachedResult struct {
+	// Result.CheckedAt is when the server was pinged.
+	Result
+	// Stale is set if the result is older than the TTL of the server, a fresh one is being fetched in the background.
+	Stale bool `json:"stale"`
+}
+
+// Cache wraps a Client, or any Pinger, holding the latest result of pinging each server for a TTL. Once a result
+// is older than its TTL it's still served immediately, marked Stale, while the server is pinged again in the
+// background, so callers never wait for a ping except the first for each server. Concurrent calls for a server
+// share the same ping. The results of servers that haven't been asked for in a while are evicted, so calls for
+// ever more servers don't use ever more memory. A Cache is safe for concurrent use.
+type Cache struct {
+	pinger Pinger
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	idle    time.Duration
+	swept   time.Time
+	ttls    map[string]time.Duration
+	entries map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	res CachedResult
+	// ready is closed once the first ping has finished
+	ready      chan struct{}
+	refreshing bool
+	// read is when the result was last asked for
+	read time.Time
+}
+
+// NewCache returns a Cache pinging servers with pinger, usually a Client, holding results for ttl.
+// A TTL of 0 pings the server again on every call, while still serving the previous result.
+func NewCache(pinger Pinger, ttl time.Duration) *Cache {
+	return &Cache{
+		pinger:  pinger,
+		ttl:     ttl,
+		idle:    DefaultCacheIdle,
+		swept:   time.Now(),
+		ttls:    make(map[string]time.Duration),
+		entries: make(map[string]*cacheEntry),
+	}
+}
+
+// SetIdle sets how long the result of a server that isn't asked for is held before it's evicted, the default
+// is DefaultCacheIdle. The next call for an evicted server waits for a ping again.
+func (c *Cache) SetIdle(idle time.Duration) {
+	c.mu.Lock()
+	c.idle = idle
+	c.mu.Unlock()
+}
+
+// SetTTL overrides the TTL of the server at address, such as to check important servers more often.
+func (c *Cache) SetTTL(address string, ttl time.Duration) {
+	c.mu.Lock()
+	c.ttls[address] = ttl
+	c.mu.Unlock()
+}
+
+// Forget removes the result of the server at address, so it's pinged again on the next call.
+func (c *Cache) Forget(address string) {
+	c.mu.Lock()
+	delete(c.entries, address)
+	c.mu.Unlock()
+}
+
+// Ping returns the latest result of pinging the server at address. The first call for a server waits for the
+// ping, unless ctx is done first. Pings carry on if ctx is cancelled, as other callers may be waiting for them.
+func (c *Cache) Ping(ctx context.Context, address string) CachedResult {
+	now := time.Now()
+	c.mu.Lock()
+	c.evict(now)
+	e, ok := c.entries[address]
+	if !ok {
+		e = &cacheEntry{ready: make(chan struct{}), refreshing: true}
+		c.entries[address] = e
+		go c.refresh(context.WithoutCancel(ctx), address, e)
+	}
+	e.read = now
+	c.mu.Unlock()
+
+	select {
+	case <-e.ready:
+	case <-ctx.Done():
+		return CachedResult{Result: Result{Address: address, Attempts: 1, CheckedAt: time.Now(), Err: ctx.Err()}}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	res := e.res
+	ttl, ok := c.ttls[address]
+	if !ok {
+		ttl = c.ttl
+	}
+	if time.Since(res.CheckedAt) >= ttl {
+		res.Stale = true
+		if !e.refreshing {
+			e.refreshing = true
+			go c.refresh(context.WithoutCancel(ctx), address, e)
+		}
+	}
+	return res
+}
+
+// evict removes the results that haven't been asked for within the idle time, at most every half of it so
+// calls don't each scan every result. c.mu must be held.
+func (c *Cache) evict(now time.Time) {
+	if now.Sub(c.swept) < c.idle/2 {
+		return
+	}
+	c.swept = now
+	for address, e := range c.entries {
+		if now.Sub(e.read) >= c.idle {
+			delete(c.entries, address)
+		}
+	}
+}
+
+// refresh pings the server at address, storing the result in e.
+func (c *Cache) refresh(ctx context.Context, address string, e *cacheEntry) {
+	checkedAt := time.Now()
+	res := c.pinger.Ping(ctx, address)
+
+	c.mu.Lock()
+	first := e.res.CheckedAt.IsZero()
+	res.CheckedAt = checkedAt
+	e.res = CachedResult{Result: res}
+	e.refreshing = false
+	c.mu.Unlock()
+	if first {
+		close(e.ready)
+	}
+}
+
+// MarshalJSON encodes r as a bedrockping.ResultJSON with a "stale" field.
+func (r CachedResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ResultJSON
+		Stale bool `json:"stale"`
+	}{r.Result.JSON(), r.Stale})
+}
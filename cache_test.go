@@ -0,0 +1,137 @@
+package bedrockping
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// startResponder starts a Responder on localhost, returning it and its address.
+func startResponder(t *testing.T, resp Response) (*Responder, string) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	r := NewResponder(resp)
+	go r.Serve(conn)
+	return r, conn.LocalAddr().String()
+}
+
+func TestCache(t *testing.T) {
+	r, address := startResponder(t, testResponse("First"))
+	client := NewClient(WithTimeout(time.Second))
+	cache := NewCache(client, 50*time.Millisecond)
+	ctx := context.Background()
+
+	// Concurrent calls for a server that isn't cached share one ping
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if res := cache.Ping(ctx, address); res.Err != nil || res.Stale || res.Response.ServerName != "First" {
+				t.Errorf("unexpected result: %+v", res)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := client.Stats().PongsReceived; n != 1 {
+		t.Errorf("expected 1 ping, got %d", n)
+	}
+
+	r.SetResponse(testResponse("Second"))
+	time.Sleep(60 * time.Millisecond)
+	res := cache.Ping(ctx, address)
+	if !res.Stale || res.Response.ServerName != "First" || time.Since(res.CheckedAt) < 50*time.Millisecond {
+		t.Errorf("expected the stale result straight away, got %+v", res)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		res = cache.Ping(ctx, address)
+		if !res.Stale {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the result wasn't refreshed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if res.Response.ServerName != "Second" {
+		t.Errorf("expected the refreshed result, got %+v", res)
+	}
+	if n := client.Stats().PongsReceived; n != 2 {
+		t.Errorf("expected 2 pings, got %d", n)
+	}
+
+	cache.SetTTL(address, time.Hour)
+	cache.Forget(address)
+	if res := cache.Ping(ctx, address); res.Stale || time.Since(res.CheckedAt) > time.Second {
+		t.Errorf("expected a fresh result after forgetting, got %+v", res)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if res := cache.Ping(ctx, address); res.Stale {
+		t.Error("expected the TTL of the server to override the default")
+	}
+}
+
+func TestCacheCancel(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	address := conn.LocalAddr().String()
+
+	cache := NewCache(NewClient(WithTimeout(200*time.Millisecond)), time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if res := cache.Ping(ctx, address); !errors.Is(res.Err, context.DeadlineExceeded) || time.Since(start) > 100*time.Millisecond {
+		t.Errorf("expected the call to stop waiting when cancelled, got %v after %v", res.Err, time.Since(start))
+	} else if res.Attempts != 1 || res.CheckedAt.IsZero() {
+		t.Errorf("expected the result to record an attempt and when it was checked, got %d attempts at %v", res.Attempts, res.CheckedAt)
+	}
+
+	// The ping carried on, so its result is cached
+	res := cache.Ping(context.Background(), address)
+	if res.Err == nil || res.CheckedAt.IsZero() || res.Stale {
+		t.Errorf("expected the cached failure, got %+v", res)
+	}
+}
+
+func TestCacheEvict(t *testing.T) {
+	var mu sync.Mutex
+	pings := make(map[string]int)
+	cache := NewCache(PingerFunc(func(ctx context.Context, address string) Result {
+		mu.Lock()
+		pings[address]++
+		mu.Unlock()
+		return Result{Address: address}
+	}), time.Minute)
+	cache.SetIdle(20 * time.Millisecond)
+	ctx := context.Background()
+
+	cache.Ping(ctx, "a:19132")
+	cache.Ping(ctx, "a:19132")
+	time.Sleep(30 * time.Millisecond)
+	// Asking for another server evicts the one that hasn't been asked for, so it's pinged again
+	cache.Ping(ctx, "b:19132")
+	cache.Ping(ctx, "a:19132")
+
+	mu.Lock()
+	defer mu.Unlock()
+	if pings["a:19132"] != 2 || pings["b:19132"] != 1 {
+		t.Errorf("unexpected pings: %v", pings)
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if len(cache.entries) != 2 {
+		t.Errorf("expected 2 results held, have %d", len(cache.entries))
+	}
+}
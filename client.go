@@ -0,0 +1,560 @@
+package bedrockping
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultClientTimeout = 5 * time.Second
+	defaultClientResend  = time.Second
+	defaultMinResend     = 50 * time.Millisecond
+	defaultMaxResend     = 3 * time.Second
+)
+
+// ErrInvalidResponse is returned, wrapped with the reason and the context's error, when a query ends having
+// only received packets that aren't valid pongs, such as from something other than a Bedrock server.
+var ErrInvalidResponse = errors.New("bedrockping: invalid response")
+
+// ErrPortClosed is returned, wrapping the socket's error, when the host of a server answers a ping with an ICMP
+// port unreachable message, as nothing is listening on the port. Queries fail with it as soon as the message
+// arrives rather than waiting for the timeout, the same on every system, although Windows reports the message
+// as WSAECONNRESET rather than ECONNREFUSED. Some hosts and firewalls drop pings silently instead.
+var ErrPortClosed = errors.New("bedrockping: port closed")
+
+// Client queries servers via the Minecraft Bedrock protocol.
+// It tracks a rolling round trip time estimate per address and derives the interval that pings
+// are resent at from it, like TCP's retransmission timeout, rather than using a fixed interval
+// that's too aggressive for distant servers and too slow for nearby ones.
+// A Client is safe for concurrent use and should be reused.
+type Client struct {
+	timeout   time.Duration
+	resend    time.Duration
+	minResend time.Duration
+	maxResend time.Duration
+	adaptive  bool
+
+	network   string
+	localAddr net.IP
+	localPort int
+	reusePort bool
+	iface     string
+	dscp      uint8
+	proxy     *url.URL
+
+	// sourcePortPolicy is the policy of WithSourcePortPolicy, and pinnedPort the port it pinned
+	sourcePortPolicy SourcePortPolicy
+	pinnedPort       atomic.Int32
+
+	resolver       Resolver
+	srvService     string
+	happyEyeballs  bool
+	failover       bool
+	dnsCacheTTL    time.Duration
+	dnsCacheMinTTL time.Duration
+
+	epoch   time.Time
+	tracer  trace.Tracer
+	metrics *clientMetrics
+	logger  *slog.Logger
+
+	mu  sync.Mutex
+	rtt map[string]*rttEstimator
+	// rttSwept is the number of estimators after idle ones were last removed
+	rttSwept int
+
+	breaker      *CircuitBreaker
+	enrichers    []Enricher
+	singleflight bool
+	flightsMu    sync.Mutex
+	flights      map[string]*flight
+
+	pingsSent     atomic.Uint64
+	pongsReceived atomic.Uint64
+	timeouts      atomic.Uint64
+	parseErrors   atomic.Uint64
+	sharedPings   atomic.Uint64
+
+	buffers bufferPool
+}
+
+// ClientStats are the counters of a Client since it was created.
+type ClientStats struct {
+	// PingsSent counts every ping packet, including resends.
+	PingsSent uint64 `json:"pingsSent"`
+	// PongsReceived counts valid pongs.
+	PongsReceived uint64 `json:"pongsReceived"`
+	// Timeouts counts queries that timed out without a response.
+	Timeouts uint64 `json:"timeouts"`
+	// ParseErrors counts packets received that weren't valid pongs.
+	ParseErrors uint64 `json:"parseErrors"`
+	// SharedPings counts Ping calls answered by another call's query, see WithSingleflight.
+	SharedPings uint64 `json:"sharedPings"`
+	// BufferGets counts the packet buffers queries took from the client's pool, two per query.
+	BufferGets uint64 `json:"bufferGets"`
+	// BufferAllocs counts the BufferGets that allocated a new buffer as none were free. It grows with the
+	// number of queries in flight at once, if it keeps growing with a steady load the buffers aren't reused.
+	BufferAllocs uint64 `json:"bufferAllocs"`
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithTimeout sets the maximum time a query may take, the default is 5 seconds.
+// A deadline on the context passed to a query also applies.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithResend sets the interval that the ping packet is resent in case there is packet loss,
+// used for addresses without a round trip time estimate yet. The default is 1 second.
+func WithResend(resend time.Duration) Option {
+	return func(c *Client) {
+		c.resend = resend
+	}
+}
+
+// WithResendBounds clamps the adaptive resend interval, the defaults are 50ms and 3s.
+func WithResendBounds(min, max time.Duration) Option {
+	return func(c *Client) {
+		c.minResend = min
+		c.maxResend = max
+	}
+}
+
+// WithAdaptiveResend enables or disables deriving the resend interval from observed round trip times.
+// When disabled the interval set by WithResend is always used. It is enabled by default.
+func WithAdaptiveResend(enabled bool) Option {
+	return func(c *Client) {
+		c.adaptive = enabled
+	}
+}
+
+// NewClient creates a Client configured by opts.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		timeout:   defaultClientTimeout,
+		resend:    defaultClientResend,
+		minResend: defaultMinResend,
+		maxResend: defaultMaxResend,
+		adaptive:  true,
+		network:   "udp",
+		resolver:  net.DefaultResolver,
+		epoch:     time.Now(),
+		tracer:    defaultTracer,
+		metrics:   defaultMetrics,
+		rtt:       make(map[string]*rttEstimator),
+		flights:   make(map[string]*flight),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.dnsCacheTTL > 0 {
+		c.resolver = newCachingResolver(c.resolver, c.dnsCacheMinTTL, c.dnsCacheTTL, c.timeout)
+	}
+	return c
+}
+
+// Query queries address and returns the Response from the server, see Ping.
+func (c *Client) Query(ctx context.Context, address string) (Response, error) {
+	res := c.Ping(ctx, address)
+	return res.Response, res.Err
+}
+
+// Ping queries address and returns a Result describing the outcome.
+// Latency is the round trip time of the ping that was answered.
+// The address is parsed with ParseAddress, so DefaultPort is used if it has no port.
+func (c *Client) Ping(ctx context.Context, address string) Result {
+	parsed, err := ParseAddress(address)
+	if err != nil {
+		return Result{Address: address, Attempts: 1, CheckedAt: time.Now(), Err: err}
+	}
+	address = parsed
+	if c.singleflight {
+		return c.sharedPing(ctx, address)
+	}
+	return c.tracedPing(ctx, address)
+}
+
+// tracedPing queries address unless its circuit is open, recording a span and metrics for the query.
+func (c *Client) tracedPing(ctx context.Context, address string) Result {
+	if err := c.breaker.Allow(address); err != nil {
+		c.debug(ctx, "circuit open", "address", address)
+		return Result{Address: address, Attempts: 1, CheckedAt: time.Now(), Err: err}
+	}
+
+	ctx, span := c.tracer.Start(ctx, "bedrockping.Ping", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("server.address", address)))
+	res := c.ping(ctx, address)
+	endPingSpan(span, res)
+	c.metrics.record(ctx, res)
+	c.breaker.Record(address, res.Err)
+	if err := Enrich(ctx, &res, c.enrichers...); err != nil {
+		c.debug(ctx, "enrichment failed", "address", address, "error", err)
+	}
+	return res
+}
+
+func (c *Client) ping(ctx context.Context, address string) Result {
+	res := Result{Address: address, Attempts: 1, CheckedAt: time.Now()}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	candidates, race, err := c.resolveCandidates(ctx, address)
+	if err != nil {
+		c.debug(ctx, "resolving failed", "address", address, "error", err)
+		res.Err = err
+		return res
+	}
+	switch {
+	case race:
+		res = c.race(ctx, res, candidates)
+	case len(candidates) > 1:
+		res = c.failOver(ctx, res, candidates)
+	default:
+		res.Resolved = candidates[0]
+		c.debug(ctx, "resolved", "address", address, "resolved", res.Resolved)
+		res = c.pingResolved(ctx, res)
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("network.peer.address", res.Resolved))
+	return res
+}
+
+// pingResolved queries res.Address by sending pings to res.Resolved.
+func (c *Client) pingResolved(ctx context.Context, res Result) Result {
+	address, resolved := res.Address, res.Resolved
+	_, dialSpan := c.tracer.Start(ctx, "bedrockping.dial")
+	conn, err := c.dial(ctx, resolved)
+	endSpan(dialSpan, err)
+	if err != nil {
+		c.debug(ctx, "dialing failed", "address", address, "error", err)
+		res.Err = err
+		return res
+	}
+	defer conn.Close()
+
+	pongs := make(chan Response, 1)
+	errs := make(chan error, 1)
+	// invalid holds why the latest packet that wasn't a valid pong was rejected
+	invalid := make(chan error, 1)
+	go func() {
+		// The buffer is only put back once reading stops, which may be after the query returns
+		pooled := c.buffers.get()
+		defer c.buffers.put(pooled)
+		buf := *pooled
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				errs <- portClosed(err)
+				return
+			}
+			c.logPacket(ctx, "received packet", address, buf[:n])
+
+			var resp Response
+			if err := DecodeUnconnectedPong(buf[:n], &resp); err != nil {
+				// Ignore anything that isn't a valid pong, but remember why in case nothing else arrives
+				c.debug(ctx, "ignoring invalid packet", "address", address, "error", err)
+				c.parseErrors.Add(1)
+				select {
+				case <-invalid:
+				default:
+				}
+				invalid <- err
+				continue
+			}
+			c.pongsReceived.Add(1)
+			pongs <- resp
+			return
+		}
+	}()
+
+	resend := c.ResendInterval(address)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	var first time.Time
+	var sent []uint64
+	pooled := c.buffers.get()
+	defer c.buffers.put(pooled)
+
+	// The receive span covers waiting for the pong from the first ping until the query ends
+	var receiveSpan trace.Span
+	defer func() {
+		if receiveSpan != nil {
+			endSpan(receiveSpan, res.Err)
+		}
+	}()
+	for {
+		select {
+		case <-timer.C:
+			timestamp := c.timestamp(time.Now())
+			if first.IsZero() {
+				first = c.sentAt(timestamp)
+			}
+			sent = append(sent, timestamp)
+			res.Sent = len(sent)
+
+			ping := AppendUnconnectedPing((*pooled)[:0], timestamp)
+			if len(sent) == 1 {
+				c.debug(ctx, "sending ping", "address", address, "resend", resend)
+			} else {
+				c.debug(ctx, "resending ping", "address", address, "attempt", len(sent), "resend", resend)
+			}
+			c.logPacket(ctx, "sent packet", address, ping)
+			_, sendSpan := c.tracer.Start(ctx, "bedrockping.send", trace.WithAttributes(attribute.Int("bedrockping.attempt", len(sent))))
+			_, err := conn.Write(ping)
+			endSpan(sendSpan, err)
+			if err != nil {
+				// An ICMP message received for an earlier ping may be reported when sending
+				res.Err = portClosed(err)
+				return res
+			}
+			c.pingsSent.Add(1)
+			if receiveSpan == nil {
+				_, receiveSpan = c.tracer.Start(ctx, "bedrockping.receive")
+			}
+
+			timer.Reset(resend)
+			// Back off in case the server is further away than expected
+			if c.adaptive {
+				if resend *= 2; resend > c.maxResend {
+					resend = c.maxResend
+				}
+			}
+		case res.Response = <-pongs:
+			// Pings sent after the one answered were still in flight, so aren't counted
+			for i, timestamp := range sent {
+				if timestamp == res.Response.Timestamp {
+					res.Sent = i + 1
+					break
+				}
+			}
+
+			// The echoed timestamp identifies which ping was answered, if the server
+			// doesn't echo it fall back to the time since the first ping
+			rtt := time.Since(c.sentAt(res.Response.Timestamp))
+			if elapsed := time.Since(first); rtt <= 0 || rtt > elapsed {
+				res.Latency = elapsed
+			} else {
+				res.Latency = rtt
+				c.observe(address, rtt)
+			}
+			c.debug(ctx, "received pong", "address", address, "sent", res.Sent, "latency", res.Latency)
+			return res
+		case res.Err = <-errs:
+			c.debug(ctx, "reading failed", "address", address, "error", res.Err)
+			return res
+		case <-ctx.Done():
+			res.Err = ctx.Err()
+			if errors.Is(res.Err, context.DeadlineExceeded) {
+				c.timeouts.Add(1)
+			}
+			select {
+			case err := <-invalid:
+				res.Err = fmt.Errorf("%w: %w (%w)", ErrInvalidResponse, err, res.Err)
+			default:
+			}
+			c.debug(ctx, "query ended without a pong", "address", address, "sent", len(sent), "error", res.Err)
+			return res
+		}
+	}
+}
+
+// portClosed returns err wrapped with ErrPortClosed if it reports an ICMP port unreachable message,
+// ECONNREFUSED on most systems and WSAECONNRESET on Windows.
+func portClosed(err error) error {
+	if isPortUnreachable(err) && !errors.Is(err, ErrPortClosed) {
+		return fmt.Errorf("%w: %w", ErrPortClosed, err)
+	}
+	return err
+}
+
+// timestamp returns the value sent in the timestamp field of a ping, which servers echo in their pong.
+func (c *Client) timestamp(t time.Time) uint64 {
+	return uint64(t.Sub(c.epoch) / time.Microsecond)
+}
+
+// sentAt returns the time a ping with timestamp was sent.
+func (c *Client) sentAt(timestamp uint64) time.Time {
+	return c.epoch.Add(time.Duration(timestamp) * time.Microsecond)
+}
+
+// Resolve returns the IP address and port that Ping would send pings for address to: the host resolved to an
+// IP address of the client's address family, the first one the resolver returns if it has several, or the
+// address of its SRV record if enabled with WithSRV. Addresses with an IP address are returned as is, except
+// for link-local IPv6 addresses without a zone, which are given the zone of the interface set by WithInterface.
+func (c *Client) Resolve(ctx context.Context, address string) (string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", err
+	}
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if !c.allows(ip) {
+			return "", &net.AddrError{Err: "address family not allowed by " + c.network, Addr: host}
+		}
+		if zoned := c.zoned(ip); zoned != ip {
+			return c.joinHostPort(zoned, port), nil
+		}
+		return address, nil
+	}
+
+	if c.srvService != "" && port == strconv.Itoa(DefaultPort) {
+		if resolved, ok := c.resolveSRV(ctx, host); ok {
+			return resolved, nil
+		}
+	}
+	ip, err := c.lookupIP(ctx, host)
+	if err != nil {
+		return "", err
+	}
+	return c.joinHostPort(ip, port), nil
+}
+
+// lookupIP returns the first IP address of the client's address family that host resolves to.
+func (c *Client) lookupIP(ctx context.Context, host string) (netip.Addr, error) {
+	addrs, err := c.lookupIPs(ctx, host)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return addrs[0], nil
+}
+
+// lookupIPs returns every IP address of the client's address family that host resolves to, in the order the
+// resolver returned them, failing if there are none.
+func (c *Client) lookupIPs(ctx context.Context, host string) ([]netip.Addr, error) {
+	ctx, span := c.tracer.Start(ctx, "bedrockping.resolve", trace.WithAttributes(attribute.String("dns.question.name", host)))
+	addrs, err := c.resolver.LookupNetIP(ctx, "ip"+strings.TrimPrefix(c.network, "udp"), host)
+	if err == nil && len(addrs) == 0 {
+		err = &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	endSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	for i, addr := range addrs {
+		addrs[i] = addr.Unmap()
+	}
+	return addrs, nil
+}
+
+// allows reports whether ip is of an address family the client pings.
+func (c *Client) allows(ip netip.Addr) bool {
+	switch c.network {
+	case "udp4":
+		return ip.Unmap().Is4()
+	case "udp6":
+		return ip.Is6() && !ip.Is4In6()
+	}
+	return true
+}
+
+// observe records a round trip time sample for address.
+func (c *Client) observe(address string, rtt time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	e, ok := c.estimator(address, now)
+	if !ok {
+		e = new(rttEstimator)
+		c.rtt[address] = e
+	}
+	e.sample(rtt, now)
+
+	// Remove idle estimators once there are twice as many as after the last sweep,
+	// so addresses no longer pinged don't pile up
+	if len(c.rtt) >= 2*max(c.rttSwept, 64) {
+		for address, e := range c.rtt {
+			if now.Sub(e.last) >= rttIdle {
+				delete(c.rtt, address)
+			}
+		}
+		c.rttSwept = len(c.rtt)
+	}
+}
+
+// estimator returns the estimator for address, unless it hasn't had a sample for rttIdle. c.mu must be held.
+func (c *Client) estimator(address string, now time.Time) (*rttEstimator, bool) {
+	e, ok := c.rtt[address]
+	if !ok || now.Sub(e.last) >= rttIdle {
+		return nil, false
+	}
+	return e, true
+}
+
+// RTT returns the smoothed round trip time observed for address, or false if it has never responded.
+func (c *Client) RTT(address string) (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.estimator(address, time.Now())
+	if !ok {
+		return 0, false
+	}
+	return e.srtt, true
+}
+
+// ResendInterval returns the interval pings to address are initially resent at.
+func (c *Client) ResendInterval(address string) time.Duration {
+	if !c.adaptive {
+		return c.resend
+	}
+
+	c.mu.Lock()
+	e, ok := c.estimator(address, time.Now())
+	var rto time.Duration
+	if ok {
+		rto = e.rto
+	}
+	c.mu.Unlock()
+	if !ok {
+		return c.resend
+	}
+
+	if rto < c.minResend {
+		return c.minResend
+	}
+	if rto > c.maxResend {
+		return c.maxResend
+	}
+	return rto
+}
+
+// Stats returns a snapshot of the counters of c.
+func (c *Client) Stats() ClientStats {
+	return ClientStats{
+		PingsSent:     c.pingsSent.Load(),
+		PongsReceived: c.pongsReceived.Load(),
+		Timeouts:      c.timeouts.Load(),
+		ParseErrors:   c.parseErrors.Load(),
+		SharedPings:   c.sharedPings.Load(),
+		BufferGets:    c.buffers.gets.Load(),
+		BufferAllocs:  c.buffers.allocs.Load(),
+	}
+}
+
+// Var returns an expvar.Var reporting the Stats of c, so it can be published with expvar.Publish:
+//
+//	expvar.Publish("bedrockping", client.Var())
+func (c *Client) Var() expvar.Var {
+	return expvar.Func(func() any {
+		return c.Stats()
+	})
+}
@@ -0,0 +1,231 @@
+package bedrockping
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/netip"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestClientQuery(t *testing.T) {
+	expect := testResponse("Server")
+	address := startTestServer(t, expect)
+
+	c := NewClient(WithTimeout(2 * time.Second))
+	resp, err := c.Query(context.Background(), address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The server echoes the timestamp of the ping it answered
+	expect.Timestamp = resp.Timestamp
+	if !reflect.DeepEqual(expect, resp) {
+		t.Errorf("incorrect resp: %v", resp)
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	// Nothing reads from this socket so pings are never answered
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	c := NewClient(WithTimeout(100*time.Millisecond), WithResend(20*time.Millisecond))
+	res := c.Ping(context.Background(), conn.LocalAddr().String())
+	if res.Err != context.DeadlineExceeded {
+		t.Errorf("expected deadline exceeded, got: %v", res.Err)
+	}
+	if res.Sent < 2 {
+		t.Errorf("expected pings to be resent, sent %d", res.Sent)
+	}
+}
+
+func TestClientPortClosed(t *testing.T) {
+	// Nothing listens on the port once the socket is closed, so the host answers with port unreachable
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := conn.LocalAddr().String()
+	conn.Close()
+
+	c := NewClient(WithTimeout(5 * time.Second))
+	start := time.Now()
+	res := c.Ping(context.Background(), address)
+	if !errors.Is(res.Err, ErrPortClosed) || !errors.Is(res.Err, syscall.ECONNREFUSED) {
+		t.Fatalf("expected port closed, got %v", res.Err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the query to fail without waiting for the timeout, took %s", elapsed)
+	}
+	if kind := ErrorKind(res.Err); kind != ErrorKindRefused {
+		t.Errorf("expected the refused kind, got %s", kind)
+	}
+
+	if _, err := Query(address, time.Second, 100*time.Millisecond); !errors.Is(err, ErrPortClosed) {
+		t.Errorf("expected Query to fail with port closed, got %v", err)
+	}
+}
+
+func TestClientResolve(t *testing.T) {
+	c := NewClient()
+	for address, expect := range map[string]string{
+		"127.0.0.1:19132": "127.0.0.1:19132",
+		"[::1]:19132":     "[::1]:19132",
+	} {
+		if got, err := c.Resolve(context.Background(), address); err != nil || got != expect {
+			t.Errorf("Resolve(%s) = %s, %v", address, got, err)
+		}
+	}
+
+	// localhost may resolve to either family first, so restrict it
+	c4 := NewClient(WithNetwork("udp4"))
+	if got, err := c4.Resolve(context.Background(), "localhost:19133"); err != nil || got != "127.0.0.1:19133" {
+		t.Errorf("Resolve(localhost:19133) = %s, %v", got, err)
+	}
+	if _, err := c4.Resolve(context.Background(), "[::1]:19132"); err == nil {
+		t.Error("expected error for an IPv6 address with udp4")
+	}
+	if _, err := c.Resolve(context.Background(), "localhost"); err == nil {
+		t.Error("expected error for a missing port")
+	}
+}
+
+func TestClientInvalidResponse(t *testing.T) {
+	// Answer every ping with a packet that isn't a pong
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			_, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			conn.WriteTo([]byte("HTTP/1.1 400 Bad Request\r\n"), addr)
+		}
+	}()
+
+	c := NewClient(WithTimeout(100*time.Millisecond), WithResend(20*time.Millisecond))
+	res := c.Ping(context.Background(), conn.LocalAddr().String())
+	if !errors.Is(res.Err, ErrInvalidResponse) || !errors.Is(res.Err, context.DeadlineExceeded) {
+		t.Fatalf("expected an invalid response, got: %v", res.Err)
+	}
+	if expect := "bedrockping: invalid response: unexpected packet id: 72 (context deadline exceeded)"; res.Err.Error() != expect {
+		t.Errorf("got %q", res.Err)
+	}
+}
+
+func TestClientAdaptiveResend(t *testing.T) {
+	address := startTestServer(t, testResponse("Echo"))
+
+	c := NewClient(WithResend(time.Second), WithResendBounds(10*time.Millisecond, 2*time.Second))
+	if resend := c.ResendInterval(address); resend != time.Second {
+		t.Errorf("expected initial resend of 1s, got %s", resend)
+	}
+	if _, ok := c.RTT(address); ok {
+		t.Error("expected no rtt before querying")
+	}
+
+	res := c.Ping(context.Background(), address)
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if res.Latency <= 0 {
+		t.Errorf("expected positive latency, got %s", res.Latency)
+	}
+	if res.Sent != 1 {
+		t.Errorf("expected the first ping to be answered, sent %d", res.Sent)
+	}
+
+	rtt, ok := c.RTT(address)
+	if !ok || rtt != res.Latency {
+		t.Errorf("expected rtt of %s, got %s", res.Latency, rtt)
+	}
+	// A local server responds far quicker than the lower bound
+	if resend := c.ResendInterval(address); resend != 10*time.Millisecond {
+		t.Errorf("expected resend at lower bound, got %s", resend)
+	}
+}
+
+func TestRTTEstimator(t *testing.T) {
+	var e rttEstimator
+
+	e.sample(100*time.Millisecond, time.Now())
+	if e.srtt != 100*time.Millisecond || e.rttvar != 50*time.Millisecond || e.rto != 300*time.Millisecond {
+		t.Errorf("unexpected first estimate: %+v", e)
+	}
+
+	for i := 0; i < 100; i++ {
+		e.sample(100*time.Millisecond, time.Now())
+	}
+	// The variation decays once the RTT is stable
+	if e.srtt != 100*time.Millisecond || e.rto > 105*time.Millisecond {
+		t.Errorf("unexpected stable estimate: %+v", e)
+	}
+}
+
+func TestClientRTTIdle(t *testing.T) {
+	c := NewClient()
+	c.observe("idle.example.com:19132", 100*time.Millisecond)
+	c.mu.Lock()
+	c.rtt["idle.example.com:19132"].last = time.Now().Add(-rttIdle)
+	c.mu.Unlock()
+
+	// Idle estimates aren't used
+	if _, ok := c.RTT("idle.example.com:19132"); ok {
+		t.Error("expected no rtt for an idle address")
+	}
+	if resend := c.ResendInterval("idle.example.com:19132"); resend != defaultClientResend {
+		t.Errorf("expected the default resend for an idle address, got %s", resend)
+	}
+
+	// And are removed once enough other addresses have been pinged
+	for i := range 128 {
+		c.observe(netip.AddrPortFrom(netip.AddrFrom4([4]byte{10, 0, 0, byte(i)}), 19132).String(), time.Millisecond)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.rtt["idle.example.com:19132"]; ok || len(c.rtt) != 128 {
+		t.Errorf("expected the idle estimator to be removed, got %d estimators", len(c.rtt))
+	}
+}
+
+func TestClientStats(t *testing.T) {
+	address := startTestServer(t, testResponse("Server"))
+
+	// Nothing reads from this socket so pings are never answered
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	c := NewClient(WithTimeout(100*time.Millisecond), WithResend(20*time.Millisecond), WithAdaptiveResend(false))
+	if res := c.Ping(context.Background(), address); res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	res := c.Ping(context.Background(), conn.LocalAddr().String())
+
+	stats := c.Stats()
+	if stats.PongsReceived != 1 || stats.Timeouts != 1 || stats.PingsSent != uint64(1+res.Sent) {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	var decoded ClientStats
+	if err := json.Unmarshal([]byte(c.Var().String()), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != stats {
+		t.Errorf("unexpected var: %s", c.Var())
+	}
+}
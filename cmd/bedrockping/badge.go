@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/badge"
+)
+
+// runBadge runs the badge subcommand, which pings a server and writes a shields.io-style SVG badge of its status.
+func runBadge(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bedrockping badge", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: bedrockping badge [flags] host[:port]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Flags:")
+		fs.PrintDefaults()
+	}
+	output := fs.String("o", "-", "write the badge to `file`, - for stdout")
+	label := fs.String("label", badge.DefaultLabel, "`text` on the left of the badge")
+	onlineColor := fs.String("online-color", badge.DefaultOnlineColor, "`color` of the status when online, a name like green or a hex color")
+	offlineColor := fs.String("offline-color", badge.DefaultOfflineColor, "`color` of the status when offline")
+	timeout := fs.Duration("timeout", 5*time.Second, "maximum time to wait for a response")
+	transport := addTransportFlags(fs)
+	logs := addLogFlags(fs)
+
+	if err := parseArgs(fs, args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if err := logs.check(); err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return exitUsage
+	}
+	stderr = logs.stderr(stderr)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+	for _, color := range []string{*onlineColor, *offlineColor} {
+		if !badge.ValidColor(color) {
+			fmt.Fprintf(stderr, "bedrockping: unknown color %q\n", color)
+			return exitUsage
+		}
+	}
+
+	opts, err := transport.options()
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return exitUsage
+	}
+	opts = append(opts, logs.options(stderr)...)
+	client := bedrockping.NewClient(append([]bedrockping.Option{bedrockping.WithTimeout(*timeout)}, opts...)...)
+	res := client.Ping(ctx, bedrockping.Target{Host: fs.Arg(0)}.Address())
+
+	b := badge.ForResult(res)
+	b.Label = *label
+	b.Color = *onlineColor
+	if res.Err != nil {
+		b.Color = *offlineColor
+		fmt.Fprintf(stderr, "bedrockping: %s: %v\n", res.Address, res.Err)
+	}
+
+	if *output == "-" {
+		err = b.WriteSVG(stdout)
+	} else {
+		err = writeBadge(*output, b)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return exitFailure
+	}
+	// The badge is written either way, the exit status says whether the server responded
+	return exitCode(res.Err)
+}
+
+// writeBadge writes b to the file at path, replacing it at once so a web server never serves a partial badge.
+func writeBadge(path string, b badge.Badge) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := b.WriteSVG(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	// Temporary files are only readable by the owner, badges are meant to be served
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+func TestRunBadge(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+
+	code, stdout, stderr := runCommand(t, "badge", "--label", "My Server", address)
+	if code != exitOK {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	if !strings.HasPrefix(stdout, "<svg") || !strings.Contains(stdout, "<title>My Server: 3/20 players</title>") || !strings.Contains(stdout, `fill="#4c1"`) {
+		t.Errorf("unexpected badge:\n%s", stdout)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "status.svg")
+	code, _, _ = runCommand(t, "badge", "-o", path, "--offline-color", "#333", "--timeout", "50ms", pingtest.DeadAddress(t))
+	if code != exitTimeout {
+		t.Errorf("exit code %d for an offline server", code)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "<title>bedrock: offline</title>") || !strings.Contains(string(data), `fill="#333"`) {
+		t.Errorf("unexpected badge:\n%s", data)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0644 {
+		t.Errorf("unexpected file: %v, %v", info.Mode(), err)
+	}
+	// Only the badge is left in the directory
+	if files, _ := os.ReadDir(dir); len(files) != 1 {
+		t.Errorf("expected only the badge, got %d files", len(files))
+	}
+}
+
+func TestRunBadgeUsage(t *testing.T) {
+	for _, args := range [][]string{
+		{"badge"},
+		{"badge", "a", "b"},
+		{"badge", "--online-color", "purple", "localhost"},
+	} {
+		if code, _, _ := runCommand(t, args...); code != exitUsage {
+			t.Errorf("%q: exit code %d", args, code)
+		}
+	}
+}
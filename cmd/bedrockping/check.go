@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// Nagios plugin exit codes.
+const (
+	checkOK       = 0
+	checkWarning  = 1
+	checkCritical = 2
+	checkUnknown  = 3
+)
+
+var checkStatus = [...]string{"OK", "WARNING", "CRITICAL", "UNKNOWN"}
+
+// checkThresholds are the limits of a check, zero values aren't checked.
+type checkThresholds struct {
+	warnLatency, critLatency         time.Duration
+	warnPlayersFree, critPlayersFree int
+}
+
+// check pings address once and prints the outcome in the format of a Nagios plugin,
+// a status line with performance data, returning the plugin exit code.
+func check(ctx context.Context, client *bedrockping.Client, address string, t checkThresholds, stdout io.Writer) int {
+	res := client.Ping(ctx, address)
+	if res.Err != nil {
+		fmt.Fprintf(stdout, "BEDROCK CRITICAL - %s: %v\n", address, res.Err)
+		return checkCritical
+	}
+
+	resp := res.Response
+	free := resp.MaxPlayers - resp.PlayerCount
+
+	code := checkOK
+	var problems []string
+	raise := func(level int, problem string) {
+		if level > code {
+			code = level
+		}
+		problems = append(problems, problem)
+	}
+	switch {
+	case t.critLatency > 0 && res.Latency >= t.critLatency:
+		raise(checkCritical, fmt.Sprintf("latency %s >= %s", formatLatency(res.Latency), t.critLatency))
+	case t.warnLatency > 0 && res.Latency >= t.warnLatency:
+		raise(checkWarning, fmt.Sprintf("latency %s >= %s", formatLatency(res.Latency), t.warnLatency))
+	}
+	switch {
+	case t.critPlayersFree > 0 && free <= t.critPlayersFree:
+		raise(checkCritical, fmt.Sprintf("%d player slots free <= %d", free, t.critPlayersFree))
+	case t.warnPlayersFree > 0 && free <= t.warnPlayersFree:
+		raise(checkWarning, fmt.Sprintf("%d player slots free <= %d", free, t.warnPlayersFree))
+	}
+
+	summary := fmt.Sprintf("%s %d/%d players, latency %s", bedrockping.StripFormatting(resp.ServerName),
+		resp.PlayerCount, resp.MaxPlayers, formatLatency(res.Latency))
+	if len(problems) > 0 {
+		summary += " (" + strings.Join(problems, ", ") + ")"
+	}
+
+	fmt.Fprintf(stdout, "BEDROCK %s - %s | latency=%ss;%s;%s;0 players=%d;;;0;%d players_free=%d;%s;%s;0;%d\n",
+		checkStatus[code], summary,
+		seconds(res.Latency), seconds(t.warnLatency), seconds(t.critLatency),
+		resp.PlayerCount, resp.MaxPlayers,
+		free, threshold(t.warnPlayersFree), threshold(t.critPlayersFree), resp.MaxPlayers)
+	return code
+}
+
+// seconds formats d in seconds for performance data, or empty if it's zero.
+func seconds(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.6f", d.Seconds())
+}
+
+// threshold formats a players free threshold for performance data. The check is triggered at or
+// below n, which is the range "n+1:" in the plugin guidelines, or empty if it isn't set.
+func threshold(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d:", n+1)
+}
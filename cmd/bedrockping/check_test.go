@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+func TestCheck(t *testing.T) {
+	// 3/20 players, so 17 slots are free
+	address := pingtest.Server(t, testResponse)
+
+	tests := []struct {
+		args   []string
+		code   int
+		status string
+	}{
+		{nil, checkOK, "BEDROCK OK - Test Server 3/20 players, latency "},
+		{[]string{"--warn-players-free", "17"}, checkWarning, "BEDROCK WARNING - "},
+		{[]string{"--warn-players-free", "18", "--crit-players-free", "17"}, checkCritical, "BEDROCK CRITICAL - "},
+		{[]string{"--warn-latency", "1ns"}, checkWarning, "BEDROCK WARNING - "},
+		{[]string{"--warn-latency", "1ns", "--crit-latency", "1ns"}, checkCritical, "(latency "},
+		{[]string{"--warn-latency", "1h", "--warn-players-free", "1"}, checkOK, "BEDROCK OK - "},
+	}
+	for _, test := range tests {
+		code, stdout, _ := runCommand(t, append([]string{"--check", address}, test.args...)...)
+		if code != test.code || !strings.Contains(stdout, test.status) {
+			t.Errorf("%v: exit code %d: %s", test.args, code, stdout)
+		}
+		if !strings.Contains(stdout, " | latency=") || !strings.Contains(stdout, "players=3;;;0;20") {
+			t.Errorf("%v: missing performance data: %s", test.args, stdout)
+		}
+	}
+
+	code, stdout, _ := runCommand(t, "--check", "--warn-players-free", "5", "--crit-players-free", "2", address)
+	if code != checkOK || !strings.HasSuffix(stdout, "players_free=17;6:;3:;0;20\n") {
+		t.Errorf("exit code %d: %s", code, stdout)
+	}
+}
+
+func TestCheckOffline(t *testing.T) {
+	address := pingtest.DeadAddress(t)
+	code, stdout, _ := runCommand(t, "--check", "--timeout", "50ms", address)
+	if code != checkCritical || !strings.HasPrefix(stdout, "BEDROCK CRITICAL - "+address) {
+		t.Errorf("exit code %d: %s", code, stdout)
+	}
+}
+
+func TestCheckUsage(t *testing.T) {
+	if code, _, _ := runCommand(t, "--check"); code != checkUnknown {
+		t.Errorf("exit code %d without a server", code)
+	}
+	if code, _, _ := runCommand(t, "--check", "a", "b"); code != checkUnknown {
+		t.Errorf("exit code %d for several servers", code)
+	}
+	if code, _, _ := runCommand(t, "--check", "--warn-latency", "soon", "a"); code != checkUnknown {
+		t.Errorf("exit code %d for invalid flag", code)
+	}
+}
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+	"github.com/ZeroErrors/go-bedrockping/notify"
+)
+
+// config is the configuration file of the serve and exporter subcommands, in YAML or TOML:
+//
+//	interval: 30s
+//	timeout: 5s
+//	down_after: 3
+//	targets:
+//	  - play.example.com
+//	  - address: other.example.com:19133
+//	    interval: 10s
+//	    latency_threshold: 150ms
+//	notifiers:
+//	  - type: discord
+//	    url: https://discord.com/api/webhooks/...
+//	    targets: [play.example.com]
+//
+// The settings at the top level are defaults for every target. Targets may be given as just an address.
+type config struct {
+	// Interval is how often each target is pinged.
+	Interval time.Duration `yaml:"interval" toml:"interval"`
+	// Timeout is the maximum time to wait for a response.
+	Timeout          time.Duration    `yaml:"timeout" toml:"timeout"`
+	DownAfter        int              `yaml:"down_after" toml:"down_after"`
+	UpAfter          int              `yaml:"up_after" toml:"up_after"`
+	LatencyThreshold time.Duration    `yaml:"latency_threshold" toml:"latency_threshold"`
+	Targets          []targetConfig   `yaml:"targets" toml:"targets"`
+	Notifiers        []notifierConfig `yaml:"notifiers" toml:"notifiers"`
+}
+
+// targetConfig configures a monitored server, zero values use the defaults from the top level.
+type targetConfig struct {
+	Address          string        `yaml:"address" toml:"address"`
+	Interval         time.Duration `yaml:"interval" toml:"interval"`
+	DownAfter        int           `yaml:"down_after" toml:"down_after"`
+	UpAfter          int           `yaml:"up_after" toml:"up_after"`
+	LatencyThreshold time.Duration `yaml:"latency_threshold" toml:"latency_threshold"`
+
+	// line is the line the target is on in a YAML file, for errors
+	line int
+}
+
+// notifierConfig configures where state changes of targets are sent.
+type notifierConfig struct {
+	// Type is "discord", "slack" or "webhook".
+	Type string `yaml:"type" toml:"type"`
+	URL  string `yaml:"url" toml:"url"`
+	// Username and AvatarURL are for Discord.
+	Username  string `yaml:"username" toml:"username"`
+	AvatarURL string `yaml:"avatar_url" toml:"avatar_url"`
+	// Channel is for Slack.
+	Channel string `yaml:"channel" toml:"channel"`
+	// Template, Secret and Retries are for webhooks.
+	Template string `yaml:"template" toml:"template"`
+	Secret   string `yaml:"secret" toml:"secret"`
+	Retries  int    `yaml:"retries" toml:"retries"`
+	// Targets limits the notifier to events of these targets, if set.
+	Targets []string `yaml:"targets" toml:"targets"`
+
+	line int
+}
+
+// plainTarget has the fields of targetConfig without its decoding methods.
+type plainTarget targetConfig
+
+// UnmarshalYAML accepts either an address or a mapping.
+func (t *targetConfig) UnmarshalYAML(node *yaml.Node) error {
+	t.line = node.Line
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&t.Address)
+	}
+	line := t.line
+	if err := node.Decode((*plainTarget)(t)); err != nil {
+		return err
+	}
+	t.line = line
+	return nil
+}
+
+// UnmarshalTOML accepts either an address or a table.
+func (t *targetConfig) UnmarshalTOML(v any) error {
+	if address, ok := v.(string); ok {
+		t.Address = address
+		return nil
+	}
+	// Decode the table into the fields by round tripping it
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(v); err != nil {
+		return err
+	}
+	_, err := toml.Decode(buf.String(), (*plainTarget)(t))
+	return err
+}
+
+type plainNotifier notifierConfig
+
+func (n *notifierConfig) UnmarshalYAML(node *yaml.Node) error {
+	if err := node.Decode((*plainNotifier)(n)); err != nil {
+		return err
+	}
+	n.line = node.Line
+	return nil
+}
+
+// loadConfig reads and validates the configuration file at path, which is TOML if it has a .toml extension
+// and YAML otherwise.
+func loadConfig(path string) (*config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := new(config)
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		_, err = toml.Decode(string(data), cfg)
+	} else {
+		err = yaml.Unmarshal(data, cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// validate checks the configuration, errors identify the offending entry.
+func (cfg *config) validate() error {
+	var errs []error
+	if cfg.Interval < 0 || cfg.Timeout < 0 || cfg.LatencyThreshold < 0 {
+		errs = append(errs, errors.New("interval, timeout and latency_threshold can't be negative"))
+	}
+	if cfg.DownAfter < 0 || cfg.UpAfter < 0 {
+		errs = append(errs, errors.New("down_after and up_after can't be negative"))
+	}
+
+	seen := make(map[string]bool)
+	for i, t := range cfg.Targets {
+		where := entryName("targets", i, t.Address, t.line)
+		switch {
+		case t.Address == "":
+			errs = append(errs, fmt.Errorf("%s: address is missing", where))
+		case seen[t.address()]:
+			errs = append(errs, fmt.Errorf("%s: duplicate target", where))
+		}
+		seen[t.address()] = true
+		if t.Interval < 0 || t.LatencyThreshold < 0 {
+			errs = append(errs, fmt.Errorf("%s: interval and latency_threshold can't be negative", where))
+		}
+		if t.DownAfter < 0 || t.UpAfter < 0 {
+			errs = append(errs, fmt.Errorf("%s: down_after and up_after can't be negative", where))
+		}
+	}
+
+	for i, n := range cfg.Notifiers {
+		where := entryName("notifiers", i, n.Type, n.line)
+		switch n.Type {
+		case "discord", "slack", "webhook":
+		case "":
+			errs = append(errs, fmt.Errorf("%s: type is missing", where))
+		default:
+			errs = append(errs, fmt.Errorf("%s: unknown type %q, expected discord, slack or webhook", where, n.Type))
+		}
+		if n.URL == "" {
+			errs = append(errs, fmt.Errorf("%s: url is missing", where))
+		}
+		for _, host := range n.Targets {
+			if !seen[bedrockping.Target{Host: host}.Address()] {
+				errs = append(errs, fmt.Errorf("%s: unknown target %q", where, host))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// entryName describes the entry at index i of the list, like "targets[2] (play.example.com, line 7)".
+func entryName(list string, i int, name string, line int) string {
+	var details []string
+	if name != "" {
+		details = append(details, name)
+	}
+	if line > 0 {
+		details = append(details, fmt.Sprintf("line %d", line))
+	}
+	if len(details) == 0 {
+		return fmt.Sprintf("%s[%d]", list, i)
+	}
+	return fmt.Sprintf("%s[%d] (%s)", list, i, strings.Join(details, ", "))
+}
+
+func (t targetConfig) address() string {
+	return bedrockping.Target{Host: t.Address}.Address()
+}
+
+// flagConfig returns the configuration of a subcommand with its --interval and --timeout flags, loading the
+// file at path if it isn't empty. Flags given explicitly override the file. The hosts are monitored as well
+// as the targets in the file.
+func flagConfig(fs *flag.FlagSet, path string, interval, timeout time.Duration, hosts []string) (*config, error) {
+	cfg := new(config)
+	if path != "" {
+		var err error
+		if cfg, err = loadConfig(path); err != nil {
+			return nil, err
+		}
+	}
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if cfg.Interval == 0 || set["interval"] {
+		cfg.Interval = interval
+	}
+	if cfg.Timeout == 0 || set["timeout"] {
+		cfg.Timeout = timeout
+	}
+	for _, host := range hosts {
+		cfg.Targets = append(cfg.Targets, targetConfig{Address: host})
+	}
+	return cfg, nil
+}
+
+// newMonitor returns a monitor pinging the targets of cfg with client.
+func (cfg *config) newMonitor(client *bedrockping.Client) (*monitor.Monitor, error) {
+	m := monitor.New(client, cfg.monitorOptions()...)
+	for _, target := range cfg.monitorTargets(cfg.Interval) {
+		if err := m.Add(target); err != nil && !errors.Is(err, monitor.ErrDuplicateTarget) {
+			return nil, fmt.Errorf("%s: %w", target.Address, err)
+		}
+	}
+	return m, nil
+}
+
+// monitorOptions returns the options configuring a monitor with the defaults and notifiers.
+func (cfg *config) monitorOptions() []monitor.Option {
+	var opts []monitor.Option
+	if cfg.DownAfter > 0 || cfg.UpAfter > 0 {
+		downAfter, upAfter := cfg.DownAfter, cfg.UpAfter
+		// Keep the monitor's default for the one that isn't set
+		if downAfter == 0 {
+			downAfter = 3
+		}
+		if upAfter == 0 {
+			upAfter = 1
+		}
+		opts = append(opts, monitor.WithThresholds(downAfter, upAfter))
+	}
+	if cfg.LatencyThreshold > 0 {
+		opts = append(opts, monitor.WithLatencyThreshold(cfg.LatencyThreshold))
+	}
+	for _, n := range cfg.Notifiers {
+		opts = append(opts, monitor.WithNotifier(n.notifier()))
+	}
+	return opts
+}
+
+// monitorTargets returns the targets to monitor, pinged every interval unless they override it.
+func (cfg *config) monitorTargets(interval time.Duration) []monitor.Target {
+	targets := make([]monitor.Target, 0, len(cfg.Targets))
+	for _, t := range cfg.Targets {
+		target := monitor.Target{
+			Address:          t.address(),
+			Interval:         t.Interval,
+			DownAfter:        t.DownAfter,
+			UpAfter:          t.UpAfter,
+			LatencyThreshold: t.LatencyThreshold,
+		}
+		if target.Interval == 0 {
+			target.Interval = interval
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// notifier returns the monitor.Notifier described by n, only notified of its targets.
+func (n notifierConfig) notifier() monitor.Notifier {
+	var notifier monitor.Notifier
+	switch n.Type {
+	case "discord":
+		notifier = &notify.Discord{WebhookURL: n.URL, Username: n.Username, AvatarURL: n.AvatarURL}
+	case "slack":
+		notifier = &notify.Slack{WebhookURL: n.URL, Channel: n.Channel}
+	case "webhook":
+		w := &notify.Webhook{URL: n.URL, Template: n.Template, Retries: n.Retries}
+		if n.Secret != "" {
+			w.Secret = []byte(n.Secret)
+		}
+		notifier = w
+	}
+	return filterNotifier(notifier, n.Targets)
+}
+
+// filterNotifier returns a notifier passing only the events of the hosts to notifier, or notifier itself if
+// hosts is empty.
+func filterNotifier(notifier monitor.Notifier, hosts []string) monitor.Notifier {
+	if len(hosts) == 0 {
+		return notifier
+	}
+
+	targets := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		targets[bedrockping.Target{Host: host}.Address()] = true
+	}
+	return monitor.NotifierFunc(func(ctx context.Context, e monitor.Event) error {
+		if !targets[eventAddress(e)] {
+			return nil
+		}
+		return notifier.Notify(ctx, e)
+	})
+}
+
+// eventAddress returns the address of the server e is about.
+func eventAddress(e monitor.Event) string {
+	switch e := e.(type) {
+	case monitor.ServerUp:
+		return e.Address
+	case monitor.ServerDown:
+		return e.Address
+	case monitor.LatencyThreshold:
+		return e.Address
+	}
+	return ""
+}
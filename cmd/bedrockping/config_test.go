@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+// writeConfig writes a configuration file named name and returns its path.
+func writeConfig(t *testing.T, name, data string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": `
+interval: 1m
+timeout: 2s
+down_after: 2
+targets:
+  - play.example.com
+  - address: other.example.com:19133
+    interval: 10s
+    latency_threshold: 150ms
+notifiers:
+  - type: slack
+    url: https://hooks.slack.com/services/x
+    targets: [other.example.com:19133]
+`,
+		"config.toml": `
+interval = "1m"
+timeout = "2s"
+down_after = 2
+targets = [
+  "play.example.com",
+  { address = "other.example.com:19133", interval = "10s", latency_threshold = "150ms" },
+]
+
+[[notifiers]]
+type = "slack"
+url = "https://hooks.slack.com/services/x"
+targets = ["other.example.com:19133"]
+`,
+	}
+	for name, data := range files {
+		cfg, err := loadConfig(writeConfig(t, name, data))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if cfg.Interval != time.Minute || cfg.Timeout != 2*time.Second || cfg.DownAfter != 2 || len(cfg.Notifiers) != 1 {
+			t.Errorf("%s: unexpected config: %+v", name, cfg)
+		}
+
+		targets := cfg.monitorTargets(cfg.Interval)
+		expect := []monitor.Target{
+			{Address: "play.example.com:19132", Interval: time.Minute},
+			{Address: "other.example.com:19133", Interval: 10 * time.Second, LatencyThreshold: 150 * time.Millisecond},
+		}
+		if len(targets) != len(expect) || targets[0] != expect[0] || targets[1] != expect[1] {
+			t.Errorf("%s: unexpected targets: %+v", name, targets)
+		}
+	}
+
+	if _, err := loadConfig(writeConfig(t, "config.yaml", "targets: {")); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	data := `
+targets:
+  - play.example.com
+  - address: ""
+    interval: 10s
+  - play.example.com:19132
+notifiers:
+  - type: teams
+    url: https://example.com
+  - type: discord
+    targets: [missing.example.com]
+`
+	_, err := loadConfig(writeConfig(t, "config.yaml", data))
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, expect := range []string{
+		"targets[1] (line 4): address is missing",
+		"targets[2] (play.example.com:19132, line 6): duplicate target",
+		`notifiers[0] (teams, line 8): unknown type "teams"`,
+		"notifiers[1] (discord, line 10): url is missing",
+		`notifiers[1] (discord, line 10): unknown target "missing.example.com"`,
+	} {
+		if !strings.Contains(err.Error(), expect) {
+			t.Errorf("missing %q in:\n%v", expect, err)
+		}
+	}
+
+	_, err = loadConfig(writeConfig(t, "config.toml", "targets = [\"a.example.com\", { interval = \"-1s\", address = \"b.example.com\" }]"))
+	if err == nil || !strings.Contains(err.Error(), "targets[1] (b.example.com): interval and latency_threshold can't be negative") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestFilterNotifier(t *testing.T) {
+	var got []string
+	record := monitor.NotifierFunc(func(ctx context.Context, e monitor.Event) error {
+		got = append(got, eventAddress(e))
+		return nil
+	})
+
+	n := filterNotifier(record, []string{"a.example.com", "b.example.com:19133"})
+	for _, e := range []monitor.Event{
+		monitor.ServerDown{Address: "a.example.com:19132"},
+		monitor.ServerUp{Address: "b.example.com:19132"},
+		monitor.LatencyThreshold{Address: "b.example.com:19133"},
+	} {
+		n.Notify(context.Background(), e)
+	}
+	if strings.Join(got, " ") != "a.example.com:19132 b.example.com:19133" {
+		t.Errorf("unexpected events: %v", got)
+	}
+}
+
+func TestRunServeConfig(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+	path := writeConfig(t, "config.yaml", "interval: 1h\ntargets:\n  - address: "+address+"\n")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	code, _, stderr := runCommandContext(ctx, "serve", "--listen", "127.0.0.1:0", "--config", path)
+	if code != 0 || !strings.Contains(stderr, "serving status of 1 servers") {
+		t.Errorf("exit code %d: %s", code, stderr)
+	}
+
+	path = writeConfig(t, "config.yaml", "targets:\n  - address: \"\"\n")
+	if code, _, stderr := runCommand(t, "serve", "--config", path); code != 2 || !strings.Contains(stderr, "targets[0] (line 2): address is missing") {
+		t.Errorf("exit code %d: %s", code, stderr)
+	}
+}
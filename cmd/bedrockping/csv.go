@@ -0,0 +1,32 @@
+package main
+
+import (
+	"io"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/csvenc"
+)
+
+// csvHeader names the columns written by csvPrinter.
+var csvHeader = csvenc.Header
+
+// csvPrinter prints each result as a CSV row, after a header row.
+type csvPrinter struct {
+	w *csvenc.Writer
+}
+
+func newCSVPrinter(w io.Writer) *csvPrinter {
+	return &csvPrinter{w: csvenc.NewWriter(w)}
+}
+
+func (p *csvPrinter) print(res bedrockping.Result) error {
+	if err := p.w.Write(res); err != nil {
+		return err
+	}
+	// Flush every row so rows appear as results arrive
+	return p.w.Flush()
+}
+
+func (p *csvPrinter) close() error {
+	return p.w.Close()
+}
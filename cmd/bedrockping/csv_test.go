@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+func TestRunCSV(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+	down := pingtest.DeadAddress(t)
+
+	code, stdout, _ := runCommand(t, "--csv", "--timeout", "50ms", address, down)
+	if code != exitTimeout {
+		t.Errorf("exit code %d", code)
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || strings.Join(rows[0], ",") != strings.Join(csvHeader, ",") {
+		t.Fatalf("unexpected output:\n%s", stdout)
+	}
+
+	row := make(map[string]string)
+	for i, column := range csvHeader {
+		row[column] = rows[1][i]
+	}
+	expect := map[string]string{
+		"address": address, "online": "true", "error": "", "game_id": "MCPE", "server_name": "Test Server",
+		"protocol_version": "390", "version": "1.14.60", "player_count": "3", "max_players": "20",
+		"extra": "123;§bWorld;Survival",
+	}
+	for column, value := range expect {
+		if row[column] != value {
+			t.Errorf("%s: got %q, expected %q", column, row[column], value)
+		}
+	}
+	if row["latency_ms"] == "" {
+		t.Error("missing latency")
+	}
+
+	if rows[2][0] != down || rows[2][1] != "false" || rows[2][3] == "" {
+		t.Errorf("unexpected offline row: %q", rows[2])
+	}
+}
+
+func TestRunCSVConflict(t *testing.T) {
+	if code, _, _ := runCommand(t, "--csv", "--json", "a"); code != 2 {
+		t.Errorf("exit code %d", code)
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// diffField is a field of a Response compared by the diff subcommand, named like the CSV columns.
+type diffField struct {
+	name  string
+	value func(resp bedrockping.Response) string
+}
+
+var diffFields = []diffField{
+	{"game_id", func(resp bedrockping.Response) string { return resp.GameID }},
+	{"server_name", func(resp bedrockping.Response) string { return resp.ServerName }},
+	{"protocol_version", func(resp bedrockping.Response) string { return strconv.Itoa(resp.ProtocolVersion) }},
+	{"version", func(resp bedrockping.Response) string { return resp.MCPEVersion }},
+	{"player_count", func(resp bedrockping.Response) string { return strconv.Itoa(resp.PlayerCount) }},
+	{"max_players", func(resp bedrockping.Response) string { return strconv.Itoa(resp.MaxPlayers) }},
+	{"server_id", func(resp bedrockping.Response) string { return strconv.FormatUint(resp.ServerID, 10) }},
+	{"extra", func(resp bedrockping.Response) string { return strings.Join(resp.Extra, ";") }},
+}
+
+// runDiff runs the diff subcommand, which compares the responses of two servers, or of a server and a
+// baseline saved with --json, field by field.
+func runDiff(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bedrockping diff", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: bedrockping diff [flags] host[:port] host[:port]")
+		fmt.Fprintln(fs.Output(), "       bedrockping diff [flags] --baseline file.json host[:port]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Flags:")
+		fs.PrintDefaults()
+	}
+	baseline := fs.String("baseline", "", "compare with the response saved in `file` by bedrockping --json")
+	ignore := fs.String("ignore", "", "comma separated `fields` not to compare, like player_count,server_id")
+	timeout := fs.Duration("timeout", 5*time.Second, "maximum time to wait for a response")
+	transport := addTransportFlags(fs)
+	logs := addLogFlags(fs)
+
+	if err := parseArgs(fs, args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if err := logs.check(); err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 2
+	}
+	stderr = logs.stderr(stderr)
+	expectArgs := 2
+	if *baseline != "" {
+		expectArgs = 1
+	}
+	if fs.NArg() != expectArgs {
+		fs.Usage()
+		return 2
+	}
+	ignored, err := parseIgnore(*ignore)
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: --ignore: %v\n", err)
+		return 2
+	}
+
+	var addresses []string
+	for _, host := range fs.Args() {
+		addresses = append(addresses, bedrockping.Target{Host: host}.Address())
+	}
+	var base *bedrockping.Result
+	if *baseline != "" {
+		res, err := loadBaseline(*baseline)
+		if err != nil {
+			fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+			return 2
+		}
+		base = &res
+	}
+
+	opts, err := transport.options()
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 2
+	}
+	opts = append(opts, logs.options(stderr)...)
+	client := bedrockping.NewClient(append([]bedrockping.Option{bedrockping.WithTimeout(*timeout)}, opts...)...)
+
+	var results []bedrockping.Result
+	if base != nil {
+		results = append(results, *base)
+	}
+	for res := range pingAll(ctx, client, addresses) {
+		results = append(results, res)
+	}
+
+	var codes []int
+	for _, res := range results {
+		if res.Err != nil {
+			fmt.Fprintf(stderr, "bedrockping: %s: %v\n", res.Address, res.Err)
+		}
+		codes = append(codes, exitCode(res.Err))
+	}
+	if code := combineExitCodes(codes...); code != exitOK {
+		return code
+	}
+
+	differ, err := printDiff(stdout, results[0], results[1], ignored)
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 1
+	}
+	if differ > 0 {
+		return 1
+	}
+	return 0
+}
+
+// parseIgnore parses the comma separated list of field names given with --ignore.
+func parseIgnore(s string) (map[string]bool, error) {
+	ignored := make(map[string]bool)
+	if s == "" {
+		return ignored, nil
+	}
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		known := false
+		for _, f := range diffFields {
+			known = known || f.name == name
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+		ignored[name] = true
+	}
+	return ignored, nil
+}
+
+// loadBaseline reads a result saved by bedrockping --json from the file at path.
+func loadBaseline(path string) (bedrockping.Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return bedrockping.Result{}, err
+	}
+	var r record
+	if err := json.Unmarshal(data, &r); err != nil {
+		return bedrockping.Result{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if r.Response == nil {
+		return bedrockping.Result{}, fmt.Errorf("%s: no response, the server was offline when it was saved", path)
+	}
+	return bedrockping.Result{Address: path, Response: *r.Response}, nil
+}
+
+// printDiff prints the fields of the responses of a and b as a table, marking those that differ with a *.
+// It returns the number of fields that differ, ignoring the ignored fields.
+func printDiff(w io.Writer, a, b bedrockping.Result, ignored map[string]bool) (int, error) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "  FIELD\t%s\t%s\n", a.Address, b.Address)
+
+	differ, compared := 0, 0
+	for _, f := range diffFields {
+		if ignored[f.name] {
+			continue
+		}
+		compared++
+		av, bv := f.value(a.Response), f.value(b.Response)
+		mark := " "
+		if av != bv {
+			mark = "*"
+			differ++
+		}
+		fmt.Fprintf(tw, "%s %s\t%s\t%s\n", mark, f.name, av, bv)
+	}
+	if err := tw.Flush(); err != nil {
+		return differ, err
+	}
+
+	if differ == 0 {
+		_, err := fmt.Fprintf(w, "\nidentical, %d fields compared\n", compared)
+		return 0, err
+	}
+	_, err := fmt.Fprintf(w, "\n%d of %d fields differ\n", differ, compared)
+	return differ, err
+}
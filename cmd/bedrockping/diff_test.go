@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+func TestRunDiff(t *testing.T) {
+	a := pingtest.Server(t, testResponse)
+	b := pingtest.Server(t, testResponse)
+	c := startTestServer(t, func(resp *bedrockping.Response) { resp.PlayerCount, resp.Extra[1] = 5, "§bOther" })
+
+	code, stdout, stderr := runCommand(t, "diff", a, b)
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "identical, 8 fields compared") || strings.Contains(stdout, "*") {
+		t.Errorf("unexpected output:\n%s", stdout)
+	}
+
+	code, stdout, _ = runCommand(t, "diff", a, c)
+	if code != 1 {
+		t.Errorf("exit code %d for different servers", code)
+	}
+	for _, expect := range []string{"* player_count      3", "* extra             123;§bWorld;Survival", "  version           1.14.60", "2 of 8 fields differ"} {
+		if !strings.Contains(stdout, expect) {
+			t.Errorf("missing %q in:\n%s", expect, stdout)
+		}
+	}
+
+	if code, stdout, _ = runCommand(t, "diff", "--ignore", "player_count,extra", a, c); code != 0 {
+		t.Errorf("exit code %d with ignored fields:\n%s", code, stdout)
+	}
+}
+
+func TestRunDiffBaseline(t *testing.T) {
+	a := pingtest.Server(t, testResponse)
+	c := startTestServer(t, func(resp *bedrockping.Response) { resp.MCPEVersion = "1.16.0" })
+
+	_, saved, _ := runCommand(t, "--json", a)
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(path, []byte(saved), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if code, stdout, stderr := runCommand(t, "diff", "--baseline", path, a); code != 0 {
+		t.Errorf("exit code %d: %s%s", code, stdout, stderr)
+	}
+	code, stdout, _ := runCommand(t, "diff", "--baseline", path, c)
+	if code != 1 || !strings.Contains(stdout, "* version") || !strings.HasPrefix(stdout, "  FIELD             "+path) {
+		t.Errorf("exit code %d:\n%s", code, stdout)
+	}
+}
+
+func TestRunDiffErrors(t *testing.T) {
+	a := pingtest.Server(t, testResponse)
+
+	for _, args := range [][]string{
+		{"diff", a},
+		{"diff", "--baseline", "baseline.json", a, a},
+		{"diff", "--ignore", "colour", a, a},
+		{"diff", "--baseline", filepath.Join(t.TempDir(), "missing.json"), a},
+	} {
+		if code, _, _ := runCommand(t, args...); code != 2 {
+			t.Errorf("%q: exit code %d", args, code)
+		}
+	}
+
+	down := pingtest.DeadAddress(t)
+	if code, _, stderr := runCommand(t, "diff", "--timeout", "50ms", a, down); code != exitTimeout || !strings.Contains(stderr, down) {
+		t.Errorf("exit code %d for an offline server: %s", code, stderr)
+	}
+}
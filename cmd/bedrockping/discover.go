@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// runDiscover runs the discover subcommand, which lists servers on the local network like the game's Friends tab.
+func runDiscover(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bedrockping discover", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: bedrockping discover [flags]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Flags:")
+		fs.PrintDefaults()
+	}
+	duration := fs.Duration("duration", 3*time.Second, "how long to listen for servers, 0 to listen until interrupted")
+	portList := fs.String("port", "19132,19133", "comma separated `ports` to broadcast to")
+	var hosts stringsFlag
+	fs.Var(&hosts, "broadcast", "broadcast `address` to ping instead of every interface's, may be repeated")
+	output := addStreamFlags(fs)
+
+	if err := parseArgs(fs, args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+	ports, err := parsePorts(*portList)
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: --port: %v\n", err)
+		return 2
+	}
+	p, err := output.printer(stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 2
+	}
+
+	if *duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *duration)
+		defer cancel()
+	}
+	results, err := bedrockping.Discover(ctx, bedrockping.DiscoverOptions{Ports: ports, Addresses: hosts})
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 1
+	}
+
+	found := 0
+	for res := range results {
+		found++
+		if err := p.print(res); err != nil {
+			fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+			return 1
+		}
+	}
+	if err := p.close(); err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 1
+	}
+	if found == 0 {
+		fmt.Fprintln(stderr, "bedrockping: no servers found")
+		return 1
+	}
+	return 0
+}
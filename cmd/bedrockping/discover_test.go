@@ -0,0 +1,28 @@
+package main
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+func TestRunDiscover(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+	host, port, _ := net.SplitHostPort(address)
+
+	code, stdout, stderr := runCommand(t, "discover", "--duration", "100ms", "--broadcast", host, "--port", port)
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	if !strings.HasPrefix(stdout, address+"  Test Server  1.14.60  3/20 players") || strings.Count(stdout, "\n") != 1 {
+		t.Errorf("unexpected output:\n%s", stdout)
+	}
+
+	_, deadPort, _ := net.SplitHostPort(pingtest.DeadAddress(t))
+	code, _, stderr = runCommand(t, "discover", "--duration", "50ms", "--broadcast", host, "--port", deadPort)
+	if code != 1 || !strings.Contains(stderr, "no servers found") {
+		t.Errorf("exit code %d: %s", code, stderr)
+	}
+}
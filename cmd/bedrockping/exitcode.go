@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// Exit codes of the command, for scripts to tell why pinging failed. They are part of the
+// interface of the command, so existing codes must never change meaning.
+const (
+	exitOK = 0
+	// exitFailure is for failures without a code of their own, or servers failing for different reasons.
+	exitFailure = 1
+	exitUsage   = 2
+	// exitDNS is for hosts that couldn't be resolved.
+	exitDNS = 3
+	// exitTimeout is for servers that didn't respond in time.
+	exitTimeout = 4
+	// exitProtocol is for servers that responded with something other than a valid pong.
+	exitProtocol = 5
+	// exitPortClosed is for servers whose host reported nothing listening on the port.
+	exitPortClosed = 6
+)
+
+// exitCode returns the exit code for a ping that failed with err, or exitOK if err is nil.
+func exitCode(err error) int {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &dnsErr):
+		return exitDNS
+	// Invalid responses also wrap the deadline, so come before timeouts
+	case errors.Is(err, bedrockping.ErrInvalidResponse):
+		return exitProtocol
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return exitTimeout
+	case errors.Is(err, bedrockping.ErrPortClosed):
+		return exitPortClosed
+	}
+	return exitFailure
+}
+
+// combineExitCodes returns the exit code for several pings, the code they share if every failure has the same
+// one, otherwise exitFailure.
+func combineExitCodes(codes ...int) int {
+	combined := exitOK
+	for _, code := range codes {
+		switch {
+		case code == exitOK:
+		case combined == exitOK:
+			combined = code
+		case combined != code:
+			combined = exitFailure
+		}
+	}
+	return combined
+}
+
+// printExitCodes documents the exit codes in the usage message written to w.
+func printExitCodes(w io.Writer) {
+	fmt.Fprintln(w, "Exit status:")
+	fmt.Fprintf(w, "  %d  every server responded\n", exitOK)
+	fmt.Fprintf(w, "  %d  pinging failed otherwise, or servers failed for different reasons\n", exitFailure)
+	fmt.Fprintf(w, "  %d  invalid arguments\n", exitUsage)
+	fmt.Fprintf(w, "  %d  a host couldn't be resolved\n", exitDNS)
+	fmt.Fprintf(w, "  %d  a server didn't respond in time\n", exitTimeout)
+	fmt.Fprintf(w, "  %d  a server responded with something other than a valid pong\n", exitProtocol)
+	fmt.Fprintf(w, "  %d  nothing listens on a server's port\n", exitPortClosed)
+	fmt.Fprintln(w, "With --check the exit status follows the Nagios plugin guidelines instead.")
+}
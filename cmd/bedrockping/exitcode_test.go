@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err    error
+		expect int
+	}{
+		{nil, exitOK},
+		{&net.DNSError{Err: "no such host", Name: "missing.example.com", IsNotFound: true}, exitDNS},
+		{context.DeadlineExceeded, exitTimeout},
+		{&net.OpError{Op: "read", Err: &net.DNSError{IsTimeout: true}}, exitDNS},
+		{fmt.Errorf("%w: unexpected packet id: 72 (%w)", bedrockping.ErrInvalidResponse, context.DeadlineExceeded), exitProtocol},
+		{errors.New("connection refused"), exitFailure},
+		{fmt.Errorf("%w: read udp: connection refused", bedrockping.ErrPortClosed), exitPortClosed},
+	}
+	for _, test := range tests {
+		if got := exitCode(test.err); got != test.expect {
+			t.Errorf("exitCode(%v) = %d, expected %d", test.err, got, test.expect)
+		}
+	}
+}
+
+func TestCombineExitCodes(t *testing.T) {
+	tests := []struct {
+		codes  []int
+		expect int
+	}{
+		{nil, exitOK},
+		{[]int{exitOK, exitOK}, exitOK},
+		{[]int{exitOK, exitTimeout, exitTimeout}, exitTimeout},
+		{[]int{exitDNS, exitOK, exitTimeout}, exitFailure},
+	}
+	for _, test := range tests {
+		if got := combineExitCodes(test.codes...); got != test.expect {
+			t.Errorf("combineExitCodes(%v) = %d, expected %d", test.codes, got, test.expect)
+		}
+	}
+}
+
+func TestRunExitCodes(t *testing.T) {
+	// Answer pings with a packet that isn't a pong
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			_, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			conn.WriteTo([]byte{0xff}, addr)
+		}
+	}()
+
+	if code, _, stderr := runCommand(t, "--timeout", "100ms", conn.LocalAddr().String()); code != exitProtocol {
+		t.Errorf("exit code %d for an invalid response: %s", code, stderr)
+	}
+	if code, _, stderr := runCommand(t, "--timeout", "2s", "missing.invalid"); code != exitDNS {
+		t.Errorf("exit code %d for a missing host: %s", code, stderr)
+	}
+
+	_, _, stderr := runCommand(t, "-h")
+	if !strings.Contains(stderr, "Exit status:") || !strings.Contains(stderr, "  4  a server didn't respond in time") {
+		t.Errorf("exit codes not documented:\n%s", stderr)
+	}
+}
+
+func TestExitCodesDocumented(t *testing.T) {
+	var buf strings.Builder
+	printExitCodes(&buf)
+	usage := buf.String()
+
+	// Every exit code constant is listed, so new codes can't be left out of the usage message
+	f, err := parser.ParseFile(token.NewFileSet(), "exitcode.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := 0
+	for _, obj := range f.Scope.Objects {
+		if obj.Kind != ast.Con || !strings.HasPrefix(obj.Name, "exit") {
+			continue
+		}
+		found++
+		value := obj.Decl.(*ast.ValueSpec).Values[0].(*ast.BasicLit).Value
+		if !strings.Contains(usage, "\n  "+value+"  ") {
+			t.Errorf("%s (%s) isn't documented:\n%s", obj.Name, value, usage)
+		}
+	}
+	if found < 7 {
+		t.Errorf("expected at least 7 exit codes, found %d", found)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/metrics"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+// runExporter runs the exporter subcommand, a Prometheus exporter exposing the state of the configured
+// targets at /metrics and probing any server at /probe?target=, like the blackbox_exporter.
+func runExporter(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bedrockping exporter", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: bedrockping exporter [flags] [host[:port]...]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Flags:")
+		fs.PrintDefaults()
+	}
+	listen := fs.String("listen", ":9133", "`address` to serve metrics on")
+	configPath := fs.String("config", "", "YAML or TOML configuration `file` with targets, thresholds and notifiers")
+	var hosts stringsFlag
+	fs.Var(&hosts, "target", "`host[:port]` to monitor, may be repeated")
+	interval := fs.Duration("interval", 30*time.Second, "time between pings of each target")
+	timeout := fs.Duration("timeout", 5*time.Second, "maximum time to wait for a response")
+	transport := addTransportFlags(fs)
+	logs := addLogFlags(fs)
+
+	if err := parseArgs(fs, args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	hosts = append(hosts, fs.Args()...)
+	if err := logs.check(); err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 2
+	}
+	stderr = logs.stderr(stderr)
+
+	cfg, err := flagConfig(fs, *configPath, *interval, *timeout, hosts)
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 2
+	}
+
+	opts, err := transport.options()
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 2
+	}
+	opts = append(opts, logs.options(stderr)...)
+	client := bedrockping.NewClient(append([]bedrockping.Option{bedrockping.WithTimeout(cfg.Timeout)}, opts...)...)
+	m, err := cfg.newMonitor(client)
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 2
+	}
+
+	l, err := net.Listen("tcp", *listen)
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stderr, "bedrockping: exporting metrics of %d servers on http://%s/metrics\n", len(m.Targets()), l.Addr())
+
+	if err := m.Start(ctx); err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 1
+	}
+	defer m.Stop()
+
+	return serveHTTP(ctx, l, newExporterHandler(m, client), stderr)
+}
+
+// newExporterHandler returns a handler exposing the targets of m at /metrics and probing servers with client at /probe.
+func newExporterHandler(m *monitor.Monitor, client *bedrockping.Client) http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics.NewCollector(m))
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	mux.Handle("GET /probe", metrics.ProbeHandler(client))
+	return mux
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+func TestExporterHandler(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+
+	client := bedrockping.NewClient(bedrockping.WithTimeout(time.Second))
+	m := monitor.New(client)
+	if err := m.Add(monitor.Target{Address: address, Interval: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	updates, unsubscribe := m.Subscribe(1)
+	defer unsubscribe()
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+	<-updates
+
+	server := httptest.NewServer(newExporterHandler(m, client))
+	defer server.Close()
+
+	body := getText(t, server.URL+"/metrics")
+	if !strings.Contains(body, `bedrock_player_count{host="`+address+`"} 3`) {
+		t.Errorf("missing target metrics:\n%s", body)
+	}
+	body = getText(t, server.URL+"/probe?target="+address)
+	if !strings.Contains(body, "probe_success 1") {
+		t.Errorf("missing probe metrics:\n%s", body)
+	}
+}
+
+func getText(t *testing.T, url string) string {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestRunExporter(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	code, _, stderr := runCommandContext(ctx, "exporter", "--listen", "127.0.0.1:0")
+	if code != 0 {
+		t.Errorf("exit code %d: %s", code, stderr)
+	}
+
+	if code, _, _ := runCommand(t, "exporter", "--config", filepath.Join(t.TempDir(), "missing.yaml")); code != 2 {
+		t.Errorf("exit code %d for a missing config", code)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// runFakeServer runs the fake-server subcommand, which answers pings like a server until interrupted.
+func runFakeServer(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bedrockping fake-server", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: bedrockping fake-server [flags]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Flags:")
+		fs.PrintDefaults()
+	}
+	listen := fs.String("listen", ":"+strconv.Itoa(bedrockping.DefaultPort), "UDP `address` to answer pings on")
+	name := fs.String("name", "Bedrock Server", "server `name`, the first line shown in the server list")
+	motd := fs.String("motd", "", "second line shown in the server list, the world name")
+	version := fs.String("version", "1.21.0", "game `version` reported")
+	protocol := fs.Int("protocol", 685, "protocol `version` reported")
+	players := fs.Int("players", 0, "number of players reported as online")
+	maxPlayers := fs.Int("max-players", 20, "maximum number of players reported")
+	gameMode := fs.String("game-mode", "Survival", "game `mode` reported")
+
+	if err := parseArgs(fs, args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+
+	r := bedrockping.NewResponder(bedrockping.Response{
+		GameID:          "MCPE",
+		ServerName:      *name,
+		ProtocolVersion: *protocol,
+		MCPEVersion:     *version,
+		PlayerCount:     *players,
+		MaxPlayers:      *maxPlayers,
+	})
+	resp := r.Response()
+	resp.Extra = []string{strconv.FormatUint(resp.ServerID, 10), *motd, *gameMode}
+	r.SetResponse(resp)
+
+	conn, err := net.ListenPacket("udp", *listen)
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stderr, "bedrockping: answering pings on %s as %q\n", conn.LocalAddr(), bedrockping.StripFormatting(*name))
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+	if err := r.Serve(conn); err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+func TestRunFakeServer(t *testing.T) {
+	// Find a free port for the server to listen on
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := conn.LocalAddr().String()
+	conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan int, 1)
+	go func() {
+		code, _, _ := runCommandContext(ctx, "fake-server", "--listen", address, "--name", "Maintenance", "--motd", "Back soon", "--max-players", "0")
+		done <- code
+	}()
+
+	// Pings are refused until the server is listening
+	client := bedrockping.NewClient(bedrockping.WithTimeout(time.Second), bedrockping.WithResend(20*time.Millisecond))
+	var resp bedrockping.Response
+	for deadline := time.Now().Add(2 * time.Second); ; {
+		if resp, err = client.Query(context.Background(), address); err == nil || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ServerName != "Maintenance" || resp.MaxPlayers != 0 || len(resp.Extra) < 2 || resp.Extra[1] != "Back soon" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	if code := <-done; code != 0 {
+		t.Errorf("exit code %d", code)
+	}
+}
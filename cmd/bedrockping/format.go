@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// formatData is the value --format templates are executed with. The fields of the
+// response are promoted, so templates can use {{.ServerName}} and {{.PlayerCount}} directly.
+type formatData struct {
+	bedrockping.Response
+	Address string
+	// Resolved is the IP address and port that was pinged.
+	Resolved string
+	Online   bool
+	Latency  time.Duration
+	// Error is the error of a failed ping, empty if it succeeded.
+	Error string
+}
+
+var formatFuncs = template.FuncMap{
+	// strip removes formatting codes, like in {{strip .ServerName}}
+	"strip": bedrockping.StripFormatting,
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	// ms converts a duration to fractional milliseconds, like in {{ms .Latency}}
+	"ms": func(d time.Duration) float64 {
+		return float64(d) / float64(time.Millisecond)
+	},
+}
+
+// parseFormat parses a --format template.
+func parseFormat(text string) (*template.Template, error) {
+	return template.New("format").Funcs(formatFuncs).Parse(text)
+}
+
+// templatePrinter prints each result with a template, followed by a newline unless the template ends with one.
+type templatePrinter struct {
+	w    io.Writer
+	tmpl *template.Template
+	// newline is whether to print a newline after each result
+	newline bool
+}
+
+func newTemplatePrinter(w io.Writer, tmpl *template.Template, text string) *templatePrinter {
+	return &templatePrinter{w: w, tmpl: tmpl, newline: !strings.HasSuffix(text, "\n")}
+}
+
+func (p *templatePrinter) print(res bedrockping.Result) error {
+	data := formatData{Address: res.Address, Resolved: res.Resolved, Online: res.Err == nil}
+	if res.Err != nil {
+		data.Error = res.Err.Error()
+	} else {
+		data.Response = res.Response
+		data.Latency = res.Latency
+	}
+
+	if err := p.tmpl.Execute(p.w, data); err != nil {
+		return err
+	}
+	if p.newline {
+		_, err := io.WriteString(p.w, "\n")
+		return err
+	}
+	return nil
+}
+
+func (p *templatePrinter) close() error {
+	return nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+func TestRunFormat(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+	down := pingtest.DeadAddress(t)
+
+	code, stdout, _ := runCommand(t, "--timeout", "50ms", "--format", "{{strip .ServerName}} {{.PlayerCount}}/{{.MaxPlayers}} {{.Online}}", address, down)
+	if code != exitTimeout {
+		t.Errorf("exit code %d", code)
+	}
+	if expected := "Test Server 3/20 true\n 0/0 false\n"; stdout != expected {
+		t.Errorf("got %q, expected %q", stdout, expected)
+	}
+}
+
+func TestRunFormatNewline(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+
+	_, stdout, _ := runCommand(t, "--format", "{{.MCPEVersion}}\n", address)
+	if stdout != "1.14.60\n" {
+		t.Errorf("got %q", stdout)
+	}
+}
+
+func TestRunFormatInvalid(t *testing.T) {
+	code, _, stderr := runCommand(t, "--format", "{{.ServerName", "a")
+	if code != 2 || !strings.Contains(stderr, "--format") {
+		t.Errorf("exit code %d, stderr %q", code, stderr)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"log/slog"
+	"strconv"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// verbosityFlag counts how many times -v is given, -vv counting twice.
+type verbosityFlag struct {
+	level *int
+	// step is how much the flag raises the level
+	step int
+}
+
+func (f verbosityFlag) String() string {
+	if f.level == nil {
+		return "0"
+	}
+	return strconv.Itoa(*f.level)
+}
+
+func (f verbosityFlag) Set(s string) error {
+	enabled, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	if enabled {
+		*f.level += f.step
+	}
+	return nil
+}
+
+func (f verbosityFlag) IsBoolFlag() bool { return true }
+
+// logFlags are the flags controlling how much the command says on stderr.
+type logFlags struct {
+	quiet     *bool
+	verbosity *int
+}
+
+func addLogFlags(fs *flag.FlagSet) logFlags {
+	f := logFlags{quiet: fs.Bool("q", false, "quiet, only print results without any messages on stderr"), verbosity: new(int)}
+	fs.Var(verbosityFlag{f.verbosity, 1}, "v", "verbose, log each query's resolving, retries and outcome on stderr")
+	fs.Var(verbosityFlag{f.verbosity, 2}, "vv", "more verbose, also log every packet as a hex dump")
+	return f
+}
+
+// check returns an error if the flags contradict each other.
+func (f logFlags) check() error {
+	if *f.quiet && *f.verbosity > 0 {
+		return errors.New("-q and -v can't be used together")
+	}
+	return nil
+}
+
+// stderr returns where messages for the user should be written, nowhere with -q.
+func (f logFlags) stderr(stderr io.Writer) io.Writer {
+	if *f.quiet {
+		return io.Discard
+	}
+	return stderr
+}
+
+// options returns the client options logging to stderr at the level selected by the flags.
+func (f logFlags) options(stderr io.Writer) []bedrockping.Option {
+	if *f.verbosity == 0 {
+		return nil
+	}
+	level := slog.LevelDebug
+	if *f.verbosity > 1 {
+		level = bedrockping.LevelTrace
+	}
+	handler := slog.NewTextHandler(stderr, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			// Name the trace level rather than showing it as DEBUG-4
+			if a.Key == slog.LevelKey && a.Value.Any() == bedrockping.LevelTrace {
+				a.Value = slog.StringValue("TRACE")
+			}
+			return a
+		},
+	})
+	return []bedrockping.Option{bedrockping.WithLogger(slog.New(handler))}
+}
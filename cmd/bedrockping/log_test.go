@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+func TestLogFlags(t *testing.T) {
+	for args, expect := range map[string]int{
+		"":          0,
+		"-v":        1,
+		"-vv":       2,
+		"-v -v":     2,
+		"-v=false":  0,
+		"-vv -v -q": 3,
+	} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		logs := addLogFlags(fs)
+		if err := fs.Parse(strings.Fields(args)); err != nil {
+			t.Fatalf("%q: %v", args, err)
+		}
+		if *logs.verbosity != expect {
+			t.Errorf("%q: verbosity %d, expected %d", args, *logs.verbosity, expect)
+		}
+	}
+}
+
+func TestRunVerbose(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+
+	code, _, stderr := runCommand(t, "-v", address)
+	if code != exitOK {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	if !strings.Contains(stderr, `level=DEBUG msg="sending ping" address=`+address) || strings.Contains(stderr, "packet") {
+		t.Errorf("unexpected log:\n%s", stderr)
+	}
+
+	_, _, stderr = runCommand(t, "-vv", address)
+	if !strings.Contains(stderr, `level=TRACE msg="sent packet"`) || !strings.Contains(stderr, `level=TRACE msg="received packet"`) {
+		t.Errorf("missing packets in log:\n%s", stderr)
+	}
+}
+
+func TestRunQuiet(t *testing.T) {
+	down := pingtest.DeadAddress(t)
+
+	code, stdout, stderr := runCommand(t, "-q", "--ndjson", "--timeout", "50ms", down)
+	if code != exitTimeout || stderr != "" || !strings.Contains(stdout, `"online":false`) {
+		t.Errorf("exit code %d, stdout %q, stderr %q", code, stdout, stderr)
+	}
+
+	if code, _, _ := runCommand(t, "-q", "-v", down); code != exitUsage {
+		t.Errorf("exit code %d for -q and -v", code)
+	}
+}
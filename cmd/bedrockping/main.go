@@ -0,0 +1,488 @@
+// Command bedrockping pings Minecraft Bedrock/MCPE servers and prints their status.
+//
+// Usage:
+//
+//	bedrockping [flags] host[:port]...
+//	bedrockping scan [flags] cidr...
+//	bedrockping serve [flags] [host[:port]...]
+//	bedrockping exporter [flags] [host[:port]...]
+//	bedrockping discover [flags]
+//	bedrockping fake-server [flags]
+//	bedrockping diff [flags] host[:port] host[:port]
+//	bedrockping top [flags] host[:port]...
+//	bedrockping badge [flags] host[:port]
+//	bedrockping traceroute [flags] host[:port]
+//
+// The port defaults to 19132. Servers can also be listed in a file with --file, in any format accepted by
+// bedrockping.LoadTargets. A single server is printed in a human readable form, several as a table
+// sortable with --sort. With --json results are printed as a
+// single JSON document (an object for a single result, otherwise an array) and with --ndjson as
+// one JSON object per line as each result arrives, with --csv as CSV with a header row, and with --logfmt
+// as a line of logfmt key=value pairs for each result.
+// With --format each result is printed with a text/template, for example
+// --format '{{.ServerName}} {{.PlayerCount}}/{{.MaxPlayers}}'.
+//
+// With --watch a single server is pinged every --interval, showing a live status line with its
+// players, latency and loss until interrupted, when the statistics are printed. With --count N
+// the server is pinged N times like ping, printing a line per ping and then the statistics. If no
+// pings were answered the exit status is that of the last failure, from the table below.
+//
+// The exit status tells scripts why pinging failed: 0 if every server responded, 2 for invalid
+// arguments, 3 if a host couldn't be resolved, 4 if a server didn't respond in time, 5 if a server
+// responded with something other than a valid pong, 6 if nothing listens on a server's port, and 1 for
+// other failures or servers failing for different reasons. These codes are stable.
+//
+// With --wait-for-up servers that don't respond are pinged again every --interval until they do or
+// --max-wait has passed, for scripts waiting on a server to start. The exit status is 0 once every
+// server responded, otherwise that of the last failure.
+//
+// With --check the command behaves like a Nagios/Icinga plugin, printing a status line with
+// performance data and exiting with 0 for OK, 1 for WARNING, 2 for CRITICAL or 3 for UNKNOWN
+// based on the --warn-* and --crit-* thresholds.
+//
+// Pings can be sent through a SOCKS5 proxy with --proxy, from a particular address with --source-ip
+// or through a network interface with --interface. With -4 or -6 hosts are only resolved to and pinged
+// over IPv4 or IPv6, and with --happy-eyeballs hosts with both are pinged over both at once, using
+// whichever answers first. The address that was pinged is always reported.
+//
+// With -q nothing but the results is printed, with no messages on stderr. With -v each query's
+// resolving, retries and outcome are logged on stderr, and with -vv also every packet as a hex dump.
+//
+// With --srv hosts given without a port are pinged at the target of their _minecraft._udp SRV record
+// if they have one, or the SRV records of another service with --srv-service.
+//
+// With --failover the other addresses of a host with several are pinged in turn if the first doesn't
+// respond, sharing the timeout.
+//
+// With --all-addresses every IP address a host resolves to is pinged, with a result for each, such as to
+// check every server behind round-robin DNS is up.
+//
+// With --resolve the DNS records of each host are printed before the results: its A and AAAA
+// records, the SRV records if there are any, and which address is pinged and why.
+//
+// Server names are colored like in game when printing to a terminal, unless --no-color is given or
+// the NO_COLOR environment variable is set.
+//
+// The scan subcommand scans network ranges for servers, printing each as it responds,
+// with --port, --rate and --concurrency controlling the scan.
+//
+// The serve subcommand monitors the servers given with --target, as arguments, with --file or in
+// the --config file, serving their status as JSON over HTTP on --listen at /status and /status/{address}.
+//
+// The exporter subcommand is a Prometheus exporter, exposing the servers listed in the --config
+// file or given with --target at /metrics, and probing any server at /probe?target=host:port.
+//
+// The --config file of serve and exporter is YAML, or TOML with a .toml extension, listing targets
+// with their own intervals and thresholds, and notifiers to tell about servers going down or up.
+//
+// The discover subcommand lists servers on the local network, like the game's Friends tab,
+// by broadcasting pings for --duration.
+//
+// The fake-server subcommand answers pings like a server with the given --name, --motd and player counts
+// until interrupted, for maintenance placeholders and testing clients.
+//
+// The diff subcommand pings two servers, or one server and a --baseline saved with --json, and prints
+// their responses field by field, exiting with 1 if any field differs. Fields that are expected to
+// differ can be left out with --ignore, like --ignore player_count,server_id.
+//
+// The top subcommand is a terminal dashboard of servers, pinged every --interval, with their state,
+// players, latency, loss and a sparkline of recent latencies. The arrow keys select a server,
+// s changes the sort column, r reverses the order and q quits.
+//
+// The badge subcommand pings a server and writes a shields.io-style SVG badge of its status and players
+// to stdout or the -o file, with the --label and colors given. The badge is written even if the server is
+// offline, the exit status tells whether it responded.
+//
+// The traceroute subcommand pings a server with an increasing TTL, up to --max-hops, waiting --wait at each
+// hop, and prints the routers that answered, on Linux, and the hop the server was reached at, to find where
+// its traffic is dropped.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"text/template"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/logfmtenc"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command with args, returning the exit code.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "scan":
+			return runScan(ctx, args[1:], stdout, stderr)
+		case "serve":
+			return runServe(ctx, args[1:], stdout, stderr)
+		case "exporter":
+			return runExporter(ctx, args[1:], stdout, stderr)
+		case "discover":
+			return runDiscover(ctx, args[1:], stdout, stderr)
+		case "fake-server":
+			return runFakeServer(ctx, args[1:], stdout, stderr)
+		case "diff":
+			return runDiff(ctx, args[1:], stdout, stderr)
+		case "top":
+			return runTop(ctx, args[1:], stdout, stderr)
+		case "badge":
+			return runBadge(ctx, args[1:], stdout, stderr)
+		case "traceroute":
+			return runTraceroute(ctx, args[1:], stdout, stderr)
+		}
+	}
+
+	fs := flag.NewFlagSet("bedrockping", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: bedrockping [flags] host[:port]...")
+		fmt.Fprintln(fs.Output(), "       bedrockping scan [flags] cidr...")
+		fmt.Fprintln(fs.Output(), "       bedrockping serve [flags] [host[:port]...]")
+		fmt.Fprintln(fs.Output(), "       bedrockping exporter [flags] [host[:port]...]")
+		fmt.Fprintln(fs.Output(), "       bedrockping discover [flags]")
+		fmt.Fprintln(fs.Output(), "       bedrockping fake-server [flags]")
+		fmt.Fprintln(fs.Output(), "       bedrockping diff [flags] host[:port] host[:port]")
+		fmt.Fprintln(fs.Output(), "       bedrockping top [flags] host[:port]...")
+		fmt.Fprintln(fs.Output(), "       bedrockping badge [flags] host[:port]")
+		fmt.Fprintln(fs.Output(), "       bedrockping traceroute [flags] host[:port]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Flags:")
+		fs.PrintDefaults()
+		fmt.Fprintln(fs.Output())
+		printExitCodes(fs.Output())
+	}
+	timeout := fs.Duration("timeout", 5*time.Second, "maximum time to wait for a response")
+	jsonOutput := fs.Bool("json", false, "print results as a single JSON document")
+	compact := fs.Bool("compact", false, "print --json output without indentation")
+	ndjson := fs.Bool("ndjson", false, "print each result as a line of JSON")
+	csvOutput := fs.Bool("csv", false, "print results as CSV with a header row")
+	logfmt := fs.Bool("logfmt", false, "print each result as a line of logfmt key=value pairs")
+	format := fs.String("format", "", "print each result with a Go `template`, like '{{.ServerName}} {{.PlayerCount}}/{{.MaxPlayers}}'")
+	watchMode := fs.Bool("watch", false, "repeatedly ping a server, showing a live status line until interrupted")
+	interval := fs.Duration("interval", time.Second, "time between pings with --watch, --count or --wait-for-up")
+	count := fs.Int("count", 0, "ping a server `n` times and print statistics")
+	waitUp := fs.Bool("wait-for-up", false, "keep pinging every --interval until the servers respond or --max-wait has passed")
+	maxWait := fs.Duration("max-wait", 5*time.Minute, "with --wait-for-up, give up after `duration`, 0 to wait forever")
+	file := fs.String("file", "", "read servers from `path`, one per line, CSV or JSON, - for stdin")
+	checkMode := fs.Bool("check", false, "act as a Nagios/Icinga check plugin")
+	var thresholds checkThresholds
+	fs.DurationVar(&thresholds.warnLatency, "warn-latency", 0, "with --check, warn if the latency is at least `duration`")
+	fs.DurationVar(&thresholds.critLatency, "crit-latency", 0, "with --check, critical if the latency is at least `duration`")
+	fs.IntVar(&thresholds.warnPlayersFree, "warn-players-free", 0, "with --check, warn if `n` or fewer player slots are free")
+	fs.IntVar(&thresholds.critPlayersFree, "crit-players-free", 0, "with --check, critical if `n` or fewer player slots are free")
+	noColor := addNoColorFlag(fs)
+	transport := addTransportFlags(fs)
+	logs := addLogFlags(fs)
+	ipv4 := fs.Bool("4", false, "resolve and ping over IPv4 only")
+	ipv6 := fs.Bool("6", false, "resolve and ping over IPv6 only")
+	resolve := fs.Bool("resolve", false, "print the DNS records of each host and which address is pinged before the results")
+	allAddresses := fs.Bool("all-addresses", false, "ping every address each host resolves to, with a result for each")
+	failover := fs.Bool("failover", false, "ping the other addresses of hosts with several if the first doesn't respond")
+	happyEyeballs := fs.Bool("happy-eyeballs", false, "ping hosts with IPv4 and IPv6 addresses on both at once, using the first to answer")
+	srv := fs.Bool("srv", false, "ping hosts on the default port at the target of their SRV record, if they have one")
+	srvService := fs.String("srv-service", bedrockping.DefaultSRVService, "with --srv, look up the SRV records of `service`, _{service}._udp.{host}")
+	sortBy := fs.String("sort", "", "sort the table by `column`: name, version, players, latency or address, prefix with - to reverse")
+
+	if err := parseArgs(fs, args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		if *checkMode {
+			return checkUnknown
+		}
+		return exitUsage
+	}
+	// Check plugins must report usage errors as UNKNOWN
+	usageError := exitUsage
+	if *checkMode {
+		usageError = checkUnknown
+	}
+	if err := logs.check(); err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return usageError
+	}
+	stderr = logs.stderr(stderr)
+
+	addresses := make([]string, 0, fs.NArg())
+	for _, host := range fs.Args() {
+		addresses = append(addresses, bedrockping.Target{Host: host}.Address())
+	}
+	if *file != "" {
+		targets, err := loadTargets(*file)
+		if err != nil {
+			fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+			return usageError
+		}
+		for _, t := range targets {
+			addresses = append(addresses, t.Address())
+		}
+	}
+	if len(addresses) == 0 {
+		fs.Usage()
+		return usageError
+	}
+	less, err := sortFunc(*sortBy)
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return usageError
+	}
+	if countTrue(*jsonOutput, *ndjson, *csvOutput, *logfmt, *format != "") > 1 {
+		fmt.Fprintln(stderr, "bedrockping: only one of --json, --ndjson, --csv, --logfmt and --format can be used")
+		return usageError
+	}
+	if *allAddresses && (*checkMode || *watchMode || *count > 0 || *waitUp) {
+		fmt.Fprintln(stderr, "bedrockping: --all-addresses can't be used with --check, --watch, --count or --wait-for-up")
+		return usageError
+	}
+	machineOutput := *jsonOutput || *ndjson || *csvOutput || *logfmt || *format != ""
+	var tmpl *template.Template
+	if *format != "" {
+		if tmpl, err = parseFormat(*format); err != nil {
+			fmt.Fprintf(stderr, "bedrockping: --format: %v\n", err)
+			return usageError
+		}
+	}
+
+	opts, err := transport.options()
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return usageError
+	}
+	opts = append(opts, logs.options(stderr)...)
+	network := "udp"
+	switch {
+	case *ipv4 && *ipv6:
+		fmt.Fprintln(stderr, "bedrockping: -4 and -6 can't be used together")
+		return usageError
+	case *ipv4:
+		network = "udp4"
+	case *ipv6:
+		network = "udp6"
+	}
+	opts = append(opts, bedrockping.WithNetwork(network))
+	if !*srv {
+		*srvService = ""
+	}
+	opts = append(opts, bedrockping.WithSRV(*srvService), bedrockping.WithHappyEyeballs(*happyEyeballs && network == "udp"),
+		bedrockping.WithFailover(*failover))
+	client := bedrockping.NewClient(append([]bedrockping.Option{bedrockping.WithTimeout(*timeout)}, opts...)...)
+	color := useColor(stdout, *noColor)
+
+	if *checkMode {
+		if len(addresses) != 1 {
+			fmt.Fprintln(stdout, "BEDROCK UNKNOWN - --check needs a single server")
+			return checkUnknown
+		}
+		return check(ctx, client, addresses[0], thresholds, stdout)
+	}
+
+	if *resolve {
+		// Keep machine output parseable
+		w := stdout
+		if machineOutput {
+			w = stderr
+		}
+		for _, address := range addresses {
+			printResolution(ctx, w, client, address, network, *srvService, *happyEyeballs)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if *count < 0 {
+		fmt.Fprintln(stderr, "bedrockping: --count must be positive")
+		return exitUsage
+	}
+	if *watchMode || *count > 0 {
+		if len(addresses) != 1 || machineOutput || *waitUp {
+			fmt.Fprintln(stderr, "bedrockping: --watch and --count need a single server and text output, without --wait-for-up")
+			return exitUsage
+		}
+		// --count prints a line per ping like ping, unless combined with --watch
+		stats := watch(ctx, client, addresses[0], *interval, *count, *watchMode && isTerminal(stdout), color, stdout)
+		if *count > 0 && stats.received == 0 {
+			if code := exitCode(stats.lastErr); code != exitOK {
+				return code
+			}
+			return exitFailure
+		}
+		return exitOK
+	}
+
+	if *waitUp && *maxWait < 0 {
+		fmt.Fprintln(stderr, "bedrockping: --max-wait can't be negative")
+		return exitUsage
+	}
+
+	var p printer
+	switch {
+	case *jsonOutput:
+		p = &jsonPrinter{w: stdout, compact: *compact}
+	case *ndjson:
+		p = &ndjsonPrinter{enc: json.NewEncoder(stdout)}
+	case *csvOutput:
+		p = newCSVPrinter(stdout)
+	case *logfmt:
+		p = &logfmtPrinter{enc: logfmtenc.NewEncoder(stdout)}
+	case tmpl != nil:
+		p = newTemplatePrinter(stdout, tmpl, *format)
+	case len(addresses) > 1 || *allAddresses:
+		p = &tablePrinter{w: stdout, less: less, color: color, resolved: *allAddresses}
+	default:
+		p = &textPrinter{stdout: stdout, stderr: stderr, color: color}
+	}
+
+	var results <-chan bedrockping.Result
+	if *waitUp {
+		results = resultsOf(waitForUp(ctx, client, addresses, *interval, *maxWait, stderr))
+	} else if *allAddresses {
+		results = queryAllAddresses(ctx, client, addresses)
+	} else {
+		results = pingAll(ctx, client, addresses)
+	}
+	var codes []int
+	for res := range results {
+		codes = append(codes, exitCode(res.Err))
+		if err := p.print(res); err != nil {
+			fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+			return exitFailure
+		}
+	}
+	if err := p.close(); err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return exitFailure
+	}
+	return combineExitCodes(codes...)
+}
+
+// loadTargets loads the targets listed in the file at path, or stdin if path is "-".
+func loadTargets(path string) ([]bedrockping.Target, error) {
+	if path == "-" {
+		return bedrockping.LoadTargets(os.Stdin)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	targets, err := bedrockping.LoadTargets(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return targets, nil
+}
+
+// countTrue returns the number of values that are true.
+func countTrue(values ...bool) int {
+	n := 0
+	for _, v := range values {
+		if v {
+			n++
+		}
+	}
+	return n
+}
+
+// isTerminal reports whether w is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// resultsOf returns a channel receiving results, for printing results that are already known like pingAll's.
+func resultsOf(results []bedrockping.Result) <-chan bedrockping.Result {
+	ch := make(chan bedrockping.Result, len(results))
+	for _, res := range results {
+		ch <- res
+	}
+	close(ch)
+	return ch
+}
+
+// pingAll pings every address at once, returning a channel receiving the results in the order of addresses.
+func pingAll(ctx context.Context, client *bedrockping.Client, addresses []string) <-chan bedrockping.Result {
+	pending := make([]chan bedrockping.Result, len(addresses))
+	for i, address := range addresses {
+		pending[i] = make(chan bedrockping.Result, 1)
+		go func(address string, ch chan<- bedrockping.Result) {
+			ch <- client.Ping(ctx, address)
+		}(address, pending[i])
+	}
+
+	results := make(chan bedrockping.Result)
+	go func() {
+		defer close(results)
+		for _, ch := range pending {
+			results <- <-ch
+		}
+	}()
+	return results
+}
+
+// queryAllAddresses pings every IP address of every address at once, returning a channel receiving the results
+// in the order of addresses, see bedrockping.Client.QueryAll.
+func queryAllAddresses(ctx context.Context, client *bedrockping.Client, addresses []string) <-chan bedrockping.Result {
+	pending := make([]chan []bedrockping.Result, len(addresses))
+	for i, address := range addresses {
+		pending[i] = make(chan []bedrockping.Result, 1)
+		go func(address string, ch chan<- []bedrockping.Result) {
+			ch <- client.QueryAll(ctx, address)
+		}(address, pending[i])
+	}
+
+	results := make(chan bedrockping.Result)
+	go func() {
+		defer close(results)
+		for _, ch := range pending {
+			for _, res := range <-ch {
+				results <- res
+			}
+		}
+	}()
+	return results
+}
+
+// parseArgs parses args with fs, allowing flags to be mixed with positional arguments,
+// like "bedrockping host --timeout 1s". Arguments after "--" are never parsed as flags.
+func parseArgs(fs *flag.FlagSet, args []string) error {
+	var positional, rest []string
+	for i, arg := range args {
+		if arg == "--" {
+			args, rest = args[:i], args[i+1:]
+			break
+		}
+	}
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		// Parse stops at the first positional argument
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	return fs.Parse(append(append([]string{"--"}, positional...), rest...))
+}
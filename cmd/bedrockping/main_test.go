@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+// testResponse is the response of the test servers, with the formatting codes and extra fields of a real server.
+var testResponse = bedrockping.Response{
+	ServerID:        0x1234,
+	GameID:          "MCPE",
+	ServerName:      "§aTest Server",
+	ProtocolVersion: 390,
+	MCPEVersion:     "1.14.60",
+	PlayerCount:     3,
+	MaxPlayers:      20,
+	Extra:           []string{"123", "§bWorld", "Survival"},
+}
+
+// startTestServer starts a server on localhost answering pings with testResponse changed by update,
+// returning its address.
+func startTestServer(t *testing.T, update func(*bedrockping.Response)) string {
+	resp := testResponse
+	resp.Extra = append([]string(nil), resp.Extra...)
+	update(&resp)
+	return pingtest.Server(t, resp)
+}
+
+// runCommand runs the command with args, returning the exit code and output.
+func runCommand(t *testing.T, args ...string) (int, string, string) {
+	return runCommandContext(context.Background(), args...)
+}
+
+// runCommandContext is like runCommand but runs the command with ctx, for commands that run until interrupted.
+func runCommandContext(ctx context.Context, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(ctx, args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+
+	code, stdout, stderr := runCommand(t, address)
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	for _, expect := range []string{"Test Server\n", "version  1.14.60 (protocol 390)", "players  3/20", "world    World", "latency"} {
+		if !strings.Contains(stdout, expect) {
+			t.Errorf("missing %q in:\n%s", expect, stdout)
+		}
+	}
+}
+
+func TestRunAllAddresses(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+	_, port, _ := net.SplitHostPort(address)
+
+	code, stdout, stderr := runCommand(t, "--all-addresses", "-4", "localhost:"+port)
+	if code != exitOK {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "RESOLVED") || !strings.Contains(stdout, "localhost:"+port+"  127.0.0.1:"+port) {
+		t.Errorf("unexpected output:\n%s", stdout)
+	}
+
+	if code, _, stderr := runCommand(t, "--all-addresses", "--watch", address); code != exitUsage || !strings.Contains(stderr, "--all-addresses") {
+		t.Errorf("exit code %d: %s", code, stderr)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	address := pingtest.DeadAddress(t)
+
+	code, _, stderr := runCommand(t, address, "--timeout", "50ms")
+	if code != exitTimeout || !strings.Contains(stderr, address) {
+		t.Errorf("exit code %d: %s", code, stderr)
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	if code, _, stderr := runCommand(t); code != 2 || !strings.Contains(stderr, "Usage") {
+		t.Errorf("exit code %d: %s", code, stderr)
+	}
+	if code, _, _ := runCommand(t, "-h"); code != 0 {
+		t.Errorf("exit code %d for help", code)
+	}
+	if code, _, _ := runCommand(t, "--bogus", "host"); code != 2 {
+		t.Errorf("exit code %d for unknown flag", code)
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+
+	code, stdout, _ := runCommand(t, "--json", address)
+	if code != 0 {
+		t.Fatalf("exit code %d", code)
+	}
+	var r record
+	if err := json.Unmarshal([]byte(stdout), &r); err != nil {
+		t.Fatal(err)
+	}
+	if !r.Online || r.Address != address || r.Response.PlayerCount != 3 || r.LatencyMs <= 0 {
+		t.Errorf("unexpected record: %s", stdout)
+	}
+	if !strings.Contains(stdout, "\n  \"address\"") {
+		t.Errorf("expected indented output: %s", stdout)
+	}
+
+	down := pingtest.DeadAddress(t)
+	code, stdout, _ = runCommand(t, "--json", "--compact", "--timeout", "50ms", address, down)
+	if code != exitTimeout {
+		t.Errorf("exit code %d", code)
+	}
+	var records []record
+	if err := json.Unmarshal([]byte(stdout), &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Address != address || records[1].Online || records[1].Error == "" {
+		t.Errorf("unexpected records: %s", stdout)
+	}
+	if strings.Count(stdout, "\n") != 1 {
+		t.Errorf("expected compact output: %s", stdout)
+	}
+}
+
+func TestRunNDJSON(t *testing.T) {
+	a := pingtest.Server(t, testResponse)
+	b := pingtest.Server(t, testResponse)
+
+	code, stdout, _ := runCommand(t, "--ndjson", a, b)
+	if code != 0 {
+		t.Fatalf("exit code %d", code)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines: %s", stdout)
+	}
+	for i, address := range []string{a, b} {
+		var r record
+		if err := json.Unmarshal([]byte(lines[i]), &r); err != nil {
+			t.Fatal(err)
+		}
+		if r.Address != address || !r.Online {
+			t.Errorf("unexpected record: %s", lines[i])
+		}
+	}
+}
+
+func TestRunLogfmt(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+	down := pingtest.DeadAddress(t)
+
+	code, stdout, _ := runCommand(t, "--logfmt", "--timeout", "50ms", address, down)
+	if code != exitTimeout {
+		t.Errorf("exit code %d", code)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "address="+address+" online=true ") ||
+		!strings.Contains(lines[0], ` serverName="Test Server" `) || !strings.Contains(lines[0], " playerCount=3 ") ||
+		!strings.HasPrefix(lines[1], "address="+down+" online=false ") || !strings.HasSuffix(lines[1], " errorKind=timeout") {
+		t.Errorf("unexpected output:\n%s", stdout)
+	}
+}
+
+func TestRunWatch(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	if code := run(ctx, []string{"--watch", "--interval", "50ms", address}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr.String())
+	}
+
+	output := stdout.String()
+	if !strings.Contains(output, "Test Server  3/20 players  latency") || !strings.Contains(output, "loss 0.0% (0/") {
+		t.Errorf("missing status line:\n%s", output)
+	}
+	if !strings.Contains(output, "--- "+address+" bedrockping statistics ---") {
+		t.Errorf("missing statistics:\n%s", output)
+	}
+	// Output isn't a terminal so each ping is a line
+	if strings.Contains(output, "\r") || strings.Count(output, "players") < 2 {
+		t.Errorf("expected a line per ping:\n%s", output)
+	}
+
+	if code, _, _ := runCommand(t, "--watch", address, address); code != 2 {
+		t.Errorf("exit code %d for several servers", code)
+	}
+}
+
+func TestRunCount(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+
+	code, stdout, stderr := runCommand(t, "--count", "3", "--interval", "10ms", address)
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	if n := strings.Count(stdout, "players"); n != 3 {
+		t.Errorf("expected 3 pings, got %d:\n%s", n, stdout)
+	}
+	if !strings.Contains(stdout, "3 pings transmitted, 3 received, 0.0% loss") || !strings.Contains(stdout, "rtt min/avg/max/mdev = ") {
+		t.Errorf("missing statistics:\n%s", stdout)
+	}
+
+	code, stdout, _ = runCommand(t, "--count", "2", "--interval", "10ms", "--timeout", "20ms", pingtest.DeadAddress(t))
+	if code != exitTimeout {
+		t.Errorf("exit code %d for an offline server", code)
+	}
+	if !strings.Contains(stdout, "2 pings transmitted, 0 received, 100.0% loss") {
+		t.Errorf("unexpected statistics:\n%s", stdout)
+	}
+}
+
+func TestRunFile(t *testing.T) {
+	a := startTestServer(t, func(resp *bedrockping.Response) { resp.ServerName, resp.PlayerCount, resp.Extra = "Alpha", 1, nil })
+	b := startTestServer(t, func(resp *bedrockping.Response) { resp.ServerName, resp.PlayerCount, resp.Extra = "Beta", 7, nil })
+
+	path := filepath.Join(t.TempDir(), "hosts.txt")
+	if err := os.WriteFile(path, []byte("# servers\n"+b+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, stdout, stderr := runCommand(t, "--file", path, "--sort", "players", a)
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "NAME") || !strings.HasPrefix(lines[1], "Beta") || !strings.HasPrefix(lines[2], "Alpha") {
+		t.Errorf("unexpected table:\n%s", stdout)
+	}
+
+	if code, _, _ := runCommand(t, "--file", filepath.Join(t.TempDir(), "missing.txt")); code != 2 {
+		t.Errorf("exit code %d for missing file", code)
+	}
+}
+
+func TestRunFailover(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+	_, port, _ := net.SplitHostPort(address)
+
+	code, stdout, stderr := runCommand(t, "--failover", "-4", "localhost:"+port)
+	if code != exitOK || !strings.Contains(stdout, "Test Server") {
+		t.Errorf("exit code %d: %s%s", code, stdout, stderr)
+	}
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/logfmtenc"
+)
+
+// printer writes results in an output format.
+type printer interface {
+	// print writes res, or buffers it until close for formats that are a single document.
+	print(res bedrockping.Result) error
+	// close finishes the output.
+	close() error
+}
+
+// textPrinter prints results in a human readable form, and errors to stderr.
+type textPrinter struct {
+	stdout, stderr io.Writer
+	// color renders formatting codes as ANSI colors
+	color   bool
+	printed bool
+}
+
+func (p *textPrinter) print(res bedrockping.Result) error {
+	if res.Err != nil {
+		_, err := fmt.Fprintf(p.stderr, "bedrockping: %s: %v\n", res.Address, res.Err)
+		return err
+	}
+
+	if p.printed {
+		fmt.Fprintln(p.stdout)
+	}
+	p.printed = true
+
+	resp := res.Response
+	fmt.Fprintln(p.stdout, displayName(resp.ServerName, p.color))
+
+	tw := tabwriter.NewWriter(p.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "  address\t%s\n", res.Address)
+	if res.Resolved != "" && res.Resolved != res.Address {
+		fmt.Fprintf(tw, "  resolved\t%s (%s)\n", res.Resolved, addressFamily(res.Resolved))
+	}
+	fmt.Fprintf(tw, "  version\t%s (protocol %d)\n", resp.MCPEVersion, resp.ProtocolVersion)
+	fmt.Fprintf(tw, "  players\t%d/%d\n", resp.PlayerCount, resp.MaxPlayers)
+	if len(resp.Extra) > 1 && resp.Extra[1] != "" {
+		fmt.Fprintf(tw, "  world\t%s\n", displayName(resp.Extra[1], p.color))
+	}
+	fmt.Fprintf(tw, "  latency\t%s\n", formatLatency(res.Latency))
+	return tw.Flush()
+}
+
+func (p *textPrinter) close() error {
+	return nil
+}
+
+// record is the JSON representation of a result.
+type record struct {
+	Address string `json:"address"`
+	// Resolved is the IP address and port that was pinged.
+	Resolved string                `json:"resolved,omitempty"`
+	Time     time.Time             `json:"time"`
+	Online   bool                  `json:"online"`
+	Response *bedrockping.Response `json:"response,omitempty"`
+	// LatencyMs is the latency in milliseconds.
+	LatencyMs float64 `json:"latencyMs,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
+
+func newRecord(res bedrockping.Result, t time.Time) record {
+	r := record{Address: res.Address, Resolved: res.Resolved, Time: t, Online: res.Err == nil}
+	if res.Err != nil {
+		r.Error = res.Err.Error()
+	} else {
+		r.Response = &res.Response
+		r.LatencyMs = float64(res.Latency) / float64(time.Millisecond)
+	}
+	return r
+}
+
+// jsonPrinter prints a single JSON document once every result is known, an object if there's
+// only one result and otherwise an array.
+type jsonPrinter struct {
+	w       io.Writer
+	compact bool
+	records []record
+}
+
+func (p *jsonPrinter) print(res bedrockping.Result) error {
+	p.records = append(p.records, newRecord(res, time.Now()))
+	return nil
+}
+
+func (p *jsonPrinter) close() error {
+	enc := json.NewEncoder(p.w)
+	if !p.compact {
+		enc.SetIndent("", "  ")
+	}
+	if len(p.records) == 1 {
+		return enc.Encode(p.records[0])
+	}
+	if p.records == nil {
+		p.records = []record{}
+	}
+	return enc.Encode(p.records)
+}
+
+// ndjsonPrinter prints each result as a line of JSON as soon as it's known.
+type ndjsonPrinter struct {
+	enc *json.Encoder
+}
+
+func (p *ndjsonPrinter) print(res bedrockping.Result) error {
+	return p.enc.Encode(newRecord(res, time.Now()))
+}
+
+func (p *ndjsonPrinter) close() error {
+	return nil
+}
+
+// logfmtPrinter prints each result as a line of logfmt key=value pairs as soon as it's known.
+type logfmtPrinter struct {
+	enc *logfmtenc.Encoder
+}
+
+func (p *logfmtPrinter) print(res bedrockping.Result) error {
+	return p.enc.Encode(res)
+}
+
+func (p *logfmtPrinter) close() error {
+	return nil
+}
+
+// serverPrinter prints a line for each discovered server as soon as it responds.
+type serverPrinter struct {
+	w     io.Writer
+	color bool
+}
+
+func (p *serverPrinter) print(res bedrockping.Result) error {
+	resp := res.Response
+	_, err := fmt.Fprintf(p.w, "%s  %s  %s  %d/%d players  %s\n", res.Address, displayName(resp.ServerName, p.color),
+		resp.MCPEVersion, resp.PlayerCount, resp.MaxPlayers, formatLatency(res.Latency))
+	return err
+}
+
+func (p *serverPrinter) close() error {
+	return nil
+}
+
+// streamFlags are the output flags of subcommands that print servers as they're found.
+type streamFlags struct {
+	ndjson, csv, logfmt, noColor *bool
+	format                       *string
+}
+
+func addStreamFlags(fs *flag.FlagSet) streamFlags {
+	return streamFlags{
+		ndjson:  fs.Bool("ndjson", false, "print each server as a line of JSON"),
+		csv:     fs.Bool("csv", false, "print servers as CSV with a header row"),
+		logfmt:  fs.Bool("logfmt", false, "print each server as a line of logfmt key=value pairs"),
+		format:  fs.String("format", "", "print each server with a Go `template`"),
+		noColor: addNoColorFlag(fs),
+	}
+}
+
+// printer returns the printer selected by the flags, by default a serverPrinter.
+func (f streamFlags) printer(w io.Writer) (printer, error) {
+	if countTrue(*f.ndjson, *f.csv, *f.logfmt, *f.format != "") > 1 {
+		return nil, errors.New("only one of --ndjson, --csv, --logfmt and --format can be used")
+	}
+
+	switch {
+	case *f.ndjson:
+		return &ndjsonPrinter{enc: json.NewEncoder(w)}, nil
+	case *f.csv:
+		return newCSVPrinter(w), nil
+	case *f.logfmt:
+		return &logfmtPrinter{enc: logfmtenc.NewEncoder(w)}, nil
+	case *f.format != "":
+		tmpl, err := parseFormat(*f.format)
+		if err != nil {
+			return nil, fmt.Errorf("--format: %w", err)
+		}
+		return newTemplatePrinter(w, tmpl, *f.format), nil
+	}
+	return &serverPrinter{w: w, color: useColor(w, *f.noColor)}, nil
+}
+
+func addNoColorFlag(fs *flag.FlagSet) *bool {
+	return fs.Bool("no-color", false, "don't color server names, also disabled by setting NO_COLOR")
+}
+
+// useColor reports whether to render formatting codes as ANSI colors in output to w,
+// which is only done for terminals, unless disabled by --no-color or the NO_COLOR environment variable.
+func useColor(w io.Writer, noColor bool) bool {
+	return !noColor && os.Getenv("NO_COLOR") == "" && isTerminal(w)
+}
+
+// displayName returns s with its formatting codes rendered as ANSI colors if color is set, otherwise removed.
+func displayName(s string, color bool) string {
+	if color {
+		return bedrockping.ANSIFormatting(s)
+	}
+	return bedrockping.StripFormatting(s)
+}
+
+// addressFamily returns "IPv4" or "IPv6" for the family of the IP address in address, which has a port.
+func addressFamily(address string) string {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return "unknown"
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+		return "IPv6"
+	}
+	return "IPv4"
+}
+
+// formatLatency formats d with a precision suiting network latencies.
+func formatLatency(d time.Duration) string {
+	if d < 10*time.Millisecond {
+		return d.Round(10 * time.Microsecond).String()
+	}
+	return d.Round(100 * time.Microsecond).String()
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+func TestDisplayName(t *testing.T) {
+	if got := displayName("§aGreen", false); got != "Green" {
+		t.Errorf("got %q", got)
+	}
+	if got := displayName("§aGreen", true); got != "\x1b[92mGreen\x1b[0m" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	if useColor(new(bytes.Buffer), false) {
+		t.Error("colored output that isn't a terminal")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if useColor(new(bytes.Buffer), false) {
+		t.Error("colored output with NO_COLOR set")
+	}
+}
+
+func TestRunNoColor(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+
+	_, stdout, _ := runCommand(t, "--no-color", address)
+	if bytes.ContainsRune([]byte(stdout), '\x1b') {
+		t.Errorf("unexpected escape sequences: %q", stdout)
+	}
+}
+
+func TestAddressFamily(t *testing.T) {
+	tests := map[string]string{
+		"127.0.0.1:19132": "IPv4",
+		"[::1]:19132":     "IPv6",
+		"invalid":         "unknown",
+	}
+	for address, expect := range tests {
+		if got := addressFamily(address); got != expect {
+			t.Errorf("addressFamily(%q) = %q, expected %q", address, got, expect)
+		}
+	}
+}
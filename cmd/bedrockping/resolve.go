@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// printResolution prints every DNS record found for the host of address, and which address client pings and why,
+// for --resolve. network is the client's network, "udp", "udp4" or "udp6", srvService the SRV service
+// the client looks up, empty without --srv, and happyEyeballs whether --happy-eyeballs was given.
+func printResolution(ctx context.Context, w io.Writer, client *bedrockping.Client, address, network, srvService string, happyEyeballs bool) {
+	fmt.Fprintf(w, "resolving %s\n", address)
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		fmt.Fprintf(w, "  using  nothing: %v\n", err)
+		return
+	}
+
+	literal := net.ParseIP(host) != nil
+	var srvTarget *net.SRV
+	// first holds the first address of each family, which are raced with --happy-eyeballs
+	var first []string
+	if !literal {
+		for _, family := range []struct {
+			record, network, flag string
+		}{
+			{"A", "ip4", "-6"},
+			{"AAAA", "ip6", "-4"},
+		} {
+			if network != "udp" && "ip"+strings.TrimPrefix(network, "udp") != family.network {
+				fmt.Fprintf(w, "  %-6s skipped with %s\n", family.record, family.flag)
+				continue
+			}
+			addrs, err := net.DefaultResolver.LookupNetIP(ctx, family.network, host)
+			fmt.Fprintf(w, "  %-6s %s\n", family.record, describeRecords(len(addrs), err, func(i int) string { return addrs[i].Unmap().String() }))
+			if len(addrs) > 0 {
+				first = append(first, net.JoinHostPort(addrs[0].Unmap().String(), port))
+			}
+		}
+
+		service := srvService
+		if service == "" {
+			service = bedrockping.DefaultSRVService
+		}
+		_, srvs, err := net.DefaultResolver.LookupSRV(ctx, service, "udp", host)
+		records := describeRecords(len(srvs), err, func(i int) string {
+			return fmt.Sprintf("%s:%d (priority %d, weight %d)", strings.TrimSuffix(srvs[i].Target, "."), srvs[i].Port, srvs[i].Priority, srvs[i].Weight)
+		})
+		switch {
+		case srvService == "":
+			fmt.Fprintf(w, "  %-6s %s, not used without --srv\n", "SRV", records)
+		case port != strconv.Itoa(bedrockping.DefaultPort):
+			fmt.Fprintf(w, "  %-6s %s, not used with port %s\n", "SRV", records, port)
+		default:
+			fmt.Fprintf(w, "  %-6s %s\n", "SRV", records)
+			srvTarget = firstSRVTarget(ctx, srvs, network)
+		}
+	}
+
+	resolved, err := client.Resolve(ctx, address)
+	switch {
+	case err != nil:
+		fmt.Fprintf(w, "  using  nothing: %v\n", err)
+	case literal:
+		fmt.Fprintf(w, "  using  %s, the host is an IP address\n", resolved)
+	case srvTarget != nil:
+		fmt.Fprintf(w, "  using  %s, from the SRV record %s:%d\n", resolved, strings.TrimSuffix(srvTarget.Target, "."), srvTarget.Port)
+	case happyEyeballs && network == "udp" && len(first) == 2:
+		fmt.Fprintf(w, "  using  %s and %s at once, the first address of each family\n", first[1], first[0])
+	case network != "udp":
+		fmt.Fprintf(w, "  using  %s, the first %s address returned by the resolver\n", resolved, addressFamily(resolved))
+	default:
+		fmt.Fprintf(w, "  using  %s, the first address returned by the resolver\n", resolved)
+	}
+}
+
+// firstSRVTarget returns the first of srvs whose target resolves on network, the record the client pings,
+// or nil if there's none.
+func firstSRVTarget(ctx context.Context, srvs []*net.SRV, network string) *net.SRV {
+	ipNetwork := "ip" + strings.TrimPrefix(network, "udp")
+	for _, srv := range srvs {
+		if srv.Target == "." {
+			continue
+		}
+		if addrs, err := net.DefaultResolver.LookupNetIP(ctx, ipNetwork, srv.Target); err == nil && len(addrs) > 0 {
+			return srv
+		}
+	}
+	return nil
+}
+
+// describeRecords lists the n records found by a lookup, formatted by record, or describes why there are none.
+func describeRecords(n int, err error, record func(i int) string) string {
+	var dnsErr *net.DNSError
+	switch {
+	case err != nil && errors.As(err, &dnsErr) && dnsErr.IsNotFound, err == nil && n == 0:
+		return "none"
+	case err != nil:
+		return "lookup failed: " + err.Error()
+	}
+
+	records := make([]string, n)
+	for i := range records {
+		records[i] = record(i)
+	}
+	return strings.Join(records, ", ")
+}
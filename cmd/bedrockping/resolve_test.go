@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+func TestRunResolve(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+	_, port, _ := net.SplitHostPort(address)
+
+	code, stdout, stderr := runCommand(t, "--resolve", address)
+	if code != exitOK {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	expect := "resolving " + address + "\n  using  " + address + ", the host is an IP address\n\nTest Server\n"
+	if !strings.HasPrefix(stdout, expect) {
+		t.Errorf("unexpected output:\n%s", stdout)
+	}
+
+	code, stdout, stderr = runCommand(t, "--resolve", "-4", "localhost:"+port)
+	if code != exitOK {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	for _, expect := range []string{
+		"resolving localhost:" + port + "\n  A      127.0.0.1",
+		"  AAAA   skipped with -4\n  SRV    ",
+		", not used without --srv\n",
+		"  using  127.0.0.1:" + port + ", the first IPv4 address returned by the resolver\n",
+	} {
+		if !strings.Contains(stdout, expect) {
+			t.Errorf("missing %q in:\n%s", expect, stdout)
+		}
+	}
+
+	code, stdout, stderr = runCommand(t, "--resolve", "-4", "--srv", "localhost:"+port)
+	if code != exitOK {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, ", not used with port "+port+"\n") {
+		t.Errorf("expected the SRV records to be skipped for port %s:\n%s", port, stdout)
+	}
+
+	// localhost may resolve to IPv4 only, in which case there's nothing to race
+	code, stdout, stderr = runCommand(t, "--resolve", "--happy-eyeballs", "localhost:"+port)
+	if code != exitOK {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, " at once, the first address of each family\n") && !strings.Contains(stdout, ", the first address returned by the resolver\n") {
+		t.Errorf("unexpected output:\n%s", stdout)
+	}
+
+	// Machine output stays parseable
+	_, stdout, stderr = runCommand(t, "--resolve", "--json", address)
+	var r record
+	if err := json.Unmarshal([]byte(stdout), &r); err != nil || !strings.Contains(stderr, "resolving "+address) {
+		t.Errorf("unexpected output: %v\n%s%s", err, stdout, stderr)
+	}
+}
+
+func TestDescribeRecords(t *testing.T) {
+	names := []string{"a", "b"}
+	record := func(i int) string { return names[i] }
+	if got := describeRecords(2, nil, record); got != "a, b" {
+		t.Errorf("got %q", got)
+	}
+	if got := describeRecords(0, &net.DNSError{Err: "no such host", IsNotFound: true}, record); got != "none" {
+		t.Errorf("got %q", got)
+	}
+	if got := describeRecords(0, errors.New("server misbehaving"), record); got != "lookup failed: server misbehaving" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/parquetenc"
+	"github.com/ZeroErrors/go-bedrockping/scan"
+)
+
+// runScan runs the scan subcommand, which scans network ranges for servers and prints them as they respond.
+func runScan(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bedrockping scan", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: bedrockping scan [flags] cidr...")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Flags:")
+		fs.PrintDefaults()
+	}
+	portList := fs.String("port", strconv.Itoa(bedrockping.DefaultPort), "comma separated `ports` to scan")
+	rate := fs.Float64("rate", 0, "maximum pings sent per second, 0 for unlimited")
+	concurrency := fs.Int("concurrency", 0, "maximum addresses probed at once, 0 for the default of 256")
+	timeout := fs.Duration("timeout", 2*time.Second, "time to wait for each address to respond")
+	parquetFile := fs.String("parquet", "", "also write the servers found to a Parquet `file`, for DuckDB or Spark")
+	output := addStreamFlags(fs)
+
+	if err := parseArgs(fs, args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	ports, err := parsePorts(*portList)
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: --port: %v\n", err)
+		return 2
+	}
+	p, err := output.printer(stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 2
+	}
+
+	var pw *parquetenc.Writer
+	if *parquetFile != "" {
+		f, err := os.Create(*parquetFile)
+		if err != nil {
+			fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		pw = parquetenc.NewWriter(f)
+	}
+
+	s := scan.Scanner{Rate: *rate, Concurrency: *concurrency, Timeout: *timeout}
+	for _, cidr := range fs.Args() {
+		results, err := s.Scan(ctx, cidr, ports)
+		if err != nil {
+			fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+			return 2
+		}
+		for res := range results {
+			if err := p.print(res); err != nil {
+				fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+				return 1
+			}
+			if pw != nil {
+				if err := pw.Write(res); err != nil {
+					fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+					return 1
+				}
+			}
+		}
+	}
+	if err := p.close(); err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 1
+	}
+	if pw != nil {
+		if err := pw.Close(); err != nil {
+			fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// parsePorts parses a comma separated list of ports.
+func parsePorts(list string) ([]int, error) {
+	var ports []int
+	for _, field := range strings.Split(list, ",") {
+		port, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("invalid port %q", field)
+		}
+		ports = append(ports, port)
+	}
+	return ports, nil
+}
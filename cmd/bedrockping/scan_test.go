@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+	"github.com/ZeroErrors/go-bedrockping/parquetenc"
+)
+
+func TestRunScan(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+	_, port, _ := net.SplitHostPort(address)
+
+	code, stdout, stderr := runCommand(t, "scan", "127.0.0.1/32", "--port", port+","+port, "--timeout", "200ms")
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], address+"  Test Server  1.14.60  3/20 players") {
+		t.Errorf("unexpected output:\n%s", stdout)
+	}
+
+	_, stdout, _ = runCommand(t, "scan", "--port", port, "--format", "{{.Address}} {{.PlayerCount}}", "127.0.0.1/32")
+	if stdout != address+" 3\n" {
+		t.Errorf("unexpected output: %q", stdout)
+	}
+}
+
+func TestRunScanParquet(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+	_, port, _ := net.SplitHostPort(address)
+	file := filepath.Join(t.TempDir(), "scan.parquet")
+
+	code, _, stderr := runCommand(t, "scan", "--port", port, "--timeout", "200ms", "--parquet", file, "127.0.0.1/32")
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := parquetenc.Read(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0].Address != address || *rows[0].ServerName != "Test Server" {
+		t.Errorf("unexpected rows %+v", rows)
+	}
+}
+
+func TestRunScanUsage(t *testing.T) {
+	for _, args := range [][]string{
+		{"scan"},
+		{"scan", "not a range"},
+		{"scan", "--port", "70000", "127.0.0.1/32"},
+		{"scan", "--csv", "--ndjson", "127.0.0.1/32"},
+	} {
+		if code, _, _ := runCommand(t, args...); code != 2 {
+			t.Errorf("%q: exit code %d", args, code)
+		}
+	}
+}
+
+func TestParsePorts(t *testing.T) {
+	ports, err := parsePorts("19132, 19133")
+	if err != nil || len(ports) != 2 || ports[0] != 19132 || ports[1] != 19133 {
+		t.Errorf("got %v, %v", ports, err)
+	}
+	if _, err := parsePorts("19132,"); err == nil {
+		t.Error("expected error for empty port")
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+// runServe runs the serve subcommand, which monitors servers and serves their status as JSON over HTTP.
+func runServe(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bedrockping serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: bedrockping serve [flags] [host[:port]...]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Flags:")
+		fs.PrintDefaults()
+	}
+	listen := fs.String("listen", ":8080", "`address` to serve HTTP on")
+	var hosts stringsFlag
+	fs.Var(&hosts, "target", "`host[:port]` to monitor, may be repeated")
+	configPath := fs.String("config", "", "YAML or TOML configuration `file` with targets, thresholds and notifiers")
+	file := fs.String("file", "", "read servers to monitor from `path`, one per line, CSV or JSON, - for stdin")
+	interval := fs.Duration("interval", 30*time.Second, "time between pings of each server")
+	timeout := fs.Duration("timeout", 5*time.Second, "maximum time to wait for a response")
+	transport := addTransportFlags(fs)
+	logs := addLogFlags(fs)
+
+	if err := parseArgs(fs, args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	hosts = append(hosts, fs.Args()...)
+	if err := logs.check(); err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 2
+	}
+	stderr = logs.stderr(stderr)
+
+	if *file != "" {
+		targets, err := loadTargets(*file)
+		if err != nil {
+			fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+			return 2
+		}
+		for _, t := range targets {
+			hosts = append(hosts, t.Address())
+		}
+	}
+	cfg, err := flagConfig(fs, *configPath, *interval, *timeout, hosts)
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 2
+	}
+	if len(cfg.Targets) == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	opts, err := transport.options()
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 2
+	}
+	opts = append(opts, logs.options(stderr)...)
+	m, err := cfg.newMonitor(bedrockping.NewClient(append([]bedrockping.Option{bedrockping.WithTimeout(cfg.Timeout)}, opts...)...))
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 2
+	}
+
+	l, err := net.Listen("tcp", *listen)
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stderr, "bedrockping: serving status of %d servers on http://%s/status\n", len(m.Targets()), l.Addr())
+
+	if err := m.Start(ctx); err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 1
+	}
+	defer m.Stop()
+
+	return serveHTTP(ctx, l, newStatusHandler(m), stderr)
+}
+
+// serveHTTP serves handler on l until ctx is done, then shuts down gracefully. It returns the exit code.
+func serveHTTP(ctx context.Context, l net.Listener, handler http.Handler, stderr io.Writer) int {
+	srv := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.Serve(l)
+	}()
+
+	select {
+	case err := <-errs:
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 1
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// serverStatus is the JSON representation of a monitored server.
+type serverStatus struct {
+	Address  string                `json:"address"`
+	State    monitor.State         `json:"state"`
+	Time     *time.Time            `json:"time,omitempty"`
+	Online   bool                  `json:"online"`
+	Response *bedrockping.Response `json:"response,omitempty"`
+	// LatencyMs is the latency of the latest ping in milliseconds.
+	LatencyMs float64 `json:"latencyMs,omitempty"`
+	Error     string  `json:"error,omitempty"`
+	// LossPercent is the percentage of ping packets lost over the monitor's loss window.
+	LossPercent float64 `json:"lossPercent"`
+	JitterMs    float64 `json:"jitterMs"`
+}
+
+func statusOf(m *monitor.Monitor, address string) (serverStatus, bool) {
+	stats, ok := m.Stats(address)
+	if !ok {
+		return serverStatus{}, false
+	}
+
+	s := serverStatus{
+		Address:     address,
+		LossPercent: stats.Loss,
+		JitterMs:    float64(stats.Jitter) / float64(time.Millisecond),
+	}
+	if u, ok := m.Status(address); ok {
+		s.State = u.State
+		s.Time = &u.Time
+		s.Online = u.Up
+		s.Response = u.Response
+		if u.Up {
+			s.LatencyMs = float64(u.Latency) / float64(time.Millisecond)
+		}
+		if u.Err != nil {
+			s.Error = u.Err.Error()
+		}
+	}
+	return s, true
+}
+
+// newStatusHandler returns a handler serving the status of every server monitored by m at /status,
+// and of a single server at /status/{address}.
+func newStatusHandler(m *monitor.Monitor) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		addresses := m.Targets()
+		sort.Strings(addresses)
+
+		statuses := make([]serverStatus, 0, len(addresses))
+		for _, address := range addresses {
+			// The target may have been removed since it was listed
+			if s, ok := statusOf(m, address); ok {
+				statuses = append(statuses, s)
+			}
+		}
+		writeJSON(w, http.StatusOK, statuses)
+	})
+	mux.HandleFunc("GET /status/{address}", func(w http.ResponseWriter, r *http.Request) {
+		address := bedrockping.Target{Host: r.PathValue("address")}.Address()
+		s, ok := statusOf(m, address)
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "unknown server " + address})
+			return
+		}
+		writeJSON(w, http.StatusOK, s)
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// stringsFlag is a flag.Value collecting every use of a repeated flag.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
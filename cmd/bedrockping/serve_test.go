@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+func TestStatusHandler(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+
+	m := monitor.New(bedrockping.NewClient(bedrockping.WithTimeout(time.Second)))
+	if err := m.Add(monitor.Target{Address: address, Interval: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	updates, unsubscribe := m.Subscribe(1)
+	defer unsubscribe()
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+	<-updates
+
+	server := httptest.NewServer(newStatusHandler(m))
+	defer server.Close()
+
+	var statuses []serverStatus
+	getJSON(t, server.URL+"/status", http.StatusOK, &statuses)
+	if len(statuses) != 1 || statuses[0].Address != address || !statuses[0].Online || statuses[0].State != monitor.StateUp {
+		t.Fatalf("unexpected statuses: %+v", statuses)
+	}
+	if statuses[0].Response == nil || statuses[0].Response.PlayerCount != 3 || statuses[0].LatencyMs <= 0 {
+		t.Errorf("unexpected status: %+v", statuses[0])
+	}
+
+	var status serverStatus
+	getJSON(t, server.URL+"/status/"+address, http.StatusOK, &status)
+	if status.Address != address || !status.Online {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	var errBody map[string]string
+	getJSON(t, server.URL+"/status/unknown.example.com", http.StatusNotFound, &errBody)
+	if errBody["error"] == "" {
+		t.Errorf("missing error: %v", errBody)
+	}
+}
+
+func getJSON(t *testing.T, url string, status int, v any) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != status {
+		t.Fatalf("%s: got status %s, expected %d", url, resp.Status, status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunServe(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if code, _, stderr := runCommandContext(ctx, "serve", "--listen", "127.0.0.1:0", "--target", address); code != 0 {
+		t.Errorf("exit code %d: %s", code, stderr)
+	}
+
+	if code, _, _ := runCommand(t, "serve", "--listen", "127.0.0.1:0"); code != 2 {
+		t.Errorf("exit code %d without targets", code)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// pingStats accumulates ping-style statistics over repeated pings of a server.
+type pingStats struct {
+	transmitted int
+	received    int
+
+	min, max time.Duration
+	sum      time.Duration
+	// sumSquares is the sum of the squared latencies in seconds, for the standard deviation
+	sumSquares float64
+	// lastErr is the error of the latest failed ping
+	lastErr error
+}
+
+// add records the outcome of a ping, latency is ignored if it failed.
+func (s *pingStats) add(latency time.Duration, ok bool) {
+	s.transmitted++
+	if !ok {
+		return
+	}
+
+	if s.received == 0 || latency < s.min {
+		s.min = latency
+	}
+	if latency > s.max {
+		s.max = latency
+	}
+	s.received++
+	s.sum += latency
+	s.sumSquares += latency.Seconds() * latency.Seconds()
+}
+
+// loss returns the percentage of pings that failed.
+func (s *pingStats) loss() float64 {
+	if s.transmitted == 0 {
+		return 0
+	}
+	return float64(s.transmitted-s.received) / float64(s.transmitted) * 100
+}
+
+func (s *pingStats) avg() time.Duration {
+	if s.received == 0 {
+		return 0
+	}
+	return s.sum / time.Duration(s.received)
+}
+
+// mdev returns the standard deviation of the latencies, as reported by ping.
+func (s *pingStats) mdev() time.Duration {
+	if s.received == 0 {
+		return 0
+	}
+	mean := s.avg().Seconds()
+	variance := s.sumSquares/float64(s.received) - mean*mean
+	if variance < 0 {
+		variance = 0
+	}
+	return time.Duration(math.Sqrt(variance) * float64(time.Second))
+}
+
+// print prints the statistics in the style of ping.
+func (s *pingStats) print(w io.Writer, address string) {
+	fmt.Fprintf(w, "--- %s bedrockping statistics ---\n", address)
+	fmt.Fprintf(w, "%d pings transmitted, %d received, %.1f%% loss\n", s.transmitted, s.received, s.loss())
+	if s.received > 0 {
+		fmt.Fprintf(w, "rtt min/avg/max/mdev = %s/%s/%s/%s\n",
+			formatLatency(s.min), formatLatency(s.avg()), formatLatency(s.max), formatLatency(s.mdev()))
+	}
+}
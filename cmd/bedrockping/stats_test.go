@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestPingStats(t *testing.T) {
+	var s pingStats
+	s.add(10*time.Millisecond, true)
+	s.add(0, false)
+	s.add(30*time.Millisecond, true)
+	s.add(20*time.Millisecond, true)
+
+	if s.transmitted != 4 || s.received != 3 || s.loss() != 25 {
+		t.Errorf("unexpected counts: %+v", s)
+	}
+	if s.min != 10*time.Millisecond || s.max != 30*time.Millisecond || s.avg() != 20*time.Millisecond {
+		t.Errorf("unexpected latencies: %+v", s)
+	}
+	// Population standard deviation of 10, 20 and 30
+	if mdev := s.mdev(); mdev < 8164*time.Microsecond || mdev > 8166*time.Microsecond {
+		t.Errorf("unexpected mdev: %s", mdev)
+	}
+
+	var buf bytes.Buffer
+	s.print(&buf, "a:19132")
+	expect := "--- a:19132 bedrockping statistics ---\n" +
+		"4 pings transmitted, 3 received, 25.0% loss\n" +
+		"rtt min/avg/max/mdev = 10ms/20ms/30ms/8.16ms\n"
+	if buf.String() != expect {
+		t.Errorf("got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// tablePrinter prints results as an aligned table once every result is known.
+type tablePrinter struct {
+	w    io.Writer
+	less func(a, b bedrockping.Result) bool
+	// color renders formatting codes as ANSI colors
+	color bool
+	// resolved adds a column with the address that was pinged, for --all-addresses
+	resolved bool
+	results  []bedrockping.Result
+}
+
+func (p *tablePrinter) print(res bedrockping.Result) error {
+	p.results = append(p.results, res)
+	return nil
+}
+
+func (p *tablePrinter) close() error {
+	if p.less != nil {
+		sort.SliceStable(p.results, func(i, j int) bool {
+			a, b := p.results[i], p.results[j]
+			// Offline servers have nothing to sort by so always go last
+			if (a.Err == nil) != (b.Err == nil) {
+				return a.Err == nil
+			}
+			return a.Err == nil && p.less(a, b)
+		})
+	}
+
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	header := "NAME\tVERSION\tPLAYERS\tLATENCY\tADDRESS"
+	if p.resolved {
+		header += "\tRESOLVED"
+	}
+	fmt.Fprintln(tw, header)
+	// names are the plain names starting each row, escape sequences would throw off the alignment
+	names := []string{"NAME"}
+	for _, res := range p.results {
+		if res.Err != nil {
+			fmt.Fprintf(tw, "-\t-\t-\toffline\t%s%s\n", res.Address, p.resolvedColumn(res))
+			names = append(names, "-")
+			continue
+		}
+		resp := res.Response
+		name := bedrockping.StripFormatting(resp.ServerName)
+		fmt.Fprintf(tw, "%s\t%s\t%d/%d\t%s\t%s%s\n", name, resp.MCPEVersion,
+			resp.PlayerCount, resp.MaxPlayers, formatLatency(res.Latency), res.Address, p.resolvedColumn(res))
+		names = append(names, name)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if p.color {
+		// Color the names once the columns are aligned
+		lines := strings.SplitAfter(buf.String(), "\n")
+		buf.Reset()
+		for i, line := range lines {
+			if i > 0 && i < len(names) && p.results[i-1].Err == nil {
+				line = bedrockping.ANSIFormatting(p.results[i-1].Response.ServerName) + strings.TrimPrefix(line, names[i])
+			}
+			buf.WriteString(line)
+		}
+	}
+	_, err := p.w.Write(buf.Bytes())
+	return err
+}
+
+// resolvedColumn returns the cell of the RESOLVED column for res, with the tab separating it,
+// or nothing without the column.
+func (p *tablePrinter) resolvedColumn(res bedrockping.Result) string {
+	if !p.resolved {
+		return ""
+	}
+	if res.Resolved == "" {
+		return "\t-"
+	}
+	return "\t" + res.Resolved
+}
+
+// sortFunc returns the function ordering results by column, or nil to keep the order servers were given in.
+// Players are sorted most first, other columns in ascending order. A "-" prefix reverses the order.
+func sortFunc(column string) (func(a, b bedrockping.Result) bool, error) {
+	reverse := strings.HasPrefix(column, "-")
+	column = strings.TrimPrefix(column, "-")
+
+	var less func(a, b bedrockping.Result) bool
+	switch column {
+	case "":
+		return nil, nil
+	case "name":
+		less = func(a, b bedrockping.Result) bool {
+			return strings.ToLower(bedrockping.StripFormatting(a.Response.ServerName)) < strings.ToLower(bedrockping.StripFormatting(b.Response.ServerName))
+		}
+	case "version":
+		less = func(a, b bedrockping.Result) bool {
+			return compareVersions(a.Response.MCPEVersion, b.Response.MCPEVersion) < 0
+		}
+	case "players":
+		less = func(a, b bedrockping.Result) bool { return a.Response.PlayerCount > b.Response.PlayerCount }
+	case "latency":
+		less = func(a, b bedrockping.Result) bool { return a.Latency < b.Latency }
+	case "address":
+		less = func(a, b bedrockping.Result) bool { return a.Address < b.Address }
+	default:
+		return nil, fmt.Errorf("unknown sort column %q", column)
+	}
+
+	if reverse {
+		return func(a, b bedrockping.Result) bool { return less(b, a) }, nil
+	}
+	return less, nil
+}
+
+// compareVersions compares dotted version numbers numerically, so 1.9 is before 1.14.
+// Parts that aren't numbers are compared as strings.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil && an != bn:
+			if an < bn {
+				return -1
+			}
+			return 1
+		case (aErr != nil || bErr != nil) && as[i] != bs[i]:
+			return strings.Compare(as[i], bs[i])
+		}
+	}
+	return len(as) - len(bs)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+func tableResults() []bedrockping.Result {
+	result := func(name, version string, players int, latency time.Duration) bedrockping.Result {
+		return bedrockping.Result{
+			Address:  strings.ToLower(name) + ":19132",
+			Response: bedrockping.Response{ServerName: name, MCPEVersion: version, PlayerCount: players, MaxPlayers: 20},
+			Latency:  latency,
+		}
+	}
+	return []bedrockping.Result{
+		result("Beta", "1.14.60", 5, 30*time.Millisecond),
+		{Address: "down:19132", Err: errors.New("timeout")},
+		result("Alpha", "1.9.0", 12, 50*time.Millisecond),
+		result("Gamma", "1.16.0", 1, 10*time.Millisecond),
+	}
+}
+
+// tableNames prints results sorted by column and returns the first column of each row.
+func tableNames(t *testing.T, column string) []string {
+	less, err := sortFunc(column)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	p := &tablePrinter{w: &buf, less: less}
+	for _, res := range tableResults() {
+		p.print(res)
+	}
+	if err := p.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n")[1:] {
+		names = append(names, strings.Fields(line)[0])
+	}
+	return names
+}
+
+func TestTableSort(t *testing.T) {
+	tests := map[string]string{
+		"":         "Beta - Alpha Gamma",
+		"name":     "Alpha Beta Gamma -",
+		"-name":    "Gamma Beta Alpha -",
+		"players":  "Alpha Beta Gamma -",
+		"latency":  "Gamma Beta Alpha -",
+		"version":  "Alpha Beta Gamma -",
+		"-version": "Gamma Beta Alpha -",
+	}
+	for column, expect := range tests {
+		if got := strings.Join(tableNames(t, column), " "); got != expect {
+			t.Errorf("%q: got %s, expected %s", column, got, expect)
+		}
+	}
+
+	if _, err := sortFunc("colour"); err == nil {
+		t.Error("expected error for unknown column")
+	}
+}
+
+func TestTableAligned(t *testing.T) {
+	var buf bytes.Buffer
+	p := &tablePrinter{w: &buf}
+	for _, res := range tableResults() {
+		p.print(res)
+	}
+	p.close()
+
+	lines := strings.Split(buf.String(), "\n")
+	if !strings.HasPrefix(lines[0], "NAME   VERSION  PLAYERS  LATENCY  ADDRESS") {
+		t.Errorf("unexpected header: %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "Beta   1.14.60  5/20     30ms     beta:19132") {
+		t.Errorf("unexpected row: %q", lines[1])
+	}
+}
+
+func TestTableResolved(t *testing.T) {
+	results := tableResults()
+	results[0].Resolved = "10.0.0.1:19132"
+
+	var buf bytes.Buffer
+	p := &tablePrinter{w: &buf, resolved: true}
+	for _, res := range results {
+		p.print(res)
+	}
+	p.close()
+
+	lines := strings.Split(buf.String(), "\n")
+	if !strings.HasSuffix(lines[0], "ADDRESS      RESOLVED") || !strings.HasSuffix(lines[1], "beta:19132   10.0.0.1:19132") ||
+		!strings.HasSuffix(lines[2], "  -") {
+		t.Errorf("unexpected table:\n%s", buf.String())
+	}
+}
+
+func TestTableColor(t *testing.T) {
+	results := tableResults()
+	results[0].Response.ServerName = "§aBeta"
+
+	var buf bytes.Buffer
+	p := &tablePrinter{w: &buf, color: true}
+	for _, res := range results {
+		p.print(res)
+	}
+	p.close()
+
+	lines := strings.Split(buf.String(), "\n")
+	// Columns stay aligned with the escape sequences
+	if !strings.HasPrefix(lines[1], "\x1b[92mBeta\x1b[0m   1.14.60  5/20") {
+		t.Errorf("unexpected row: %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "-      -        -        offline") {
+		t.Errorf("unexpected row: %q", lines[2])
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		expect int
+	}{
+		{"1.9.0", "1.14.60", -1},
+		{"1.14.60", "1.14.60", 0},
+		{"1.16", "1.16.0", -1},
+		{"1.16.0", "1.2", 1},
+		{"1.x", "1.y", -1},
+	}
+	for _, test := range tests {
+		got := compareVersions(test.a, test.b)
+		if (got < 0) != (test.expect < 0) || (got > 0) != (test.expect > 0) {
+			t.Errorf("compareVersions(%s, %s) = %d", test.a, test.b, got)
+		}
+	}
+}
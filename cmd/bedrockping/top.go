@@ -0,0 +1,457 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+// historySize is the number of latencies kept for each server's sparkline.
+const historySize = 30
+
+// sparks are the bars of a sparkline, from the lowest latency to the highest.
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// topColumns are the columns the dashboard can be sorted by, in the order s cycles through them.
+var topColumns = []string{"name", "state", "players", "latency", "loss", "address"}
+
+// runTop runs the top subcommand, a terminal dashboard of the monitored servers updated live.
+func runTop(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bedrockping top", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: bedrockping top [flags] host[:port]...")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Keys: up/down or k/j select a server, s changes the sort column, r reverses it, q quits.")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Flags:")
+		fs.PrintDefaults()
+	}
+	file := fs.String("file", "", "read servers to monitor from `path`, one per line, CSV or JSON")
+	interval := fs.Duration("interval", 2*time.Second, "time between pings of each server")
+	timeout := fs.Duration("timeout", 5*time.Second, "maximum time to wait for a response")
+	sortBy := fs.String("sort", "", "sort by `column`: "+strings.Join(topColumns, ", ")+", prefix with - to reverse")
+	noColor := addNoColorFlag(fs)
+	transport := addTransportFlags(fs)
+
+	if err := parseArgs(fs, args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	hosts := fs.Args()
+	if *file != "" {
+		targets, err := loadTargets(*file)
+		if err != nil {
+			fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+			return 2
+		}
+		for _, t := range targets {
+			hosts = append(hosts, t.Address())
+		}
+	}
+	if len(hosts) == 0 {
+		fs.Usage()
+		return 2
+	}
+	d, err := newDashboard(*sortBy, useColor(stdout, *noColor))
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 2
+	}
+	out, ok := stdout.(*os.File)
+	if !ok || !isTerminal(stdout) || !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprintln(stderr, "bedrockping: top needs a terminal, use --watch or serve to monitor servers otherwise")
+		return 2
+	}
+
+	opts, err := transport.options()
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 2
+	}
+	m := monitor.New(bedrockping.NewClient(append([]bedrockping.Option{bedrockping.WithTimeout(*timeout)}, opts...)...))
+	for _, host := range hosts {
+		address := bedrockping.Target{Host: host}.Address()
+		if err := m.Add(monitor.Target{Address: address, Interval: *interval}); err != nil && !errors.Is(err, monitor.ErrDuplicateTarget) {
+			fmt.Fprintf(stderr, "bedrockping: %s: %v\n", address, err)
+			return 2
+		}
+		d.add(address)
+	}
+	if err := m.Start(ctx); err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 1
+	}
+	defer m.Stop()
+
+	// Raw mode delivers each key press as it's typed, without echoing it
+	state, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 1
+	}
+	defer term.Restore(int(os.Stdin.Fd()), state)
+
+	// Draw on the alternate screen, leaving the terminal as it was when done
+	fmt.Fprint(stdout, "\033[?1049h\033[?25l")
+	defer fmt.Fprint(stdout, "\033[?25h\033[?1049l")
+
+	size := func() (int, int) {
+		width, height, err := term.GetSize(int(out.Fd()))
+		if err != nil {
+			return 80, 24
+		}
+		return width, height
+	}
+	if err := d.run(ctx, m, os.Stdin, stdout, size); err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// dashboardRow is the state of a server shown by the dashboard.
+type dashboardRow struct {
+	address string
+	latest  *monitor.Update
+	loss    float64
+	// history is the latency of the latest pings, oldest first, -1 for failed pings
+	history []time.Duration
+}
+
+func (r *dashboardRow) name() string {
+	if r.latest == nil || r.latest.Response == nil {
+		return "-"
+	}
+	return bedrockping.StripFormatting(r.latest.Response.ServerName)
+}
+
+func (r *dashboardRow) state() monitor.State {
+	if r.latest == nil {
+		return monitor.StateUnknown
+	}
+	return r.latest.State
+}
+
+func (r *dashboardRow) players() int {
+	if r.latest == nil || r.latest.Response == nil {
+		return -1
+	}
+	return r.latest.Response.PlayerCount
+}
+
+func (r *dashboardRow) latency() time.Duration {
+	if r.latest == nil || !r.latest.Up {
+		// Sort offline servers after every online one
+		return time.Duration(1<<63 - 1)
+	}
+	return r.latest.Latency
+}
+
+// dashboard is the live table of servers shown by the top subcommand.
+type dashboard struct {
+	rows     []*dashboardRow
+	column   int
+	reverse  bool
+	selected string
+	color    bool
+}
+
+// newDashboard returns an empty dashboard sorted by column, like the --sort flag.
+func newDashboard(column string, color bool) (*dashboard, error) {
+	d := &dashboard{color: color}
+	d.reverse = strings.HasPrefix(column, "-")
+	column = strings.TrimPrefix(column, "-")
+	if column == "" {
+		column = "name"
+	}
+	d.column = -1
+	for i, c := range topColumns {
+		if c == column {
+			d.column = i
+		}
+	}
+	if d.column < 0 {
+		return nil, fmt.Errorf("unknown sort column %q", column)
+	}
+	return d, nil
+}
+
+// add adds a server with no status yet.
+func (d *dashboard) add(address string) {
+	if d.row(address) == nil {
+		d.rows = append(d.rows, &dashboardRow{address: address})
+	}
+	if d.selected == "" {
+		d.selected = address
+	}
+}
+
+func (d *dashboard) row(address string) *dashboardRow {
+	for _, r := range d.rows {
+		if r.address == address {
+			return r
+		}
+	}
+	return nil
+}
+
+// update records the latest ping of a server and its loss.
+func (d *dashboard) update(u monitor.Update, loss float64) {
+	d.add(u.Address)
+	r := d.row(u.Address)
+	r.latest = &u
+	r.loss = loss
+
+	latency := time.Duration(-1)
+	if u.Up {
+		latency = u.Latency
+	}
+	r.history = append(r.history, latency)
+	if len(r.history) > historySize {
+		r.history = r.history[len(r.history)-historySize:]
+	}
+}
+
+// sorted returns the rows in the order they are shown.
+func (d *dashboard) sorted() []*dashboardRow {
+	rows := append([]*dashboardRow(nil), d.rows...)
+	var less func(a, b *dashboardRow) bool
+	switch topColumns[d.column] {
+	case "name":
+		less = func(a, b *dashboardRow) bool { return strings.ToLower(a.name()) < strings.ToLower(b.name()) }
+	case "state":
+		less = func(a, b *dashboardRow) bool { return a.state() < b.state() }
+	case "players":
+		less = func(a, b *dashboardRow) bool { return a.players() > b.players() }
+	case "latency":
+		less = func(a, b *dashboardRow) bool { return a.latency() < b.latency() }
+	case "loss":
+		less = func(a, b *dashboardRow) bool { return a.loss < b.loss }
+	case "address":
+		less = func(a, b *dashboardRow) bool { return a.address < b.address }
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if d.reverse {
+			return less(rows[j], rows[i])
+		}
+		return less(rows[i], rows[j])
+	})
+	return rows
+}
+
+// key handles a key press, returning true if the dashboard should quit.
+func (d *dashboard) key(k string) bool {
+	switch k {
+	case "q", "Q", "\x03", "\x1b":
+		return true
+	case "s":
+		d.column = (d.column + 1) % len(topColumns)
+	case "r":
+		d.reverse = !d.reverse
+	case "up", "k":
+		d.move(-1)
+	case "down", "j":
+		d.move(1)
+	}
+	return false
+}
+
+// move moves the selection by n rows in the shown order.
+func (d *dashboard) move(n int) {
+	rows := d.sorted()
+	for i, r := range rows {
+		if r.address == d.selected {
+			i += n
+			if i >= 0 && i < len(rows) {
+				d.selected = rows[i].address
+			}
+			return
+		}
+	}
+}
+
+// render draws the dashboard to fit a terminal of width by height.
+func (d *dashboard) render(w io.Writer, width, height int) error {
+	rows := d.sorted()
+	nameWidth := len("NAME")
+	for _, r := range rows {
+		if n := utf8.RuneCountInString(r.name()); n > nameWidth {
+			nameWidth = n
+		}
+	}
+
+	var b strings.Builder
+	// Move to the top left and clear the screen
+	b.WriteString("\033[H\033[2J")
+	order := "ascending"
+	if d.reverse {
+		order = "descending"
+	}
+	fmt.Fprintf(&b, "bedrockping top - %d servers, sorted by %s (%s)    s sort  r reverse  q quit\r\n\r\n",
+		len(rows), topColumns[d.column], order)
+	fmt.Fprintf(&b, "%-*s  %-7s  %-9s  %-9s  %-6s  %-*s  %s\r\n", nameWidth, "NAME", "STATE", "PLAYERS", "LATENCY", "LOSS", historySize, "HISTORY", "ADDRESS")
+
+	for i, r := range rows {
+		// Leave room for the header lines
+		if height > 0 && i >= height-3 {
+			break
+		}
+		players, latency := "-", "-"
+		if r.latest != nil && r.latest.Response != nil {
+			players = fmt.Sprintf("%d/%d", r.latest.Response.PlayerCount, r.latest.Response.MaxPlayers)
+		}
+		if r.latest != nil && r.latest.Up {
+			latency = formatLatency(r.latest.Latency)
+		}
+		state := fmt.Sprintf("%-7s", r.state())
+		line := fmt.Sprintf("%-*s  %s  %-9s  %-9s  %-6s  %-*s  %s", nameWidth, r.name(), state, players, latency,
+			fmt.Sprintf("%.1f%%", r.loss), historySize, sparkline(r.history), r.address)
+		if width > 0 && utf8.RuneCountInString(line) > width {
+			line = string([]rune(line)[:width])
+		}
+
+		if d.color {
+			switch r.state() {
+			case monitor.StateUp:
+				line = strings.Replace(line, state, "\033[32m"+state+"\033[39m", 1)
+			case monitor.StateDown:
+				line = strings.Replace(line, state, "\033[31m"+state+"\033[39m", 1)
+			}
+		}
+		if r.address == d.selected {
+			// Reverse video highlights the selected row
+			line = "\033[7m" + line + "\033[0m"
+		}
+		b.WriteString(line + "\r\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// sparkline draws latencies as bars scaled between the lowest and highest, with failed pings as a space.
+func sparkline(latencies []time.Duration) string {
+	lowest, highest := time.Duration(-1), time.Duration(-1)
+	for _, l := range latencies {
+		if l < 0 {
+			continue
+		}
+		if lowest < 0 || l < lowest {
+			lowest = l
+		}
+		if l > highest {
+			highest = l
+		}
+	}
+
+	var b strings.Builder
+	for _, l := range latencies {
+		switch {
+		case l < 0:
+			b.WriteRune(' ')
+		case highest == lowest:
+			b.WriteRune(sparks[0])
+		default:
+			b.WriteRune(sparks[int(l-lowest)*(len(sparks)-1)/int(highest-lowest)])
+		}
+	}
+	return b.String()
+}
+
+// parseKeys splits input from a terminal in raw mode into key presses, naming the arrow keys "up" and "down".
+func parseKeys(input []byte) []string {
+	var keys []string
+	for len(input) > 0 {
+		switch {
+		case strings.HasPrefix(string(input), "\x1b[A"):
+			keys = append(keys, "up")
+			input = input[3:]
+		case strings.HasPrefix(string(input), "\x1b[B"):
+			keys = append(keys, "down")
+			input = input[3:]
+		case len(input) >= 3 && strings.HasPrefix(string(input), "\x1b["):
+			// Ignore other escape sequences, like the other arrow keys
+			input = input[3:]
+		default:
+			keys = append(keys, string(input[:1]))
+			input = input[1:]
+		}
+	}
+	return keys
+}
+
+// run shows the dashboard for the servers of m on out, reading key presses from in, until ctx is done or
+// the user quits. size returns the size of the terminal.
+func (d *dashboard) run(ctx context.Context, m *monitor.Monitor, in io.Reader, out io.Writer, size func() (int, int)) error {
+	updates, unsubscribe := m.Subscribe(len(d.rows) + 16)
+	defer unsubscribe()
+	for _, address := range m.Targets() {
+		if u, ok := m.Status(address); ok {
+			stats, _ := m.Stats(address)
+			d.update(u, stats.Loss)
+		}
+	}
+
+	input := make(chan []byte)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, err := in.Read(buf)
+			if n > 0 {
+				select {
+				case input <- append([]byte(nil), buf[:n]...):
+				case <-done:
+					return
+				}
+			}
+			if err != nil {
+				close(input)
+				return
+			}
+		}
+	}()
+
+	// Redraw regularly to follow changes in the size of the terminal
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		width, height := size()
+		if err := d.render(out, width, height); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case u := <-updates:
+			stats, _ := m.Stats(u.Address)
+			d.update(u, stats.Loss)
+		case data, ok := <-input:
+			if !ok {
+				return nil
+			}
+			for _, k := range parseKeys(data) {
+				if d.key(k) {
+					return nil
+				}
+			}
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+func testDashboard(t *testing.T) *dashboard {
+	d, err := newDashboard("", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	update := func(address, name string, players int, latency time.Duration) {
+		d.update(monitor.Update{
+			Address:  address,
+			Up:       true,
+			State:    monitor.StateUp,
+			Response: &bedrockping.Response{ServerName: name, PlayerCount: players, MaxPlayers: 20},
+			Latency:  latency,
+		}, 0)
+	}
+	update("b:19132", "§aBeta", 5, 30*time.Millisecond)
+	update("a:19132", "Alpha", 12, 50*time.Millisecond)
+	d.update(monitor.Update{Address: "down:19132", State: monitor.StateDown}, 100)
+	return d
+}
+
+// dashboardOrder returns the addresses of the rows in the order they are shown.
+func dashboardOrder(d *dashboard) string {
+	var addresses []string
+	for _, r := range d.sorted() {
+		addresses = append(addresses, r.address)
+	}
+	return strings.Join(addresses, " ")
+}
+
+func TestDashboardSort(t *testing.T) {
+	d := testDashboard(t)
+	if got := dashboardOrder(d); got != "down:19132 a:19132 b:19132" {
+		t.Errorf("sorted by name: %s", got)
+	}
+
+	d.key("s") // state
+	d.key("s") // players
+	if got := dashboardOrder(d); topColumns[d.column] != "players" || got != "a:19132 b:19132 down:19132" {
+		t.Errorf("sorted by %s: %s", topColumns[d.column], got)
+	}
+	d.key("s")
+	if got := dashboardOrder(d); got != "b:19132 a:19132 down:19132" {
+		t.Errorf("sorted by latency: %s", got)
+	}
+	d.key("r")
+	if got := dashboardOrder(d); got != "down:19132 a:19132 b:19132" {
+		t.Errorf("sorted by latency reversed: %s", got)
+	}
+
+	if _, err := newDashboard("colour", false); err == nil {
+		t.Error("expected error for unknown column")
+	}
+}
+
+func TestDashboardKeys(t *testing.T) {
+	d := testDashboard(t)
+	if d.selected != "b:19132" {
+		t.Fatalf("expected the first server to be selected, got %s", d.selected)
+	}
+
+	for _, k := range parseKeys([]byte("\x1b[A\x1b[Aj\x1b[C")) {
+		if d.key(k) {
+			t.Fatalf("quit on %q", k)
+		}
+	}
+	// Up twice stops at the top of the list
+	if d.selected != "a:19132" {
+		t.Errorf("selected %s", d.selected)
+	}
+	if keys := parseKeys([]byte("\x1b[B\x1b[")); strings.Join(keys, ",") != "down,\x1b,[" {
+		t.Errorf("unexpected keys: %q", keys)
+	}
+	if !d.key("q") || !d.key("\x03") {
+		t.Error("expected q and ctrl-c to quit")
+	}
+}
+
+func TestDashboardRender(t *testing.T) {
+	d := testDashboard(t)
+	d.color = true
+	d.update(monitor.Update{Address: "a:19132", State: monitor.StateUp, Up: true, Latency: 10 * time.Millisecond,
+		Response: &bedrockping.Response{ServerName: "Alpha", PlayerCount: 12, MaxPlayers: 20}}, 0)
+
+	var buf bytes.Buffer
+	if err := d.render(&buf, 200, 24); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+	for _, expect := range []string{
+		"sorted by name (ascending)",
+		"NAME   STATE    PLAYERS    LATENCY    LOSS",
+		"Alpha  \x1b[32mup     \x1b[39m  12/20      10ms       0.0%    █▁",
+		"\x1b[7mBeta   \x1b[32mup",
+		"-      \x1b[31mdown   \x1b[39m  -          -          100.0%",
+	} {
+		if !strings.Contains(output, expect) {
+			t.Errorf("missing %q in:\n%q", expect, output)
+		}
+	}
+
+	// Rows that don't fit are left out
+	buf.Reset()
+	d.render(&buf, 20, 4)
+	if n := strings.Count(buf.String(), "\r\n"); n != 4 {
+		t.Errorf("expected 4 lines, got %d:\n%q", n, buf.String())
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	ms := time.Millisecond
+	if got := sparkline([]time.Duration{10 * ms, 20 * ms, -1, 80 * ms}); got != "▁▂ █" {
+		t.Errorf("got %q", got)
+	}
+	if got := sparkline([]time.Duration{5 * ms, 5 * ms}); got != "▁▁" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestDashboardRun(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+
+	m := monitor.New(bedrockping.NewClient(bedrockping.WithTimeout(time.Second)))
+	if err := m.Add(monitor.Target{Address: address, Interval: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	updates, unsubscribe := m.Subscribe(1)
+	defer unsubscribe()
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+	<-updates
+
+	d, _ := newDashboard("", false)
+	in, keys := io.Pipe()
+	defer keys.Close()
+	var out bytes.Buffer
+	errs := make(chan error, 1)
+	go func() {
+		errs <- d.run(context.Background(), m, in, &out, func() (int, int) { return 120, 24 })
+	}()
+
+	keys.Write([]byte("q"))
+	select {
+	case err := <-errs:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("dashboard didn't quit")
+	}
+	if !strings.Contains(out.String(), "Test Server  up       3/20") {
+		t.Errorf("missing server in:\n%q", out.String())
+	}
+}
+
+func TestRunTopUsage(t *testing.T) {
+	for _, args := range [][]string{
+		{"top"},
+		{"top", "--sort", "colour", "localhost"},
+		// Tests don't run in a terminal
+		{"top", "localhost"},
+	} {
+		if code, _, _ := runCommand(t, args...); code != 2 {
+			t.Errorf("%q: exit code %d", args, code)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// runTraceroute runs the traceroute subcommand, which pings a server with an increasing TTL to find where
+// its traffic stops.
+func runTraceroute(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bedrockping traceroute", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: bedrockping traceroute [flags] host[:port]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Flags:")
+		fs.PrintDefaults()
+	}
+	maxHops := fs.Int("max-hops", 30, "highest TTL to send pings with")
+	wait := fs.Duration("wait", time.Second, "maximum time to wait for an answer at each hop")
+	timeout := fs.Duration("timeout", 5*time.Second, "maximum time to wait for the host to resolve")
+	transport := addTransportFlags(fs)
+	logs := addLogFlags(fs)
+
+	if err := parseArgs(fs, args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if err := logs.check(); err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return exitUsage
+	}
+	stderr = logs.stderr(stderr)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+	if *maxHops < 1 || *maxHops > 255 {
+		fmt.Fprintln(stderr, "bedrockping: --max-hops must be from 1 to 255")
+		return exitUsage
+	}
+
+	opts, err := transport.options()
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %v\n", err)
+		return exitUsage
+	}
+	opts = append(opts, logs.options(stderr)...)
+	client := bedrockping.NewClient(append([]bedrockping.Option{bedrockping.WithTimeout(*timeout)}, opts...)...)
+	address := bedrockping.Target{Host: fs.Arg(0)}.Address()
+	route, err := client.Traceroute(ctx, address, bedrockping.TracerouteOptions{MaxHops: *maxHops, HopTimeout: *wait})
+	if route.Resolved == "" {
+		fmt.Fprintf(stderr, "bedrockping: %s: %v\n", address, err)
+		return exitCode(err)
+	}
+
+	fmt.Fprintf(stdout, "traceroute to %s (%s), %d hops max\n", address, route.Resolved, *maxHops)
+	for _, hop := range route.Hops {
+		fmt.Fprintf(stdout, "%3d  %s\n", hop.TTL, formatHop(hop))
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "bedrockping: %s: %v\n", address, err)
+		return exitCode(err)
+	}
+
+	if route.Reached() {
+		return exitOK
+	}
+	if last, ok := route.LastResponse(); ok {
+		fmt.Fprintf(stdout, "no answer past hop %d (%s)\n", last.TTL, last.Router)
+	} else {
+		fmt.Fprintln(stdout, "no answer from any hop")
+	}
+	if last := route.Hops[len(route.Hops)-1]; last.Err != nil {
+		return exitCode(last.Err)
+	}
+	return exitTimeout
+}
+
+// formatHop returns what answered at hop, as a line of traceroute output without the TTL.
+func formatHop(hop bedrockping.Hop) string {
+	rtt := hop.RTT.Round(time.Microsecond)
+	switch {
+	case hop.Reached:
+		return fmt.Sprintf("%s  server %q", rtt, bedrockping.StripFormatting(hop.Response.ServerName))
+	case errors.Is(hop.Err, context.DeadlineExceeded):
+		return "*"
+	case hop.Err != nil && hop.Router.IsValid():
+		return fmt.Sprintf("%s  %s  %v", hop.Router, rtt, hop.Err)
+	case hop.Err != nil:
+		return fmt.Sprintf("%s  %v", rtt, hop.Err)
+	}
+	return fmt.Sprintf("%s  %s", hop.Router, rtt)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+func TestRunTraceroute(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+
+	code, stdout, stderr := runCommand(t, "traceroute", "--max-hops", "5", address)
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "traceroute to "+address) ||
+		!strings.HasPrefix(lines[1], "  1  ") || !strings.Contains(lines[1], `server "Test Server"`) {
+		t.Errorf("unexpected output %q", stdout)
+	}
+}
+
+func TestRunTraceroutePortClosed(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := conn.LocalAddr().String()
+	conn.Close()
+
+	code, stdout, _ := runCommand(t, "traceroute", address)
+	if code != exitPortClosed {
+		t.Errorf("expected exit code %d, got %d", exitPortClosed, code)
+	}
+	if !strings.Contains(stdout, "port closed") || !strings.Contains(stdout, "no answer past hop 1") {
+		t.Errorf("unexpected output %q", stdout)
+	}
+}
+
+func TestRunTracerouteUsage(t *testing.T) {
+	for _, args := range [][]string{{"traceroute"}, {"traceroute", "--max-hops", "0", "127.0.0.1"}} {
+		if code, _, _ := runCommand(t, args...); code != exitUsage {
+			t.Errorf("%q: expected exit code %d, got %d", args, exitUsage, code)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+func TestTransportFlags(t *testing.T) {
+	tests := map[string]bool{
+		"":                                     true,
+		"--proxy socks5://127.0.0.1:1080":      true,
+		"--proxy http://127.0.0.1:8080":        false,
+		"--source-ip 127.0.0.1":                true,
+		"--source-ip localhost":                false,
+		"--interface nonexistent0":             false,
+		"--proxy socks5://u:p@proxy:1080":      true,
+		"--dns-cache 30s":                      true,
+		"--dns-server 1.1.1.1 --dns-cache 30s": true,
+		"--dscp ef":                            true,
+		"--source-port 19133 --reuse-port":     true,
+		"--source-port 65536":                  false,
+		"--pin-source-port":                    true,
+		"--dscp 64":                            false,
+		"--dns-cache -1s":                      false,
+	}
+	for args, ok := range tests {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		f := addTransportFlags(fs)
+		if err := fs.Parse(strings.Fields(args)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.options(); (err == nil) != ok {
+			t.Errorf("%q: unexpected error %v", args, err)
+		}
+	}
+}
+
+func TestParseDSCP(t *testing.T) {
+	tests := map[string]uint8{"0": 0, "46": 46, "63": 63, "ef": 46, "EF": 46, "default": 0, "cs0": 0, "cs4": 32, "cs7": 56, "af11": 10, "af41": 34, "af43": 38}
+	for s, expected := range tests {
+		if dscp, err := parseDSCP(s); err != nil || dscp != expected {
+			t.Errorf("parseDSCP(%q) = %d, %v, expected %d", s, dscp, err, expected)
+		}
+	}
+	for _, s := range []string{"", "64", "-1", "cs8", "af10", "af51", "af4", "fast"} {
+		if dscp, err := parseDSCP(s); err == nil {
+			t.Errorf("parseDSCP(%q) = %d, expected an error", s, dscp)
+		}
+	}
+}
+
+func TestRunSourceIP(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+
+	if code, _, stderr := runCommand(t, "--source-ip", "127.0.0.1", address); code != 0 {
+		t.Errorf("exit code %d: %s", code, stderr)
+	}
+	if code, _, _ := runCommand(t, "--proxy", "ftp://example.com", address); code != 2 {
+		t.Errorf("exit code %d for an invalid proxy", code)
+	}
+}
+
+func TestRunAddressFamily(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+	_, port, _ := net.SplitHostPort(address)
+
+	code, stdout, stderr := runCommand(t, "-4", "localhost:"+port)
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "resolved  "+address+" (IPv4)") {
+		t.Errorf("missing resolved address:\n%s", stdout)
+	}
+
+	if code, _, _ := runCommand(t, "-6", address); code != 1 {
+		t.Errorf("exit code %d pinging IPv4 over IPv6", code)
+	}
+	if code, _, _ := runCommand(t, "-4", "-6", address); code != 2 {
+		t.Errorf("exit code %d with both families", code)
+	}
+}
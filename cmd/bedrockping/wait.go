@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// waitForUp pings every address each interval until it responds or maxWait has passed, or until ctx is done,
+// reporting each failed attempt to stderr. It returns the result of each address in order, the successful one
+// or otherwise its last failure.
+func waitForUp(ctx context.Context, client *bedrockping.Client, addresses []string, interval, maxWait time.Duration, stderr io.Writer) []bedrockping.Result {
+	start := time.Now()
+	if maxWait > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, maxWait)
+		defer cancel()
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	results := make([]bedrockping.Result, len(addresses))
+	pending := make([]int, len(addresses))
+	for i := range pending {
+		pending[i] = i
+	}
+	for attempt := 1; ; attempt++ {
+		batch := make([]string, len(pending))
+		for i, index := range pending {
+			batch[i] = addresses[index]
+		}
+
+		var still []int
+		i := 0
+		for res := range pingAll(ctx, client, batch) {
+			index := pending[i]
+			i++
+			// A ping cut short by the end of the wait says less than the failure before it
+			if res.Err == nil || ctx.Err() == nil || results[index].Err == nil {
+				results[index] = res
+			}
+			if res.Err != nil {
+				still = append(still, index)
+			}
+		}
+		pending = still
+		if len(pending) == 0 {
+			return results
+		}
+
+		for _, index := range pending {
+			if ctx.Err() != nil {
+				fmt.Fprintf(stderr, "bedrockping: gave up waiting for %s after %s: %v\n",
+					addresses[index], time.Since(start).Round(time.Second), results[index].Err)
+			} else {
+				fmt.Fprintf(stderr, "bedrockping: waiting for %s, attempt %d: %v\n", addresses[index], attempt, results[index].Err)
+			}
+		}
+		if ctx.Err() != nil {
+			return results
+		}
+
+		select {
+		case <-ctx.Done():
+			// Report the addresses still down as having been given up on
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+func TestWaitForUp(t *testing.T) {
+	// Reserve an address and only start answering on it after a while, like a server booting
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := conn.LocalAddr().String()
+	conn.Close()
+	up := pingtest.Server(t, testResponse)
+
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		conn, err := net.ListenPacket("udp", address)
+		if err != nil {
+			return
+		}
+		t.Cleanup(func() { conn.Close() })
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			// Forward the ping to a real test server and its pong back
+			relay, err := net.Dial("udp", up)
+			if err != nil {
+				return
+			}
+			relay.Write(buf[:n])
+			relay.SetReadDeadline(time.Now().Add(time.Second))
+			pong := make([]byte, 1500)
+			if m, err := relay.Read(pong); err == nil {
+				conn.WriteTo(pong[:m], addr)
+			}
+			relay.Close()
+		}
+	}()
+
+	client := bedrockping.NewClient(bedrockping.WithTimeout(50 * time.Millisecond))
+	var stderr bytes.Buffer
+	results := waitForUp(context.Background(), client, []string{up, address}, 50*time.Millisecond, 5*time.Second, &stderr)
+	if results[0].Err != nil || results[1].Err != nil || results[1].Address != address {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if !strings.Contains(stderr.String(), "waiting for "+address+", attempt 1: ") || strings.Contains(stderr.String(), up) {
+		t.Errorf("unexpected messages:\n%s", stderr.String())
+	}
+}
+
+func TestRunWaitForUp(t *testing.T) {
+	address := pingtest.Server(t, testResponse)
+	if code, stdout, stderr := runCommand(t, "--wait-for-up", address); code != exitOK || !strings.Contains(stdout, "Test Server") {
+		t.Errorf("exit code %d: %s%s", code, stdout, stderr)
+	}
+
+	down := pingtest.DeadAddress(t)
+	code, _, stderr := runCommand(t, "--wait-for-up", "--max-wait", "200ms", "--interval", "50ms", "--timeout", "30ms", down)
+	if code != exitTimeout {
+		t.Errorf("exit code %d for a server that never came up", code)
+	}
+	if !strings.Contains(stderr, "gave up waiting for "+down+" after 0s: context deadline exceeded") {
+		t.Errorf("unexpected messages:\n%s", stderr)
+	}
+
+	if code, _, _ := runCommand(t, "--wait-for-up", "--count", "3", address); code != exitUsage {
+		t.Errorf("exit code %d with --count", code)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// watch pings address every interval until ctx is done, or count pings have been sent if count is positive,
+// printing a status line after each ping and the statistics at the end, which are returned.
+// If redraw is set the status line is redrawn in place, like watch, otherwise a line is printed for each ping, like ping.
+// If color is set server names are colored.
+func watch(ctx context.Context, client *bedrockping.Client, address string, interval time.Duration, count int, redraw, color bool, stdout io.Writer) pingStats {
+	var stats pingStats
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		res := client.Ping(ctx, address)
+		if ctx.Err() != nil {
+			// Interrupted part way through, the ping doesn't count
+			break
+		}
+		stats.add(res.Latency, res.Err == nil)
+		if res.Err != nil {
+			stats.lastErr = res.Err
+		}
+
+		line := statusLine(res, &stats, color)
+		if redraw {
+			// Return to the start of the line and clear it
+			fmt.Fprint(stdout, "\r\033[K"+line)
+		} else {
+			fmt.Fprintln(stdout, line)
+		}
+		if count > 0 && stats.transmitted >= count {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+			continue
+		}
+		break
+	}
+
+	if redraw {
+		fmt.Fprintln(stdout)
+	}
+	stats.print(stdout, address)
+	return stats
+}
+
+// statusLine describes the latest ping of a watched server.
+func statusLine(res bedrockping.Result, stats *pingStats, color bool) string {
+	loss := fmt.Sprintf("loss %.1f%% (%d/%d)", stats.loss(), stats.transmitted-stats.received, stats.transmitted)
+	if res.Err != nil {
+		return fmt.Sprintf("%s  offline: %v  %s", res.Address, res.Err, loss)
+	}
+	return fmt.Sprintf("%s  %d/%d players  latency %s  %s",
+		displayName(res.Response.ServerName, color), res.Response.PlayerCount, res.Response.MaxPlayers,
+		formatLatency(res.Latency), loss)
+}
@@ -0,0 +1,130 @@
+// Package csvenc writes results as CSV, for exporting batch and scan output to spreadsheets and data pipelines.
+//
+// Every file has the same columns in the same order, see Header. Columns are only ever added to the end, so
+// readers that look columns up by position keep working.
+package csvenc
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// Header names the columns of every row, in order. The response columns are empty for offline servers,
+// and error and error_kind for online ones.
+var Header = []string{
+	"address", "online", "latency_ms", "error",
+	"game_id", "server_name", "protocol_version", "version", "player_count", "max_players",
+	"server_id", "extra", "resolved",
+	"error_kind", "attempts", "checked_at",
+}
+
+// The positions of the columns in Header.
+const (
+	colAddress = iota
+	colOnline
+	colLatency
+	colError
+	colGameID
+	colServerName
+	colProtocolVersion
+	colVersion
+	colPlayerCount
+	colMaxPlayers
+	colServerID
+	colExtra
+	colResolved
+	colErrorKind
+	colAttempts
+	colCheckedAt
+)
+
+// Row returns the columns of res. The latency is in milliseconds, the server name has its formatting codes
+// removed and the remaining payload fields are joined with ";" like in the payload.
+func Row(res bedrockping.Result) []string {
+	row := make([]string, len(Header))
+	row[colAddress] = res.Address
+	row[colOnline] = strconv.FormatBool(res.Err == nil)
+	row[colResolved] = res.Resolved
+	if res.Attempts > 0 {
+		row[colAttempts] = strconv.Itoa(res.Attempts)
+	}
+	if !res.CheckedAt.IsZero() {
+		row[colCheckedAt] = res.CheckedAt.UTC().Format(time.RFC3339Nano)
+	}
+	if res.Err != nil {
+		row[colError] = res.Err.Error()
+		row[colErrorKind] = bedrockping.ErrorKind(res.Err)
+		return row
+	}
+
+	resp := res.Response
+	row[colLatency] = strconv.FormatFloat(float64(res.Latency)/float64(time.Millisecond), 'f', 3, 64)
+	row[colGameID] = resp.GameID
+	row[colServerName] = bedrockping.StripFormatting(resp.ServerName)
+	row[colProtocolVersion] = strconv.Itoa(resp.ProtocolVersion)
+	row[colVersion] = resp.MCPEVersion
+	row[colPlayerCount] = strconv.Itoa(resp.PlayerCount)
+	row[colMaxPlayers] = strconv.Itoa(resp.MaxPlayers)
+	row[colServerID] = strconv.FormatUint(resp.ServerID, 10)
+	row[colExtra] = strings.Join(resp.Extra, ";")
+	return row
+}
+
+// Write writes results to w as a CSV file with a header row.
+func Write(w io.Writer, results []bedrockping.Result) error {
+	cw := NewWriter(w)
+	for _, res := range results {
+		if err := cw.Write(res); err != nil {
+			return err
+		}
+	}
+	return cw.Close()
+}
+
+// Writer writes results as CSV rows as they arrive, after a header row.
+type Writer struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+// NewWriter returns a Writer writing to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: csv.NewWriter(w)}
+}
+
+// Write writes res as a row, writing the header first if it hasn't been yet.
+// Rows are buffered, call Flush to write them out.
+func (w *Writer) Write(res bedrockping.Result) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	return w.w.Write(Row(res))
+}
+
+// Flush writes any buffered rows to the underlying writer.
+func (w *Writer) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// Close writes the header if no results were written, so the output is always a valid file, and flushes.
+// It doesn't close the underlying writer.
+func (w *Writer) Close() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func (w *Writer) writeHeader() error {
+	if w.wroteHeader {
+		return nil
+	}
+	w.wroteHeader = true
+	return w.w.Write(Header)
+}
@@ -0,0 +1,87 @@
+package csvenc
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+func TestWrite(t *testing.T) {
+	checkedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	results := []bedrockping.Result{
+		{
+			Address: "play.example.com:19132",
+			Response: bedrockping.Response{ServerID: 1<<64 - 1, GameID: "MCPE", ServerName: "§aMy, Server",
+				ProtocolVersion: 390, MCPEVersion: "1.14.60", PlayerCount: 3, MaxPlayers: 20, Extra: []string{"1", "world"}},
+			Latency:   23500 * time.Microsecond,
+			Attempts:  1,
+			Resolved:  "203.0.113.7:19132",
+			CheckedAt: checkedAt,
+		},
+		{Address: "down.example.com:19132", Attempts: 2, CheckedAt: checkedAt, Err: context.DeadlineExceeded},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || strings.Join(rows[0], ",") != strings.Join(Header, ",") {
+		t.Fatalf("unexpected rows %q", rows)
+	}
+
+	expect := []map[string]string{
+		{
+			"address": "play.example.com:19132", "online": "true", "latency_ms": "23.500", "error": "",
+			"game_id": "MCPE", "server_name": "My, Server", "protocol_version": "390", "version": "1.14.60",
+			"player_count": "3", "max_players": "20", "server_id": "18446744073709551615", "extra": "1;world",
+			"resolved": "203.0.113.7:19132", "error_kind": "", "attempts": "1", "checked_at": "2024-05-01T12:00:00Z",
+		},
+		{
+			"address": "down.example.com:19132", "online": "false", "latency_ms": "", "error": "context deadline exceeded",
+			"game_id": "", "server_name": "", "player_count": "", "error_kind": bedrockping.ErrorKindTimeout,
+			"attempts": "2", "checked_at": "2024-05-01T12:00:00Z",
+		},
+	}
+	for i, want := range expect {
+		for j, column := range Header {
+			if value, ok := want[column]; ok && rows[i+1][j] != value {
+				t.Errorf("row %d %s: got %q, expected %q", i+1, column, rows[i+1][j], value)
+			}
+		}
+	}
+}
+
+func TestWriteEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), strings.Join(Header, ",")+"\n"; got != want {
+		t.Errorf("got %q, expected %q", got, want)
+	}
+}
+
+func TestWriterHeaderOnce(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for range 2 {
+		if err := w.Write(bedrockping.Result{Address: "a:1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), "address,online"); n != 1 {
+		t.Errorf("expected 1 header, got %d", n)
+	}
+}
@@ -0,0 +1,158 @@
+package bedrockping
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"strconv"
+	"time"
+)
+
+const defaultDiscoverInterval = time.Second
+
+// DiscoverOptions configures Discover.
+// Zero values are replaced with sensible defaults.
+type DiscoverOptions struct {
+	// Ports are the ports pings are broadcast to, the default is DefaultPort.
+	Ports []int
+	// Interval is how often the broadcast is repeated, so servers that miss one are found by the next.
+	// The default is 1 second.
+	Interval time.Duration
+	// Addresses, if set, overrides the IP addresses pinged, which are by default the limited broadcast
+	// address 255.255.255.255 and the directed broadcast address of every IPv4 interface.
+	Addresses []string
+}
+
+// Discover finds servers on the local network the way the game's Friends tab lists LAN games,
+// by repeatedly broadcasting pings and listening for the pongs.
+// Each server is reported once, the first time it responds, on the returned channel, which is
+// closed once ctx is done. The channel must be drained or ctx cancelled.
+func Discover(ctx context.Context, opts DiscoverOptions) (<-chan Result, error) {
+	ports := opts.Ports
+	if len(ports) == 0 {
+		ports = []int{DefaultPort}
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = defaultDiscoverInterval
+	}
+	hosts := opts.Addresses
+	if len(hosts) == 0 {
+		hosts = broadcastAddrs()
+	}
+
+	var targets []*net.UDPAddr
+	for _, host := range hosts {
+		for _, port := range ports {
+			addr, err := net.ResolveUDPAddr("udp4", net.JoinHostPort(host, strconv.Itoa(port)))
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, addr)
+		}
+	}
+
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return nil, err
+	}
+
+	epoch := time.Now()
+	results := make(chan Result)
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+	go func() {
+		defer close(results)
+
+		seen := make(map[string]bool)
+		buf := make([]byte, maxPacketSize)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			var resp Response
+			if err := ReadUnconnectedPong(bufio.NewReader(bytes.NewReader(buf[:n])), &resp); err != nil {
+				continue
+			}
+			address := addr.String()
+			if seen[address] {
+				continue
+			}
+			seen[address] = true
+
+			res := Result{Address: address, Response: resp, Attempts: 1}
+			// Broadcasts are sent periodically, so the echoed timestamp identifies which one was answered
+			if rtt := time.Since(epoch.Add(time.Duration(resp.Timestamp) * time.Microsecond)); rtt > 0 && rtt < time.Since(epoch) {
+				res.Latency = rtt
+			}
+			select {
+			case results <- res:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		ping := new(bytes.Buffer)
+		for {
+			ping.Reset()
+			WriteUnconnectedPing(ping, uint64(time.Since(epoch)/time.Microsecond))
+			for _, addr := range targets {
+				// Some interfaces may refuse broadcasts, the others are still worth trying
+				conn.WriteTo(ping.Bytes(), addr)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results, nil
+}
+
+// broadcastAddrs returns the limited broadcast address and the directed broadcast address of every IPv4
+// interface that supports broadcasting.
+func broadcastAddrs() []string {
+	addrs := []string{net.IPv4bcast.String()}
+
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return addrs
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagBroadcast == 0 {
+			continue
+		}
+		ifaceAddrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, a := range ifaceAddrs {
+			ipNet, ok := a.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			ip := ipNet.IP.To4()
+			if ip == nil || len(ipNet.Mask) != net.IPv4len {
+				continue
+			}
+			bcast := make(net.IP, net.IPv4len)
+			for i := range ip {
+				bcast[i] = ip[i] | ^ipNet.Mask[i]
+			}
+			addrs = append(addrs, bcast.String())
+		}
+	}
+	return addrs
+}
@@ -0,0 +1,41 @@
+package bedrockping
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDiscover(t *testing.T) {
+	address := startTestServer(t, testResponse("Echo"))
+	host, portStr, _ := net.SplitHostPort(address)
+	port, _ := strconv.Atoi(portStr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	results, err := Discover(ctx, DiscoverOptions{Ports: []int{port}, Interval: 20 * time.Millisecond, Addresses: []string{host}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found []Result
+	for res := range results {
+		found = append(found, res)
+	}
+	// The server answers every broadcast but is only reported once
+	if len(found) != 1 {
+		t.Fatalf("expected 1 server, got %d", len(found))
+	}
+	if found[0].Address != address || found[0].Response.ServerName != "Echo" || found[0].Latency <= 0 {
+		t.Errorf("unexpected result: %+v", found[0])
+	}
+}
+
+func TestBroadcastAddrs(t *testing.T) {
+	addrs := broadcastAddrs()
+	if len(addrs) == 0 || addrs[0] != "255.255.255.255" {
+		t.Errorf("unexpected addresses: %v", addrs)
+	}
+}
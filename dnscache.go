@@ -0,0 +1,207 @@
+package bedrockping
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// TTLResolver is a Resolver that also reports how long the records it returns may be cached,
+// the lowest TTL of the records. WithDNSCacheTTL keeps results for their TTL if the resolver implements it,
+// as DNSResolver does.
+type TTLResolver interface {
+	Resolver
+	LookupNetIPTTL(ctx context.Context, network, host string) ([]netip.Addr, time.Duration, error)
+	LookupSRVTTL(ctx context.Context, service, proto, name string) (string, []*net.SRV, time.Duration, error)
+}
+
+// WithDNSCacheTTL makes the client cache the addresses and SRV records hosts resolve to for at most ttl, so
+// monitors pinging servers every few seconds don't look them up each time. Records are kept for their own TTL,
+// down to the minimum of WithDNSCacheMinTTL, if the resolver reports it by implementing TTLResolver like
+// DNSResolver does. *net.Resolver, the default, doesn't report the TTLs of records, so they are kept for ttl.
+// Hosts that don't exist are cached for ttl too, other failures aren't. Concurrent lookups of the same record
+// share one query to the resolver. A ttl of 0 or less disables the cache, which is the default.
+func WithDNSCacheTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.dnsCacheTTL = ttl
+	}
+}
+
+// WithDNSCacheMinTTL sets the shortest time records are cached for when the cache is enabled by WithDNSCacheTTL,
+// so a resolver reporting TTLs of 0 or a few seconds isn't queried for nearly every ping. It's capped at the
+// cache's TTL, and is 0 by default, keeping records for exactly their own TTL.
+func WithDNSCacheMinTTL(minTTL time.Duration) Option {
+	return func(c *Client) {
+		c.dnsCacheMinTTL = minTTL
+	}
+}
+
+// cachingResolver is a Resolver caching the results of another, see WithDNSCacheTTL.
+type cachingResolver struct {
+	resolver Resolver
+	// minTTL and ttl clamp how long records are cached for
+	minTTL, ttl time.Duration
+	// timeout bounds the lookups shared by callers, which aren't bound by any one caller's context
+	timeout time.Duration
+
+	mu      sync.Mutex
+	entries map[dnsCacheKey]*dnsCacheEntry
+	// lookups are the lookups in flight, which callers missing the cache for the same key wait for
+	lookups map[dnsCacheKey]*dnsLookup
+	// swept is the number of entries after expired ones were last removed
+	swept int
+}
+
+type dnsCacheKey struct {
+	// kind is the network of an IP lookup, or "srv"
+	kind, name string
+}
+
+type dnsCacheEntry struct {
+	addrs   []netip.Addr
+	cname   string
+	srvs    []*net.SRV
+	err     error
+	expires time.Time
+}
+
+// dnsLookup is a lookup shared by concurrent callers missing the cache for a key.
+type dnsLookup struct {
+	done  chan struct{}
+	entry *dnsCacheEntry
+}
+
+func newCachingResolver(resolver Resolver, minTTL, ttl, timeout time.Duration) *cachingResolver {
+	return &cachingResolver{
+		resolver: resolver,
+		minTTL:   minTTL,
+		ttl:      ttl,
+		timeout:  timeout,
+		entries:  make(map[dnsCacheKey]*dnsCacheEntry),
+		lookups:  make(map[dnsCacheKey]*dnsLookup),
+	}
+}
+
+func (r *cachingResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	e, err := r.lookup(ctx, dnsCacheKey{network, host}, func(ctx context.Context) (*dnsCacheEntry, time.Duration) {
+		var addrs []netip.Addr
+		ttl := r.ttl
+		var err error
+		if tr, ok := r.resolver.(TTLResolver); ok {
+			addrs, ttl, err = tr.LookupNetIPTTL(ctx, network, host)
+		} else {
+			addrs, err = r.resolver.LookupNetIP(ctx, network, host)
+		}
+		return &dnsCacheEntry{addrs: addrs, err: err}, ttl
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]netip.Addr(nil), e.addrs...), e.err
+}
+
+func (r *cachingResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	key := dnsCacheKey{"srv", "_" + service + "._" + proto + "." + name}
+	e, err := r.lookup(ctx, key, func(ctx context.Context) (*dnsCacheEntry, time.Duration) {
+		var cname string
+		var srvs []*net.SRV
+		ttl := r.ttl
+		var err error
+		if tr, ok := r.resolver.(TTLResolver); ok {
+			cname, srvs, ttl, err = tr.LookupSRVTTL(ctx, service, proto, name)
+		} else {
+			cname, srvs, err = r.resolver.LookupSRV(ctx, service, proto, name)
+		}
+		return &dnsCacheEntry{cname: cname, srvs: srvs, err: err}, ttl
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return e.cname, copySRVs(e.srvs), e.err
+}
+
+// lookup returns the entry for key if it hasn't expired, or else looks it up with resolve, which returns the
+// entry and how long it may be cached. Callers missing the cache while a lookup of key is in flight wait for it
+// rather than starting their own. The lookup isn't cancelled with the caller that started it, as others may be
+// waiting for it, a caller whose context is done first returns early with its error. It's bound by the resolver's
+// timeout instead, so a hung resolver doesn't hold up every lookup of key forever. The entries returned are
+// shared, so callers copy the records in them.
+func (r *cachingResolver) lookup(ctx context.Context, key dnsCacheKey, resolve func(context.Context) (*dnsCacheEntry, time.Duration)) (*dnsCacheEntry, error) {
+	r.mu.Lock()
+	if e, ok := r.entries[key]; ok && time.Now().Before(e.expires) {
+		r.mu.Unlock()
+		return e, nil
+	}
+	l, ok := r.lookups[key]
+	if !ok {
+		l = &dnsLookup{done: make(chan struct{})}
+		r.lookups[key] = l
+		go func() {
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.timeout)
+			defer cancel()
+			e, ttl := resolve(ctx)
+			// Copy the records so changes made to them by the caller don't reach the cache
+			e.addrs = append([]netip.Addr(nil), e.addrs...)
+			e.srvs = copySRVs(e.srvs)
+			l.entry = e
+
+			r.mu.Lock()
+			delete(r.lookups, key)
+			r.put(key, e, ttl)
+			r.mu.Unlock()
+			close(l.done)
+		}()
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-l.done:
+		return l.entry, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// put caches e for ttl, clamped to between the cache's minimum TTL and its TTL, unless it holds an error other
+// than the host not existing. r.mu must be held.
+func (r *cachingResolver) put(key dnsCacheKey, e *dnsCacheEntry, ttl time.Duration) {
+	var dnsErr *net.DNSError
+	if e.err != nil && !(errors.As(e.err, &dnsErr) && dnsErr.IsNotFound) {
+		return
+	}
+	if e.err != nil {
+		ttl = r.ttl
+	}
+	ttl = min(max(ttl, r.minTTL), r.ttl)
+	if ttl <= 0 {
+		return
+	}
+	now := time.Now()
+	e.expires = now.Add(ttl)
+
+	r.entries[key] = e
+	// Remove expired entries once the cache has doubled in size, so hosts no longer pinged don't pile up
+	if len(r.entries) >= 2*max(r.swept, 64) {
+		for key, e := range r.entries {
+			if !now.Before(e.expires) {
+				delete(r.entries, key)
+			}
+		}
+		r.swept = len(r.entries)
+	}
+}
+
+func copySRVs(srvs []*net.SRV) []*net.SRV {
+	if srvs == nil {
+		return nil
+	}
+	copied := make([]*net.SRV, len(srvs))
+	for i, srv := range srvs {
+		s := *srv
+		copied[i] = &s
+	}
+	return copied
+}
@@ -0,0 +1,236 @@
+package bedrockping
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingResolver counts the lookups made of a staticResolver, reporting ttl if it's set.
+type countingResolver struct {
+	staticResolver
+	lookups atomic.Int32
+	ttl     time.Duration
+	err     error
+}
+
+func (r *countingResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	r.lookups.Add(1)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.staticResolver.LookupNetIP(ctx, network, host)
+}
+
+func (r *countingResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	r.lookups.Add(1)
+	return "", []*net.SRV{{Target: "srv.example.com.", Port: 19133}}, nil
+}
+
+// ttlResolver is a countingResolver implementing TTLResolver.
+type ttlResolver struct {
+	*countingResolver
+}
+
+func (r ttlResolver) LookupNetIPTTL(ctx context.Context, network, host string) ([]netip.Addr, time.Duration, error) {
+	addrs, err := r.LookupNetIP(ctx, network, host)
+	return addrs, r.ttl, err
+}
+
+func (r ttlResolver) LookupSRVTTL(ctx context.Context, service, proto, name string) (string, []*net.SRV, time.Duration, error) {
+	cname, srvs, err := r.LookupSRV(ctx, service, proto, name)
+	return cname, srvs, r.ttl, err
+}
+
+func TestDNSCache(t *testing.T) {
+	resolver := &countingResolver{staticResolver: staticResolver{"play.example.com": {netip.MustParseAddr("127.0.0.1")}}}
+	c := NewClient(WithResolver(resolver), WithDNSCacheTTL(time.Hour))
+
+	for range 3 {
+		if resolved, err := c.Resolve(context.Background(), "play.example.com:19132"); err != nil || resolved != "127.0.0.1:19132" {
+			t.Fatalf("unexpected resolution %s %v", resolved, err)
+		}
+	}
+	var dnsErr *net.DNSError
+	for range 2 {
+		if _, err := c.Resolve(context.Background(), "missing.example.com:19132"); !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+			t.Fatalf("expected a not found error, got %v", err)
+		}
+	}
+	if n := resolver.lookups.Load(); n != 2 {
+		t.Errorf("expected each host to be looked up once, got %d lookups", n)
+	}
+
+	// The cache is per client and disabled by default
+	c = NewClient(WithResolver(resolver))
+	c.Resolve(context.Background(), "play.example.com:19132")
+	c.Resolve(context.Background(), "play.example.com:19132")
+	if n := resolver.lookups.Load(); n != 4 {
+		t.Errorf("expected no caching without WithDNSCacheTTL, got %d lookups", n)
+	}
+}
+
+func TestDNSCacheTTL(t *testing.T) {
+	resolver := &countingResolver{
+		staticResolver: staticResolver{"play.example.com": {netip.MustParseAddr("127.0.0.1")}},
+		ttl:            time.Hour,
+	}
+	r := newCachingResolver(ttlResolver{resolver}, 0, 200*time.Millisecond, time.Second)
+
+	for range 2 {
+		r.LookupNetIP(context.Background(), "ip", "play.example.com")
+		r.LookupSRV(context.Background(), "minecraft", "udp", "play.example.com")
+	}
+	if n := resolver.lookups.Load(); n != 2 {
+		t.Fatalf("expected a lookup of each record, got %d", n)
+	}
+	// The TTL of an hour is capped at the cache's
+	time.Sleep(250 * time.Millisecond)
+	r.LookupNetIP(context.Background(), "ip", "play.example.com")
+	if n := resolver.lookups.Load(); n != 3 {
+		t.Errorf("expected the record to expire after the cache's TTL, got %d lookups", n)
+	}
+
+	// A TTL of 0 means the record isn't cached at all
+	resolver.ttl = 0
+	r = newCachingResolver(ttlResolver{resolver}, 0, time.Hour, time.Second)
+	r.LookupNetIP(context.Background(), "ip", "play.example.com")
+	r.LookupNetIP(context.Background(), "ip", "play.example.com")
+	if n := resolver.lookups.Load(); n != 5 {
+		t.Errorf("expected records with a TTL of 0 not to be cached, got %d lookups", n)
+	}
+
+	// Unless the cache has a minimum TTL, which records with a TTL of 0 are kept for
+	r = newCachingResolver(ttlResolver{resolver}, 200*time.Millisecond, time.Hour, time.Second)
+	r.LookupNetIP(context.Background(), "ip", "play.example.com")
+	r.LookupNetIP(context.Background(), "ip", "play.example.com")
+	if n := resolver.lookups.Load(); n != 6 {
+		t.Errorf("expected records with a TTL of 0 to be kept for the minimum TTL, got %d lookups", n)
+	}
+	time.Sleep(250 * time.Millisecond)
+	r.LookupNetIP(context.Background(), "ip", "play.example.com")
+	if n := resolver.lookups.Load(); n != 7 {
+		t.Errorf("expected the record to expire after the minimum TTL, got %d lookups", n)
+	}
+}
+
+// blockingResolver is a countingResolver whose lookups wait for release to be closed.
+type blockingResolver struct {
+	*countingResolver
+	release chan struct{}
+}
+
+func (r blockingResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	<-r.release
+	return r.countingResolver.LookupNetIP(ctx, network, host)
+}
+
+func TestDNSCacheShared(t *testing.T) {
+	resolver := blockingResolver{
+		countingResolver: &countingResolver{staticResolver: staticResolver{"play.example.com": {netip.MustParseAddr("127.0.0.1")}}},
+		release:          make(chan struct{}),
+	}
+	r := newCachingResolver(resolver, 0, time.Hour, time.Second)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if addrs, err := r.LookupNetIP(context.Background(), "ip", "play.example.com"); err != nil || len(addrs) != 1 {
+				t.Errorf("unexpected lookup result %v %v", addrs, err)
+			}
+		}()
+	}
+
+	// A caller giving up doesn't cancel the lookup the others are waiting for
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := r.LookupNetIP(ctx, "ip", "play.example.com"); err != context.DeadlineExceeded {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	close(resolver.release)
+	wg.Wait()
+	if n := resolver.lookups.Load(); n != 1 {
+		t.Errorf("expected concurrent misses to share one lookup, got %d lookups", n)
+	}
+}
+
+// hangingResolver is a countingResolver whose lookups don't return until their context is done.
+type hangingResolver struct {
+	*countingResolver
+}
+
+func (r hangingResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	r.lookups.Add(1)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestDNSCacheLookupTimeout(t *testing.T) {
+	resolver := hangingResolver{&countingResolver{}}
+	r := newCachingResolver(resolver, 0, time.Hour, 50*time.Millisecond)
+
+	// The shared lookup gives up after the resolver's timeout, even though the caller would wait longer
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := r.LookupNetIP(ctx, "ip", "play.example.com"); err != context.DeadlineExceeded {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if ctx.Err() != nil {
+		t.Error("expected the lookup to time out before the caller's context")
+	}
+	// The failure isn't cached and the next caller makes a lookup of its own
+	r.LookupNetIP(context.Background(), "ip", "play.example.com")
+	if n := resolver.lookups.Load(); n != 2 {
+		t.Errorf("expected a second lookup after the first timed out, got %d", n)
+	}
+}
+
+func TestDNSCacheErrors(t *testing.T) {
+	resolver := &countingResolver{err: &net.DNSError{Err: "server misbehaving", Name: "play.example.com", IsTemporary: true}}
+	r := newCachingResolver(resolver, 0, time.Hour, time.Second)
+	r.LookupNetIP(context.Background(), "ip", "play.example.com")
+	r.LookupNetIP(context.Background(), "ip", "play.example.com")
+	if n := resolver.lookups.Load(); n != 2 {
+		t.Errorf("expected temporary failures not to be cached, got %d lookups", n)
+	}
+}
+
+func TestDNSCacheCopies(t *testing.T) {
+	resolver := &countingResolver{staticResolver: staticResolver{"play.example.com": {netip.MustParseAddr("127.0.0.1")}}}
+	r := newCachingResolver(resolver, 0, time.Hour, time.Second)
+
+	addrs, _ := r.LookupNetIP(context.Background(), "ip", "play.example.com")
+	addrs[0] = netip.MustParseAddr("10.0.0.1")
+	_, srvs, _ := r.LookupSRV(context.Background(), "minecraft", "udp", "play.example.com")
+	srvs[0].Port = 1
+
+	addrs, _ = r.LookupNetIP(context.Background(), "ip", "play.example.com")
+	_, srvs, _ = r.LookupSRV(context.Background(), "minecraft", "udp", "play.example.com")
+	if addrs[0] != netip.MustParseAddr("127.0.0.1") || srvs[0].Port != 19133 {
+		t.Errorf("expected cached records to be unaffected by changes to those returned, got %v %+v", addrs, srvs[0])
+	}
+}
+
+func TestDNSCacheSweep(t *testing.T) {
+	resolver := &countingResolver{staticResolver: staticResolver{}}
+	r := newCachingResolver(resolver, 0, time.Millisecond, time.Second)
+	for i := range 100 {
+		r.LookupNetIP(context.Background(), "ip", netip.AddrFrom4([4]byte{10, 0, 0, byte(i)}).String())
+	}
+	time.Sleep(2 * time.Millisecond)
+	for i := range 100 {
+		r.LookupNetIP(context.Background(), "ip", netip.AddrFrom4([4]byte{10, 0, 1, byte(i)}).String())
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) >= 200 {
+		t.Errorf("expected expired entries to be removed, got %d", len(r.entries))
+	}
+}
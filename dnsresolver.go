@@ -0,0 +1,270 @@
+package bedrockping
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// DNSResolver is a TTLResolver querying a DNS server directly, so WithDNSCacheTTL keeps records for as long as
+// their TTL allows. Queries are sent over UDP, and again over TCP if the answer doesn't fit. Unlike
+// net.DefaultResolver it doesn't read the hosts file or apply search domains, so hosts must be fully qualified.
+//
+//	client := bedrockping.NewClient(
+//		bedrockping.WithResolver(&bedrockping.DNSResolver{Server: "10.0.0.53:53"}),
+//		bedrockping.WithDNSCacheTTL(5*time.Minute),
+//	)
+type DNSResolver struct {
+	// Server is the address of the DNS server, port 53 is used if it has none.
+	Server string
+}
+
+func (r *DNSResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	addrs, _, err := r.LookupNetIPTTL(ctx, network, host)
+	return addrs, err
+}
+
+func (r *DNSResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	cname, srvs, _, err := r.LookupSRVTTL(ctx, service, proto, name)
+	return cname, srvs, err
+}
+
+// LookupNetIPTTL returns the IP addresses of host and the lowest TTL of the records they came from, network
+// being "ip", "ip4" or "ip6". Both A and AAAA records are looked up for "ip".
+func (r *DNSResolver) LookupNetIPTTL(ctx context.Context, network, host string) ([]netip.Addr, time.Duration, error) {
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return []netip.Addr{ip}, 0, nil
+	}
+
+	var types []dnsmessage.Type
+	switch network {
+	case "ip":
+		types = []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA}
+	case "ip4":
+		types = []dnsmessage.Type{dnsmessage.TypeA}
+	case "ip6":
+		types = []dnsmessage.Type{dnsmessage.TypeAAAA}
+	default:
+		return nil, 0, net.UnknownNetworkError(network)
+	}
+
+	var addrs []netip.Addr
+	ttl := time.Duration(math.MaxInt64)
+	var lastErr error
+	for _, typ := range types {
+		answers, answerTTL, err := r.query(ctx, host, typ)
+		if err != nil {
+			// A host with only IPv4 addresses has no AAAA records, which isn't an error if it has A records,
+			// and a failure to look up either is worth more than that
+			var dnsErr *net.DNSError
+			if lastErr == nil || !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+				lastErr = err
+			}
+			continue
+		}
+		for _, answer := range answers {
+			switch body := answer.Body.(type) {
+			case *dnsmessage.AResource:
+				addrs = append(addrs, netip.AddrFrom4(body.A))
+			case *dnsmessage.AAAAResource:
+				addrs = append(addrs, netip.AddrFrom16(body.AAAA))
+			}
+		}
+		ttl = min(ttl, answerTTL)
+	}
+	if len(addrs) == 0 {
+		if lastErr == nil {
+			lastErr = r.notFound(host)
+		}
+		return nil, 0, lastErr
+	}
+	return addrs, ttl, nil
+}
+
+// LookupSRVTTL returns the SRV records of _{service}._{proto}.{name}, ordered by priority and randomized by
+// weight, and the lowest TTL of the records. cname is the name the records were found at.
+func (r *DNSResolver) LookupSRVTTL(ctx context.Context, service, proto, name string) (string, []*net.SRV, time.Duration, error) {
+	target := "_" + service + "._" + proto + "." + name
+	answers, ttl, err := r.query(ctx, target, dnsmessage.TypeSRV)
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	cname := dnsName(target)
+	var srvs []*net.SRV
+	for _, answer := range answers {
+		switch body := answer.Body.(type) {
+		case *dnsmessage.CNAMEResource:
+			cname = body.CNAME.String()
+		case *dnsmessage.SRVResource:
+			srvs = append(srvs, &net.SRV{Target: body.Target.String(), Port: body.Port, Priority: body.Priority, Weight: body.Weight})
+		}
+	}
+	if len(srvs) == 0 {
+		return "", nil, 0, r.notFound(target)
+	}
+	return cname, orderSRVs(srvs), ttl, nil
+}
+
+// query looks up the records of type typ for name, returning the answers and their lowest TTL.
+func (r *DNSResolver) query(ctx context.Context, name string, typ dnsmessage.Type) ([]dnsmessage.Resource, time.Duration, error) {
+	qname, err := dnsmessage.NewName(dnsName(name))
+	if err != nil {
+		return nil, 0, &net.DNSError{Err: "invalid host name", Name: name}
+	}
+	query := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: uint16(rand.Uint32()), RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: qname, Type: typ, Class: dnsmessage.ClassINET}},
+	}
+	packet, err := query.Pack()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	reply, err := r.exchange(ctx, "udp", packet, query.ID)
+	if err == nil && reply.Truncated {
+		reply, err = r.exchange(ctx, "tcp", packet, query.ID)
+	}
+	if err != nil {
+		return nil, 0, &net.DNSError{Err: err.Error(), Name: name, Server: r.server(), IsTimeout: isTimeout(err), IsTemporary: true}
+	}
+
+	switch reply.RCode {
+	case dnsmessage.RCodeSuccess:
+	case dnsmessage.RCodeNameError:
+		return nil, 0, r.notFound(name)
+	default:
+		return nil, 0, &net.DNSError{Err: "server misbehaving", Name: name, Server: r.server(), IsTemporary: true}
+	}
+
+	var answers []dnsmessage.Resource
+	ttl := time.Duration(math.MaxInt64)
+	for _, answer := range reply.Answers {
+		if answer.Header.Class != dnsmessage.ClassINET {
+			continue
+		}
+		answers = append(answers, answer)
+		ttl = min(ttl, time.Duration(answer.Header.TTL)*time.Second)
+	}
+	if len(answers) == 0 {
+		return nil, 0, r.notFound(name)
+	}
+	return answers, ttl, nil
+}
+
+// exchange sends the query packet with id to the server over network and returns its reply.
+func (r *DNSResolver) exchange(ctx context.Context, network string, packet []byte, id uint16) (*dnsmessage.Message, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, r.server())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	if network == "tcp" {
+		// Messages over TCP are prefixed with their length
+		packet = append(binary.BigEndian.AppendUint16(nil, uint16(len(packet))), packet...)
+	}
+	if _, err := conn.Write(packet); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 65535)
+	for {
+		var n int
+		if network == "tcp" {
+			if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+				return nil, err
+			}
+			n, err = io.ReadFull(conn, buf[:binary.BigEndian.Uint16(buf[:2])])
+		} else {
+			n, err = conn.Read(buf)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+
+		var reply dnsmessage.Message
+		// Ignore replies to other queries, such as late answers to an earlier one from the same port
+		if reply.Unpack(buf[:n]) != nil || !reply.Response || reply.ID != id {
+			if network == "tcp" {
+				return nil, errors.New("invalid reply")
+			}
+			continue
+		}
+		return &reply, nil
+	}
+}
+
+func (r *DNSResolver) server() string {
+	if _, _, err := net.SplitHostPort(r.Server); err != nil {
+		return net.JoinHostPort(r.Server, "53")
+	}
+	return r.Server
+}
+
+func (r *DNSResolver) notFound(name string) error {
+	return &net.DNSError{Err: "no such host", Name: name, Server: r.server(), IsNotFound: true}
+}
+
+// dnsName returns name fully qualified, with a trailing dot.
+func dnsName(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// orderSRVs sorts srvs by priority, shuffling records of the same priority by weight as described in RFC 2782.
+func orderSRVs(srvs []*net.SRV) []*net.SRV {
+	slices.SortStableFunc(srvs, func(a, b *net.SRV) int { return int(a.Priority) - int(b.Priority) })
+	for start := 0; start < len(srvs); {
+		end := start + 1
+		for end < len(srvs) && srvs[end].Priority == srvs[start].Priority {
+			end++
+		}
+		group := srvs[start:end]
+		for i := range group {
+			total := 0
+			for _, srv := range group[i:] {
+				total += int(srv.Weight)
+			}
+			if total == 0 {
+				break
+			}
+			pick := rand.IntN(total)
+			for j, srv := range group[i:] {
+				pick -= int(srv.Weight)
+				if pick < 0 {
+					group[i], group[i+j] = group[i+j], group[i]
+					break
+				}
+			}
+		}
+		start = end
+	}
+	return srvs
+}
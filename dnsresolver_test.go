@@ -0,0 +1,62 @@
+package bedrockping
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestDNSResolver(t *testing.T) {
+	zone := testZone{
+		a:   map[string]netip.Addr{"play.example.com": netip.MustParseAddr("127.0.0.2")},
+		srv: map[string][]net.SRV{"_minecraft._udp.play.example.com": {{Target: "srv.example.com.", Port: 19133}}},
+		ttl: 30,
+	}
+	for _, truncate := range []bool{false, true} {
+		zone.truncate = truncate
+		r := &DNSResolver{Server: serveDNS(t, zone)}
+		ctx := context.Background()
+
+		addrs, ttl, err := r.LookupNetIPTTL(ctx, "ip", "play.example.com")
+		if err != nil || len(addrs) != 1 || addrs[0] != netip.MustParseAddr("127.0.0.2") || ttl != 30*time.Second {
+			t.Errorf("truncate %t: unexpected addresses %v, TTL %s, %v", truncate, addrs, ttl, err)
+		}
+
+		cname, srvs, ttl, err := r.LookupSRVTTL(ctx, DefaultSRVService, "udp", "play.example.com")
+		if err != nil || cname != "_minecraft._udp.play.example.com." || len(srvs) != 1 || srvs[0].Port != 19133 || ttl != 30*time.Second {
+			t.Errorf("truncate %t: unexpected SRV records %q %v, TTL %s, %v", truncate, cname, srvs, ttl, err)
+		}
+
+		var dnsErr *net.DNSError
+		if _, err := r.LookupNetIP(ctx, "ip", "missing.example.com"); !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+			t.Errorf("truncate %t: expected a not found error, got %v", truncate, err)
+		}
+	}
+}
+
+func TestDNSResolverCache(t *testing.T) {
+	address := startTestServer(t, testResponse("Cached"))
+	_, port, _ := net.SplitHostPort(address)
+	zone := testZone{a: map[string]netip.Addr{"play.example.com": netip.MustParseAddr("127.0.0.1")}, ttl: 1}
+
+	// The record's TTL of a second is shorter than the cache's, and longer than its minimum
+	c := NewClient(WithResolver(&DNSResolver{Server: serveDNS(t, zone)}), WithNetwork("udp4"), WithTimeout(time.Second),
+		WithDNSCacheTTL(time.Hour), WithDNSCacheMinTTL(100*time.Millisecond))
+	r := c.resolver.(*cachingResolver)
+	if res := c.Ping(context.Background(), "play.example.com:"+port); res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.entries[dnsCacheKey{"ip4", "play.example.com"}]
+	if !ok {
+		t.Fatalf("expected the record to be cached, got %v", r.entries)
+	}
+	if expires := time.Until(e.expires); expires <= 0 || expires > time.Second {
+		t.Errorf("expected the record to be cached for its TTL, expires in %s", expires)
+	}
+}
@@ -0,0 +1,105 @@
+package bedrockping
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// EnrichmentReverseDNS is the key of the hostname found by ReverseDNS in Result.Enrichment.
+const EnrichmentReverseDNS = "rdns"
+
+// Enricher adds information about a server to the result of a successful ping, such as its reverse DNS name,
+// its network's ASN or tags from an inventory. The values returned are added to Result.Enrichment.
+type Enricher interface {
+	Enrich(ctx context.Context, res Result) (map[string]string, error)
+}
+
+// EnricherFunc is a function used as an Enricher.
+type EnricherFunc func(ctx context.Context, res Result) (map[string]string, error)
+
+func (f EnricherFunc) Enrich(ctx context.Context, res Result) (map[string]string, error) {
+	return f(ctx, res)
+}
+
+// WithEnrichers runs enrichers in order after each successful ping, adding their values to the Result.
+// An Enricher failing doesn't fail the ping, its error is logged and the others still run.
+func WithEnrichers(enrichers ...Enricher) Option {
+	return func(c *Client) {
+		c.enrichers = append(c.enrichers, enrichers...)
+	}
+}
+
+// Enrich runs enrichers in order on res if the ping succeeded, adding their values to res.Enrichment,
+// with later enrichers overwriting the values of earlier ones. It returns the errors of the enrichers that failed.
+func Enrich(ctx context.Context, res *Result, enrichers ...Enricher) error {
+	if res.Err != nil || len(enrichers) == 0 {
+		return nil
+	}
+	var errs []error
+	for _, e := range enrichers {
+		values, err := e.Enrich(ctx, *res)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(values) == 0 {
+			continue
+		}
+		// The map is copied so results shared between callers are never modified
+		enrichment := make(map[string]string, len(res.Enrichment)+len(values))
+		for k, v := range res.Enrichment {
+			enrichment[k] = v
+		}
+		for k, v := range values {
+			enrichment[k] = v
+		}
+		res.Enrichment = enrichment
+	}
+	return errors.Join(errs...)
+}
+
+// Tags returns an Enricher adding tags to every result.
+func Tags(tags map[string]string) Enricher {
+	return EnricherFunc(func(context.Context, Result) (map[string]string, error) {
+		return tags, nil
+	})
+}
+
+// ReverseDNS is an Enricher looking up the hostname of a server's IP address, adding the first name found
+// as EnrichmentReverseDNS. The IP address is taken from Result.Resolved, or the address pinged if it's an IP.
+// Addresses without names aren't an error.
+type ReverseDNS struct {
+	// Resolver looks up names, if nil net.DefaultResolver is used.
+	Resolver *net.Resolver
+}
+
+func (r ReverseDNS) Enrich(ctx context.Context, res Result) (map[string]string, error) {
+	address := res.Resolved
+	if address == "" {
+		address = res.Address
+	}
+	ip, err := netip.ParseAddrPort(address)
+	if err != nil {
+		// The address was a hostname that the Multiplexer resolved without reporting the IP
+		return nil, nil
+	}
+	resolver := r.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	names, err := resolver.LookupAddr(ctx, ip.Addr().Unmap().String())
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return map[string]string{EnrichmentReverseDNS: strings.TrimSuffix(names[0], ".")}, nil
+}
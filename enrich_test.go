@@ -0,0 +1,69 @@
+package bedrockping
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEnrich(t *testing.T) {
+	failing := EnricherFunc(func(context.Context, Result) (map[string]string, error) {
+		return nil, errors.New("lookup failed")
+	})
+	res := Result{Address: "127.0.0.1:19132"}
+	err := Enrich(context.Background(), &res, Tags(map[string]string{"region": "eu", "env": "test"}), failing,
+		Tags(map[string]string{"env": "prod"}))
+	if err == nil || err.Error() != "lookup failed" {
+		t.Errorf("expected the enricher's error, got %v", err)
+	}
+	if len(res.Enrichment) != 2 || res.Enrichment["region"] != "eu" || res.Enrichment["env"] != "prod" {
+		t.Errorf("unexpected enrichment: %v", res.Enrichment)
+	}
+
+	// Failed pings aren't enriched
+	res = Result{Address: "127.0.0.1:19132", Err: context.DeadlineExceeded}
+	if err := Enrich(context.Background(), &res, Tags(map[string]string{"env": "test"})); err != nil || res.Enrichment != nil {
+		t.Errorf("expected no enrichment, got %v %v", res.Enrichment, err)
+	}
+}
+
+func TestClientEnrichers(t *testing.T) {
+	_, address := startResponder(t, testResponse("Test"))
+	var seen Result
+	client := NewClient(WithTimeout(time.Second), WithEnrichers(
+		EnricherFunc(func(_ context.Context, res Result) (map[string]string, error) {
+			seen = res
+			return map[string]string{"name": res.Response.ServerName}, nil
+		}),
+		ReverseDNS{}))
+
+	res := client.Ping(context.Background(), address)
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if seen.Resolved != address || seen.Latency <= 0 {
+		t.Errorf("expected the enricher to get the result, got %+v", seen)
+	}
+	if res.Enrichment["name"] != "Test" || res.Enrichment[EnrichmentReverseDNS] != "localhost" {
+		t.Errorf("unexpected enrichment: %v", res.Enrichment)
+	}
+}
+
+func TestBatchEnrichers(t *testing.T) {
+	address := startTestServer(t, testResponse("Test"))
+	m, err := NewMultiplexer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	results := m.QueryMany(context.Background(), []Target{{Host: address}, {Host: "127.0.0.1:1"}},
+		BatchOptions{Timeout: 200 * time.Millisecond, Enrichers: []Enricher{Tags(map[string]string{"source": "batch"})}})
+	if results[0].Err != nil || results[0].Enrichment["source"] != "batch" {
+		t.Errorf("expected the result to be enriched, got %+v", results[0])
+	}
+	if results[1].Err == nil || results[1].Enrichment != nil {
+		t.Errorf("expected the failed result not to be enriched, got %+v", results[1])
+	}
+}
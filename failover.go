@@ -0,0 +1,48 @@
+package bedrockping
+
+import (
+	"context"
+	"time"
+)
+
+// WithFailover makes the client ping the other addresses of hosts with several when the first fails, in the order
+// the resolver returned them, such as when one server behind round-robin DNS is down. The query's timeout is shared
+// between the addresses left, so each gets an equal part of what remains, and Result.Resolved is the address that
+// answered. Attempts counts the addresses tried and Sent the pings sent to all of them. Hosts resolved from an SRV
+// record are pinged as usual. It is disabled by default.
+func WithFailover(enabled bool) Option {
+	return func(c *Client) {
+		c.failover = enabled
+	}
+}
+
+// failOver pings each candidate address for res.Address in turn until one answers, returning its result,
+// or the result of the last if none do.
+func (c *Client) failOver(ctx context.Context, res Result, candidates []string) Result {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(c.timeout)
+	}
+
+	sent := 0
+	var last Result
+	for i, resolved := range candidates {
+		attemptCtx, cancel := context.WithTimeout(ctx, time.Until(deadline)/time.Duration(len(candidates)-i))
+		attempt := res
+		attempt.Resolved = resolved
+		attempt = c.pingResolved(attemptCtx, attempt)
+		cancel()
+
+		sent += attempt.Sent
+		attempt.Sent = sent
+		attempt.Attempts = i + 1
+		if attempt.Err == nil || ctx.Err() != nil {
+			return attempt
+		}
+		if i < len(candidates)-1 {
+			c.debug(ctx, "address failed, trying the next", "address", res.Address, "resolved", resolved, "error", attempt.Err)
+		}
+		last = attempt
+	}
+	return last
+}
@@ -0,0 +1,52 @@
+package bedrockping
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestFailover(t *testing.T) {
+	address := startTestServer(t, testResponse("Backup"))
+	_, port, _ := net.SplitHostPort(address)
+	// Nothing reads from the socket on 127.0.0.2 so its pings time out, and nothing listens on 127.0.0.3
+	silent, err := net.ListenPacket("udp", "127.0.0.2:"+port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer silent.Close()
+	resolver := staticResolver{
+		"timeout.example.com": {netip.MustParseAddr("127.0.0.2"), netip.MustParseAddr("127.0.0.1")},
+		"closed.example.com":  {netip.MustParseAddr("127.0.0.3"), netip.MustParseAddr("127.0.0.1")},
+		"down.example.com":    {netip.MustParseAddr("127.0.0.2"), netip.MustParseAddr("127.0.0.3")},
+	}
+
+	c := NewClient(WithResolver(resolver), WithTimeout(400*time.Millisecond), WithResend(100*time.Millisecond))
+	if res := c.Ping(context.Background(), "timeout.example.com:"+port); !errors.Is(res.Err, context.DeadlineExceeded) {
+		t.Errorf("expected only the first address to be pinged without failover, got %+v", res)
+	}
+
+	c = NewClient(WithResolver(resolver), WithTimeout(400*time.Millisecond), WithResend(100*time.Millisecond), WithFailover(true))
+	start := time.Now()
+	res := c.Ping(context.Background(), "timeout.example.com:"+port)
+	if res.Err != nil || res.Resolved != address || res.Response.ServerName != "Backup" || res.Attempts != 2 || res.Sent < 3 {
+		t.Errorf("expected the second address to answer, got %+v", res)
+	}
+	// The first address only gets half of the timeout
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("expected the first address to time out after half the timeout, took %s", elapsed)
+	}
+
+	res = c.Ping(context.Background(), "closed.example.com:"+port)
+	if res.Err != nil || res.Resolved != address || res.Attempts != 2 {
+		t.Errorf("expected the second address to answer, got %+v", res)
+	}
+
+	res = c.Ping(context.Background(), "down.example.com:"+port)
+	if !errors.Is(res.Err, ErrPortClosed) || res.Resolved != "127.0.0.3:"+port || res.Attempts != 2 {
+		t.Errorf("expected the result of the last address, got %+v", res)
+	}
+}
@@ -0,0 +1,88 @@
+package bedrockping
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FormattingCode is the character that starts a Minecraft formatting code, such as "§a" for green text.
+const FormattingCode = '§'
+
+// StripFormatting removes Minecraft formatting codes from s, such as the colors in a server name.
+func StripFormatting(s string) string {
+	if !strings.ContainsRune(s, FormattingCode) {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+
+	skip := false
+	for _, r := range s {
+		switch {
+		case skip:
+			skip = false
+		case r == FormattingCode:
+			skip = true
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// ansiCodes maps Minecraft formatting codes to the ANSI SGR parameters closest to them.
+var ansiCodes = map[rune]string{
+	'0': "30", // black
+	'1': "34", // dark blue
+	'2': "32", // dark green
+	'3': "36", // dark aqua
+	'4': "31", // dark red
+	'5': "35", // dark purple
+	'6': "33", // gold
+	'7': "37", // gray
+	'8': "90", // dark gray
+	'9': "94", // blue
+	'a': "92", // green
+	'b': "96", // aqua
+	'c': "91", // red
+	'd': "95", // light purple
+	'e': "93", // yellow
+	'f': "97", // white
+	'g': "93", // minecoin gold
+	'l': "1",  // bold
+	'o': "3",  // italic
+	'r': "0",  // reset
+}
+
+// ANSIFormatting replaces Minecraft formatting codes in s with ANSI escape sequences, so text looks in a
+// terminal the way it does in game. Codes without an ANSI equivalent, such as obfuscated text, are removed.
+// If s has any formatting it's reset at the end.
+func ANSIFormatting(s string) string {
+	if !strings.ContainsRune(s, FormattingCode) {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s) * 2)
+
+	skip, formatted := false, false
+	for _, r := range s {
+		switch {
+		case skip:
+			skip = false
+			if code, ok := ansiCodes[unicode.ToLower(r)]; ok {
+				b.WriteString("\x1b[" + code + "m")
+				formatted = true
+			}
+		case r == FormattingCode:
+			skip = true
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if formatted {
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
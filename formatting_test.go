@@ -0,0 +1,33 @@
+package bedrockping
+
+import "testing"
+
+func TestStripFormatting(t *testing.T) {
+	tests := map[string]string{
+		"Plain":                  "Plain",
+		"§aGreen §lBold§r Reset": "Green Bold Reset",
+		"Trailing§":              "Trailing",
+		"§§Double":               "Double",
+		"Ünïcödé §6Gold":         "Ünïcödé Gold",
+	}
+	for input, expect := range tests {
+		if got := StripFormatting(input); got != expect {
+			t.Errorf("StripFormatting(%q) = %q, expected %q", input, got, expect)
+		}
+	}
+}
+
+func TestANSIFormatting(t *testing.T) {
+	tests := map[string]string{
+		"Plain":                  "Plain",
+		"§aGreen §lBold§r Reset": "\x1b[92mGreen \x1b[1mBold\x1b[0m Reset\x1b[0m",
+		"§kHidden":               "Hidden",
+		"§CUpper":                "\x1b[91mUpper\x1b[0m",
+		"Trailing§":              "Trailing",
+	}
+	for input, expect := range tests {
+		if got := ANSIFormatting(input); got != expect {
+			t.Errorf("ANSIFormatting(%q) = %q, expected %q", input, got, expect)
+		}
+	}
+}
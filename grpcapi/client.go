@@ -0,0 +1,91 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/grpcapi/pingpb"
+)
+
+// Client pings servers through a remote ping service.
+type Client struct {
+	client pingpb.PingServiceClient
+}
+
+// NewClient returns a Client calling the ping service over conn, usually a *grpc.ClientConn.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: pingpb.NewPingServiceClient(conn)}
+}
+
+// Ping asks the service to ping the server at address. Err is set if the server couldn't be reached,
+// to a *RemoteError, or if the call failed.
+func (c *Client) Ping(ctx context.Context, address string) bedrockping.Result {
+	pb, err := c.client.Ping(ctx, &pingpb.PingRequest{Address: address})
+	if err != nil {
+		return bedrockping.Result{Address: address, Err: err}
+	}
+	return FromProto(pb)
+}
+
+// PingStream asks the service to ping the server at address every interval, count times or until ctx is done
+// if count is 0. Each result is sent on the returned channel, which is closed once the stream ends.
+// If the stream fails a final result is sent with Err set to the failure.
+func (c *Client) PingStream(ctx context.Context, address string, interval time.Duration, count int) (<-chan bedrockping.Result, error) {
+	stream, err := c.client.PingStream(ctx, &pingpb.PingStreamRequest{
+		Address:  address,
+		Interval: durationpb.New(interval),
+		Count:    int32(count),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return receive(ctx, stream, address), nil
+}
+
+// Scan asks the service to scan cidr on each of ports, like scan.Scan. Each server found is sent on the
+// returned channel, which is closed once the scan ends. If the stream fails a final result is sent
+// with the Address cidr and Err set to the failure.
+func (c *Client) Scan(ctx context.Context, cidr string, ports []int) (<-chan bedrockping.Result, error) {
+	req := &pingpb.ScanRequest{Cidr: cidr}
+	for _, port := range ports {
+		req.Ports = append(req.Ports, int32(port))
+	}
+	stream, err := c.client.Scan(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return receive(ctx, stream, cidr), nil
+}
+
+// receive sends the results received from stream on the returned channel until it ends.
+func receive(ctx context.Context, stream grpc.ServerStreamingClient[pingpb.PingResult], address string) <-chan bedrockping.Result {
+	results := make(chan bedrockping.Result)
+	go func() {
+		defer close(results)
+		for {
+			pb, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			res := bedrockping.Result{Address: address, Err: err}
+			if err == nil {
+				res = FromProto(pb)
+			}
+			select {
+			case results <- res:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return results
+}
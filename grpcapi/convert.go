@@ -0,0 +1,259 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/grpcapi/pingpb"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+// ToProto returns the protobuf message for the result of a ping made at checkedAt.
+func ToProto(res bedrockping.Result, checkedAt time.Time) *pingpb.PingResult {
+	pb := &pingpb.PingResult{
+		Address:    res.Address,
+		Online:     res.Err == nil,
+		Attempts:   int32(res.Attempts),
+		Sent:       int32(res.Sent),
+		Resolved:   res.Resolved,
+		CheckedAt:  timestamppb.New(checkedAt),
+		Enrichment: res.Enrichment,
+	}
+	if res.Err != nil {
+		pb.Error = res.Err.Error()
+		pb.ErrorKind = bedrockping.ErrorKind(res.Err)
+		return pb
+	}
+	pb.Latency = durationpb.New(res.Latency)
+	pb.Response = ResponseToProto(res.Response)
+	return pb
+}
+
+// FromProto returns the result held by pb. The error of an offline server is a *RemoteError.
+func FromProto(pb *pingpb.PingResult) bedrockping.Result {
+	res := bedrockping.Result{
+		Address:    pb.GetAddress(),
+		Latency:    pb.GetLatency().AsDuration(),
+		Attempts:   int(pb.GetAttempts()),
+		Sent:       int(pb.GetSent()),
+		Resolved:   pb.GetResolved(),
+		CheckedAt:  timeFromProto(pb.GetCheckedAt()),
+		Enrichment: pb.GetEnrichment(),
+	}
+	if !pb.GetOnline() {
+		res.Err = remoteError(pb.GetError(), pb.GetErrorKind())
+		return res
+	}
+	res.Response = ResponseFromProto(pb.GetResponse())
+	return res
+}
+
+// ResponseToProto returns the protobuf message for resp.
+func ResponseToProto(resp bedrockping.Response) *pingpb.Response {
+	return &pingpb.Response{
+		Timestamp:       resp.Timestamp,
+		ServerId:        resp.ServerID,
+		GameId:          resp.GameID,
+		ServerName:      resp.ServerName,
+		ProtocolVersion: int32(resp.ProtocolVersion),
+		McpeVersion:     resp.MCPEVersion,
+		PlayerCount:     int32(resp.PlayerCount),
+		MaxPlayers:      int32(resp.MaxPlayers),
+		Extra:           resp.Extra,
+	}
+}
+
+// ResponseFromProto returns the response held by pb.
+func ResponseFromProto(pb *pingpb.Response) bedrockping.Response {
+	return bedrockping.Response{
+		Timestamp:       pb.GetTimestamp(),
+		ServerID:        pb.GetServerId(),
+		GameID:          pb.GetGameId(),
+		ServerName:      pb.GetServerName(),
+		ProtocolVersion: int(pb.GetProtocolVersion()),
+		MCPEVersion:     pb.GetMcpeVersion(),
+		PlayerCount:     int(pb.GetPlayerCount()),
+		MaxPlayers:      int(pb.GetMaxPlayers()),
+		Extra:           pb.GetExtra(),
+	}
+}
+
+// UpdateToProto returns the protobuf message for u.
+func UpdateToProto(u monitor.Update) *pingpb.MonitorUpdate {
+	pb := &pingpb.MonitorUpdate{
+		Address: u.Address,
+		Time:    timestamppb.New(u.Time),
+		Up:      u.Up,
+		State:   stateToProto(u.State),
+	}
+	if u.Response != nil {
+		pb.Response = ResponseToProto(*u.Response)
+	}
+	if u.Up {
+		pb.Latency = durationpb.New(u.Latency)
+	}
+	if u.Err != nil {
+		pb.Error = u.Err.Error()
+		pb.ErrorKind = bedrockping.ErrorKind(u.Err)
+	}
+	return pb
+}
+
+// UpdateFromProto returns the update held by pb. Its error is a *RemoteError.
+func UpdateFromProto(pb *pingpb.MonitorUpdate) monitor.Update {
+	u := monitor.Update{
+		Address: pb.GetAddress(),
+		Time:    timeFromProto(pb.GetTime()),
+		Up:      pb.GetUp(),
+		State:   stateFromProto(pb.GetState()),
+		Latency: pb.GetLatency().AsDuration(),
+	}
+	if pb.GetResponse() != nil {
+		resp := ResponseFromProto(pb.GetResponse())
+		u.Response = &resp
+	}
+	if pb.GetError() != "" || pb.GetErrorKind() != "" {
+		u.Err = remoteError(pb.GetError(), pb.GetErrorKind())
+	}
+	return u
+}
+
+// EventToProto returns the protobuf message for e, or nil if it isn't a known event.
+func EventToProto(e monitor.Event) *pingpb.MonitorEvent {
+	switch e := e.(type) {
+	case monitor.ServerUp:
+		up := &pingpb.ServerUp{Address: e.Address, Time: timestamppb.New(e.Time), Response: ResponseToProto(e.Response)}
+		if e.Downtime > 0 {
+			up.Downtime = durationpb.New(e.Downtime)
+		}
+		return &pingpb.MonitorEvent{Event: &pingpb.MonitorEvent_ServerUp{ServerUp: up}}
+	case monitor.ServerDown:
+		down := &pingpb.ServerDown{Address: e.Address, Time: timestamppb.New(e.Time), Since: timestamppb.New(e.Since)}
+		if e.Err != nil {
+			down.Error = e.Err.Error()
+			down.ErrorKind = bedrockping.ErrorKind(e.Err)
+		}
+		if e.LastResponse != nil {
+			down.LastResponse = ResponseToProto(*e.LastResponse)
+		}
+		return &pingpb.MonitorEvent{Event: &pingpb.MonitorEvent_ServerDown{ServerDown: down}}
+	case monitor.LatencyThreshold:
+		return &pingpb.MonitorEvent{Event: &pingpb.MonitorEvent_LatencyThreshold{LatencyThreshold: &pingpb.LatencyThreshold{
+			Address:   e.Address,
+			Time:      timestamppb.New(e.Time),
+			Latency:   durationpb.New(e.Latency),
+			Threshold: durationpb.New(e.Threshold),
+			Response:  ResponseToProto(e.Response),
+			Exceeded:  e.Exceeded,
+		}}}
+	}
+	return nil
+}
+
+// EventFromProto returns the event held by pb, or nil if it holds none. The error of a ServerDown
+// event is a *RemoteError.
+func EventFromProto(pb *pingpb.MonitorEvent) monitor.Event {
+	switch {
+	case pb.GetServerUp() != nil:
+		up := pb.GetServerUp()
+		return monitor.ServerUp{
+			Address:  up.GetAddress(),
+			Time:     timeFromProto(up.GetTime()),
+			Response: ResponseFromProto(up.GetResponse()),
+			Downtime: up.GetDowntime().AsDuration(),
+		}
+	case pb.GetServerDown() != nil:
+		down := pb.GetServerDown()
+		e := monitor.ServerDown{
+			Address: down.GetAddress(),
+			Time:    timeFromProto(down.GetTime()),
+			Since:   timeFromProto(down.GetSince()),
+		}
+		if down.GetError() != "" || down.GetErrorKind() != "" {
+			e.Err = remoteError(down.GetError(), down.GetErrorKind())
+		}
+		if down.GetLastResponse() != nil {
+			resp := ResponseFromProto(down.GetLastResponse())
+			e.LastResponse = &resp
+		}
+		return e
+	case pb.GetLatencyThreshold() != nil:
+		lt := pb.GetLatencyThreshold()
+		return monitor.LatencyThreshold{
+			Address:   lt.GetAddress(),
+			Time:      timeFromProto(lt.GetTime()),
+			Latency:   lt.GetLatency().AsDuration(),
+			Threshold: lt.GetThreshold().AsDuration(),
+			Response:  ResponseFromProto(lt.GetResponse()),
+			Exceeded:  lt.GetExceeded(),
+		}
+	}
+	return nil
+}
+
+func stateToProto(s monitor.State) pingpb.ServerState {
+	switch s {
+	case monitor.StateUp:
+		return pingpb.ServerState_SERVER_STATE_UP
+	case monitor.StateDown:
+		return pingpb.ServerState_SERVER_STATE_DOWN
+	}
+	return pingpb.ServerState_SERVER_STATE_UNKNOWN
+}
+
+func stateFromProto(s pingpb.ServerState) monitor.State {
+	switch s {
+	case pingpb.ServerState_SERVER_STATE_UP:
+		return monitor.StateUp
+	case pingpb.ServerState_SERVER_STATE_DOWN:
+		return monitor.StateDown
+	}
+	return monitor.StateUnknown
+}
+
+// timeFromProto returns the time held by ts, or the zero time if it's unset.
+func timeFromProto(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+// remoteError returns the *RemoteError for an error sent with its message and kind.
+func remoteError(message, kind string) error {
+	return &RemoteError{Message: message, Kind: kind}
+}
+
+// RemoteError is why a server pinged by the ping service couldn't be reached.
+// It wraps an error of the same kind, so bedrockping.ErrorKind classifies it like the original.
+type RemoteError struct {
+	Message string
+	// Kind is one of the bedrockping.ErrorKind constants.
+	Kind string
+}
+
+func (e *RemoteError) Error() string {
+	return e.Message
+}
+
+func (e *RemoteError) Unwrap() error {
+	switch e.Kind {
+	case bedrockping.ErrorKindTimeout:
+		return context.DeadlineExceeded
+	case bedrockping.ErrorKindCanceled:
+		return context.Canceled
+	case bedrockping.ErrorKindDNS:
+		return &net.DNSError{Err: e.Message}
+	case bedrockping.ErrorKindRefused:
+		return bedrockping.ErrPortClosed
+	case bedrockping.ErrorKindCircuitOpen:
+		return bedrockping.ErrCircuitOpen
+	}
+	return errors.New(e.Message)
+}
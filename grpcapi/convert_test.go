@@ -0,0 +1,113 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/grpcapi/pingpb"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+func TestProtoRoundTrip(t *testing.T) {
+	res := bedrockping.Result{
+		Address: "play.example.com:19132",
+		Response: bedrockping.Response{Timestamp: 1, ServerID: 2, GameID: "MCPE", ServerName: "Test", ProtocolVersion: 390,
+			MCPEVersion: "1.14.60", PlayerCount: 3, MaxPlayers: 20, Extra: []string{"a", "b"}},
+		Latency:    25 * time.Millisecond,
+		Attempts:   1,
+		Sent:       2,
+		Resolved:   "203.0.113.1:19132",
+		CheckedAt:  time.Unix(1700000000, 0).UTC(),
+		Enrichment: map[string]string{"rdns": "host.example.com"},
+	}
+	checkedAt := res.CheckedAt
+	pb := ToProto(res, checkedAt)
+	if got := FromProto(pb); !reflect.DeepEqual(got, res) {
+		t.Errorf("expected %+v, got %+v", res, got)
+	}
+	if !pb.GetCheckedAt().AsTime().Equal(checkedAt) {
+		t.Errorf("unexpected checked at %v", pb.GetCheckedAt().AsTime())
+	}
+}
+
+func TestRemoteErrorKind(t *testing.T) {
+	for _, err := range []error{
+		context.DeadlineExceeded,
+		context.Canceled,
+		&net.DNSError{Err: "no such host", Name: "example.invalid"},
+		fmt.Errorf("%w: read: connection refused", bedrockping.ErrPortClosed),
+		bedrockping.ErrCircuitOpen,
+		errors.New("something else"),
+	} {
+		res := FromProto(ToProto(bedrockping.Result{Address: "a:1", Err: err}, time.Now()))
+		if res.Err.Error() != err.Error() {
+			t.Errorf("expected message %q, got %q", err, res.Err)
+		}
+		if want, got := bedrockping.ErrorKind(err), bedrockping.ErrorKind(res.Err); got != want {
+			t.Errorf("%v: expected kind %s, got %s", err, want, got)
+		}
+	}
+}
+
+func TestUpdateProtoRoundTrip(t *testing.T) {
+	now := time.Unix(1700000000, 0).UTC()
+	for _, u := range []monitor.Update{
+		{Address: "a:1", Time: now, Up: true, State: monitor.StateUp, Latency: 20 * time.Millisecond,
+			Response: &bedrockping.Response{ServerName: "Test", Extra: []string{"x"}}},
+		{Address: "a:1", Time: now, State: monitor.StateDown, Err: &RemoteError{Message: "timeout", Kind: bedrockping.ErrorKindTimeout}},
+		{Address: "a:1", Time: now},
+	} {
+		data, err := proto.Marshal(UpdateToProto(u))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var pb pingpb.MonitorUpdate
+		if err := proto.Unmarshal(data, &pb); err != nil {
+			t.Fatal(err)
+		}
+		if got := UpdateFromProto(&pb); !reflect.DeepEqual(got, u) {
+			t.Errorf("expected %+v, got %+v", u, got)
+		}
+	}
+}
+
+func TestEventProtoRoundTrip(t *testing.T) {
+	now := time.Unix(1700000000, 0).UTC()
+	resp := bedrockping.Response{ServerID: 1<<64 - 1, ServerName: "Test", PlayerCount: 3}
+	for _, e := range []monitor.Event{
+		monitor.ServerUp{Address: "a:1", Time: now, Response: resp, Downtime: time.Minute},
+		monitor.ServerUp{Address: "a:1", Time: now, Response: resp},
+		monitor.ServerDown{Address: "a:1", Time: now, Since: now.Add(-time.Minute),
+			Err: &RemoteError{Message: "refused", Kind: bedrockping.ErrorKindRefused}, LastResponse: &resp},
+		monitor.LatencyThreshold{Address: "a:1", Time: now, Latency: 300 * time.Millisecond,
+			Threshold: 200 * time.Millisecond, Response: resp, Exceeded: true},
+	} {
+		data, err := proto.Marshal(EventToProto(e))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var pb pingpb.MonitorEvent
+		if err := proto.Unmarshal(data, &pb); err != nil {
+			t.Fatal(err)
+		}
+		if got := EventFromProto(&pb); !reflect.DeepEqual(got, e) {
+			t.Errorf("expected %+v, got %+v", e, got)
+		}
+	}
+
+	if got := EventFromProto(&pingpb.MonitorEvent{}); got != nil {
+		t.Errorf("expected no event, got %+v", got)
+	}
+	down := EventFromProto(EventToProto(monitor.ServerDown{Address: "a:1", Err: context.DeadlineExceeded})).(monitor.ServerDown)
+	if !errors.Is(down.Err, context.DeadlineExceeded) {
+		t.Errorf("expected the error kind to be kept, got %v", down.Err)
+	}
+}
@@ -0,0 +1,6 @@
+// Package pingpb holds the protobuf messages and gRPC service of the ping service, generated from ping.proto,
+// and the messages of monitor updates and events, generated from events.proto. The messages can also be used
+// on their own, such as to send results through Kafka.
+package pingpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative ping.proto events.proto
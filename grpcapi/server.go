@@ -0,0 +1,207 @@
+// Package grpcapi provides a gRPC ping service and a client for it, so servers can be pinged from central
+// probe agents on behalf of other services. The service is defined in pingpb/ping.proto.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/access"
+	"github.com/ZeroErrors/go-bedrockping/grpcapi/pingpb"
+	"github.com/ZeroErrors/go-bedrockping/scan"
+)
+
+// DefaultMinInterval is the shortest interval between the pings of a PingStream call by default.
+const DefaultMinInterval = time.Second
+
+// Option configures a Server.
+type Option func(*options)
+
+type options struct {
+	minInterval time.Duration
+	scanner     *scan.Scanner
+	policy      *access.TargetPolicy
+	limiter     *access.RateLimiter
+}
+
+// WithMinInterval sets the shortest interval between the pings of a PingStream call, shorter intervals
+// requested are raised to it. The default is DefaultMinInterval.
+func WithMinInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.minInterval = interval
+	}
+}
+
+// WithScanner enables the Scan call, scanning with s. Without it Scan fails with codes.Unimplemented,
+// as scanning lets callers send pings to any address.
+func WithScanner(s *scan.Scanner) Option {
+	return func(o *options) {
+		o.scanner = s
+	}
+}
+
+// WithTargetPolicy only pings the servers p allows, and only scans prefixes it allows entirely.
+// Calls for other servers fail with codes.PermissionDenied.
+func WithTargetPolicy(p *access.TargetPolicy) Option {
+	return func(o *options) {
+		o.policy = p
+	}
+}
+
+// WithRateLimiter limits the calls of each peer with l, calls over the limit fail with codes.ResourceExhausted.
+// Each ping of a PingStream call counts towards the limit, while a Scan call counts once.
+func WithRateLimiter(l *access.RateLimiter) Option {
+	return func(o *options) {
+		o.limiter = l
+	}
+}
+
+// Server implements the ping service by pinging servers with a bedrockping.Client.
+// Register it with pingpb.RegisterPingServiceServer.
+type Server struct {
+	pingpb.UnimplementedPingServiceServer
+
+	client *bedrockping.Client
+	opts   options
+}
+
+// NewServer returns a Server pinging servers with client.
+func NewServer(client *bedrockping.Client, opts ...Option) *Server {
+	o := options{minInterval: DefaultMinInterval}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Server{client: client, opts: o}
+}
+
+func (s *Server) Ping(ctx context.Context, req *pingpb.PingRequest) (*pingpb.PingResult, error) {
+	if req.GetAddress() == "" {
+		return nil, status.Error(codes.InvalidArgument, "address is missing")
+	}
+	if err := s.allow(ctx); err != nil {
+		return nil, err
+	}
+	checkedAt := time.Now()
+	res, err := s.ping(ctx, req.GetAddress())
+	if err != nil {
+		return nil, err
+	}
+	return ToProto(res, checkedAt), nil
+}
+
+func (s *Server) PingStream(req *pingpb.PingStreamRequest, stream pingpb.PingService_PingStreamServer) error {
+	if req.GetAddress() == "" {
+		return status.Error(codes.InvalidArgument, "address is missing")
+	}
+	if req.GetCount() < 0 {
+		return status.Error(codes.InvalidArgument, "count is negative")
+	}
+	interval := req.GetInterval().AsDuration()
+	if interval < s.opts.minInterval {
+		interval = s.opts.minInterval
+	}
+
+	ctx := stream.Context()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for sent := int32(0); req.GetCount() == 0 || sent < req.GetCount(); sent++ {
+		if sent > 0 {
+			select {
+			case <-ctx.Done():
+				return status.FromContextError(ctx.Err()).Err()
+			case <-ticker.C:
+			}
+		}
+
+		if err := s.allow(ctx); err != nil {
+			return err
+		}
+		checkedAt := time.Now()
+		res, err := s.ping(ctx, req.GetAddress())
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		if err := stream.Send(ToProto(res, checkedAt)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) Scan(req *pingpb.ScanRequest, stream pingpb.PingService_ScanServer) error {
+	if s.opts.scanner == nil {
+		return status.Error(codes.Unimplemented, "scanning is disabled")
+	}
+	prefix, err := netip.ParsePrefix(req.GetCidr())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.opts.policy.AllowPrefix(prefix); err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err := s.allow(stream.Context()); err != nil {
+		return err
+	}
+	var ports []int
+	for _, port := range req.GetPorts() {
+		if port <= 0 || port > 65535 {
+			return status.Errorf(codes.InvalidArgument, "invalid port %d", port)
+		}
+		ports = append(ports, int(port))
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	results, err := s.opts.scanner.Scan(ctx, req.GetCidr(), ports)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	for res := range results {
+		if err := stream.Send(ToProto(res, time.Now())); err != nil {
+			// Cancelling stops the scan, the channel is drained so it can finish
+			cancel()
+			for range results {
+			}
+			return err
+		}
+	}
+	if err := stream.Context().Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return nil
+}
+
+// allow returns a codes.ResourceExhausted error if the peer making the call with ctx is over the rate limit.
+func (s *Server) allow(ctx context.Context) error {
+	var ip string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+	if ok, wait := s.opts.limiter.Allow(ip); !ok {
+		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded, retry in %v", wait.Round(time.Millisecond))
+	}
+	return nil
+}
+
+// ping pings the server at address if the policy allows it, otherwise returning a codes.PermissionDenied error.
+func (s *Server) ping(ctx context.Context, address string) (bedrockping.Result, error) {
+	res := s.opts.policy.Ping(ctx, s.client, address)
+	if errors.Is(res.Err, access.ErrNotAllowed) {
+		return res, status.Error(codes.PermissionDenied, res.Err.Error())
+	}
+	return res, nil
+}
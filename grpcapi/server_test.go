@@ -0,0 +1,201 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/access"
+	"github.com/ZeroErrors/go-bedrockping/grpcapi/pingpb"
+	"github.com/ZeroErrors/go-bedrockping/scan"
+)
+
+// startServer starts a Bedrock server answering pings, returning its address.
+func startServer(t *testing.T) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	r := bedrockping.NewResponder(bedrockping.Response{GameID: "MCPE", ServerName: "Test Server", ProtocolVersion: 390,
+		MCPEVersion: "1.14.60", PlayerCount: 3, MaxPlayers: 20, Extra: []string{"", "world"}})
+	go r.Serve(conn)
+	return conn.LocalAddr().String()
+}
+
+// startService serves s over an in-memory connection, returning a Client calling it.
+func startService(t *testing.T, s *Server) *Client {
+	lis := bufconn.Listen(1 << 16)
+	gs := grpc.NewServer()
+	pingpb.RegisterPingServiceServer(gs, s)
+	go gs.Serve(lis)
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewClient(conn)
+}
+
+func TestPing(t *testing.T) {
+	address := startServer(t)
+	client := startService(t, NewServer(bedrockping.NewClient(bedrockping.WithTimeout(time.Second))))
+
+	res := client.Ping(context.Background(), address)
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if res.Address != address || res.Response.ServerName != "Test Server" || res.Response.PlayerCount != 3 ||
+		res.Response.Extra[1] != "world" || res.Latency <= 0 {
+		t.Errorf("unexpected result: %+v", res)
+	}
+
+	// A server that doesn't respond is a result, not a failed call
+	dead, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dead.Close()
+	client = startService(t, NewServer(bedrockping.NewClient(bedrockping.WithTimeout(50*time.Millisecond))))
+	res = client.Ping(context.Background(), dead.LocalAddr().String())
+	if _, ok := res.Err.(*RemoteError); !ok || bedrockping.ErrorKind(res.Err) != bedrockping.ErrorKindTimeout {
+		t.Errorf("expected a remote timeout, got %#v", res.Err)
+	}
+
+	res = client.Ping(context.Background(), "")
+	if status.Code(res.Err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", res.Err)
+	}
+}
+
+func TestPingStream(t *testing.T) {
+	address := startServer(t)
+	client := startService(t, NewServer(bedrockping.NewClient(bedrockping.WithTimeout(time.Second)), WithMinInterval(10*time.Millisecond)))
+
+	start := time.Now()
+	results, err := client.PingStream(context.Background(), address, time.Millisecond, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for res := range results {
+		if res.Err != nil {
+			t.Fatal(res.Err)
+		}
+		n++
+	}
+	if n != 3 {
+		t.Errorf("expected 3 results, got %d", n)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("expected the interval to be raised to the minimum, took %v", elapsed)
+	}
+
+	// An unlimited stream ends when cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	results, err = client.PingStream(ctx, address, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-results
+	cancel()
+	for range results {
+	}
+}
+
+func TestScan(t *testing.T) {
+	address := startServer(t)
+	_, port, _ := net.SplitHostPort(address)
+	p, _ := strconv.Atoi(port)
+	client := startService(t, NewServer(bedrockping.NewClient(), WithScanner(&scan.Scanner{Timeout: 500 * time.Millisecond})))
+
+	results, err := client.Scan(context.Background(), "127.0.0.1/32", []int{p})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found []bedrockping.Result
+	for res := range results {
+		found = append(found, res)
+	}
+	if len(found) != 1 || found[0].Err != nil || found[0].Address != address {
+		t.Errorf("unexpected results: %+v", found)
+	}
+
+	results, err = client.Scan(context.Background(), "not a range", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := <-results; status.Code(res.Err) != codes.InvalidArgument || res.Address != "not a range" {
+		t.Errorf("expected InvalidArgument, got %+v", res)
+	}
+}
+
+func TestScanDisabled(t *testing.T) {
+	client := startService(t, NewServer(bedrockping.NewClient()))
+	results, err := client.Scan(context.Background(), "127.0.0.1/32", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := <-results; status.Code(res.Err) != codes.Unimplemented {
+		t.Errorf("expected Unimplemented, got %v", res.Err)
+	}
+}
+
+func TestTargetPolicy(t *testing.T) {
+	address := startServer(t)
+	policy, err := access.NewTargetPolicy(nil, []string{"127.0.0.0/8", "blocked.example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := startService(t, NewServer(bedrockping.NewClient(bedrockping.WithTimeout(time.Second)),
+		WithTargetPolicy(policy), WithScanner(&scan.Scanner{Timeout: 500 * time.Millisecond})))
+
+	for _, target := range []string{address, "blocked.example.com:19132"} {
+		if res := client.Ping(context.Background(), target); status.Code(res.Err) != codes.PermissionDenied {
+			t.Errorf("%s: expected PermissionDenied, got %v", target, res.Err)
+		}
+	}
+	results, err := client.PingStream(context.Background(), address, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := <-results; status.Code(res.Err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied from PingStream, got %v", res.Err)
+	}
+	results, err = client.Scan(context.Background(), "127.0.0.0/30", []int{19132})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := <-results; status.Code(res.Err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied from Scan, got %v", res.Err)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	address := startServer(t)
+	client := startService(t, NewServer(bedrockping.NewClient(bedrockping.WithTimeout(time.Second)),
+		WithRateLimiter(access.NewRateLimiter(0.1, 2))))
+
+	for i := 0; i < 2; i++ {
+		if res := client.Ping(context.Background(), address); res.Err != nil {
+			t.Fatalf("ping %d: %v", i, res.Err)
+		}
+	}
+	if res := client.Ping(context.Background(), address); status.Code(res.Err) != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted, got %v", res.Err)
+	}
+}
@@ -0,0 +1,102 @@
+package bedrockping
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strconv"
+)
+
+// WithHappyEyeballs makes the client ping hosts with both IPv4 and IPv6 addresses on both at once, like Happy
+// Eyeballs (RFC 8305) does for TCP connections, rather than only the first address the resolver returns. The
+// first pong received wins and Result.Resolved is the address that sent it. Pinging the other address stops
+// then. Only the first address of each family is pinged, and hosts resolved from an SRV record or with
+// WithNetwork set to "udp4" or "udp6" are pinged as usual. It takes precedence over WithFailover for hosts with
+// both. It is disabled by default.
+func WithHappyEyeballs(enabled bool) Option {
+	return func(c *Client) {
+		c.happyEyeballs = enabled
+	}
+}
+
+// resolveCandidates returns the IP addresses and ports to ping for address, in the order the resolver returned
+// them: the address returned by Resolve, with WithHappyEyeballs the first IPv6 and the first IPv4 address of the
+// host if it has both, reporting they're to be raced, or with WithFailover every address of the host.
+func (c *Client) resolveCandidates(ctx context.Context, address string) (candidates []string, race bool, err error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, false, err
+	}
+	raceFamilies := c.happyEyeballs && c.network == "udp"
+	if _, err := netip.ParseAddr(host); err == nil || !(raceFamilies || c.failover) {
+		resolved, err := c.Resolve(ctx, address)
+		if err != nil {
+			return nil, false, err
+		}
+		return []string{resolved}, false, nil
+	}
+	if c.srvService != "" && port == strconv.Itoa(DefaultPort) {
+		if resolved, ok := c.resolveSRV(ctx, host); ok {
+			return []string{resolved}, false, nil
+		}
+	}
+
+	addrs, err := c.lookupIPs(ctx, host)
+	if err != nil {
+		return nil, false, err
+	}
+	if raceFamilies {
+		var ipv4, ipv6 netip.Addr
+		for _, addr := range addrs {
+			switch {
+			case addr.Is4() && !ipv4.IsValid():
+				ipv4 = addr
+			case addr.Is6() && !ipv6.IsValid():
+				ipv6 = addr
+			default:
+				continue
+			}
+			candidates = append(candidates, c.joinHostPort(addr, port))
+		}
+		if len(candidates) > 1 || !c.failover {
+			return candidates, len(candidates) > 1, nil
+		}
+		candidates = nil
+	}
+	for _, addr := range addrs {
+		candidates = append(candidates, c.joinHostPort(addr, port))
+	}
+	return candidates, false, nil
+}
+
+// race pings every candidate address for res.Address at once, returning the result of the first to receive
+// a pong. If none do, the result of the first candidate is returned.
+func (c *Client) race(ctx context.Context, res Result, candidates []string) Result {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type raced struct {
+		i   int
+		res Result
+	}
+	results := make(chan raced, len(candidates))
+	for i, resolved := range candidates {
+		go func() {
+			res := res
+			res.Resolved = resolved
+			results <- raced{i, c.pingResolved(ctx, res)}
+		}()
+	}
+	c.debug(ctx, "racing addresses", "address", res.Address, "resolved", candidates)
+
+	failed := make([]Result, len(candidates))
+	for range candidates {
+		r := <-results
+		if r.res.Err == nil {
+			c.debug(ctx, "address won the race", "address", res.Address, "resolved", r.res.Resolved)
+			return r.res
+		}
+		failed[r.i] = r.res
+	}
+	return failed[0]
+}
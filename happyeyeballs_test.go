@@ -0,0 +1,67 @@
+package bedrockping
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHappyEyeballs(t *testing.T) {
+	address := startTestServer(t, testResponse("Dual Stack"))
+	_, port, _ := net.SplitHostPort(address)
+	// Nothing listens on ::1, so only the IPv4 address answers
+	resolver := staticResolver{"play.example.com": {netip.MustParseAddr("::1"), netip.MustParseAddr("127.0.0.1")}}
+
+	c := NewClient(WithResolver(resolver), WithTimeout(500*time.Millisecond))
+	if res := c.Ping(context.Background(), "play.example.com:"+port); res.Err == nil || res.Resolved != "[::1]:"+port {
+		t.Errorf("expected only the first address to be pinged without happy eyeballs, got %+v", res)
+	}
+
+	c = NewClient(WithResolver(resolver), WithTimeout(time.Second), WithHappyEyeballs(true))
+	res := c.Ping(context.Background(), "play.example.com:"+port)
+	if res.Err != nil || res.Resolved != address || res.Response.ServerName != "Dual Stack" {
+		t.Errorf("expected the IPv4 address to win, got %+v", res)
+	}
+}
+
+func TestHappyEyeballsFailure(t *testing.T) {
+	resolver := staticResolver{"play.example.com": {netip.MustParseAddr("::1"), netip.MustParseAddr("127.0.0.1")}}
+	c := NewClient(WithResolver(resolver), WithTimeout(200*time.Millisecond), WithHappyEyeballs(true))
+	res := c.Ping(context.Background(), "play.example.com:1")
+	if res.Err == nil || res.Resolved != "[::1]:1" {
+		t.Errorf("expected the result of the first address, got %+v", res)
+	}
+}
+
+func TestResolveCandidates(t *testing.T) {
+	resolver := staticResolver{
+		"dual.example.com": {
+			netip.MustParseAddr("127.0.0.1"), netip.MustParseAddr("127.0.0.2"),
+			netip.MustParseAddr("::1"), netip.MustParseAddr("::2"),
+		},
+		"v4.example.com": {netip.MustParseAddr("127.0.0.1"), netip.MustParseAddr("127.0.0.2")},
+	}
+	for _, test := range []struct {
+		opts     []Option
+		address  string
+		expected []string
+	}{
+		{nil, "dual.example.com:19132", []string{"127.0.0.1:19132"}},
+		{[]Option{WithHappyEyeballs(true)}, "dual.example.com:19132", []string{"127.0.0.1:19132", "[::1]:19132"}},
+		{[]Option{WithHappyEyeballs(true)}, "v4.example.com:19132", []string{"127.0.0.1:19132"}},
+		{[]Option{WithHappyEyeballs(true)}, "[::2]:19132", []string{"[::2]:19132"}},
+		{[]Option{WithHappyEyeballs(true), WithNetwork("udp6")}, "dual.example.com:19132", []string{"[::1]:19132"}},
+		{[]Option{WithFailover(true)}, "dual.example.com:19132", []string{"127.0.0.1:19132", "127.0.0.2:19132", "[::1]:19132", "[::2]:19132"}},
+		{[]Option{WithHappyEyeballs(true), WithFailover(true)}, "v4.example.com:19132", []string{"127.0.0.1:19132", "127.0.0.2:19132"}},
+		{[]Option{WithHappyEyeballs(true), WithSRV(DefaultSRVService)}, "dual.example.com:19132", []string{"127.0.0.1:19132", "[::1]:19132"}},
+	} {
+		c := NewClient(append(test.opts, WithResolver(resolver))...)
+		candidates, _, err := c.resolveCandidates(context.Background(), test.address)
+		if err != nil || !reflect.DeepEqual(candidates, test.expected) {
+			t.Errorf("%s with %d options: expected %v, got %v %v", test.address, len(test.opts), test.expected, candidates, err)
+		}
+	}
+}
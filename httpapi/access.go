@@ -0,0 +1,71 @@
+package httpapi
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping/access"
+)
+
+// WithTargetPolicy only pings the servers p allows, also checking the addresses hostnames resolve to.
+// Requests for other servers are refused with 403 Forbidden. Public deployments should set a policy,
+// such as one denying access.PrivateNetworks, so the API can't direct UDP traffic at arbitrary hosts.
+func WithTargetPolicy(p *access.TargetPolicy) Option {
+	return func(o *options) {
+		o.policy = p
+	}
+}
+
+// WithRateLimiter limits the requests of each client with l, responding with 429 Too Many Requests and a
+// Retry-After header to clients over the limit. A RateLimiter may be shared between handlers to limit them together.
+func WithRateLimiter(l *access.RateLimiter) Option {
+	return func(o *options) {
+		o.limiter = l
+	}
+}
+
+// WithClientIPHeader identifies clients for rate limiting by the last address in the header given, such as
+// "X-Forwarded-For", rather than the address of the connection. Only use it behind a proxy setting the header,
+// as clients could otherwise set it themselves.
+func WithClientIPHeader(name string) Option {
+	return func(o *options) {
+		o.clientIPHeader = name
+	}
+}
+
+// clientIP returns the IP address of the client making r.
+func (o *options) clientIP(r *http.Request) string {
+	if o.clientIPHeader != "" {
+		if values := r.Header.Values(o.clientIPHeader); len(values) > 0 {
+			// Proxies append the address they received the request from, so the last one is the one to trust
+			addrs := strings.Split(values[len(values)-1], ",")
+			return strings.TrimSpace(addrs[len(addrs)-1])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// allowRequest responds with 429 Too Many Requests if the client making r is over the rate limit,
+// returning whether it isn't.
+func (o *options) allowRequest(w http.ResponseWriter, r *http.Request) bool {
+	ok, wait := o.limiter.Allow(o.clientIP(r))
+	if ok {
+		return true
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(int((wait+time.Second-1)/time.Second)))
+	writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+	return false
+}
+
+// notAllowed reports whether err is why a server wasn't pinged.
+func notAllowed(err error) bool {
+	return errors.Is(err, access.ErrNotAllowed)
+}
@@ -0,0 +1,86 @@
+package httpapi
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/access"
+)
+
+func TestServerTargetPolicy(t *testing.T) {
+	address, pings := startServer(t, "Test Server")
+	_, port, _ := net.SplitHostPort(address)
+	policy, err := access.NewTargetPolicy(nil, access.PrivateNetworks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := bedrockping.NewClient(bedrockping.WithTimeout(time.Second))
+	server := httptest.NewServer(NewServer(client, WithAllowedHosts("127.0.0.1", "localhost"), WithTargetPolicy(policy)))
+	defer server.Close()
+
+	// localhost is allowed by the server, but resolves to a denied address
+	for _, path := range []string{"/v1/status/127.0.0.1/" + port, "/v1/status/localhost/" + port} {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		readBody(t, resp)
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("%s: expected %d, got %s", path, http.StatusForbidden, resp.Status)
+		}
+	}
+	if n := pings.Load(); n != 0 {
+		t.Errorf("expected no pings, got %d", n)
+	}
+}
+
+func TestBadgeHandlerTargetPolicy(t *testing.T) {
+	address, pings := startServer(t, "Test Server")
+	policy, err := access.NewTargetPolicy([]string{"play.example.com"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(BadgeHandler(bedrockping.NewClient(bedrockping.WithTimeout(time.Second)), WithTargetPolicy(policy)))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/badge/" + address + ".svg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	readBody(t, resp)
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected %d, got %s", http.StatusForbidden, resp.Status)
+	}
+	if n := pings.Load(); n != 0 {
+		t.Errorf("expected no pings, got %d", n)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	address, _ := startServer(t, "Test Server")
+	handler := StatusHandler(bedrockping.NewClient(bedrockping.WithTimeout(time.Second)), address,
+		WithRateLimiter(access.NewRateLimiter(0.5, 1)), WithClientIPHeader("X-Forwarded-For"))
+
+	get := func(forwardedFor string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+	if w := get("203.0.113.1, 198.51.100.1"); w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, w.Code)
+	}
+	// Only the last address, added by the trusted proxy, identifies the client
+	w := get("203.0.113.2, 198.51.100.1")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "2" {
+		t.Errorf("expected %d with Retry-After 2, got %d %q", http.StatusTooManyRequests, w.Code, w.Header().Get("Retry-After"))
+	}
+	if w := get("198.51.100.2"); w.Code != http.StatusOK {
+		t.Errorf("expected other clients to be allowed, got %d", w.Code)
+	}
+}
@@ -0,0 +1,90 @@
+package httpapi
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/badge"
+)
+
+// WithBadgeLabel sets the label on the left of badges, the default is badge.DefaultLabel.
+func WithBadgeLabel(label string) Option {
+	return func(o *options) {
+		o.badgeLabel = label
+	}
+}
+
+// WithBadgeColors sets the colors of the status on badges when the server is online and offline,
+// names from badge.Colors or hex colors. The defaults are badge.DefaultOnlineColor and badge.DefaultOfflineColor.
+func WithBadgeColors(online, offline string) Option {
+	return func(o *options) {
+		o.badgeOnline = online
+		o.badgeOffline = offline
+	}
+}
+
+// BadgeHandler returns a handler responding with an SVG badge of the status of the server named by the last
+// element of the request path, with an optional .svg extension, for embedding with an <img> tag:
+//
+//	http.Handle("GET /badge/{target}", httpapi.BadgeHandler(client))
+//	<img src="/badge/play.example.com:19132.svg">
+//
+// The port defaults to 19132. Like StatusHandler each server's status is cached for the TTL. As any server
+// may be requested, public deployments should restrict them with WithTargetPolicy.
+func BadgeHandler(client *bedrockping.Client, opts ...Option) http.Handler {
+	o := newOptions(opts)
+	cache := o.newCache(client)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethods(w, r) || !o.allowRequest(w, r) {
+			return
+		}
+		address, ok := targetAddress(strings.TrimSuffix(path.Base(r.URL.Path), ".svg"))
+		if !ok {
+			http.Error(w, "invalid target", http.StatusBadRequest)
+			return
+		}
+
+		host, _, _ := net.SplitHostPort(address)
+		if err := o.policy.AllowHost(host); err != nil {
+			http.Error(w, "target not allowed", http.StatusForbidden)
+			return
+		}
+
+		cached := cache.Ping(r.Context(), address)
+		res, checkedAt := cached.Result, cached.CheckedAt
+		if notAllowed(res.Err) {
+			http.Error(w, "target not allowed", http.StatusForbidden)
+			return
+		}
+		b := badge.ForResult(res)
+		b.Label = o.badgeLabel
+		if res.Err != nil {
+			b.Color = o.badgeOffline
+		} else {
+			b.Color = o.badgeOnline
+		}
+		var buf bytes.Buffer
+		b.WriteSVG(&buf)
+		writeCached(w, r, checkedAt, o.ttl, "image/svg+xml", buf.Bytes())
+	})
+}
+
+// targetAddress returns the address of the server named by target, a host with an optional port, or false
+// if it isn't one.
+func targetAddress(target string) (string, bool) {
+	if target == "" || target == "." || target == "/" || strings.ContainsAny(target, "/?#") {
+		return "", false
+	}
+	address := bedrockping.Target{Host: target}.Address()
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host == "" {
+		return "", false
+	}
+	n, err := strconv.Atoi(port)
+	return address, err == nil && n > 0 && n <= 65535
+}
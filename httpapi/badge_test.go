@@ -0,0 +1,61 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+func TestBadgeHandler(t *testing.T) {
+	address, pings := startServer(t, "Test Server")
+	client := bedrockping.NewClient(bedrockping.WithTimeout(time.Second))
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /badge/{target}", BadgeHandler(client, WithBadgeLabel("my server"), WithBadgeColors("blue", "#333")))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		resp, err := http.Get(server.URL + "/badge/" + address + ".svg")
+		if err != nil {
+			t.Fatal(err)
+		}
+		body := readBody(t, resp)
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "image/svg+xml" || !strings.HasPrefix(resp.Header.Get("Cache-Control"), "public, max-age=") {
+			t.Fatalf("unexpected response %s: %v", resp.Status, resp.Header)
+		}
+		if !strings.Contains(body, "<title>my server: 3/20 players</title>") || !strings.Contains(body, `fill="#007ec6"`) {
+			t.Errorf("unexpected badge:\n%s", body)
+		}
+	}
+	if n := pings.Load(); n != 1 {
+		t.Errorf("expected the status to be cached, got %d pings", n)
+	}
+
+	for _, target := range []string{"host:notaport", "host:70000", ":19132"} {
+		resp, err := http.Get(server.URL + "/badge/" + target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %s", target, resp.Status)
+		}
+	}
+}
+
+func TestBadgeHandlerOffline(t *testing.T) {
+	address := pingtest.DeadAddress(t)
+	client := bedrockping.NewClient(bedrockping.WithTimeout(50 * time.Millisecond))
+
+	w := httptest.NewRecorder()
+	BadgeHandler(client).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/"+address, nil))
+	if body := w.Body.String(); !strings.Contains(body, "<title>bedrock: offline</title>") || !strings.Contains(body, `fill="#e05d44"`) {
+		t.Errorf("unexpected badge:\n%s", body)
+	}
+}
@@ -0,0 +1,58 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+// HealthThresholds are the conditions the latest sample of a server must meet for Healthz to pass.
+// The server must always be up, after the Monitor's damping of failed pings.
+type HealthThresholds struct {
+	// MaxLatency, if set, fails the check when the latest ping took longer.
+	MaxLatency time.Duration
+	// MaxAge, if set, fails the check when the latest ping is older, such as when the Monitor has stopped.
+	MaxAge time.Duration
+}
+
+// Healthz returns a handler for readiness and liveness probes of services depending on the server at target,
+// which must be monitored by m. It responds 200 OK if the latest sample of the server meets the thresholds,
+// otherwise 503 Service Unavailable, with a plain text reason. The server isn't pinged, so probes can be
+// frequent without adding traffic.
+func Healthz(m *monitor.Monitor, target string, thresholds HealthThresholds) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethods(w, r) {
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		if reason := unhealthy(m, target, thresholds); reason != "" {
+			http.Error(w, reason, http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, "ok")
+	})
+}
+
+// unhealthy returns why the server at target fails the thresholds, or "" if it passes.
+func unhealthy(m *monitor.Monitor, target string, thresholds HealthThresholds) string {
+	u, ok := m.Status(target)
+	switch {
+	case !ok && !monitored(m, target):
+		return target + " is not monitored"
+	case !ok:
+		return target + " hasn't been pinged yet"
+	case thresholds.MaxAge > 0 && time.Since(u.Time) > thresholds.MaxAge:
+		return fmt.Sprintf("%s was last pinged %s ago", target, time.Since(u.Time).Round(time.Second))
+	case u.State != monitor.StateUp:
+		if u.Err != nil {
+			return fmt.Sprintf("%s is %s: %v", target, u.State, u.Err)
+		}
+		return fmt.Sprintf("%s is %s", target, u.State)
+	case thresholds.MaxLatency > 0 && u.Up && u.Latency > thresholds.MaxLatency:
+		return fmt.Sprintf("%s latency %s is above %s", target, u.Latency.Round(time.Millisecond), thresholds.MaxLatency)
+	}
+	return ""
+}
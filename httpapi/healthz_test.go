@@ -0,0 +1,59 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+// waitForStatus waits until m has pinged the server at address.
+func waitForStatus(m *monitor.Monitor, address string) {
+	for {
+		if _, ok := m.Status(address); ok {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	address, _ := startServer(t, "Test Server")
+	dead := pingtest.DeadAddress(t)
+	m := monitor.New(bedrockping.NewClient(bedrockping.WithTimeout(50*time.Millisecond)), monitor.WithThresholds(1, 1))
+	for _, target := range []string{address, dead} {
+		if err := m.Add(monitor.Target{Address: target, Interval: time.Hour}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	check := func(target string, thresholds HealthThresholds, code int, reason string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		Healthz(m, target, thresholds).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		if w.Code != code || !strings.Contains(w.Body.String(), reason) {
+			t.Errorf("expected %d %q, got %d %q", code, reason, w.Code, w.Body.String())
+		}
+	}
+
+	check(address, HealthThresholds{}, http.StatusServiceUnavailable, "hasn't been pinged yet")
+	check("unknown:19132", HealthThresholds{}, http.StatusServiceUnavailable, "is not monitored")
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+	waitForStatus(m, address)
+	waitForStatus(m, dead)
+
+	check(address, HealthThresholds{MaxLatency: time.Second, MaxAge: time.Minute}, http.StatusOK, "ok")
+	check(address, HealthThresholds{MaxLatency: time.Nanosecond}, http.StatusServiceUnavailable, "latency")
+	check(address, HealthThresholds{MaxAge: time.Nanosecond}, http.StatusServiceUnavailable, "was last pinged")
+	check(dead, HealthThresholds{}, http.StatusServiceUnavailable, dead+" is down")
+}
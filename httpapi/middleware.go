@@ -0,0 +1,129 @@
+package httpapi
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/subtle"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Middleware wraps a handler, such as to authenticate, log or measure requests.
+type Middleware func(http.Handler) http.Handler
+
+// WithMiddleware wraps a Server's routes in middleware, the first given being the outermost.
+// Middleware runs after rate limiting, so requests refused by it, such as with a wrong API key, still count
+// towards the limit and keys can't be guessed faster than it allows.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(o *options) {
+		o.middleware = append(o.middleware, middleware...)
+	}
+}
+
+// chain wraps h in middleware, the first being the outermost.
+func chain(h http.Handler, middleware []Middleware) http.Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+	return h
+}
+
+// APIKeyAuth returns middleware only serving requests with one of keys in the X-API-Key header or as a bearer
+// token in the Authorization header, responding to others with 401 Unauthorized.
+func APIKeyAuth(keys ...string) Middleware {
+	// Comparing hashes takes the same time however much of a key matches, or how long it is
+	hashes := make([][sha256.Size]byte, len(keys))
+	for i, key := range keys {
+		hashes[i] = sha256.Sum256([]byte(key))
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("X-API-Key")
+			if key == "" {
+				auth := r.Header.Get("Authorization")
+				if scheme, token, ok := strings.Cut(auth, " "); ok && strings.EqualFold(scheme, "Bearer") {
+					key = strings.TrimSpace(token)
+				}
+			}
+			if key != "" {
+				sum := sha256.Sum256([]byte(key))
+				valid := 0
+				for _, hash := range hashes {
+					valid |= subtle.ConstantTimeCompare(sum[:], hash[:])
+				}
+				if valid == 1 {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "invalid or missing API key")
+		})
+	}
+}
+
+// RequestLogger returns middleware logging each request to logger at slog.LevelInfo once it has been served,
+// with its method, path, response status, size, duration and the remote address.
+func RequestLogger(logger *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			logger.LogAttrs(r.Context(), slog.LevelInfo, "http request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rec.status),
+				slog.Int64("bytes", rec.bytes),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("remote", r.RemoteAddr))
+		})
+	}
+}
+
+// statusRecorder records the status and size of a response. It can still be flushed and hijacked,
+// so streams and WebSockets work through it.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+func (r *statusRecorder) Flush() {
+	http.NewResponseController(r.ResponseWriter).Flush()
+}
+
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(r.ResponseWriter).Hijack()
+	if err == nil && r.status == 0 {
+		r.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the wrapped ResponseWriter for http.ResponseController.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,141 @@
+package httpapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/access"
+)
+
+func TestAPIKeyAuth(t *testing.T) {
+	address, _ := startServer(t, "Test Server")
+	client := bedrockping.NewClient(bedrockping.WithTimeout(time.Second))
+	server := httptest.NewServer(NewServer(client, WithTargets(address), WithMiddleware(APIKeyAuth("secret", "other"))))
+	defer server.Close()
+
+	for header, code := range map[[2]string]int{
+		{"", ""}:                           http.StatusUnauthorized,
+		{"X-API-Key", "wrong"}:             http.StatusUnauthorized,
+		{"X-API-Key", "secrets"}:           http.StatusUnauthorized,
+		{"Authorization", "Basic abc"}:     http.StatusUnauthorized,
+		{"X-API-Key", "secret"}:            http.StatusOK,
+		{"Authorization", "Bearer other"}:  http.StatusOK,
+		{"Authorization", "bearer secret"}: http.StatusOK,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/v1/targets", nil)
+		if header[0] != "" {
+			req.Header.Set(header[0], header[1])
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		readBody(t, resp)
+		if resp.StatusCode != code {
+			t.Errorf("%s: %s: expected %d, got %s", header[0], header[1], code, resp.Status)
+		}
+		if code == http.StatusUnauthorized && resp.Header.Get("WWW-Authenticate") != "Bearer" {
+			t.Errorf("%s: %s: expected a WWW-Authenticate header", header[0], header[1])
+		}
+	}
+}
+
+func TestAPIKeyAuthRateLimited(t *testing.T) {
+	limiter := access.NewRateLimiter(0.001, 2)
+	server := httptest.NewServer(NewServer(bedrockping.NewClient(), WithMiddleware(APIKeyAuth("secret")), WithRateLimiter(limiter)))
+	defer server.Close()
+
+	// Guessing keys uses up the limit like any other request
+	for i, code := range []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests} {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/v1/targets", nil)
+		req.Header.Set("X-API-Key", "guess")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		readBody(t, resp)
+		if resp.StatusCode != code {
+			t.Errorf("request %d: expected %d, got %s", i, code, resp.Status)
+		}
+	}
+}
+
+func TestRequestLogger(t *testing.T) {
+	address, _ := startServer(t, "Test Server")
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	var order []string
+	mark := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	client := bedrockping.NewClient(bedrockping.WithTimeout(time.Second))
+	server := NewServer(client, WithTargets(address), WithMiddleware(RequestLogger(logger), mark("first")), WithMiddleware(mark("second")))
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/targets", nil))
+	if w.Code != http.StatusOK || strings.Join(order, ",") != "first,second" {
+		t.Errorf("unexpected status %d or middleware order %v", w.Code, order)
+	}
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/missing", nil))
+
+	var entries []map[string]any
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var entry map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 log entries, got %d", len(entries))
+	}
+	if e := entries[0]; e["msg"] != "http request" || e["method"] != "GET" || e["path"] != "/v1/targets" ||
+		e["status"] != float64(http.StatusOK) || e["bytes"] == float64(0) {
+		t.Errorf("unexpected log entry: %v", e)
+	}
+	if e := entries[1]; e["status"] != float64(http.StatusNotFound) {
+		t.Errorf("unexpected log entry: %v", e)
+	}
+}
+
+func TestRequestLoggerStream(t *testing.T) {
+	address, _ := startServer(t, "Test Server")
+	m := newMonitor(t, address, time.Hour)
+	var buf bytes.Buffer
+	server := httptest.NewServer(NewServer(bedrockping.NewClient(), WithMonitor(m),
+		WithMiddleware(RequestLogger(slog.New(slog.NewTextHandler(&buf, nil))))))
+	defer server.Close()
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The stream is flushed through the logger's ResponseWriter
+	resp, err := http.Get(server.URL + "/v1/stream?target=" + address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected %d, got %s", http.StatusOK, resp.Status)
+	}
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "event: ") {
+		t.Errorf("expected an event, got %q %v", line, err)
+	}
+}
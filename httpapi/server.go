@@ -0,0 +1,172 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/mcsrvstat"
+)
+
+// WithTargets sets the servers listed by a Server's /v1/targets endpoint, hostnames or IP addresses with
+// optional ports. Their hosts are also allowed to be pinged through /v1/status.
+func WithTargets(targets ...string) Option {
+	return func(o *options) {
+		o.targets = append(o.targets, targets...)
+	}
+}
+
+// WithAllowedHosts allows a Server to ping the hosts given, besides those of its targets, through /v1/status.
+// A host starting with "*." allows any subdomain of the rest, such as "*.example.com" for "play.example.com",
+// and IP addresses and CIDR prefixes may be given too, as for an access.TargetPolicy. Only allowed hosts are
+// pinged, so the server can't be used to send UDP traffic to arbitrary addresses. If a prefix is invalid no
+// host is allowed.
+func WithAllowedHosts(hosts ...string) Option {
+	return func(o *options) {
+		o.allowedHosts = append(o.allowedHosts, hosts...)
+	}
+}
+
+// Server is an http.Handler serving the status of servers as JSON from a versioned REST API:
+//
+//	GET /v1/status/{host}/{port}  the Status of a server, which must be an allowed host
+//	GET /v1/targets               the Status of each target set with WithTargets
+//	GET /v1/stream?target=...     the events of the Monitor set with WithMonitor, see StreamHandler
+//	GET /bedrock/3/{address}      the status of a server, which must be an allowed host, in the JSON of the
+//	                              mcsrvstat.us API, see mcsrvstat.Status. The port defaults to 19132.
+//
+// Statuses are held in a bedrockping.Cache for the TTL, after which the previous status is served while the
+// server is pinged again, and concurrent requests for a server that isn't cached wait for a single ping rather
+// than each sending one. Requests are rate limited first, then pass through the middleware set with WithMiddleware.
+type Server struct {
+	opts    options
+	cache   *bedrockping.Cache
+	targets []string
+	mux     *http.ServeMux
+	// handler is the mux wrapped in middleware and rate limiting
+	handler http.Handler
+}
+
+// NewServer returns a Server pinging servers with client.
+func NewServer(client *bedrockping.Client, opts ...Option) *Server {
+	o := newOptions(opts)
+	s := &Server{mux: http.NewServeMux()}
+	allowed := o.allowedHosts
+	for _, target := range o.targets {
+		address := bedrockping.Target{Host: target}.Address()
+		s.targets = append(s.targets, address)
+		host, _, _ := net.SplitHostPort(address)
+		allowed = append(allowed, host)
+	}
+	// Only the targets and allowed hosts are pinged, on top of any policy set
+	policy, err := o.policy.Restrict(allowed...)
+	if err != nil {
+		policy, _ = o.policy.Restrict()
+	}
+	o.policy = policy
+	s.opts, s.cache = o, o.newCache(client)
+
+	s.mux.HandleFunc("GET /v1/status/{host}/{port}", s.handleStatus)
+	s.mux.HandleFunc("GET /v1/targets", s.handleTargets)
+	s.mux.HandleFunc("GET /bedrock/3/{address}", s.handleMCSrvStat)
+	if o.monitor != nil {
+		s.mux.Handle("GET /v1/stream", StreamHandler(o.monitor))
+	}
+	// Rate limiting is outermost so requests refused by middleware, such as with a wrong API key, count too
+	routes := chain(s.mux, o.middleware)
+	s.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.opts.allowRequest(w, r) {
+			routes.ServeHTTP(w, r)
+		}
+	})
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	host := strings.ToLower(strings.Trim(r.PathValue("host"), "[]"))
+	port, err := strconv.Atoi(r.PathValue("port"))
+	if host == "" || err != nil || port <= 0 || port > 65535 {
+		writeError(w, http.StatusBadRequest, "invalid host or port")
+		return
+	}
+	if s.opts.policy.AllowHost(host) != nil {
+		writeError(w, http.StatusForbidden, "host not allowed")
+		return
+	}
+
+	res := s.cache.Ping(r.Context(), net.JoinHostPort(host, strconv.Itoa(port)))
+	if notAllowed(res.Err) {
+		writeError(w, http.StatusForbidden, "host not allowed")
+		return
+	}
+	body, _ := json.Marshal(NewStatus(res.Result, res.CheckedAt))
+	writeCached(w, r, res.CheckedAt, s.opts.ttl, "application/json", append(body, '\n'))
+}
+
+func (s *Server) handleMCSrvStat(w http.ResponseWriter, r *http.Request) {
+	address := bedrockping.Target{Host: r.PathValue("address")}.Address()
+	host, port, _ := net.SplitHostPort(address)
+	host = strings.ToLower(host)
+	if n, err := strconv.Atoi(port); host == "" || err != nil || n <= 0 || n > 65535 {
+		writeError(w, http.StatusBadRequest, "invalid host or port")
+		return
+	}
+	if s.opts.policy.AllowHost(host) != nil {
+		writeError(w, http.StatusForbidden, "host not allowed")
+		return
+	}
+
+	start := time.Now()
+	res := s.cache.Ping(r.Context(), net.JoinHostPort(host, port))
+	if notAllowed(res.Err) {
+		writeError(w, http.StatusForbidden, "host not allowed")
+		return
+	}
+	status := mcsrvstat.New(res.Result, res.CheckedAt, s.opts.ttl)
+	// The result is a hit if it's fresh and wasn't pinged for this request
+	status.Debug.CacheHit = !res.Stale && res.CheckedAt.Before(start)
+	body, _ := json.Marshal(status)
+	writeCached(w, r, res.CheckedAt, s.opts.ttl, "application/json", append(body, '\n'))
+}
+
+func (s *Server) handleTargets(w http.ResponseWriter, r *http.Request) {
+	statuses := make([]Status, len(s.targets))
+	var wg sync.WaitGroup
+	for i, address := range s.targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := s.cache.Ping(r.Context(), address)
+			statuses[i] = NewStatus(res.Result, res.CheckedAt)
+		}()
+	}
+	wg.Wait()
+
+	// The response is only fresh until the oldest status expires
+	checkedAt := time.Now()
+	for _, status := range statuses {
+		if status.CheckedAt.Before(checkedAt) {
+			checkedAt = status.CheckedAt
+		}
+	}
+	body, _ := json.Marshal(statuses)
+	writeCached(w, r, checkedAt, s.opts.ttl, "application/json", append(body, '\n'))
+}
+
+// writeError responds with a JSON object holding the error message.
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{message})
+}
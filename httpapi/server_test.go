@@ -0,0 +1,136 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+	"github.com/ZeroErrors/go-bedrockping/mcsrvstat"
+)
+
+func TestServerStatus(t *testing.T) {
+	address, pings := startServer(t, "Test Server")
+	_, port, _ := net.SplitHostPort(address)
+	client := bedrockping.NewClient(bedrockping.WithTimeout(time.Second))
+	server := httptest.NewServer(NewServer(client, WithAllowedHosts("127.0.0.1", "*.example.com")))
+	defer server.Close()
+
+	// Concurrent requests share a single ping
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := http.Get(server.URL + "/v1/status/127.0.0.1/" + port)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			var status Status
+			if err := json.Unmarshal([]byte(readBody(t, resp)), &status); err != nil {
+				t.Error(err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK || !status.Online || status.Response.ServerName != "Test Server" {
+				t.Errorf("unexpected response %s: %+v", resp.Status, status)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := pings.Load(); n != 1 {
+		t.Errorf("expected 1 ping, got %d", n)
+	}
+
+	for path, code := range map[string]int{
+		"/v1/status/10.0.0.1/19132":          http.StatusForbidden,
+		"/v1/status/example.com/19132":       http.StatusForbidden,
+		"/v1/status/evil.com.attacker/19132": http.StatusForbidden,
+		"/v1/status/127.0.0.1/notaport":      http.StatusBadRequest,
+		"/v1/status/127.0.0.1/0":             http.StatusBadRequest,
+		"/v1/status/127.0.0.1":               http.StatusNotFound,
+	} {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		readBody(t, resp)
+		if resp.StatusCode != code {
+			t.Errorf("%s: expected %d, got %s", path, code, resp.Status)
+		}
+	}
+}
+
+func TestServerAllowedHost(t *testing.T) {
+	s := NewServer(bedrockping.NewClient(), WithTargets("Play.Example.net:19133"), WithAllowedHosts("*.example.com", "[::1]"))
+	for host, allowed := range map[string]bool{
+		"play.example.net":  true,
+		"play.example.com":  true,
+		"a.b.example.com":   true,
+		"example.com":       false,
+		".example.com":      false,
+		"badexample.com":    false,
+		"::1":               true,
+		"other.example.net": false,
+	} {
+		if got := s.opts.policy.AllowHost(host) == nil; got != allowed {
+			t.Errorf("%s: expected allowed %v, got %v", host, allowed, got)
+		}
+	}
+}
+
+func TestServerTargets(t *testing.T) {
+	online, _ := startServer(t, "Online")
+	offline := pingtest.DeadAddress(t)
+	client := bedrockping.NewClient(bedrockping.WithTimeout(50 * time.Millisecond))
+
+	w := httptest.NewRecorder()
+	NewServer(client, WithTargets(online, offline)).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/targets", nil))
+	var statuses []Status
+	if err := json.Unmarshal(w.Body.Bytes(), &statuses); err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || statuses[0].Address != online || !statuses[0].Online ||
+		statuses[1].Address != offline || statuses[1].Online {
+		t.Errorf("unexpected targets: %+v", statuses)
+	}
+}
+
+func TestServerMCSrvStat(t *testing.T) {
+	address, pings := startServer(t, "§aTest Server")
+	_, port, _ := net.SplitHostPort(address)
+	s := NewServer(bedrockping.NewClient(bedrockping.WithTimeout(time.Second)), WithAllowedHosts("127.0.0.1"))
+
+	for i, hit := range []bool{false, true} {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/bedrock/3/127.0.0.1:"+port, nil))
+		var status mcsrvstat.Status
+		if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+			t.Fatal(err)
+		}
+		if w.Code != http.StatusOK || !status.Online || status.IP != "127.0.0.1" || strconv.Itoa(status.Port) != port ||
+			status.MOTD.Clean[0] != "Test Server" || status.Players.Online != 3 || status.Debug.CacheHit != hit {
+			t.Errorf("request %d: unexpected response %d: %s", i, w.Code, w.Body)
+		}
+	}
+	if n := pings.Load(); n != 1 {
+		t.Errorf("expected 1 ping, got %d", n)
+	}
+
+	for path, code := range map[string]int{
+		"/bedrock/3/10.0.0.1":           http.StatusForbidden,
+		"/bedrock/3/127.0.0.1:notaport": http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != code {
+			t.Errorf("%s: expected %d, got %d", path, code, w.Code)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+// sseKeepAlive is how often a comment is sent on idle event streams, so proxies don't close them.
+const sseKeepAlive = 30 * time.Second
+
+// WithMonitor serves the events of m from a Server's /v1/stream endpoint.
+func WithMonitor(m *monitor.Monitor) Option {
+	return func(o *options) {
+		o.monitor = m
+	}
+}
+
+// StreamHandler returns a handler streaming the updates of m as Server-Sent Events, for browser dashboards
+// that can't use WebSockets. The targets streamed are given by target query parameters, each must be monitored
+// by m:
+//
+//	const events = new EventSource("/v1/stream?target=play.example.com:19132");
+//	events.addEventListener("status", (e) => render(JSON.parse(e.data)));
+//
+// Each message is an Event named by its type, the latest status of each target is sent straight away.
+func StreamHandler(m *monitor.Monitor) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		targets := r.URL.Query()["target"]
+		if len(targets) == 0 {
+			writeError(w, http.StatusBadRequest, "no target")
+			return
+		}
+
+		sub := subscribe(m)
+		defer sub.close()
+		var latest []Event
+		for _, address := range targets {
+			e, ok := sub.add(address)
+			if !ok {
+				writeError(w, http.StatusNotFound, "not monitored: "+address)
+				return
+			}
+			if e != nil {
+				latest = append(latest, *e)
+			}
+		}
+
+		rc := http.NewResponseController(w)
+		h := w.Header()
+		h.Set("Content-Type", "text/event-stream")
+		h.Set("Cache-Control", "no-cache")
+		// Stops nginx buffering the stream
+		h.Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		for _, e := range latest {
+			writeSSE(w, e)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		ctx := r.Context()
+		events := make(chan Event)
+		go func() {
+			for {
+				e, ok := sub.next(ctx)
+				if !ok {
+					return
+				}
+				select {
+				case events <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		ticker := time.NewTicker(sseKeepAlive)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+			case e := <-events:
+				writeSSE(w, e)
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	})
+}
+
+// writeSSE writes e as a Server-Sent Event named by its type.
+func writeSSE(w http.ResponseWriter, e Event) {
+	data, _ := json.Marshal(e)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+}
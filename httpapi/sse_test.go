@@ -0,0 +1,91 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// readSSE reads the next event from r, returning its name and data.
+func readSSE(t *testing.T, r *bufio.Reader) (string, wireEvent) {
+	t.Helper()
+	var name string
+	var e wireEvent
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if name != "" {
+				return name, e
+			}
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestStreamHandler(t *testing.T) {
+	address, _ := startServer(t, "Test Server")
+	m := newMonitor(t, address, 20*time.Millisecond)
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	waitForStatus(m, address)
+	server := httptest.NewServer(NewServer(bedrockping.NewClient(), WithMonitor(m)))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/v1/stream?target=" + address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected response %s: %v", resp.Status, resp.Header)
+	}
+
+	// The latest status is sent straight away, then one for each ping
+	r := bufio.NewReader(resp.Body)
+	for i := 0; i < 3; i++ {
+		name, e := readSSE(t, r)
+		if name != "status" || e.Type != "status" || e.Address != address || !e.Status.Online || e.State != "up" {
+			t.Errorf("unexpected event %s: %+v", name, e)
+		}
+	}
+
+	for path, code := range map[string]int{
+		"/v1/stream":                      http.StatusBadRequest,
+		"/v1/stream?target=unknown:19132": http.StatusNotFound,
+	} {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		readBody(t, resp)
+		if resp.StatusCode != code {
+			t.Errorf("%s: expected %d, got %s", path, code, resp.Status)
+		}
+	}
+}
+
+func TestServerWithoutMonitor(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewServer(bedrockping.NewClient()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/stream?target=a:1", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without a monitor, got %d", w.Code)
+	}
+}
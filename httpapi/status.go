@@ -0,0 +1,148 @@
+// Package httpapi serves the status of Minecraft Bedrock servers over HTTP, for web backends and status pages.
+package httpapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/access"
+	"github.com/ZeroErrors/go-bedrockping/badge"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+// DefaultTTL is how long a status is cached by default.
+const DefaultTTL = 30 * time.Second
+
+// Status is the JSON representation of a server's status.
+type Status struct {
+	Address string `json:"address"`
+	Online  bool   `json:"online"`
+	// Response is only set if the server is online.
+	Response *bedrockping.Response `json:"response,omitempty"`
+	// LatencyMs is the latency of the ping in milliseconds.
+	LatencyMs float64 `json:"latencyMs,omitempty"`
+	Error     string  `json:"error,omitempty"`
+	// CheckedAt is when the server was pinged.
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// NewStatus returns the Status for the result of a ping made at checkedAt.
+func NewStatus(res bedrockping.Result, checkedAt time.Time) Status {
+	s := Status{Address: res.Address, Online: res.Err == nil, CheckedAt: checkedAt}
+	if res.Err != nil {
+		s.Error = res.Err.Error()
+	} else {
+		s.Response = &res.Response
+		s.LatencyMs = float64(res.Latency) / float64(time.Millisecond)
+	}
+	return s
+}
+
+// Option configures a handler.
+type Option func(*options)
+
+type options struct {
+	ttl time.Duration
+
+	badgeLabel                string
+	badgeOnline, badgeOffline string
+
+	targets      []string
+	allowedHosts []string
+
+	checkOrigin func(r *http.Request) bool
+	monitor     *monitor.Monitor
+
+	policy         *access.TargetPolicy
+	limiter        *access.RateLimiter
+	clientIPHeader string
+
+	middleware []Middleware
+}
+
+// WithTTL sets how long a status is cached before the server is pinged again, the default is DefaultTTL.
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		ttl:          DefaultTTL,
+		badgeLabel:   badge.DefaultLabel,
+		badgeOnline:  badge.DefaultOnlineColor,
+		badgeOffline: badge.DefaultOfflineColor,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// newCache returns a Cache of the results of pinging servers with client, once the policy allows them.
+func (o options) newCache(client *bedrockping.Client) *bedrockping.Cache {
+	return bedrockping.NewCache(bedrockping.PingerFunc(func(ctx context.Context, address string) bedrockping.Result {
+		return o.policy.Ping(ctx, client, address)
+	}), o.ttl)
+}
+
+// StatusHandler returns a handler responding to GET requests with the Status of the server at target as JSON,
+// with the port defaulting to 19132. The status is held in a bedrockping.Cache, so the server is pinged at most
+// once per TTL however many requests there are, after which the previous status is served while it's pinged
+// again. The response has cache headers telling clients and proxies how long it's fresh.
+func StatusHandler(client *bedrockping.Client, target string, opts ...Option) http.Handler {
+	o := newOptions(opts)
+	cache := o.newCache(client)
+	address := bedrockping.Target{Host: target}.Address()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethods(w, r) || !o.allowRequest(w, r) {
+			return
+		}
+		res := cache.Ping(r.Context(), address)
+		body, _ := json.Marshal(NewStatus(res.Result, res.CheckedAt))
+		writeCached(w, r, res.CheckedAt, o.ttl, "application/json", append(body, '\n'))
+	})
+}
+
+// allowMethods responds with 405 Method Not Allowed unless r is a GET or HEAD request, returning whether it is.
+func allowMethods(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return true
+	}
+	w.Header().Set("Allow", "GET, HEAD")
+	http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	return false
+}
+
+// writeCached writes body with headers allowing it to be cached until ttl after checkedAt,
+// or 304 Not Modified if the request has the ETag of body.
+func writeCached(w http.ResponseWriter, r *http.Request, checkedAt time.Time, ttl time.Duration, contentType string, body []byte) {
+	maxAge := int((ttl - time.Since(checkedAt)) / time.Second)
+	if maxAge < 0 {
+		maxAge = 0
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	h := w.Header()
+	h.Set("Cache-Control", "public, max-age="+strconv.Itoa(maxAge))
+	h.Set("Last-Modified", checkedAt.UTC().Format(http.TimeFormat))
+	h.Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	h.Set("Content-Type", contentType)
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	if r.Method != http.MethodHead {
+		w.Write(body)
+	}
+}
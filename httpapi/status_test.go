@@ -0,0 +1,154 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+// countingConn counts the packets read from it.
+type countingConn struct {
+	net.PacketConn
+	reads *atomic.Int32
+}
+
+func (c countingConn) ReadFrom(p []byte) (int, net.Addr, error) {
+	n, addr, err := c.PacketConn.ReadFrom(p)
+	if err == nil {
+		c.reads.Add(1)
+	}
+	return n, addr, err
+}
+
+// startServer starts a server answering pings with a response named name, returning its address and
+// the number of pings it has received.
+func startServer(t *testing.T, name string) (string, *atomic.Int32) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	pings := new(atomic.Int32)
+	r := bedrockping.NewResponder(bedrockping.Response{GameID: "MCPE", ServerName: name, ProtocolVersion: 390,
+		MCPEVersion: "1.14.60", PlayerCount: 3, MaxPlayers: 20})
+	go r.Serve(countingConn{conn, pings})
+	return conn.LocalAddr().String(), pings
+}
+
+// readBody reads and closes the body of resp.
+func readBody(t *testing.T, resp *http.Response) string {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestStatusHandler(t *testing.T) {
+	address, pings := startServer(t, "Test Server")
+	client := bedrockping.NewClient(bedrockping.WithTimeout(time.Second))
+	server := httptest.NewServer(StatusHandler(client, address, WithTTL(time.Minute)))
+	defer server.Close()
+
+	// Concurrent requests share a single ping
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := http.Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var s Status
+	if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Online || s.Address != address || s.Response.ServerName != "Test Server" || s.LatencyMs <= 0 || s.CheckedAt.IsZero() {
+		t.Errorf("unexpected status: %+v", s)
+	}
+	if n := pings.Load(); n != 1 {
+		t.Errorf("expected 1 ping, got %d", n)
+	}
+
+	if cc := resp.Header.Get("Cache-Control"); cc != "public, max-age=59" && cc != "public, max-age=60" {
+		t.Errorf("unexpected Cache-Control: %s", cc)
+	}
+	if resp.Header.Get("Content-Type") != "application/json" || resp.Header.Get("Last-Modified") == "" {
+		t.Errorf("unexpected headers: %v", resp.Header)
+	}
+
+	// Clients with the current version get 304 Not Modified
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("If-None-Match", resp.Header.Get("ETag"))
+	notModified, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notModified.Body.Close()
+	if notModified.StatusCode != http.StatusNotModified {
+		t.Errorf("expected 304, got %s", notModified.Status)
+	}
+
+	post, err := http.Post(server.URL, "text/plain", strings.NewReader(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	post.Body.Close()
+	if post.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %s", post.Status)
+	}
+}
+
+func TestStatusHandlerExpiry(t *testing.T) {
+	address, pings := startServer(t, "Test Server")
+	client := bedrockping.NewClient(bedrockping.WithTimeout(time.Second))
+	h := StatusHandler(client, address, WithTTL(20*time.Millisecond))
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("unexpected status %d", w.Code)
+		}
+		time.Sleep(30 * time.Millisecond)
+	}
+	if n := pings.Load(); n != 2 {
+		t.Errorf("expected a ping per request once expired, got %d", n)
+	}
+}
+
+func TestStatusHandlerOffline(t *testing.T) {
+	client := bedrockping.NewClient(bedrockping.WithTimeout(50 * time.Millisecond))
+	w := httptest.NewRecorder()
+	StatusHandler(client, pingtest.DeadAddress(t)).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var s Status
+	if err := json.Unmarshal(w.Body.Bytes(), &s); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || s.Online || s.Response != nil || s.Error != "context deadline exceeded" {
+		t.Errorf("unexpected status: %s", w.Body)
+	}
+}
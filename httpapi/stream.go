@@ -0,0 +1,145 @@
+package httpapi
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+// streamBuffer is the number of updates and events held for a slow client before they're dropped.
+const streamBuffer = 64
+
+// Event is a message streamed to clients watching monitored servers.
+type Event struct {
+	// Type is "status" for the result of each ping, "up", "down", "slow" or "recovered" when the state of
+	// the server changes, or "error" if a request from the client failed.
+	Type    string `json:"type"`
+	Address string `json:"address"`
+	// Status and State are set for "status" events, State is the damped state after the ping.
+	Status *Status `json:"status,omitempty"`
+	State  string  `json:"state,omitempty"`
+	// Event is the monitor event for changes of state.
+	Event monitor.Event `json:"event,omitempty"`
+	Error string        `json:"error,omitempty"`
+}
+
+// statusEvent returns the "status" event for u.
+func statusEvent(u monitor.Update) Event {
+	s := &Status{Address: u.Address, Online: u.Up, Response: u.Response, CheckedAt: u.Time}
+	if u.Err != nil {
+		s.Error = u.Err.Error()
+	} else {
+		s.LatencyMs = float64(u.Latency) / float64(time.Millisecond)
+	}
+	return Event{Type: "status", Address: u.Address, Status: s, State: u.State.String()}
+}
+
+// monitorEvent returns the Event for e.
+func monitorEvent(e monitor.Event) Event {
+	switch e := e.(type) {
+	case monitor.ServerUp:
+		return Event{Type: "up", Address: e.Address, Event: e}
+	case monitor.ServerDown:
+		return Event{Type: "down", Address: e.Address, Event: e}
+	case monitor.LatencyThreshold:
+		if e.Exceeded {
+			return Event{Type: "slow", Address: e.Address, Event: e}
+		}
+		return Event{Type: "recovered", Address: e.Address, Event: e}
+	}
+	return Event{Event: e}
+}
+
+// subscription receives the updates and events of a Monitor for the targets subscribed to.
+type subscription struct {
+	m       *monitor.Monitor
+	updates <-chan monitor.Update
+	events  <-chan monitor.Event
+	cancel  []func()
+
+	mu        sync.Mutex
+	addresses map[string]bool
+}
+
+func subscribe(m *monitor.Monitor) *subscription {
+	updates, cancelUpdates := m.Subscribe(streamBuffer)
+	events, cancelEvents := m.SubscribeEvents(streamBuffer)
+	return &subscription{
+		m:         m,
+		updates:   updates,
+		events:    events,
+		cancel:    []func(){cancelUpdates, cancelEvents},
+		addresses: make(map[string]bool),
+	}
+}
+
+// add subscribes to the target with address, returning its latest status if it's been pinged,
+// or false if it isn't monitored.
+func (s *subscription) add(address string) (*Event, bool) {
+	if !monitored(s.m, address) {
+		return nil, false
+	}
+	s.mu.Lock()
+	s.addresses[address] = true
+	s.mu.Unlock()
+
+	if u, ok := s.m.Status(address); ok {
+		e := statusEvent(u)
+		return &e, true
+	}
+	return nil, true
+}
+
+func (s *subscription) remove(address string) {
+	s.mu.Lock()
+	delete(s.addresses, address)
+	s.mu.Unlock()
+}
+
+func (s *subscription) subscribed(address string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addresses[address]
+}
+
+// next returns the next event for a subscribed target, or false once ctx is done.
+func (s *subscription) next(ctx context.Context) (Event, bool) {
+	for {
+		select {
+		case <-ctx.Done():
+			return Event{}, false
+		case u, ok := <-s.updates:
+			if !ok {
+				return Event{}, false
+			}
+			if s.subscribed(u.Address) {
+				return statusEvent(u), true
+			}
+		case e, ok := <-s.events:
+			if !ok {
+				return Event{}, false
+			}
+			if event := monitorEvent(e); s.subscribed(event.Address) {
+				return event, true
+			}
+		}
+	}
+}
+
+func (s *subscription) close() {
+	for _, cancel := range s.cancel {
+		cancel()
+	}
+}
+
+// monitored reports whether m monitors the server with address.
+func monitored(m *monitor.Monitor, address string) bool {
+	for _, target := range m.Targets() {
+		if target == address {
+			return true
+		}
+	}
+	return false
+}
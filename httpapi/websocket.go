@@ -0,0 +1,145 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+const (
+	// wsPingInterval is how often WebSocket connections are pinged to detect dead clients.
+	wsPingInterval = 30 * time.Second
+	// wsReadTimeout is how long a WebSocket client may go without answering a ping.
+	wsReadTimeout = 2 * wsPingInterval
+	// wsWriteTimeout is how long a message may take to send.
+	wsWriteTimeout = 10 * time.Second
+)
+
+// WithCheckOrigin sets the function deciding whether WebSocket connections are accepted from the page origin
+// of a request. By default only pages on the same host as the handler may connect.
+func WithCheckOrigin(fn func(r *http.Request) bool) Option {
+	return func(o *options) {
+		o.checkOrigin = fn
+	}
+}
+
+// Subscription is a message a WebSocket client sends to change the targets it receives events for.
+type Subscription struct {
+	// Subscribe and Unsubscribe are the addresses of monitored targets, as given to the Monitor.
+	Subscribe   []string `json:"subscribe,omitempty"`
+	Unsubscribe []string `json:"unsubscribe,omitempty"`
+}
+
+// WebSocketHandler returns a handler upgrading requests to WebSocket connections and pushing the updates of
+// m to them as JSON Events, for live status pages that don't poll. Clients choose the targets they receive
+// events for by sending Subscription messages, or with target query parameters:
+//
+//	{"subscribe": ["play.example.com:19132"]}
+//
+// On subscribing the latest status of the target is sent straight away. Only targets monitored by m can be
+// subscribed to, others are answered with an "error" Event. Events are dropped for clients too slow to
+// receive them.
+func WebSocketHandler(m *monitor.Monitor, opts ...Option) http.Handler {
+	o := newOptions(opts)
+	upgrader := websocket.Upgrader{CheckOrigin: o.checkOrigin}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// The upgrader has already responded
+			return
+		}
+		defer conn.Close()
+
+		sub := subscribe(m)
+		defer sub.close()
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+
+		// Replies to subscriptions are passed to the writer, as only one goroutine may write
+		replies := make(chan Event, streamBuffer)
+		reply := func(e Event) {
+			select {
+			case replies <- e:
+			default:
+			}
+		}
+		handle := func(s Subscription) {
+			for _, address := range s.Subscribe {
+				latest, ok := sub.add(address)
+				if !ok {
+					reply(Event{Type: "error", Address: address, Error: "not monitored"})
+				} else if latest != nil {
+					reply(*latest)
+				}
+			}
+			for _, address := range s.Unsubscribe {
+				sub.remove(address)
+			}
+		}
+		handle(Subscription{Subscribe: r.URL.Query()["target"]})
+
+		go func() {
+			defer cancel()
+			conn.SetReadLimit(4096)
+			conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+			conn.SetPongHandler(func(string) error {
+				return conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+			})
+			for {
+				_, data, err := conn.ReadMessage()
+				if err != nil {
+					return
+				}
+				var s Subscription
+				if err := json.Unmarshal(data, &s); err != nil {
+					reply(Event{Type: "error", Error: "invalid subscription: " + err.Error()})
+					continue
+				}
+				handle(s)
+			}
+		}()
+
+		events := make(chan Event)
+		go func() {
+			for {
+				e, ok := sub.next(ctx)
+				if !ok {
+					return
+				}
+				select {
+				case events <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		ticker := time.NewTicker(wsPingInterval)
+		defer ticker.Stop()
+		for {
+			var e Event
+			select {
+			case <-ctx.Done():
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+					time.Now().Add(wsWriteTimeout))
+				return
+			case <-ticker.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+					return
+				}
+				continue
+			case e = <-replies:
+			case e = <-events:
+			}
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := conn.WriteJSON(e); err != nil {
+				return
+			}
+		}
+	})
+}
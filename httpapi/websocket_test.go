@@ -0,0 +1,124 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+// newMonitor returns a Monitor pinging the server at address every interval once started.
+func newMonitor(t *testing.T, address string, interval time.Duration) *monitor.Monitor {
+	m := monitor.New(bedrockping.NewClient(bedrockping.WithTimeout(time.Second)))
+	if err := m.Add(monitor.Target{Address: address, Interval: interval}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(m.Stop)
+	return m
+}
+
+// wireEvent is an Event as received by clients, which can't decode the monitor event into an interface.
+type wireEvent struct {
+	Type, Address, State, Error string
+	Status                      *Status
+	Event                       json.RawMessage
+}
+
+// readEvent reads the next Event from conn, failing the test if none arrives in time.
+func readEvent(t *testing.T, conn *websocket.Conn) wireEvent {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var e wireEvent
+	if err := conn.ReadJSON(&e); err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestWebSocketHandler(t *testing.T) {
+	address, _ := startServer(t, "Test Server")
+	m := newMonitor(t, address, 20*time.Millisecond)
+	server := httptest.NewServer(WebSocketHandler(m))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte("{")); err != nil {
+		t.Fatal(err)
+	}
+	if e := readEvent(t, conn); e.Type != "error" || !strings.HasPrefix(e.Error, "invalid subscription") {
+		t.Errorf("expected an error for an invalid subscription, got %+v", e)
+	}
+	if err := conn.WriteJSON(Subscription{Subscribe: []string{address, "unknown:19132"}}); err != nil {
+		t.Fatal(err)
+	}
+	if e := readEvent(t, conn); e.Type != "error" || e.Address != "unknown:19132" || e.Error != "not monitored" {
+		t.Errorf("expected an error for an unmonitored target, got %+v", e)
+	}
+
+	// Once subscribed, both status updates and the server coming up are pushed
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var sawStatus, sawUp bool
+	for !sawStatus || !sawUp {
+		e := readEvent(t, conn)
+		switch e.Type {
+		case "status":
+			sawStatus = true
+			if e.Address != address || !e.Status.Online || e.Status.Response.ServerName != "Test Server" {
+				t.Errorf("unexpected status: %+v", e.Status)
+			}
+		case "up":
+			sawUp = true
+			if !strings.Contains(string(e.Event), `"serverName":"Test Server"`) {
+				t.Errorf("unexpected up event: %s", e.Event)
+			}
+		default:
+			t.Fatalf("unexpected event %+v", e)
+		}
+	}
+}
+
+func TestWebSocketHandlerQuery(t *testing.T) {
+	address, _ := startServer(t, "Test Server")
+	m := newMonitor(t, address, time.Hour)
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// Wait for the first ping, so its status is sent straight away on subscribing
+	waitForStatus(m, address)
+	server := httptest.NewServer(WebSocketHandler(m))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"?target="+address, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if e := readEvent(t, conn); e.Type != "status" || e.Address != address || e.State != "up" {
+		t.Errorf("expected the latest status, got %+v", e)
+	}
+}
+
+func TestWebSocketHandlerOrigin(t *testing.T) {
+	m := monitor.New(nil)
+	server := httptest.NewServer(WebSocketHandler(m))
+	defer server.Close()
+
+	header := map[string][]string{"Origin": {"https://other.example.com"}}
+	if _, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), header); err == nil {
+		t.Error("expected a cross-origin connection to be refused")
+	}
+}
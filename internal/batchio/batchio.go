@@ -0,0 +1,81 @@
+// Package batchio reads and writes UDP packets in batches, with a single recvmmsg or sendmmsg system call for
+// each batch on Linux, to cut the system call overhead of pinging tens of thousands of servers. Elsewhere the
+// packets of a batch are read and written one at a time.
+package batchio
+
+import (
+	"net"
+
+	"golang.org/x/net/ipv4"
+)
+
+// Size is the number of packets read or written in a batch, enough to spread the cost of a system call
+// without holding on to packets for long.
+const Size = 64
+
+// Message is a packet read or written in a batch, only the first of its Buffers is used.
+type Message = ipv4.Message
+
+// batcher reads and writes several messages per system call.
+type batcher interface {
+	ReadBatch(ms []ipv4.Message, flags int) (int, error)
+	WriteBatch(ms []ipv4.Message, flags int) (int, error)
+}
+
+// Conn reads and writes batches of packets on a UDP socket.
+type Conn struct {
+	conn  net.PacketConn
+	batch batcher // nil where batches aren't supported
+}
+
+// NewConn returns a Conn reading and writing batches on conn.
+func NewConn(conn net.PacketConn) *Conn {
+	return &Conn{conn: conn, batch: newBatcher(conn)}
+}
+
+// NewMessages returns n messages to read packets into, each with a buffer of size bytes.
+func NewMessages(n, size int) []Message {
+	ms := make([]Message, n)
+	buf := make([]byte, n*size)
+	for i := range ms {
+		ms[i].Buffers = [][]byte{buf[i*size : (i+1)*size : (i+1)*size]}
+	}
+	return ms
+}
+
+// ReadBatch waits for at least one packet and reads as many as are queued, up to len(ms), returning how
+// many were read. The N of each message read is the length of its packet and Addr is its sender.
+func (c *Conn) ReadBatch(ms []Message) (int, error) {
+	if c.batch != nil {
+		return c.batch.ReadBatch(ms, 0)
+	}
+	n, addr, err := c.conn.ReadFrom(ms[0].Buffers[0])
+	if err != nil {
+		return 0, err
+	}
+	ms[0].N, ms[0].Addr = n, addr
+	return 1, nil
+}
+
+// WriteBatch writes the packet of each message to its Addr. A packet that can't be sent doesn't stop the rest,
+// failed is called with its index and error if it isn't nil.
+func (c *Conn) WriteBatch(ms []Message, failed func(i int, err error)) {
+	for i := 0; i < len(ms); {
+		var n int
+		var err error
+		if c.batch != nil {
+			n, err = c.batch.WriteBatch(ms[i:], 0)
+		} else {
+			_, err = c.conn.WriteTo(ms[i].Buffers[0], ms[i].Addr)
+			n = 1
+		}
+		if err != nil && failed != nil {
+			failed(i, err)
+		}
+		// A batch stops at the first packet that fails, which is skipped
+		if err != nil || n < 1 {
+			n = 1
+		}
+		i += n
+	}
+}
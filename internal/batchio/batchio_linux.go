@@ -0,0 +1,16 @@
+package batchio
+
+import (
+	"net"
+
+	"golang.org/x/net/ipv4"
+)
+
+// newBatcher returns a batcher using recvmmsg and sendmmsg on conn, which works for IPv6 sockets as well as
+// IPv4 ones, or nil if conn isn't a UDP socket.
+func newBatcher(conn net.PacketConn) batcher {
+	if _, ok := conn.(*net.UDPConn); !ok {
+		return nil
+	}
+	return ipv4.NewPacketConn(conn)
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package batchio
+
+import "net"
+
+// newBatcher returns nil, batches are only read and written with a single system call on Linux.
+func newBatcher(conn net.PacketConn) batcher {
+	return nil
+}
@@ -0,0 +1,66 @@
+package batchio
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestBatch(t *testing.T) {
+	for _, test := range []struct {
+		network, laddr, raddr string
+	}{
+		{"udp4", "127.0.0.1:0", "127.0.0.1"},
+		{"udp", ":0", "127.0.0.1"},
+		{"udp6", "[::1]:0", "::1"},
+	} {
+		sender, err := net.ListenPacket(test.network, test.laddr)
+		if err != nil {
+			t.Logf("%s isn't available: %v", test.laddr, err)
+			continue
+		}
+		defer sender.Close()
+		receiver, err := net.ListenPacket("udp", net.JoinHostPort(test.raddr, "0"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer receiver.Close()
+
+		// The second packet goes to an address that can't be sent to, the others must still arrive
+		to := &net.UDPAddr{IP: net.ParseIP(test.raddr), Port: receiver.LocalAddr().(*net.UDPAddr).Port}
+		ms := make([]Message, 4)
+		for i := range ms {
+			ms[i].Buffers = [][]byte{{byte(i)}}
+			ms[i].Addr = to
+		}
+		ms[1].Addr = &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 0}
+		var failures []int
+		NewConn(sender).WriteBatch(ms, func(i int, err error) {
+			failures = append(failures, i)
+		})
+		if len(failures) != 1 || failures[0] != 1 {
+			t.Errorf("%s: expected the second packet to fail, got %v", test.laddr, failures)
+		}
+
+		receiver.SetReadDeadline(time.Now().Add(time.Second))
+		conn := NewConn(receiver)
+		read := NewMessages(Size, 16)
+		var got []byte
+		for len(got) < 3 {
+			n, err := conn.ReadBatch(read)
+			if err != nil {
+				t.Fatalf("%s: %v", test.laddr, err)
+			}
+			for _, m := range read[:n] {
+				if m.Addr.(*net.UDPAddr).Port != sender.LocalAddr().(*net.UDPAddr).Port {
+					t.Errorf("%s: unexpected sender %s", test.laddr, m.Addr)
+				}
+				got = append(got, m.Buffers[0][:m.N]...)
+			}
+		}
+		if !bytes.Equal(got, []byte{0, 2, 3}) {
+			t.Errorf("%s: expected packets 0, 2 and 3, got %v", test.laddr, got)
+		}
+	}
+}
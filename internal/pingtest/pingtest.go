@@ -0,0 +1,46 @@
+// Package pingtest starts Bedrock servers on localhost for the tests of the packages of this module,
+// so they don't each need their own copy of a pong server.
+package pingtest
+
+import (
+	"net"
+	"testing"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// Response returns the response of a test server named name, with 3 of 10 players online.
+func Response(name string) bedrockping.Response {
+	return bedrockping.Response{
+		GameID:          "MCPE",
+		ServerName:      name,
+		ProtocolVersion: 390,
+		MCPEVersion:     "1.14.60",
+		PlayerCount:     3,
+		MaxPlayers:      10,
+	}
+}
+
+// Server starts a bedrockping.Responder on localhost answering every ping with resp until the test ends,
+// returning its address.
+func Server(t testing.TB, resp bedrockping.Response) string {
+	conn := Listen(t)
+	go bedrockping.NewResponder(resp).Serve(conn)
+	return conn.LocalAddr().String()
+}
+
+// DeadAddress returns an address on localhost nothing answers pings on. The port is kept open until the
+// test ends so pings to it are dropped, rather than reported closed.
+func DeadAddress(t testing.TB) string {
+	return Listen(t).LocalAddr().String()
+}
+
+// Listen listens on a UDP port on localhost until the test ends.
+func Listen(t testing.TB) net.PacketConn {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
@@ -0,0 +1,67 @@
+// Package tracker counts the targets of batch operations as they are queried, for the progress callbacks of
+// Multiplexer.QueryMany and the scanners.
+package tracker
+
+import "sync"
+
+// Progress counts targets as they are queried and reports to a callback with the number of targets done, the
+// total, and the number being queried. The callback is never called concurrently.
+type Progress struct {
+	fn func(done, total, inflight int)
+
+	mu       sync.Mutex
+	done     int
+	total    int
+	inflight int
+}
+
+// New returns a Progress reporting to fn, which may be nil, out of total targets or -1 if there are too many
+// to count.
+func New(fn func(done, total, inflight int), total int) *Progress {
+	return &Progress{fn: fn, total: total}
+}
+
+// Total returns the number of targets, or -1 if there are too many to count.
+func (p *Progress) Total() int {
+	return p.total
+}
+
+func (p *Progress) report() {
+	if p.fn != nil {
+		p.fn(p.done, p.total, p.inflight)
+	}
+}
+
+// Start counts a target as being queried.
+func (p *Progress) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.inflight++
+	p.report()
+}
+
+// Finish counts a target that was being queried as done.
+func (p *Progress) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.inflight--
+	p.done++
+	p.report()
+}
+
+// Add counts n targets that are done without ever being counted as queried, reporting each.
+func (p *Progress) Add(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := 0; i < n; i++ {
+		p.done++
+		p.report()
+	}
+}
+
+// Resume sets the number of targets done before a resumed operation started, without reporting it.
+func (p *Progress) Resume(done int) {
+	p.mu.Lock()
+	p.done = done
+	p.mu.Unlock()
+}
@@ -0,0 +1,29 @@
+package tracker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProgress(t *testing.T) {
+	var calls [][3]int
+	p := New(func(done, total, inflight int) {
+		calls = append(calls, [3]int{done, total, inflight})
+	}, 4)
+
+	p.Resume(1)
+	p.Start()
+	p.Finish()
+	p.Add(2)
+
+	expect := [][3]int{{1, 4, 1}, {2, 4, 0}, {3, 4, 0}, {4, 4, 0}}
+	if !reflect.DeepEqual(calls, expect) {
+		t.Errorf("got calls %v, expected %v", calls, expect)
+	}
+	if p.Total() != 4 {
+		t.Errorf("got total %d, expected 4", p.Total())
+	}
+
+	// Progress may be counted without a callback
+	New(nil, -1).Start()
+}
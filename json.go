@@ -0,0 +1,306 @@
+package bedrockping
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// JSONSchemaVersion is the version of the JSON schema of Result and Response, see ResultJSON.
+// It's only incremented when fields are removed or change meaning, new fields may be added to a version.
+const JSONSchemaVersion = 1
+
+// The Extra fields of a pong, after the server's unique ID.
+const (
+	extraLevelName = 1 + iota
+	extraGameMode
+	extraGameModeID
+	extraPortV4
+	extraPortV6
+)
+
+// extra returns the Extra field at i, or "" if the server didn't send it.
+func (r Response) extra(i int) string {
+	if i < len(r.Extra) {
+		return r.Extra[i]
+	}
+	return ""
+}
+
+// extraInt returns the Extra field at i as an integer, or false if the server didn't send a valid one.
+func (r Response) extraInt(i int) (int, bool) {
+	n, err := strconv.Atoi(r.extra(i))
+	return n, err == nil
+}
+
+// LevelName returns the name of the world the server is running, with any formatting codes, or "" if it wasn't sent.
+func (r Response) LevelName() string {
+	return r.extra(extraLevelName)
+}
+
+// GameMode returns the name of the default game mode, such as "Survival", or "" if it wasn't sent.
+func (r Response) GameMode() string {
+	return r.extra(extraGameMode)
+}
+
+// GameModeID returns the numeric ID of the default game mode, 0 for survival, 1 for creative and 2 for adventure,
+// or false if it wasn't sent.
+func (r Response) GameModeID() (int, bool) {
+	return r.extraInt(extraGameModeID)
+}
+
+// PortV4 returns the IPv4 port the server listens on, or 0 if it wasn't sent.
+func (r Response) PortV4() int {
+	port, _ := r.extraInt(extraPortV4)
+	return port
+}
+
+// PortV6 returns the IPv6 port the server listens on, or 0 if it wasn't sent.
+func (r Response) PortV6() int {
+	port, _ := r.extraInt(extraPortV6)
+	return port
+}
+
+// ResponseJSON is the JSON representation of a Response, part of schema JSONSchemaVersion. Fields derived
+// from Extra are omitted if the server didn't send them, and are ignored when decoding.
+// It has the same fields in YAML, TOML and XML.
+type ResponseJSON struct {
+	Timestamp       Uint64   `json:"timestamp" yaml:"timestamp" toml:"timestamp" xml:"timestamp"`
+	ServerID        Uint64   `json:"serverId" yaml:"serverId" toml:"serverId" xml:"serverId"`
+	GameID          string   `json:"gameId" yaml:"gameId" toml:"gameId" xml:"gameId"`
+	ServerName      string   `json:"serverName" yaml:"serverName" toml:"serverName" xml:"serverName"`
+	ProtocolVersion int      `json:"protocolVersion" yaml:"protocolVersion" toml:"protocolVersion" xml:"protocolVersion"`
+	MCPEVersion     string   `json:"mcpeVersion" yaml:"mcpeVersion" toml:"mcpeVersion" xml:"mcpeVersion"`
+	PlayerCount     int      `json:"playerCount" yaml:"playerCount" toml:"playerCount" xml:"playerCount"`
+	MaxPlayers      int      `json:"maxPlayers" yaml:"maxPlayers" toml:"maxPlayers" xml:"maxPlayers"`
+	LevelName       string   `json:"levelName,omitempty" yaml:"levelName,omitempty" toml:"levelName,omitempty" xml:"levelName,omitempty"`
+	GameMode        string   `json:"gameMode,omitempty" yaml:"gameMode,omitempty" toml:"gameMode,omitempty" xml:"gameMode,omitempty"`
+	GameModeID      *int     `json:"gameModeId,omitempty" yaml:"gameModeId,omitempty" toml:"gameModeId,omitempty" xml:"gameModeId,omitempty"`
+	PortV4          int      `json:"portV4,omitempty" yaml:"portV4,omitempty" toml:"portV4,omitempty" xml:"portV4,omitempty"`
+	PortV6          int      `json:"portV6,omitempty" yaml:"portV6,omitempty" toml:"portV6,omitempty" xml:"portV6,omitempty"`
+	Extra           []string `json:"extra,omitempty" yaml:"extra,omitempty" toml:"extra,omitempty" xml:"extra>value,omitempty"`
+}
+
+// JSON returns the JSON representation of r.
+func (r Response) JSON() ResponseJSON {
+	j := ResponseJSON{
+		Timestamp:       Uint64(r.Timestamp),
+		ServerID:        Uint64(r.ServerID),
+		GameID:          r.GameID,
+		ServerName:      r.ServerName,
+		ProtocolVersion: r.ProtocolVersion,
+		MCPEVersion:     r.MCPEVersion,
+		PlayerCount:     r.PlayerCount,
+		MaxPlayers:      r.MaxPlayers,
+		LevelName:       r.LevelName(),
+		GameMode:        r.GameMode(),
+		PortV4:          r.PortV4(),
+		PortV6:          r.PortV6(),
+		Extra:           r.Extra,
+	}
+	if id, ok := r.GameModeID(); ok {
+		j.GameModeID = &id
+	}
+	return j
+}
+
+// MarshalJSON encodes r as a ResponseJSON.
+func (r Response) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.JSON())
+}
+
+// UnmarshalJSON decodes a ResponseJSON into r.
+func (r *Response) UnmarshalJSON(data []byte) error {
+	var j ResponseJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*r = j.Response()
+	return nil
+}
+
+// MarshalYAML encodes r as a ResponseJSON.
+func (r Response) MarshalYAML() (any, error) {
+	return r.JSON(), nil
+}
+
+// UnmarshalYAML decodes a ResponseJSON into r.
+func (r *Response) UnmarshalYAML(unmarshal func(any) error) error {
+	var j ResponseJSON
+	if err := unmarshal(&j); err != nil {
+		return err
+	}
+	*r = j.Response()
+	return nil
+}
+
+// Response returns the Response j represents.
+func (j ResponseJSON) Response() Response {
+	return Response{
+		Timestamp:       uint64(j.Timestamp),
+		ServerID:        uint64(j.ServerID),
+		GameID:          j.GameID,
+		ServerName:      j.ServerName,
+		ProtocolVersion: j.ProtocolVersion,
+		MCPEVersion:     j.MCPEVersion,
+		PlayerCount:     j.PlayerCount,
+		MaxPlayers:      j.MaxPlayers,
+		Extra:           j.Extra,
+	}
+}
+
+// ResultJSON is the JSON representation of a Result, schema JSONSchemaVersion:
+//
+//	{
+//	  "schemaVersion": 1,
+//	  "address": "play.example.com:19132",
+//	  "online": true,
+//	  "resolved": "203.0.113.7:19132",
+//	  "checkedAt": "2024-05-01T12:00:00Z",
+//	  "latencyMs": 23.5,
+//	  "attempts": 1,
+//	  "sent": 1,
+//	  "response": {"serverName": "My Server", "playerCount": 3, ...},
+//	  "enrichment": {"rdns": "host.example.com"}
+//	}
+//
+// Offline servers have "online": false with "error" and "errorKind" instead of "response" and "latencyMs".
+// Fields that weren't reported are omitted. It has the same fields in YAML and TOML, and in XML, see MarshalXML.
+type ResultJSON struct {
+	SchemaVersion int           `json:"schemaVersion" yaml:"schemaVersion" toml:"schemaVersion" xml:"schemaVersion,attr"`
+	Address       string        `json:"address" yaml:"address" toml:"address" xml:"address"`
+	Online        bool          `json:"online" yaml:"online" toml:"online" xml:"online"`
+	Resolved      string        `json:"resolved,omitempty" yaml:"resolved,omitempty" toml:"resolved,omitempty" xml:"resolved,omitempty"`
+	CheckedAt     time.Time     `json:"checkedAt,omitzero" yaml:"checkedAt,omitempty" toml:"checkedAt,omitempty" xml:"checkedAt,omitempty"`
+	LatencyMs     float64       `json:"latencyMs,omitempty" yaml:"latencyMs,omitempty" toml:"latencyMs,omitempty" xml:"latencyMs,omitempty"`
+	Attempts      int           `json:"attempts,omitempty" yaml:"attempts,omitempty" toml:"attempts,omitempty" xml:"attempts,omitempty"`
+	Sent          int           `json:"sent,omitempty" yaml:"sent,omitempty" toml:"sent,omitempty" xml:"sent,omitempty"`
+	Response      *ResponseJSON `json:"response,omitempty" yaml:"response,omitempty" toml:"response,omitempty" xml:"response,omitempty"`
+	// Error is the error message of an offline server, ErrorKind one of the ErrorKind constants.
+	Error      string            `json:"error,omitempty" yaml:"error,omitempty" toml:"error,omitempty" xml:"error,omitempty"`
+	ErrorKind  string            `json:"errorKind,omitempty" yaml:"errorKind,omitempty" toml:"errorKind,omitempty" xml:"errorKind,omitempty"`
+	Enrichment map[string]string `json:"enrichment,omitempty" yaml:"enrichment,omitempty" toml:"enrichment,omitempty" xml:"-"`
+}
+
+// JSON returns the JSON representation of r.
+func (r Result) JSON() ResultJSON {
+	j := ResultJSON{
+		SchemaVersion: JSONSchemaVersion,
+		Address:       r.Address,
+		Online:        r.Err == nil,
+		Resolved:      r.Resolved,
+		CheckedAt:     r.CheckedAt,
+		Attempts:      r.Attempts,
+		Sent:          r.Sent,
+		Enrichment:    r.Enrichment,
+	}
+	if r.Err != nil {
+		j.Error = r.Err.Error()
+		j.ErrorKind = ErrorKind(r.Err)
+	} else {
+		resp := r.Response.JSON()
+		j.Response = &resp
+		j.LatencyMs = float64(r.Latency) / float64(time.Millisecond)
+	}
+	return j
+}
+
+// MarshalJSON encodes r as a ResultJSON.
+func (r Result) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.JSON())
+}
+
+// UnmarshalJSON decodes a ResultJSON into r, see ResultJSON.Result.
+func (r *Result) UnmarshalJSON(data []byte) error {
+	var j ResultJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	res, err := j.Result()
+	if err != nil {
+		return err
+	}
+	*r = res
+	return nil
+}
+
+// MarshalYAML encodes r as a ResultJSON.
+func (r Result) MarshalYAML() (any, error) {
+	return r.JSON(), nil
+}
+
+// UnmarshalYAML decodes a ResultJSON into r, see ResultJSON.Result.
+func (r *Result) UnmarshalYAML(unmarshal func(any) error) error {
+	var j ResultJSON
+	if err := unmarshal(&j); err != nil {
+		return err
+	}
+	res, err := j.Result()
+	if err != nil {
+		return err
+	}
+	*r = res
+	return nil
+}
+
+// Result returns the Result j represents. The error of an offline server only keeps its message.
+// It fails for documents of a newer schema version than JSONSchemaVersion.
+func (j ResultJSON) Result() (Result, error) {
+	if j.SchemaVersion > JSONSchemaVersion {
+		return Result{}, fmt.Errorf("bedrockping: unsupported schema version %d", j.SchemaVersion)
+	}
+	res := Result{
+		Address:    j.Address,
+		Latency:    time.Duration(j.LatencyMs * float64(time.Millisecond)),
+		Attempts:   j.Attempts,
+		Sent:       j.Sent,
+		Resolved:   j.Resolved,
+		CheckedAt:  j.CheckedAt,
+		Enrichment: j.Enrichment,
+	}
+	if j.Response != nil {
+		res.Response = j.Response.Response()
+	}
+	if !j.Online {
+		res.Err = errors.New(j.Error)
+	}
+	return res, nil
+}
+
+// Uint64 is an unsigned integer such as a server ID. It's a number in JSON and YAML, but a string in TOML,
+// whose integers can't be larger than math.MaxInt64.
+type Uint64 uint64
+
+func (u Uint64) MarshalJSON() ([]byte, error) {
+	return strconv.AppendUint(nil, uint64(u), 10), nil
+}
+
+func (u *Uint64) UnmarshalJSON(data []byte) error {
+	var n uint64
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*u = Uint64(n)
+	return nil
+}
+
+func (u Uint64) MarshalYAML() (any, error) {
+	return uint64(u), nil
+}
+
+// MarshalText encodes u as a decimal string, used by TOML.
+func (u Uint64) MarshalText() ([]byte, error) {
+	return strconv.AppendUint(nil, uint64(u), 10), nil
+}
+
+// UnmarshalText decodes u from a decimal string, used by TOML and YAML.
+func (u *Uint64) UnmarshalText(text []byte) error {
+	n, err := strconv.ParseUint(string(text), 10, 64)
+	if err != nil {
+		return err
+	}
+	*u = Uint64(n)
+	return nil
+}
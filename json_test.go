@@ -0,0 +1,101 @@
+package bedrockping
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResponseFields(t *testing.T) {
+	resp := Response{Extra: []string{"12345", "§aworld", "Creative", "1", "19132", "19133", ""}}
+	if resp.LevelName() != "§aworld" || resp.GameMode() != "Creative" || resp.PortV4() != 19132 || resp.PortV6() != 19133 {
+		t.Errorf("unexpected fields: %q %q %d %d", resp.LevelName(), resp.GameMode(), resp.PortV4(), resp.PortV6())
+	}
+	if id, ok := resp.GameModeID(); !ok || id != 1 {
+		t.Errorf("expected game mode 1, got %d %v", id, ok)
+	}
+
+	resp = Response{Extra: []string{"12345"}}
+	if resp.LevelName() != "" || resp.GameMode() != "" || resp.PortV4() != 0 || resp.PortV6() != 0 {
+		t.Error("expected missing fields to be empty")
+	}
+	if _, ok := resp.GameModeID(); ok {
+		t.Error("expected no game mode")
+	}
+}
+
+func TestResultJSON(t *testing.T) {
+	checkedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	res := Result{
+		Address:  "play.example.com:19132",
+		Resolved: "203.0.113.7:19132",
+		Response: Response{GameID: "MCPE", ServerName: "My Server", ProtocolVersion: 390, MCPEVersion: "1.14.60",
+			PlayerCount: 3, MaxPlayers: 20, Extra: []string{"1", "world", "Survival", "0", "19132"}},
+		Latency:    23500 * time.Microsecond,
+		Attempts:   1,
+		Sent:       2,
+		CheckedAt:  checkedAt,
+		Enrichment: map[string]string{"rdns": "host.example.com"},
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"schemaVersion":1,"address":"play.example.com:19132","online":true,"resolved":"203.0.113.7:19132",` +
+		`"checkedAt":"2024-05-01T12:00:00Z","latencyMs":23.5,"attempts":1,"sent":2,"response":{"timestamp":0,` +
+		`"serverId":0,"gameId":"MCPE","serverName":"My Server","protocolVersion":390,"mcpeVersion":"1.14.60",` +
+		`"playerCount":3,"maxPlayers":20,"levelName":"world","gameMode":"Survival","gameModeId":0,"portV4":19132,` +
+		`"extra":["1","world","Survival","0","19132"]},"enrichment":{"rdns":"host.example.com"}}`
+	if string(data) != expected {
+		t.Errorf("unexpected JSON:\n%s\nexpected:\n%s", data, expected)
+	}
+
+	var decoded Result
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, res) {
+		t.Errorf("expected the result to round trip, got %+v", decoded)
+	}
+}
+
+func TestResultJSONOffline(t *testing.T) {
+	data, err := json.Marshal(Result{Address: "play.example.com:19132", Err: context.DeadlineExceeded})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"schemaVersion":1,"address":"play.example.com:19132","online":false,` +
+		`"error":"context deadline exceeded","errorKind":"timeout"}`
+	if string(data) != expected {
+		t.Errorf("unexpected JSON:\n%s\nexpected:\n%s", data, expected)
+	}
+
+	var res Result
+	if err := json.Unmarshal(data, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Err == nil || res.Err.Error() != "context deadline exceeded" {
+		t.Errorf("expected the error message, got %v", res.Err)
+	}
+
+	err = json.Unmarshal([]byte(`{"schemaVersion":2,"address":"a"}`), &res)
+	if err == nil || !strings.Contains(err.Error(), "schema version 2") {
+		t.Errorf("expected a newer schema to fail, got %v", err)
+	}
+}
+
+func TestCachedResultJSON(t *testing.T) {
+	res := CachedResult{Result: Result{Address: "a:1", Err: errors.New("down")}, Stale: true}
+	data, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"schemaVersion":1,"address":"a:1","online":false,"error":"down","errorKind":"other","stale":true}`
+	if string(data) != expected {
+		t.Errorf("unexpected JSON:\n%s\nexpected:\n%s", data, expected)
+	}
+}
@@ -0,0 +1,35 @@
+package bedrockping
+
+import (
+	"context"
+	"encoding/hex"
+	"log/slog"
+)
+
+// LevelTrace is the level packets are logged at, with a hex dump of their contents.
+// It's below slog.LevelDebug as it's only useful when debugging the protocol.
+const LevelTrace = slog.LevelDebug - 4
+
+// WithLogger logs the progress of queries to logger: resolving, each ping sent and retried, and the
+// outcome at slog.LevelDebug, and every packet sent and received at LevelTrace.
+// By default nothing is logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// debug logs msg at slog.LevelDebug if a logger is set.
+func (c *Client) debug(ctx context.Context, msg string, args ...any) {
+	if c.logger != nil {
+		c.logger.DebugContext(ctx, msg, args...)
+	}
+}
+
+// logPacket logs a packet at LevelTrace with a hex dump of data, only encoding it if the level is enabled.
+func (c *Client) logPacket(ctx context.Context, msg, address string, data []byte) {
+	if c.logger == nil || !c.logger.Enabled(ctx, LevelTrace) {
+		return
+	}
+	c.logger.Log(ctx, LevelTrace, msg, "address", address, "length", len(data), "dump", hex.Dump(data))
+}
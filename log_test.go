@@ -0,0 +1,52 @@
+package bedrockping
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientLogger(t *testing.T) {
+	address := startTestServer(t, testResponse("Server"))
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: LevelTrace}))
+	c := NewClient(WithLogger(logger))
+	if res := c.Ping(context.Background(), address); res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	output := buf.String()
+	for _, expect := range []string{
+		`msg=resolved address=` + address,
+		`msg="sending ping"`,
+		`msg="sent packet"`,
+		`length=25 dump="00000000  01 `,
+		`msg="received packet"`,
+		`msg="received pong" address=` + address + ` sent=1 latency=`,
+	} {
+		if !strings.Contains(output, expect) {
+			t.Errorf("missing %q in:\n%s", expect, output)
+		}
+	}
+
+	// Packets aren't logged at the debug level
+	buf.Reset()
+	logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c = NewClient(WithLogger(logger), WithTimeout(100*time.Millisecond), WithResend(20*time.Millisecond))
+	c.Ping(context.Background(), conn.LocalAddr().String())
+
+	output = buf.String()
+	if strings.Contains(output, "packet") || !strings.Contains(output, `msg="resending ping"`) || !strings.Contains(output, `msg="query ended without a pong"`) {
+		t.Errorf("unexpected log:\n%s", output)
+	}
+}
@@ -0,0 +1,135 @@
+// Package logfmtenc writes results as logfmt, a line of flat key=value pairs for each result, for shell scripts
+// and log processors such as Loki, Splunk and Vector:
+//
+//	address=play.example.com:19132 online=true latencyMs=23.5 serverName="My Server" playerCount=3 maxPlayers=20
+//
+// Keys are those of the JSON schema, bedrockping.ResultJSON, with the response's fields at the top level and
+// each enrichment value as enrichment.{key}. Fields that weren't reported are left out, formatting codes are
+// removed from names, and values are quoted if they contain spaces, quotes, "=" or control characters.
+package logfmtenc
+
+import (
+	"bytes"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// Marshal returns the logfmt line of res, without a trailing newline.
+func Marshal(res bedrockping.Result) []byte {
+	j := res.JSON()
+	var b lineBuilder
+
+	b.add("address", j.Address)
+	b.add("online", strconv.FormatBool(j.Online))
+	b.addNonEmpty("resolved", j.Resolved)
+	if !j.CheckedAt.IsZero() {
+		b.add("checkedAt", j.CheckedAt.UTC().Format(time.RFC3339Nano))
+	}
+	if j.Online {
+		b.add("latencyMs", strconv.FormatFloat(j.LatencyMs, 'f', -1, 64))
+	}
+	if j.Attempts > 0 {
+		b.add("attempts", strconv.Itoa(j.Attempts))
+	}
+	if j.Sent > 0 {
+		b.add("sent", strconv.Itoa(j.Sent))
+	}
+	b.addNonEmpty("error", j.Error)
+	b.addNonEmpty("errorKind", j.ErrorKind)
+
+	if resp := j.Response; resp != nil {
+		b.add("serverId", strconv.FormatUint(uint64(resp.ServerID), 10))
+		b.add("gameId", resp.GameID)
+		b.add("serverName", bedrockping.StripFormatting(resp.ServerName))
+		b.add("protocolVersion", strconv.Itoa(resp.ProtocolVersion))
+		b.add("mcpeVersion", resp.MCPEVersion)
+		b.add("playerCount", strconv.Itoa(resp.PlayerCount))
+		b.add("maxPlayers", strconv.Itoa(resp.MaxPlayers))
+		b.addNonEmpty("levelName", bedrockping.StripFormatting(resp.LevelName))
+		b.addNonEmpty("gameMode", resp.GameMode)
+		if resp.GameModeID != nil {
+			b.add("gameModeId", strconv.Itoa(*resp.GameModeID))
+		}
+		if resp.PortV4 != 0 {
+			b.add("portV4", strconv.Itoa(resp.PortV4))
+		}
+		if resp.PortV6 != 0 {
+			b.add("portV6", strconv.Itoa(resp.PortV6))
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(j.Enrichment)) {
+		b.add("enrichment."+key, j.Enrichment[key])
+	}
+	return b.Bytes()
+}
+
+// Encoder writes results to a stream as logfmt lines.
+type Encoder struct {
+	w io.Writer
+}
+
+// NewEncoder returns an Encoder writing to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes res as a line.
+func (e *Encoder) Encode(res bedrockping.Result) error {
+	_, err := e.w.Write(append(Marshal(res), '\n'))
+	return err
+}
+
+// Write writes results to w, a line for each.
+func Write(w io.Writer, results []bedrockping.Result) error {
+	enc := NewEncoder(w)
+	for _, res := range results {
+		if err := enc.Encode(res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lineBuilder builds a logfmt line.
+type lineBuilder struct {
+	bytes.Buffer
+}
+
+// add adds the pair key=value.
+func (b *lineBuilder) add(key, value string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(key)
+	b.WriteByte('=')
+	if needsQuotes(value) {
+		b.WriteString(strconv.Quote(value))
+	} else {
+		b.WriteString(value)
+	}
+}
+
+// addNonEmpty adds the pair key=value unless value is empty.
+func (b *lineBuilder) addNonEmpty(key, value string) {
+	if value != "" {
+		b.add(key, value)
+	}
+}
+
+// needsQuotes reports whether value must be quoted to be read back as a single value.
+func needsQuotes(value string) bool {
+	if value == "" || !utf8.ValidString(value) {
+		return true
+	}
+	return strings.ContainsFunc(value, func(r rune) bool {
+		return r <= ' ' || r == '=' || r == '"' || r == '\\' || unicode.IsSpace(r) || unicode.IsControl(r)
+	})
+}
@@ -0,0 +1,61 @@
+package logfmtenc
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+func TestWrite(t *testing.T) {
+	checkedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	results := []bedrockping.Result{
+		{
+			Address: "play.example.com:19132",
+			Response: bedrockping.Response{ServerID: 1<<64 - 1, GameID: "MCPE", ServerName: `§aMy "Server"`,
+				ProtocolVersion: 390, MCPEVersion: "1.14.60", PlayerCount: 3, MaxPlayers: 20,
+				Extra: []string{"1", "§bWorld", "Survival", "0", "19132", "19133"}},
+			Latency:    23500 * time.Microsecond,
+			Attempts:   1,
+			Sent:       1,
+			Resolved:   "203.0.113.7:19132",
+			CheckedAt:  checkedAt,
+			Enrichment: map[string]string{"rdns": "host.example.com", "region": "eu west"},
+		},
+		{Address: "down.example.com:19132", Attempts: 2, Err: context.DeadlineExceeded},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	expect := `address=play.example.com:19132 online=true resolved=203.0.113.7:19132 checkedAt=2024-05-01T12:00:00Z ` +
+		`latencyMs=23.5 attempts=1 sent=1 serverId=18446744073709551615 gameId=MCPE serverName="My \"Server\"" ` +
+		`protocolVersion=390 mcpeVersion=1.14.60 playerCount=3 maxPlayers=20 levelName=World gameMode=Survival ` +
+		`gameModeId=0 portV4=19132 portV6=19133 enrichment.rdns=host.example.com enrichment.region="eu west"` + "\n" +
+		`address=down.example.com:19132 online=false attempts=2 error="context deadline exceeded" errorKind=timeout` + "\n"
+	if buf.String() != expect {
+		t.Errorf("got\n%s\nexpected\n%s", buf.String(), expect)
+	}
+}
+
+func TestNeedsQuotes(t *testing.T) {
+	for value, quote := range map[string]bool{
+		"plain":     false,
+		"1.14.60":   false,
+		"":          true,
+		"two words": true,
+		"a=b":       true,
+		`"`:         true,
+		`back\`:     true,
+		"tab\t":     true,
+		"\xff":      true,
+		"Ünïcödé":   false,
+	} {
+		if got := needsQuotes(value); got != quote {
+			t.Errorf("needsQuotes(%q) = %v, expected %v", value, got, quote)
+		}
+	}
+}
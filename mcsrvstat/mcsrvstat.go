@@ -0,0 +1,200 @@
+// Package mcsrvstat converts results to the JSON of version 3 of the mcsrvstat.us API for Bedrock servers,
+// https://api.mcsrvstat.us/bedrock/3/{address}, so frontends and Discord bots written against it can switch
+// to self-hosted pings without rewriting their parsers. httpapi.Server serves it at the same path.
+//
+// Only the fields a Bedrock pong can fill are set. The API's query and SRV debug flags are always false,
+// as servers are only pinged.
+package mcsrvstat
+
+import (
+	"html"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// APIVersion is the version of the mcsrvstat.us API the JSON is compatible with.
+const APIVersion = 3
+
+// Status is the status of a server as returned by the mcsrvstat.us API. Fields other than Online, IP,
+// Port, Hostname and Debug are omitted for offline servers.
+type Status struct {
+	Online   bool   `json:"online"`
+	IP       string `json:"ip"`
+	Port     int    `json:"port"`
+	Hostname string `json:"hostname,omitempty"`
+	Debug    Debug  `json:"debug"`
+
+	Version  string    `json:"version,omitempty"`
+	Protocol *Protocol `json:"protocol,omitempty"`
+	MOTD     *Text     `json:"motd,omitempty"`
+	Players  *Players  `json:"players,omitempty"`
+	Map      *Text     `json:"map,omitempty"`
+	GameMode string    `json:"gamemode,omitempty"`
+	ServerID string    `json:"serverid,omitempty"`
+}
+
+// Debug holds how the status was found and cached.
+type Debug struct {
+	Ping          bool  `json:"ping"`
+	Query         bool  `json:"query"`
+	SRV           bool  `json:"srv"`
+	QueryMismatch bool  `json:"querymismatch"`
+	IPInSRV       bool  `json:"ipinsrv"`
+	CNAMEInSRV    bool  `json:"cnameinsrv"`
+	AnimatedMOTD  bool  `json:"animatedmotd"`
+	CacheHit      bool  `json:"cachehit"`
+	CacheTime     int64 `json:"cachetime"`
+	CacheExpire   int64 `json:"cacheexpire"`
+	APIVersion    int   `json:"apiversion"`
+}
+
+// Protocol is the network protocol version of the server.
+type Protocol struct {
+	Version int `json:"version"`
+}
+
+// Players is the number of players online and the maximum.
+type Players struct {
+	Online int `json:"online"`
+	Max    int `json:"max"`
+}
+
+// Text is text with formatting codes, as sent, with them removed and as HTML.
+// The MOTD has a line for the server name and one for the level name, if it was sent.
+type Text struct {
+	Raw   []string `json:"raw"`
+	Clean []string `json:"clean"`
+	HTML  []string `json:"html"`
+}
+
+// newText returns the Text of lines.
+func newText(lines ...string) *Text {
+	t := &Text{Raw: lines, Clean: make([]string, len(lines)), HTML: make([]string, len(lines))}
+	for i, line := range lines {
+		t.Clean[i] = bedrockping.StripFormatting(line)
+		t.HTML[i] = HTMLFormatting(line)
+	}
+	return t
+}
+
+// New returns the Status for the result of a ping made at checkedAt, which is cached until ttl later.
+// The IP and port are those that were pinged if the result has them, otherwise those of its address.
+func New(res bedrockping.Result, checkedAt time.Time, ttl time.Duration) Status {
+	host, port := splitAddress(res.Address)
+	s := Status{
+		Online: res.Err == nil,
+		IP:     host,
+		Port:   port,
+		Debug: Debug{
+			Ping:        true,
+			CacheTime:   checkedAt.Unix(),
+			CacheExpire: checkedAt.Add(ttl).Unix(),
+			APIVersion:  APIVersion,
+		},
+	}
+	if net.ParseIP(host) == nil {
+		s.Hostname = host
+		s.IP = ""
+	}
+	if ip, port := splitAddress(res.Resolved); ip != "" {
+		s.IP, s.Port = ip, port
+	}
+	if res.Err != nil {
+		return s
+	}
+
+	resp := res.Response
+	s.Version = resp.MCPEVersion
+	s.Protocol = &Protocol{Version: resp.ProtocolVersion}
+	s.Players = &Players{Online: resp.PlayerCount, Max: resp.MaxPlayers}
+	s.GameMode = resp.GameMode()
+	s.ServerID = strconv.FormatUint(resp.ServerID, 10)
+	if level := resp.LevelName(); level != "" {
+		s.MOTD = newText(resp.ServerName, level)
+		s.Map = newText(level)
+	} else {
+		s.MOTD = newText(resp.ServerName)
+	}
+	return s
+}
+
+// splitAddress returns the host and port of address, or "" if it has neither.
+func splitAddress(address string) (string, int) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return strings.Trim(address, "[]"), 0
+	}
+	n, _ := strconv.Atoi(port)
+	return host, n
+}
+
+// htmlColors maps Minecraft color codes to the colors they're shown in.
+var htmlColors = map[rune]string{
+	'0': "#000000",
+	'1': "#0000AA",
+	'2': "#00AA00",
+	'3': "#00AAAA",
+	'4': "#AA0000",
+	'5': "#AA00AA",
+	'6': "#FFAA00",
+	'7': "#AAAAAA",
+	'8': "#555555",
+	'9': "#5555FF",
+	'a': "#55FF55",
+	'b': "#55FFFF",
+	'c': "#FF5555",
+	'd': "#FF55FF",
+	'e': "#FFFF55",
+	'f': "#FFFFFF",
+	'g': "#DDD605",
+}
+
+// htmlStyles maps Minecraft formatting codes other than colors to CSS.
+var htmlStyles = map[rune]string{
+	'l': "font-weight: bold",
+	'o': "font-style: italic",
+	'm': "text-decoration: line-through",
+	'n': "text-decoration: underline",
+}
+
+// HTMLFormatting escapes s for HTML and replaces its Minecraft formatting codes with styled spans, like the
+// html fields of the mcsrvstat.us API. A color or "§r" closes the spans opened before it, as in game.
+func HTMLFormatting(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) * 2)
+
+	open := 0
+	closeSpans := func() {
+		b.WriteString(strings.Repeat("</span>", open))
+		open = 0
+	}
+	skip := false
+	for _, r := range s {
+		switch {
+		case skip:
+			skip = false
+			code := unicode.ToLower(r)
+			if color, ok := htmlColors[code]; ok {
+				closeSpans()
+				b.WriteString(`<span style="color: ` + color + `">`)
+				open++
+			} else if style, ok := htmlStyles[code]; ok {
+				b.WriteString(`<span style="` + style + `">`)
+				open++
+			} else if code == 'r' {
+				closeSpans()
+			}
+		case r == bedrockping.FormattingCode:
+			skip = true
+		default:
+			b.WriteString(html.EscapeString(string(r)))
+		}
+	}
+	closeSpans()
+	return b.String()
+}
@@ -0,0 +1,71 @@
+package mcsrvstat
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+func TestNew(t *testing.T) {
+	checkedAt := time.Unix(1700000000, 0)
+	res := bedrockping.Result{
+		Address: "play.example.com:19132",
+		Response: bedrockping.Response{ServerID: 1<<64 - 1, GameID: "MCPE", ServerName: "§aMy Server",
+			ProtocolVersion: 390, MCPEVersion: "1.14.60", PlayerCount: 3, MaxPlayers: 20,
+			Extra: []string{"1", "§bWorld", "Survival"}},
+		Resolved: "203.0.113.7:19132",
+	}
+
+	data, err := json.Marshal(New(res, checkedAt, time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `{"online":true,"ip":"203.0.113.7","port":19132,"hostname":"play.example.com",` +
+		`"debug":{"ping":true,"query":false,"srv":false,"querymismatch":false,"ipinsrv":false,"cnameinsrv":false,` +
+		`"animatedmotd":false,"cachehit":false,"cachetime":1700000000,"cacheexpire":1700000060,"apiversion":3},` +
+		`"version":"1.14.60","protocol":{"version":390},` +
+		`"motd":{"raw":["§aMy Server","§bWorld"],"clean":["My Server","World"],` +
+		`"html":["<span style=\"color: #55FF55\">My Server</span>",` +
+		`"<span style=\"color: #55FFFF\">World</span>"]},` +
+		`"players":{"online":3,"max":20},` +
+		`"map":{"raw":["§bWorld"],"clean":["World"],"html":["<span style=\"color: #55FFFF\">World</span>"]},` +
+		`"gamemode":"Survival","serverid":"18446744073709551615"}`
+	var got, want any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(expect), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n%s\nexpected\n%s", data, expect)
+	}
+}
+
+func TestNewOffline(t *testing.T) {
+	s := New(bedrockping.Result{Address: "203.0.113.7:19133", Err: context.DeadlineExceeded}, time.Now(), time.Minute)
+	if s.Online || s.IP != "203.0.113.7" || s.Port != 19133 || s.Hostname != "" || s.MOTD != nil || s.Players != nil {
+		t.Errorf("unexpected status %+v", s)
+	}
+}
+
+func TestHTMLFormatting(t *testing.T) {
+	tests := map[string]string{
+		"Plain <b>":        "Plain &lt;b&gt;",
+		"§aGreen §lBold":   `<span style="color: #55FF55">Green <span style="font-weight: bold">Bold</span></span>`,
+		"§lBold§r Reset":   `<span style="font-weight: bold">Bold</span> Reset`,
+		"§aA§cB":           `<span style="color: #55FF55">A</span><span style="color: #FF5555">B</span>`,
+		"§kHidden§":        "Hidden",
+		"§CUpper":          `<span style="color: #FF5555">Upper</span>`,
+		"Ünïcödé & §6Gold": `Ünïcödé &amp; <span style="color: #FFAA00">Gold</span>`,
+	}
+	for input, expect := range tests {
+		if got := HTMLFormatting(input); got != expect {
+			t.Errorf("HTMLFormatting(%q) = %q, expected %q", input, got, expect)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package bedrockping
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// clientMetrics are the OpenTelemetry instruments a Client records to.
+type clientMetrics struct {
+	rtt    metric.Float64Histogram
+	errors metric.Int64Counter
+}
+
+// WithMeterProvider records OpenTelemetry metrics for every query made by the Client:
+// a bedrockping.client.rtt histogram of the latency of successful queries in seconds and a
+// bedrockping.client.errors counter of failed queries by error.type, see ErrorKind.
+// Metrics aren't recorded by default.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *Client) {
+		c.metrics = newClientMetrics(provider)
+	}
+}
+
+func newClientMetrics(provider metric.MeterProvider) *clientMetrics {
+	meter := provider.Meter(instrumentationName)
+
+	// Creating an instrument only fails for invalid names or units, the instrument returned
+	// alongside the error is still usable
+	rtt, _ := meter.Float64Histogram("bedrockping.client.rtt",
+		metric.WithDescription("Round trip time of successful queries."), metric.WithUnit("s"))
+	errors, _ := meter.Int64Counter("bedrockping.client.errors",
+		metric.WithDescription("Number of failed queries."), metric.WithUnit("{query}"))
+	return &clientMetrics{rtt: rtt, errors: errors}
+}
+
+// defaultMetrics discards measurements.
+var defaultMetrics = newClientMetrics(noop.NewMeterProvider())
+
+// record records the outcome of res.
+func (m *clientMetrics) record(ctx context.Context, res Result) {
+	if res.Err != nil {
+		m.errors.Add(ctx, 1, metric.WithAttributes(attribute.String("error.type", ErrorKind(res.Err))))
+		return
+	}
+	m.rtt.Record(ctx, res.Latency.Seconds())
+}
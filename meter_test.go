@@ -0,0 +1,57 @@
+package bedrockping
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestClientMetrics(t *testing.T) {
+	address := startTestServer(t, testResponse("Measured"))
+
+	// Nothing reads from this socket so pings are never answered
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	reader := sdkmetric.NewManualReader()
+	c := NewClient(WithTimeout(100*time.Millisecond), WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	c.Ping(context.Background(), address)
+	c.Ping(context.Background(), address)
+	c.Ping(context.Background(), conn.LocalAddr().String())
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	var found int
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Histogram[float64]:
+				found++
+				if m.Name != "bedrockping.client.rtt" || len(data.DataPoints) != 1 || data.DataPoints[0].Count != 2 {
+					t.Errorf("unexpected histogram %s: %+v", m.Name, data)
+				}
+			case metricdata.Sum[int64]:
+				found++
+				if m.Name != "bedrockping.client.errors" || len(data.DataPoints) != 1 || data.DataPoints[0].Value != 1 {
+					t.Fatalf("unexpected counter %s: %+v", m.Name, data)
+				}
+				if kind, _ := data.DataPoints[0].Attributes.Value("error.type"); kind.AsString() != ErrorKindTimeout {
+					t.Errorf("unexpected error.type: %v", kind)
+				}
+			}
+		}
+	}
+	if found != 2 {
+		t.Errorf("expected 2 metrics, got %d", found)
+	}
+}
@@ -0,0 +1,197 @@
+package metrics
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+const (
+	defaultGraphitePrefix  = "bedrock."
+	defaultGraphiteTimeout = 5 * time.Second
+
+	// After a failed connection attempt reconnecting waits between minGraphiteRetry and maxGraphiteRetry,
+	// doubling with each failure
+	minGraphiteRetry = time.Second
+	maxGraphiteRetry = time.Minute
+)
+
+// ErrGraphiteUnavailable is returned by Graphite.Write while it waits to reconnect to an unreachable server.
+var ErrGraphiteUnavailable = errors.New("metrics: graphite server unavailable")
+
+// Graphite sends the updates of a monitor.Monitor to a Graphite/Carbon server using the plaintext
+// protocol over TCP, as metrics such as bedrock.play_example_com_19132.latency in seconds, up,
+// players and max_players. Register it with Monitor.OnUpdate:
+//
+//	m.OnUpdate((&metrics.Graphite{Address: "carbon:2003"}).Observe)
+//
+// The connection is opened on the first update, if it breaks it's reopened and the write retried once,
+// so metrics are only lost while the server is unreachable. After failing to connect, updates are dropped
+// without trying again for a while, backing off up to a minute, so they don't each wait for a connection
+// that's going to fail.
+type Graphite struct {
+	// Address is the host:port of the Carbon plaintext listener.
+	Address string
+	// Prefix is prepended to every metric name, the default is "bedrock.".
+	Prefix string
+	// Timeout limits connecting and writing, the default is 5 seconds.
+	Timeout time.Duration
+	// OnError, if set, is called with errors sending metrics from Observe.
+	OnError func(error)
+
+	mu   sync.Mutex
+	conn *graphiteConn
+	// dialing is set while a connection is being opened, without holding mu
+	dialing bool
+	// retry is how long to wait before reconnecting after the last failure, and retryAt when that is
+	retry   time.Duration
+	retryAt time.Time
+}
+
+// graphiteConn is a connection to a Carbon server, with a goroutine reading from it to notice it closing.
+type graphiteConn struct {
+	net.Conn
+	closed chan struct{}
+}
+
+func newGraphiteConn(conn net.Conn) *graphiteConn {
+	c := &graphiteConn{Conn: conn, closed: make(chan struct{})}
+	go func() {
+		// Carbon never sends anything, so reads only return once the connection is closed
+		var b [64]byte
+		for {
+			if _, err := conn.Read(b[:]); err != nil {
+				close(c.closed)
+				return
+			}
+		}
+	}()
+	return c
+}
+
+// broken reports whether the connection has been closed by the server. A write to a connection the server
+// has closed usually succeeds and the data is silently lost, so this is checked before writing.
+func (c *graphiteConn) broken() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Observe sends the metrics for u, reporting any error to OnError.
+func (g *Graphite) Observe(u monitor.Update) {
+	if err := g.Write(u); err != nil && g.OnError != nil {
+		g.OnError(err)
+	}
+}
+
+// Write sends the metrics for u, reconnecting if the connection has broken.
+func (g *Graphite) Write(u monitor.Update) error {
+	prefix := g.Prefix
+	if prefix == "" {
+		prefix = defaultGraphitePrefix
+	}
+	prefix += metricName(u.Address) + "."
+	timestamp := " " + strconv.FormatInt(u.Time.Unix(), 10) + "\n"
+
+	var buf []byte
+	line := func(name, value string) {
+		buf = append(buf, prefix...)
+		buf = append(buf, name...)
+		buf = append(buf, ' ')
+		buf = append(buf, value...)
+		buf = append(buf, timestamp...)
+	}
+	up := 0
+	if u.Up {
+		up = 1
+		line("latency", strconv.FormatFloat(u.Latency.Seconds(), 'f', -1, 64))
+	}
+	line("up", strconv.Itoa(up))
+	if u.Response != nil {
+		line("players", strconv.Itoa(u.Response.PlayerCount))
+		line("max_players", strconv.Itoa(u.Response.MaxPlayers))
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	reused := g.conn != nil && !g.conn.broken()
+	if err := g.connect(); err != nil {
+		return err
+	}
+	// The connection may still break while writing
+	err := g.write(buf)
+	if err != nil && reused {
+		if err = g.connect(); err == nil {
+			err = g.write(buf)
+		}
+	}
+	return err
+}
+
+func (g *Graphite) timeout() time.Duration {
+	if g.Timeout <= 0 {
+		return defaultGraphiteTimeout
+	}
+	return g.Timeout
+}
+
+// connect opens a connection if there isn't a working one, unless it's waiting to retry after a failure
+// or another update is already connecting. g.mu must be held, it's released while dialing.
+func (g *Graphite) connect() error {
+	if g.conn != nil && g.conn.broken() {
+		g.conn.Close()
+		g.conn = nil
+	}
+	if g.conn != nil {
+		return nil
+	}
+	if g.dialing || time.Now().Before(g.retryAt) {
+		return ErrGraphiteUnavailable
+	}
+
+	g.dialing = true
+	g.mu.Unlock()
+	conn, err := net.DialTimeout("tcp", g.Address, g.timeout())
+	g.mu.Lock()
+	g.dialing = false
+	if err != nil {
+		g.retry = min(max(2*g.retry, minGraphiteRetry), maxGraphiteRetry)
+		g.retryAt = time.Now().Add(g.retry)
+		return err
+	}
+	g.retry = 0
+	g.retryAt = time.Time{}
+	g.conn = newGraphiteConn(conn)
+	return nil
+}
+
+// write writes buf to the connection, closing it if that fails. g.mu must be held.
+func (g *Graphite) write(buf []byte) error {
+	g.conn.SetWriteDeadline(time.Now().Add(g.timeout()))
+	if _, err := g.conn.Write(buf); err != nil {
+		g.conn.Close()
+		g.conn = nil
+		return err
+	}
+	return nil
+}
+
+// Close closes the connection to the Carbon server, if any.
+func (g *Graphite) Close() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.conn == nil {
+		return nil
+	}
+	err := g.conn.Close()
+	g.conn = nil
+	return err
+}
@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+)
+
+// startCarbon starts a TCP server that sends the lines it receives to the returned channel,
+// closing each connection after the first batch of lines.
+func startCarbon(t *testing.T) (string, <-chan []string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	batches := make(chan []string, 10)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			var lines []string
+			scanner := bufio.NewScanner(conn)
+			conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+			for scanner.Scan() {
+				lines = append(lines, scanner.Text())
+				// Every batch ends with the up line, or max_players if there's a response
+				if len(lines) == 4 {
+					break
+				}
+			}
+			conn.Close()
+			batches <- lines
+		}
+	}()
+
+	return l.Addr().String(), batches
+}
+
+func TestGraphite(t *testing.T) {
+	address, batches := startCarbon(t)
+
+	var errs []error
+	g := &Graphite{Address: address, OnError: func(err error) { errs = append(errs, err) }}
+	defer g.Close()
+
+	expect := []string{
+		"bedrock.play_example_com_19132.latency 0.0125 1577934245",
+		"bedrock.play_example_com_19132.up 1 1577934245",
+		"bedrock.play_example_com_19132.players 3 1577934245",
+		"bedrock.play_example_com_19132.max_players 20 1577934245",
+	}
+
+	// The server closes the connection after every batch, so each update needs a reconnect
+	for i := 0; i < 3; i++ {
+		g.Observe(testUpdate())
+
+		select {
+		case lines := <-batches:
+			if len(lines) != len(expect) {
+				t.Fatalf("got %q", lines)
+			}
+			for j := range expect {
+				if lines[j] != expect[j] {
+					t.Errorf("got %q, expected %q", lines[j], expect[j])
+				}
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("update %d wasn't received", i)
+		}
+		// Give the close time to arrive
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(errs) != 0 {
+		t.Error(errs)
+	}
+}
+
+func TestGraphiteUnreachable(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := l.Addr().String()
+	l.Close()
+
+	var errs []error
+	g := &Graphite{Address: address, Timeout: 100 * time.Millisecond, OnError: func(err error) { errs = append(errs, err) }}
+	g.Observe(testUpdate())
+	if len(errs) != 1 {
+		t.Errorf("expected an error, got %v", errs)
+	}
+}
+
+func TestGraphiteBackoff(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := l.Addr().String()
+	l.Close()
+
+	g := &Graphite{Address: address, Timeout: 100 * time.Millisecond}
+	if err := g.Write(testUpdate()); err == nil || err == ErrGraphiteUnavailable {
+		t.Fatalf("expected the connection to fail, got %v", err)
+	}
+	// Updates until the retry time are dropped without trying to connect again
+	if err := g.Write(testUpdate()); err != ErrGraphiteUnavailable {
+		t.Errorf("expected ErrGraphiteUnavailable, got %v", err)
+	}
+
+	// Once the retry time has passed the server is connected to again
+	l, err = net.Listen("tcp", address)
+	if err != nil {
+		t.Skip("couldn't listen on the address again:", err)
+	}
+	defer l.Close()
+	g.mu.Lock()
+	g.retryAt = time.Now()
+	g.mu.Unlock()
+	if err := g.Write(testUpdate()); err != nil {
+		t.Errorf("expected the write to succeed after reconnecting, got %v", err)
+	}
+	g.Close()
+}
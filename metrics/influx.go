@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+const (
+	defaultInfluxMeasurement = "bedrock"
+	defaultInfluxTimeout     = 5 * time.Second
+)
+
+var (
+	influxTagEscaper    = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+	influxStringEscaper = strings.NewReplacer(`"`, `\"`, `\`, `\\`)
+)
+
+// AppendInflux appends u to dst encoded as an InfluxDB line protocol point of measurement,
+// tagged with the host, and returns the extended buffer. Latency is in seconds.
+func AppendInflux(dst []byte, measurement string, u monitor.Update) []byte {
+	dst = append(dst, influxTagEscaper.Replace(measurement)...)
+	dst = append(dst, ",host="...)
+	dst = append(dst, influxTagEscaper.Replace(u.Address)...)
+
+	up := 0
+	if u.Up {
+		up = 1
+	}
+	dst = append(dst, " up="...)
+	dst = strconv.AppendInt(dst, int64(up), 10)
+	dst = append(dst, `i,state="`...)
+	dst = append(dst, u.State.String()...)
+	dst = append(dst, '"')
+	if u.Up {
+		dst = append(dst, ",latency="...)
+		dst = strconv.AppendFloat(dst, u.Latency.Seconds(), 'f', -1, 64)
+	}
+	if u.Response != nil {
+		dst = append(dst, ",players="...)
+		dst = strconv.AppendInt(dst, int64(u.Response.PlayerCount), 10)
+		dst = append(dst, "i,max_players="...)
+		dst = strconv.AppendInt(dst, int64(u.Response.MaxPlayers), 10)
+		dst = append(dst, `i,version="`...)
+		dst = append(dst, influxStringEscaper.Replace(u.Response.MCPEVersion)...)
+		dst = append(dst, '"')
+	}
+	if u.Err != nil {
+		dst = append(dst, `,error="`...)
+		dst = append(dst, influxStringEscaper.Replace(u.Err.Error())...)
+		dst = append(dst, '"')
+	}
+
+	dst = append(dst, ' ')
+	dst = strconv.AppendInt(dst, u.Time.UnixNano(), 10)
+	return append(dst, '\n')
+}
+
+// Influx writes the updates of a monitor.Monitor as InfluxDB line protocol points,
+// either to Writer or to the InfluxDB v2 HTTP write API if URL is set.
+// Register it with Monitor.OnUpdate:
+//
+//	m.OnUpdate((&metrics.Influx{URL: "http://localhost:8086", Org: "org", Bucket: "bedrock", Token: token}).Observe)
+type Influx struct {
+	// Measurement is the name of the measurement, the default is "bedrock".
+	Measurement string
+
+	// Writer receives the points when URL isn't set.
+	Writer io.Writer
+
+	// URL is the base URL of the InfluxDB server.
+	URL string
+	// Org and Bucket are where points are written.
+	Org    string
+	Bucket string
+	// Token is the API token used to authenticate, if any.
+	Token string
+	// Client is the HTTP client used to write, http.DefaultClient is used if nil.
+	Client *http.Client
+	// Timeout limits each write from Observe, the default is 5 seconds.
+	Timeout time.Duration
+
+	// OnError, if set, is called with errors writing points from Observe.
+	OnError func(error)
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+// Observe writes u, reporting any error to OnError. It's called from the monitor's ping loop,
+// so the write is bound by Timeout rather than holding up pings while InfluxDB is unresponsive.
+func (i *Influx) Observe(u monitor.Update) {
+	timeout := i.Timeout
+	if timeout <= 0 {
+		timeout = defaultInfluxTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := i.Write(ctx, u); err != nil && i.OnError != nil {
+		i.OnError(err)
+	}
+}
+
+// Write writes u to Writer or the HTTP API.
+func (i *Influx) Write(ctx context.Context, u monitor.Update) error {
+	measurement := i.Measurement
+	if measurement == "" {
+		measurement = defaultInfluxMeasurement
+	}
+
+	if i.URL == "" {
+		// Reuse the buffer, writes to Writer are serialized anyway
+		i.mu.Lock()
+		defer i.mu.Unlock()
+		i.buf = AppendInflux(i.buf[:0], measurement, u)
+		_, err := i.Writer.Write(i.buf)
+		return err
+	}
+
+	return i.post(ctx, AppendInflux(nil, measurement, u))
+}
+
+func (i *Influx) post(ctx context.Context, body []byte) error {
+	query := url.Values{"org": {i.Org}, "bucket": {i.Bucket}, "precision": {"ns"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(i.URL, "/")+"/api/v2/write?"+query.Encode(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if i.Token != "" {
+		req.Header.Set("Authorization", "Token "+i.Token)
+	}
+
+	client := i.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("metrics: unexpected status %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
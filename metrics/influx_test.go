@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+func TestAppendInflux(t *testing.T) {
+	u := testUpdate()
+	u.Response.MCPEVersion = "1.14.60"
+
+	expect := `bedrock,host=play.example.com:19132 up=1i,state="up",latency=0.0125,players=3i,max_players=20i,version="1.14.60" 1577934245000000000` + "\n"
+	if got := string(AppendInflux(nil, "bedrock", u)); got != expect {
+		t.Errorf("got %q", got)
+	}
+
+	down := monitor.Update{
+		Address: "a b,c",
+		Time:    time.Unix(1, 0),
+		State:   monitor.StateDown,
+		Err:     errors.New(`read "udp": refused`),
+	}
+	expect = `my\ server,host=a\ b\,c up=0i,state="down",error="read \"udp\": refused" 1000000000` + "\n"
+	if got := string(AppendInflux(nil, "my server", down)); got != expect {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestInfluxWriter(t *testing.T) {
+	var buf bytes.Buffer
+	i := &Influx{Writer: &buf, Measurement: "mc"}
+	i.Observe(testUpdate())
+	i.Observe(testUpdate())
+
+	line := string(AppendInflux(nil, "mc", testUpdate()))
+	if buf.String() != line+line {
+		t.Errorf("got %q", buf.String())
+	}
+}
+
+func TestInfluxHTTP(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/write" || r.URL.Query().Get("bucket") != "bedrock" || r.URL.Query().Get("org") != "org" {
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+		if r.Header.Get("Authorization") != "Token secret" {
+			t.Errorf("unexpected authorization: %s", r.Header.Get("Authorization"))
+		}
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var errs []error
+	i := &Influx{URL: server.URL + "/", Org: "org", Bucket: "bedrock", Token: "secret", OnError: func(err error) { errs = append(errs, err) }}
+	i.Observe(testUpdate())
+
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if string(body) != string(AppendInflux(nil, "bedrock", testUpdate())) {
+		t.Errorf("got %q", body)
+	}
+}
+
+func TestInfluxHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	var errs []error
+	i := &Influx{URL: server.URL, OnError: func(err error) { errs = append(errs, err) }}
+	i.Observe(testUpdate())
+	if len(errs) != 1 {
+		t.Errorf("expected an error, got %v", errs)
+	}
+}
+
+func TestInfluxHTTPTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	var errs []error
+	i := &Influx{URL: server.URL, Timeout: 50 * time.Millisecond, OnError: func(err error) { errs = append(errs, err) }}
+	start := time.Now()
+	i.Observe(testUpdate())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the write to give up after the timeout, took %s", elapsed)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], context.DeadlineExceeded) {
+		t.Errorf("expected a deadline exceeded error, got %v", errs)
+	}
+}
@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+var (
+	probeSuccessDesc = prometheus.NewDesc("probe_success",
+		"Whether the server responded (1) or not (0).", nil, nil)
+	probeDurationDesc = prometheus.NewDesc("probe_duration_seconds",
+		"How long the probe took.", nil, nil)
+	probePlayersDesc = prometheus.NewDesc("bedrock_player_count",
+		"Number of players online.", nil, nil)
+	probeMaxPlayersDesc = prometheus.NewDesc("bedrock_max_players",
+		"Maximum number of players.", nil, nil)
+	probeLatencyDesc = prometheus.NewDesc("bedrock_latency_seconds",
+		"Round trip time of the ping that was answered.", nil, nil)
+)
+
+// ProbeHandler returns a handler that pings the server in the target query parameter with client and
+// responds with the outcome in the Prometheus exposition format, like the blackbox_exporter:
+//
+//	/probe?target=play.example.com:19132
+//
+// Prometheus passes the target using relabeling, so a single exporter can probe any number of servers
+// without them being configured in advance. The probe is cut short by the scrape timeout Prometheus sends.
+func ProbeHandler(client *bedrockping.Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.URL.Query().Get("target")
+		if host == "" {
+			http.Error(w, "target parameter is missing", http.StatusBadRequest)
+			return
+		}
+
+		ctx := r.Context()
+		if v := r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds"); v != "" {
+			if seconds, err := strconv.ParseFloat(v, 64); err == nil && seconds > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, time.Duration(seconds*float64(time.Second)))
+				defer cancel()
+			}
+		}
+
+		start := time.Now()
+		res := client.Ping(ctx, bedrockping.Target{Host: host}.Address())
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(probeCollector{res: res, duration: time.Since(start)})
+		promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	})
+}
+
+// probeCollector exposes the result of a single probe.
+type probeCollector struct {
+	res      bedrockping.Result
+	duration time.Duration
+}
+
+func (c probeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- probeSuccessDesc
+	ch <- probeDurationDesc
+	ch <- probePlayersDesc
+	ch <- probeMaxPlayersDesc
+	ch <- probeLatencyDesc
+}
+
+func (c probeCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(probeDurationDesc, prometheus.GaugeValue, c.duration.Seconds())
+	if c.res.Err != nil {
+		ch <- prometheus.MustNewConstMetric(probeSuccessDesc, prometheus.GaugeValue, 0)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(probeSuccessDesc, prometheus.GaugeValue, 1)
+	ch <- prometheus.MustNewConstMetric(probePlayersDesc, prometheus.GaugeValue, float64(c.res.Response.PlayerCount))
+	ch <- prometheus.MustNewConstMetric(probeMaxPlayersDesc, prometheus.GaugeValue, float64(c.res.Response.MaxPlayers))
+	ch <- prometheus.MustNewConstMetric(probeLatencyDesc, prometheus.GaugeValue, c.res.Latency.Seconds())
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+func probe(t *testing.T, handler http.Handler, target string) (int, string) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/probe?target=" + url.QueryEscape(target))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestProbeHandler(t *testing.T) {
+	address := pingtest.Server(t, pingtest.Response("Test"))
+	handler := ProbeHandler(bedrockping.NewClient(bedrockping.WithTimeout(100 * time.Millisecond)))
+
+	status, body := probe(t, handler, address)
+	if status != http.StatusOK {
+		t.Fatalf("status %d: %s", status, body)
+	}
+	for _, line := range []string{"probe_success 1", "bedrock_player_count 3", "bedrock_max_players 10", "bedrock_latency_seconds "} {
+		if !strings.Contains(body, line) {
+			t.Errorf("missing %q:\n%s", line, body)
+		}
+	}
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_, body = probe(t, handler, conn.LocalAddr().String())
+	if !strings.Contains(body, "probe_success 0") || strings.Contains(body, "bedrock_player_count") {
+		t.Errorf("unexpected metrics for offline server:\n%s", body)
+	}
+
+	if status, _ := probe(t, handler, ""); status != http.StatusBadRequest {
+		t.Errorf("status %d without target", status)
+	}
+}
@@ -0,0 +1,72 @@
+// Package metrics exports the state of monitored servers to metrics systems.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+var (
+	upDesc = prometheus.NewDesc("bedrock_server_up",
+		"Whether the server is up (1) or down (0), after flap damping.", []string{"host"}, nil)
+	playersDesc = prometheus.NewDesc("bedrock_player_count",
+		"Number of players online as of the latest successful ping.", []string{"host"}, nil)
+	maxPlayersDesc = prometheus.NewDesc("bedrock_max_players",
+		"Maximum number of players as of the latest successful ping.", []string{"host"}, nil)
+	latencyDesc = prometheus.NewDesc("bedrock_latency_seconds",
+		"Round trip time of the latest successful ping.", []string{"host"}, nil)
+	lossDesc = prometheus.NewDesc("bedrock_packet_loss_ratio",
+		"Fraction of pings lost over the monitor's loss window.", []string{"host"}, nil)
+	jitterDesc = prometheus.NewDesc("bedrock_jitter_seconds",
+		"RFC 3550 interarrival jitter of pings.", []string{"host"}, nil)
+)
+
+// Collector is a prometheus.Collector exposing the state of every target of a monitor.Monitor,
+// labeled by host. Metrics are read from the monitor when scraped, targets that haven't been
+// pinged yet are omitted.
+type Collector struct {
+	monitor *monitor.Monitor
+}
+
+// NewCollector creates a Collector for m. Register it with a prometheus.Registerer to expose it.
+func NewCollector(m *monitor.Monitor) *Collector {
+	return &Collector{monitor: m}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- upDesc
+	ch <- playersDesc
+	ch <- maxPlayersDesc
+	ch <- latencyDesc
+	ch <- lossDesc
+	ch <- jitterDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, address := range c.monitor.Targets() {
+		u, ok := c.monitor.Status(address)
+		if !ok {
+			continue
+		}
+
+		up := 0.0
+		if u.State == monitor.StateUp {
+			up = 1
+		}
+		ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, up, address)
+
+		if u.Response != nil {
+			ch <- prometheus.MustNewConstMetric(playersDesc, prometheus.GaugeValue, float64(u.Response.PlayerCount), address)
+			ch <- prometheus.MustNewConstMetric(maxPlayersDesc, prometheus.GaugeValue, float64(u.Response.MaxPlayers), address)
+			ch <- prometheus.MustNewConstMetric(latencyDesc, prometheus.GaugeValue, u.Latency.Seconds(), address)
+		}
+
+		if stats, ok := c.monitor.Stats(address); ok {
+			ch <- prometheus.MustNewConstMetric(lossDesc, prometheus.GaugeValue, stats.Loss/100, address)
+			ch <- prometheus.MustNewConstMetric(jitterDesc, prometheus.GaugeValue, stats.Jitter.Seconds(), address)
+		}
+	}
+}
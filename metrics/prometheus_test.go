@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+// startMonitor starts a monitor of address and waits for the first update.
+func startMonitor(t *testing.T, address string) *monitor.Monitor {
+	client := bedrockping.NewClient(bedrockping.WithTimeout(100*time.Millisecond), bedrockping.WithResend(20*time.Millisecond))
+	m := monitor.New(client, monitor.WithThresholds(1, 1))
+	if err := m.Add(monitor.Target{Address: address, Interval: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+
+	updates, unsubscribe := m.Subscribe(1)
+	defer unsubscribe()
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(m.Stop)
+
+	select {
+	case <-updates:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for update")
+	}
+	return m
+}
+
+func TestCollector(t *testing.T) {
+	address := pingtest.Server(t, pingtest.Response("Scraped"))
+	m := startMonitor(t, address)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewCollector(m))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			if label := metric.GetLabel()[0]; label.GetName() != "host" || label.GetValue() != address {
+				t.Errorf("%s: unexpected label %v", family.GetName(), label)
+			}
+			values[family.GetName()] = metric.GetGauge().GetValue()
+		}
+	}
+
+	if values["bedrock_server_up"] != 1 || values["bedrock_player_count"] != 3 || values["bedrock_max_players"] != 10 {
+		t.Errorf("unexpected values: %v", values)
+	}
+	if values["bedrock_latency_seconds"] <= 0 {
+		t.Errorf("expected latency, got %v", values["bedrock_latency_seconds"])
+	}
+	if _, ok := values["bedrock_packet_loss_ratio"]; !ok {
+		t.Errorf("missing loss: %v", values)
+	}
+}
@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"bytes"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+const defaultStatsDPrefix = "bedrock."
+
+// StatsD emits the updates of a monitor.Monitor as StatsD metrics over UDP: a latency timing in
+// milliseconds for successful pings, and up, players and max_players gauges.
+// Register it with Monitor.OnUpdate:
+//
+//	m.OnUpdate((&metrics.StatsD{Address: "127.0.0.1:8125", DogStatsD: true}).Observe)
+//
+// Plain StatsD has no tags, so the host is part of the metric name, such as bedrock.play_example_com_19132.latency.
+// With DogStatsD the name is bedrock.latency with a host tag instead.
+type StatsD struct {
+	// Address is the host:port of the StatsD server.
+	Address string
+	// Prefix is prepended to every metric name, the default is "bedrock.".
+	Prefix string
+	// DogStatsD enables the DogStatsD extension, sending the host and Tags as tags.
+	DogStatsD bool
+	// Tags are added to every metric when DogStatsD is enabled.
+	Tags map[string]string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// Observe sends the metrics for u. Errors are ignored, like any other lost UDP packet.
+func (s *StatsD) Observe(u monitor.Update) {
+	var buf bytes.Buffer
+	up := 0
+	if u.Up {
+		up = 1
+		s.write(&buf, u.Address, "latency", strconv.FormatFloat(float64(u.Latency.Microseconds())/1000, 'f', -1, 64), "ms")
+	}
+	s.write(&buf, u.Address, "up", strconv.Itoa(up), "g")
+	if u.Response != nil {
+		s.write(&buf, u.Address, "players", strconv.Itoa(u.Response.PlayerCount), "g")
+		s.write(&buf, u.Address, "max_players", strconv.Itoa(u.Response.MaxPlayers), "g")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		conn, err := net.Dial("udp", s.Address)
+		if err != nil {
+			return
+		}
+		s.conn = conn
+	}
+	s.conn.Write(buf.Bytes())
+}
+
+// write appends a single metric line to buf.
+func (s *StatsD) write(buf *bytes.Buffer, address, name, value, kind string) {
+	prefix := s.Prefix
+	if prefix == "" {
+		prefix = defaultStatsDPrefix
+	}
+
+	buf.WriteString(prefix)
+	if !s.DogStatsD {
+		buf.WriteString(metricName(address))
+		buf.WriteByte('.')
+	}
+	buf.WriteString(name)
+	buf.WriteByte(':')
+	buf.WriteString(value)
+	buf.WriteByte('|')
+	buf.WriteString(kind)
+
+	if s.DogStatsD {
+		buf.WriteString("|#host:")
+		buf.WriteString(address)
+		keys := make([]string, 0, len(s.Tags))
+		for k := range s.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			buf.WriteByte(',')
+			buf.WriteString(k)
+			buf.WriteByte(':')
+			buf.WriteString(s.Tags[k])
+		}
+	}
+	buf.WriteByte('\n')
+}
+
+// Close closes the connection to the StatsD server, if any.
+func (s *StatsD) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// metricName converts address into a single metric path component, replacing the separators
+// used by metrics systems.
+func metricName(address string) string {
+	return strings.NewReplacer(".", "_", ":", "_", "[", "", "]", "", " ", "_").Replace(address)
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+// listenStatsD returns a StatsD server address and a function reading the next packet sent to it.
+func listenStatsD(t *testing.T) (string, func() string) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn.LocalAddr().String(), func() string {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		buf := make([]byte, 1500)
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(buf[:n])
+	}
+}
+
+func testUpdate() monitor.Update {
+	return monitor.Update{
+		Address:  "play.example.com:19132",
+		Time:     time.Unix(1577934245, 0),
+		Up:       true,
+		State:    monitor.StateUp,
+		Response: &bedrockping.Response{ServerName: "Server", PlayerCount: 3, MaxPlayers: 20},
+		Latency:  12500 * time.Microsecond,
+	}
+}
+
+func TestStatsD(t *testing.T) {
+	address, read := listenStatsD(t)
+
+	s := &StatsD{Address: address}
+	defer s.Close()
+	s.Observe(testUpdate())
+
+	expect := "bedrock.play_example_com_19132.latency:12.5|ms\n" +
+		"bedrock.play_example_com_19132.up:1|g\n" +
+		"bedrock.play_example_com_19132.players:3|g\n" +
+		"bedrock.play_example_com_19132.max_players:20|g\n"
+	if got := read(); got != expect {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestDogStatsD(t *testing.T) {
+	address, read := listenStatsD(t)
+
+	s := &StatsD{Address: address, Prefix: "mc.", DogStatsD: true, Tags: map[string]string{"region": "eu", "env": "prod"}}
+	defer s.Close()
+	s.Observe(monitor.Update{Address: "play.example.com:19132", Err: bedrockping.ErrMultiplexerClosed})
+
+	if got := read(); got != "mc.up:0|g|#host:play.example.com:19132,env:prod,region:eu\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestMetricName(t *testing.T) {
+	if name := metricName("[::1]:19132"); name != "__1_19132" {
+		t.Errorf("got %s", name)
+	}
+}
@@ -0,0 +1,52 @@
+package monitor
+
+import (
+	"expvar"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+type monitorVar struct {
+	Client  bedrockping.ClientStats `json:"client"`
+	Targets map[string]targetVar    `json:"targets"`
+}
+
+type targetVar struct {
+	State         State   `json:"state"`
+	PingsSent     int     `json:"pingsSent"`
+	PongsReceived int     `json:"pongsReceived"`
+	Loss          float64 `json:"loss"`
+	// Latencies are in seconds
+	LatencyP50 float64 `json:"latencyP50"`
+	LatencyP99 float64 `json:"latencyP99"`
+	Jitter     float64 `json:"jitter"`
+}
+
+// Var returns an expvar.Var reporting the counters of the Client and the state and Stats
+// of every target, so it can be published with expvar.Publish:
+//
+//	expvar.Publish("monitor", m.Var())
+func (m *Monitor) Var() expvar.Var {
+	return expvar.Func(func() any {
+		v := monitorVar{Client: m.client.Stats(), Targets: make(map[string]targetVar)}
+		for _, address := range m.Targets() {
+			stats, ok := m.Stats(address)
+			if !ok {
+				continue
+			}
+			t := targetVar{
+				PingsSent:     stats.PingsSent,
+				PongsReceived: stats.PongsReceived,
+				Loss:          stats.Loss,
+				LatencyP50:    stats.Latency.P50().Seconds(),
+				LatencyP99:    stats.Latency.P99().Seconds(),
+				Jitter:        stats.Jitter.Seconds(),
+			}
+			if u, ok := m.Status(address); ok {
+				t.State = u.State
+			}
+			v.Targets[address] = t
+		}
+		return v
+	})
+}
@@ -0,0 +1,53 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+func TestMonitorVar(t *testing.T) {
+	address := pingtest.Server(t, pingtest.Response("Published"))
+
+	m := New(testClient(), WithThresholds(1, 1))
+	m.Add(Target{Address: address, Interval: time.Hour})
+
+	updates, unsubscribe := m.Subscribe(1)
+	defer unsubscribe()
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+
+	select {
+	case <-updates:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for update")
+	}
+
+	var v struct {
+		Client struct {
+			PongsReceived int `json:"pongsReceived"`
+		} `json:"client"`
+		Targets map[string]struct {
+			State         string  `json:"state"`
+			PongsReceived int     `json:"pongsReceived"`
+			LatencyP50    float64 `json:"latencyP50"`
+		} `json:"targets"`
+	}
+	if err := json.Unmarshal([]byte(m.Var().String()), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	target, ok := v.Targets[address]
+	if !ok {
+		t.Fatalf("missing target: %s", m.Var())
+	}
+	if v.Client.PongsReceived != 1 || target.State != "up" || target.PongsReceived != 1 || target.LatencyP50 <= 0 {
+		t.Errorf("unexpected var: %s", m.Var())
+	}
+}
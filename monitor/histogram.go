@@ -0,0 +1,149 @@
+package monitor
+
+import (
+	"math/bits"
+	"time"
+)
+
+// histogramSubBuckets is the number of linear buckets per power of two,
+// bounding the relative error of recorded values to under 1%.
+const (
+	histogramSubBucketBits = 7
+	histogramSubBuckets    = 1 << histogramSubBucketBits
+	histogramHalfBuckets   = histogramSubBuckets / 2
+)
+
+// Histogram records latencies in log-linear buckets in the style of an HDR histogram,
+// keeping percentiles accurate to within 1% using a small fixed amount of memory
+// regardless of how many samples are recorded. Values are recorded with microsecond resolution.
+// The zero value is an empty Histogram. A Histogram is not safe for concurrent use.
+type Histogram struct {
+	counts []uint64
+	count  uint64
+	sum    time.Duration
+	min    time.Duration
+	max    time.Duration
+}
+
+// histogramIndex returns the bucket holding the value v.
+func histogramIndex(v uint64) int {
+	if v < histogramSubBuckets {
+		return int(v)
+	}
+	shift := uint(bits.Len64(v) - histogramSubBucketBits)
+	return histogramSubBuckets + int(shift-1)*histogramHalfBuckets + int(v>>shift) - histogramHalfBuckets
+}
+
+// histogramValue returns the value in the middle of the bucket i.
+func histogramValue(i int) uint64 {
+	if i < histogramSubBuckets {
+		return uint64(i)
+	}
+	k := i - histogramSubBuckets
+	shift := uint(k/histogramHalfBuckets + 1)
+	m := uint64(k%histogramHalfBuckets + histogramHalfBuckets)
+	low := m << shift
+	high := (m+1)<<shift - 1
+	return low + (high-low)/2
+}
+
+// Record adds a latency to the histogram, negative values are recorded as 0.
+func (h *Histogram) Record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+
+	i := histogramIndex(uint64(d / time.Microsecond))
+	if i >= len(h.counts) {
+		counts := make([]uint64, i+1)
+		copy(counts, h.counts)
+		h.counts = counts
+	}
+	h.counts[i]++
+
+	if h.count == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.count++
+	h.sum += d
+}
+
+// Count returns the number of recorded values.
+func (h *Histogram) Count() uint64 {
+	return h.count
+}
+
+// Min returns the smallest recorded value.
+func (h *Histogram) Min() time.Duration {
+	return h.min
+}
+
+// Max returns the largest recorded value.
+func (h *Histogram) Max() time.Duration {
+	return h.max
+}
+
+// Mean returns the average of the recorded values.
+func (h *Histogram) Mean() time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+	return h.sum / time.Duration(h.count)
+}
+
+// Percentile returns the value below which p percent of recorded values fall, p is between 0 and 100.
+func (h *Histogram) Percentile(p float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+	if p >= 100 {
+		return h.max
+	}
+
+	rank := uint64(p / 100 * float64(h.count))
+	if rank < 1 {
+		rank = 1
+	}
+
+	var seen uint64
+	for i, count := range h.counts {
+		seen += count
+		if seen >= rank {
+			v := time.Duration(histogramValue(i)) * time.Microsecond
+			// The middle of a bucket may lie outside of what was actually recorded
+			if v < h.min {
+				return h.min
+			}
+			if v > h.max {
+				return h.max
+			}
+			return v
+		}
+	}
+	return h.max
+}
+
+// P50 returns the median recorded value.
+func (h *Histogram) P50() time.Duration {
+	return h.Percentile(50)
+}
+
+// P95 returns the 95th percentile of recorded values.
+func (h *Histogram) P95() time.Duration {
+	return h.Percentile(95)
+}
+
+// P99 returns the 99th percentile of recorded values.
+func (h *Histogram) P99() time.Duration {
+	return h.Percentile(99)
+}
+
+// Clone returns an independent copy of the histogram.
+func (h *Histogram) Clone() *Histogram {
+	c := *h
+	c.counts = append([]uint64(nil), h.counts...)
+	return &c
+}
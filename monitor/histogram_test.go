@@ -0,0 +1,62 @@
+package monitor
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestHistogramIndex(t *testing.T) {
+	// Every value must land in a bucket whose middle is within 1% of it
+	for _, v := range []uint64{0, 1, 127, 128, 129, 255, 256, 1000, 12345, 999999, 3600000000} {
+		mid := histogramValue(histogramIndex(v))
+		if diff := math.Abs(float64(mid) - float64(v)); v > 0 && diff/float64(v) > 0.01 {
+			t.Errorf("value %d landed in bucket with middle %d", v, mid)
+		}
+	}
+
+	// Indexes are contiguous
+	for v := uint64(histogramSubBuckets - 1); v < 100000; v++ {
+		if histogramIndex(v+1)-histogramIndex(v) > 1 {
+			t.Fatalf("gap in indexes between %d and %d", v, v+1)
+		}
+	}
+}
+
+func TestHistogramPercentiles(t *testing.T) {
+	var h Histogram
+	for i := 1; i <= 1000; i++ {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+
+	if h.Count() != 1000 {
+		t.Errorf("unexpected count: %d", h.Count())
+	}
+	if h.Min() != time.Millisecond || h.Max() != time.Second {
+		t.Errorf("unexpected min/max: %s/%s", h.Min(), h.Max())
+	}
+	if mean := h.Mean(); mean != 500500*time.Microsecond {
+		t.Errorf("unexpected mean: %s", mean)
+	}
+
+	tests := []struct {
+		p      time.Duration
+		expect time.Duration
+	}{
+		{h.P50(), 500 * time.Millisecond},
+		{h.P95(), 950 * time.Millisecond},
+		{h.P99(), 990 * time.Millisecond},
+	}
+	for _, test := range tests {
+		if diff := math.Abs(float64(test.p - test.expect)); diff/float64(test.expect) > 0.01 {
+			t.Errorf("expected about %s, got %s", test.expect, test.p)
+		}
+	}
+}
+
+func TestHistogramEmpty(t *testing.T) {
+	var h Histogram
+	if h.P99() != 0 || h.Mean() != 0 {
+		t.Error("expected zero values for empty histogram")
+	}
+}
@@ -0,0 +1,24 @@
+package monitor
+
+import "time"
+
+// jitter estimates interarrival jitter from consecutive round trip times,
+// smoothed the same way as RFC 3550 section 6.4.1.
+type jitter struct {
+	value   time.Duration
+	last    time.Duration
+	samples int
+}
+
+// observe records a round trip time.
+func (j *jitter) observe(rtt time.Duration) {
+	if j.samples > 0 {
+		d := rtt - j.last
+		if d < 0 {
+			d = -d
+		}
+		j.value += (d - j.value) / 16
+	}
+	j.last = rtt
+	j.samples++
+}
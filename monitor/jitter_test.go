@@ -0,0 +1,36 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitter(t *testing.T) {
+	var j jitter
+
+	j.observe(100 * time.Millisecond)
+	if j.value != 0 {
+		t.Errorf("expected no jitter from a single sample, got %s", j.value)
+	}
+
+	j.observe(116 * time.Millisecond)
+	if j.value != time.Millisecond {
+		t.Errorf("expected 1ms jitter, got %s", j.value)
+	}
+
+	// A steady RTT decays the jitter towards 0
+	for i := 0; i < 200; i++ {
+		j.observe(116 * time.Millisecond)
+	}
+	if j.value > time.Microsecond {
+		t.Errorf("expected jitter to decay, got %s", j.value)
+	}
+
+	// Alternating RTTs converge on the difference between them
+	for i := 0; i < 500; i++ {
+		j.observe(time.Duration(100+10*(i%2)) * time.Millisecond)
+	}
+	if j.value < 9*time.Millisecond || j.value > 10*time.Millisecond {
+		t.Errorf("expected jitter of about 10ms, got %s", j.value)
+	}
+}
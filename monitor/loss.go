@@ -0,0 +1,39 @@
+package monitor
+
+// lossWindow tracks pings sent and pongs received over the last size queries.
+type lossWindow struct {
+	size     int
+	sent     []int
+	received []int
+	next     int
+}
+
+// observe records a query that sent pings and received pongs.
+func (w *lossWindow) observe(sent, received int) {
+	if len(w.sent) < w.size {
+		w.sent = append(w.sent, sent)
+		w.received = append(w.received, received)
+		return
+	}
+	w.sent[w.next] = sent
+	w.received[w.next] = received
+	w.next = (w.next + 1) % w.size
+}
+
+// totals returns the number of pings sent and pongs received in the window.
+func (w *lossWindow) totals() (sent, received int) {
+	for i := range w.sent {
+		sent += w.sent[i]
+		received += w.received[i]
+	}
+	return sent, received
+}
+
+// loss returns the percentage of pings in the window that went unanswered.
+func (w *lossWindow) loss() float64 {
+	sent, received := w.totals()
+	if sent == 0 {
+		return 0
+	}
+	return float64(sent-received) / float64(sent) * 100
+}
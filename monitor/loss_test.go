@@ -0,0 +1,27 @@
+package monitor
+
+import "testing"
+
+func TestLossWindow(t *testing.T) {
+	w := lossWindow{size: 3}
+
+	if loss := w.loss(); loss != 0 {
+		t.Errorf("expected no loss for empty window, got %f", loss)
+	}
+
+	w.observe(1, 1)
+	w.observe(3, 1) // Two resends before an answer
+	w.observe(4, 0) // Timed out
+	if sent, received := w.totals(); sent != 8 || received != 2 {
+		t.Errorf("expected 8 sent and 2 received, got %d and %d", sent, received)
+	}
+	if loss := w.loss(); loss != 75 {
+		t.Errorf("expected 75%% loss, got %f", loss)
+	}
+
+	// The first query falls out of the window
+	w.observe(2, 1)
+	if sent, received := w.totals(); sent != 9 || received != 2 {
+		t.Errorf("expected 9 sent and 2 received, got %d and %d", sent, received)
+	}
+}
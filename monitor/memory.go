@@ -0,0 +1,97 @@
+package monitor
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store keeping the latest samples of each address in memory,
+// for lightweight embedding where history doesn't need to survive restarts.
+// Samples of an address are expected to be saved in time order, as the Monitor does.
+type MemoryStore struct {
+	size int
+
+	mu    sync.Mutex
+	rings map[string]*ring
+}
+
+// ring is a fixed-size circular buffer of samples, oldest first.
+type ring struct {
+	samples []Sample
+	start   int
+	count   int
+}
+
+// at returns the i-th oldest sample.
+func (r *ring) at(i int) Sample {
+	return r.samples[(r.start+i)%len(r.samples)]
+}
+
+// NewMemoryStore creates a MemoryStore keeping the latest size samples of each address.
+func NewMemoryStore(size int) *MemoryStore {
+	if size < 1 {
+		size = 1
+	}
+	return &MemoryStore{size: size, rings: make(map[string]*ring)}
+}
+
+// SaveSample implements Store, overwriting the oldest sample of the address if it already has size samples.
+func (s *MemoryStore) SaveSample(ctx context.Context, sample Sample) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.rings[sample.Address]
+	if !ok {
+		r = &ring{samples: make([]Sample, s.size)}
+		s.rings[sample.Address] = r
+	}
+
+	if r.count < len(r.samples) {
+		r.samples[(r.start+r.count)%len(r.samples)] = sample
+		r.count++
+	} else {
+		r.samples[r.start] = sample
+		r.start = (r.start + 1) % len(r.samples)
+	}
+	return nil
+}
+
+// QueryRange implements Store.
+func (s *MemoryStore) QueryRange(ctx context.Context, address string, from, to time.Time) ([]Sample, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.rings[address]
+	if !ok {
+		return nil, nil
+	}
+
+	first := sort.Search(r.count, func(i int) bool { return !r.at(i).Time.Before(from) })
+	end := sort.Search(r.count, func(i int) bool { return !r.at(i).Time.Before(to) })
+
+	var samples []Sample
+	for i := first; i < end; i++ {
+		samples = append(samples, r.at(i))
+	}
+	return samples, nil
+}
+
+// Prune implements Store. Addresses left without samples are forgotten.
+func (s *MemoryStore) Prune(ctx context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for address, r := range s.rings {
+		for r.count > 0 && r.at(0).Time.Before(before) {
+			r.samples[r.start] = Sample{}
+			r.start = (r.start + 1) % len(r.samples)
+			r.count--
+		}
+		if r.count == 0 {
+			delete(s.rings, address)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package monitor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+	"github.com/ZeroErrors/go-bedrockping/monitor/storetest"
+)
+
+func TestMemoryStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) monitor.Store {
+		return monitor.NewMemoryStore(100)
+	})
+}
+
+func TestMemoryStoreWraps(t *testing.T) {
+	s := monitor.NewMemoryStore(3)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		s.SaveSample(context.Background(), monitor.Sample{Address: "a", Time: start.Add(time.Duration(i) * time.Second), PlayerCount: i})
+	}
+
+	samples, err := s.QueryRange(context.Background(), "a", start, start.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 3 {
+		t.Fatalf("expected the latest 3 samples, got %+v", samples)
+	}
+	for i, sample := range samples {
+		if sample.PlayerCount != i+2 {
+			t.Errorf("sample %d: got %+v", i, sample)
+		}
+	}
+
+	samples, _ = s.QueryRange(context.Background(), "a", start.Add(3*time.Second), start.Add(4*time.Second))
+	if len(samples) != 1 || samples[0].PlayerCount != 3 {
+		t.Errorf("unexpected range: %+v", samples)
+	}
+}
@@ -0,0 +1,464 @@
+// Package monitor periodically pings Minecraft Bedrock/MCPE servers and reports their state.
+package monitor
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+const (
+	defaultInterval   = 30 * time.Second
+	defaultLossWindow = 100
+)
+
+var (
+	// ErrRunning is returned by Start when the Monitor is already running.
+	ErrRunning = errors.New("monitor: already running")
+	// ErrDuplicateTarget is returned by Add when the address is already monitored.
+	ErrDuplicateTarget = errors.New("monitor: duplicate target")
+)
+
+// Target configures a monitored server. In YAML and TOML its keys are those of the targets in the
+// configuration file of the bedrockping command, such as down_after.
+type Target struct {
+	// Address is the host:port of the server.
+	Address string `yaml:"address" toml:"address"`
+	// Interval is how often the server is pinged, the default is 30 seconds.
+	Interval time.Duration `yaml:"interval,omitempty" toml:"interval,omitempty"`
+	// DownAfter overrides the number of consecutive failed pings before the server is declared down.
+	DownAfter int `yaml:"down_after,omitempty" toml:"down_after,omitempty"`
+	// UpAfter overrides the number of consecutive successful pings before the server is declared up.
+	UpAfter int `yaml:"up_after,omitempty" toml:"up_after,omitempty"`
+	// LatencyThreshold overrides the latency above which a LatencyThreshold event is emitted.
+	LatencyThreshold time.Duration `yaml:"latency_threshold,omitempty" toml:"latency_threshold,omitempty"`
+}
+
+// Update is the outcome of a single ping of a monitored server.
+// Up reports whether this ping succeeded, State is the damped state after it.
+type Update struct {
+	Address  string                `json:"address"`
+	Time     time.Time             `json:"time"`
+	Up       bool                  `json:"up"`
+	State    State                 `json:"state"`
+	Response *bedrockping.Response `json:"response,omitempty"`
+	Latency  time.Duration         `json:"latency"`
+	Err      error                 `json:"-"`
+}
+
+// Monitor periodically pings a set of targets, each in its own goroutine,
+// and reports the outcome to subscribers and callbacks.
+// A Monitor is safe for concurrent use.
+type Monitor struct {
+	client     *bedrockping.Client
+	downAfter  int
+	upAfter    int
+	lossWindow int
+	latency    time.Duration
+
+	notifiers     []Notifier
+	notifyTimeout time.Duration
+	notifyError   func(Notifier, Event, error)
+
+	store      Store
+	retention  time.Duration
+	storeError func(error)
+
+	mu               sync.Mutex
+	targets          map[string]*target
+	subscribers      map[chan Update]struct{}
+	callbacks        []func(Update)
+	eventSubscribers map[chan Event]struct{}
+	eventCallbacks   []func(Event)
+	ctx              context.Context
+	cancel           context.CancelFunc
+	wg               sync.WaitGroup
+}
+
+// target is the state of a monitored server.
+type target struct {
+	Target
+	cancel  context.CancelFunc
+	latest  *Update
+	state   stateMachine
+	latency Histogram
+	loss    lossWindow
+	jitter  jitter
+
+	latencyThreshold time.Duration
+	slow             bool
+}
+
+// Stats are the statistics collected for a monitored server.
+type Stats struct {
+	// Latency is a histogram of the latency of successful pings since the server was added.
+	Latency *Histogram
+	// PingsSent and PongsReceived count individual packets over the loss window, see WithLossWindow.
+	PingsSent     int
+	PongsReceived int
+	// Loss is the percentage of ping packets that went unanswered over the loss window.
+	// Unlike failed pings, which only happen once every resend times out, this reflects
+	// packets lost even when the server is up.
+	Loss float64
+	// Jitter is the smoothed variation between consecutive latencies, as defined by RFC 3550.
+	Jitter time.Duration
+}
+
+// Option configures a Monitor.
+type Option func(*Monitor)
+
+// WithThresholds sets the number of consecutive failed pings before a server is declared down,
+// and successful pings before it is declared up again, so alerts aren't triggered by a single
+// lost datagram. The defaults are 3 and 1.
+func WithThresholds(downAfter, upAfter int) Option {
+	return func(m *Monitor) {
+		m.downAfter = downAfter
+		m.upAfter = upAfter
+	}
+}
+
+// WithLossWindow sets the number of most recent pings of each server that packet loss is calculated over.
+// The default is 100, which is also used if pings isn't positive.
+func WithLossWindow(pings int) Option {
+	return func(m *Monitor) {
+		if pings <= 0 {
+			pings = defaultLossWindow
+		}
+		m.lossWindow = pings
+	}
+}
+
+// WithLatencyThreshold emits a LatencyThreshold event when a server's latency rises above threshold,
+// and again when it falls back below it. By default latency isn't checked.
+func WithLatencyThreshold(threshold time.Duration) Option {
+	return func(m *Monitor) {
+		m.latency = threshold
+	}
+}
+
+// New creates a Monitor that pings servers with client.
+// If client is nil a Client with default settings is used.
+func New(client *bedrockping.Client, opts ...Option) *Monitor {
+	if client == nil {
+		client = bedrockping.NewClient()
+	}
+	m := &Monitor{
+		client:           client,
+		downAfter:        defaultDownAfter,
+		upAfter:          defaultUpAfter,
+		lossWindow:       defaultLossWindow,
+		notifyTimeout:    defaultNotifyTimeout,
+		targets:          make(map[string]*target),
+		subscribers:      make(map[chan Update]struct{}),
+		eventSubscribers: make(map[chan Event]struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Add starts monitoring t. If the Monitor is running pinging starts immediately,
+// otherwise it starts when Start is called.
+func (m *Monitor) Add(t Target) error {
+	if t.Interval <= 0 {
+		t.Interval = defaultInterval
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.targets[t.Address]; ok {
+		return ErrDuplicateTarget
+	}
+
+	tgt := &target{Target: t}
+	tgt.loss.size = m.lossWindow
+	tgt.latencyThreshold = m.latency
+	if t.LatencyThreshold > 0 {
+		tgt.latencyThreshold = t.LatencyThreshold
+	}
+	tgt.state.downAfter = m.downAfter
+	if t.DownAfter > 0 {
+		tgt.state.downAfter = t.DownAfter
+	}
+	tgt.state.upAfter = m.upAfter
+	if t.UpAfter > 0 {
+		tgt.state.upAfter = t.UpAfter
+	}
+	m.targets[t.Address] = tgt
+	if m.ctx != nil {
+		m.startTarget(tgt)
+	}
+	return nil
+}
+
+// Remove stops monitoring the server with address.
+func (m *Monitor) Remove(address string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if tgt, ok := m.targets[address]; ok {
+		if tgt.cancel != nil {
+			tgt.cancel()
+		}
+		delete(m.targets, address)
+	}
+}
+
+// Targets returns the addresses of every monitored server, sorted.
+func (m *Monitor) Targets() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	addresses := make([]string, 0, len(m.targets))
+	for address := range m.targets {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+	return addresses
+}
+
+// Status returns the latest update for the server with address,
+// or false if it isn't monitored or hasn't been pinged yet.
+func (m *Monitor) Status(address string) (Update, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tgt, ok := m.targets[address]
+	if !ok || tgt.latest == nil {
+		return Update{}, false
+	}
+	return *tgt.latest, true
+}
+
+// Stats returns a snapshot of the statistics for the server with address, or false if it isn't monitored.
+func (m *Monitor) Stats(address string) (Stats, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tgt, ok := m.targets[address]
+	if !ok {
+		return Stats{}, false
+	}
+	stats := Stats{Latency: tgt.latency.Clone(), Loss: tgt.loss.loss(), Jitter: tgt.jitter.value}
+	stats.PingsSent, stats.PongsReceived = tgt.loss.totals()
+	return stats, true
+}
+
+// Subscribe returns a channel receiving every update, buffered to hold buffer updates.
+// Updates are dropped rather than blocking the Monitor if the channel is full.
+// The returned function unsubscribes and closes the channel.
+func (m *Monitor) Subscribe(buffer int) (<-chan Update, func()) {
+	ch := make(chan Update, buffer)
+
+	m.mu.Lock()
+	m.subscribers[ch] = struct{}{}
+	m.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			m.mu.Lock()
+			delete(m.subscribers, ch)
+			m.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// OnUpdate registers fn to be called with every update.
+// fn is called from the goroutine monitoring the target, so it should return quickly.
+func (m *Monitor) OnUpdate(fn func(Update)) {
+	m.mu.Lock()
+	m.callbacks = append(m.callbacks, fn)
+	m.mu.Unlock()
+}
+
+// SubscribeEvents returns a channel receiving every event, buffered to hold buffer events.
+// Events are dropped rather than blocking the Monitor if the channel is full.
+// The returned function unsubscribes and closes the channel.
+func (m *Monitor) SubscribeEvents(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+
+	m.mu.Lock()
+	m.eventSubscribers[ch] = struct{}{}
+	m.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			m.mu.Lock()
+			delete(m.eventSubscribers, ch)
+			m.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// OnEvent registers fn to be called with every event.
+// fn is called from the goroutine monitoring the target, so it should return quickly.
+func (m *Monitor) OnEvent(fn func(Event)) {
+	m.mu.Lock()
+	m.eventCallbacks = append(m.eventCallbacks, fn)
+	m.mu.Unlock()
+}
+
+// Start starts pinging every target until ctx is done or Stop is called.
+func (m *Monitor) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ctx != nil {
+		return ErrRunning
+	}
+
+	m.ctx, m.cancel = context.WithCancel(ctx)
+	for _, tgt := range m.targets {
+		m.startTarget(tgt)
+	}
+	if m.store != nil && m.retention > 0 {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			m.prune(m.ctx)
+		}()
+	}
+	return nil
+}
+
+// Stop stops pinging and waits for every in-flight ping to finish.
+// The Monitor may be started again afterwards.
+func (m *Monitor) Stop() {
+	m.mu.Lock()
+	if m.ctx == nil {
+		m.mu.Unlock()
+		return
+	}
+	m.cancel()
+	m.ctx, m.cancel = nil, nil
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
+
+// startTarget starts the goroutine pinging tgt, m.mu must be held.
+func (m *Monitor) startTarget(tgt *target) {
+	ctx, cancel := context.WithCancel(m.ctx)
+	tgt.cancel = cancel
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.run(ctx, tgt)
+	}()
+}
+
+func (m *Monitor) run(ctx context.Context, tgt *target) {
+	ticker := time.NewTicker(tgt.Interval)
+	defer ticker.Stop()
+
+	for {
+		m.ping(ctx, tgt)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Monitor) ping(ctx context.Context, tgt *target) {
+	res := m.client.Ping(ctx, tgt.Address)
+	if ctx.Err() != nil {
+		// Stopped part way through, the result is meaningless
+		return
+	}
+
+	received := 0
+	if res.Err == nil {
+		received = 1
+	}
+
+	u := Update{
+		Address: tgt.Address,
+		Time:    time.Now(),
+		Up:      res.Err == nil,
+		Latency: res.Latency,
+		Err:     res.Err,
+	}
+	if res.Err == nil {
+		u.Response = &res.Response
+	}
+
+	m.mu.Lock()
+	if u.Up {
+		tgt.latency.Record(u.Latency)
+		tgt.jitter.observe(u.Latency)
+	}
+	tgt.loss.observe(res.Sent, received)
+	var events []Event
+	if event := tgt.state.observe(u); event != nil {
+		events = append(events, event)
+	}
+	if event := tgt.checkLatency(u); event != nil {
+		events = append(events, event)
+	}
+	u.State = tgt.state.state
+	tgt.latest = &u
+	callbacks := m.callbacks
+	for ch := range m.subscribers {
+		select {
+		case ch <- u:
+		default:
+		}
+	}
+	eventCallbacks := m.eventCallbacks
+	for _, event := range events {
+		for ch := range m.eventSubscribers {
+			select {
+			case ch <- event:
+			default:
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	m.save(ctx, u)
+	for _, fn := range callbacks {
+		fn(u)
+	}
+	for _, event := range events {
+		for _, fn := range eventCallbacks {
+			fn(event)
+		}
+		m.notify(ctx, event)
+	}
+}
+
+// checkLatency returns a LatencyThreshold event if u crossed the target's latency threshold.
+func (tgt *target) checkLatency(u Update) Event {
+	if tgt.latencyThreshold <= 0 || !u.Up {
+		return nil
+	}
+
+	slow := u.Latency > tgt.latencyThreshold
+	if slow == tgt.slow {
+		return nil
+	}
+	tgt.slow = slow
+
+	return LatencyThreshold{
+		Address:   u.Address,
+		Time:      u.Time,
+		Latency:   u.Latency,
+		Threshold: tgt.latencyThreshold,
+		Exceeded:  slow,
+		Response:  *u.Response,
+	}
+}
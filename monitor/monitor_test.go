@@ -0,0 +1,244 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+func testClient() *bedrockping.Client {
+	return bedrockping.NewClient(bedrockping.WithTimeout(100*time.Millisecond), bedrockping.WithResend(20*time.Millisecond))
+}
+
+func TestMonitor(t *testing.T) {
+	up := pingtest.Server(t, pingtest.Response("Monitored"))
+	down := pingtest.DeadAddress(t)
+
+	m := New(testClient())
+	if err := m.Add(Target{Address: up, Interval: 50 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add(Target{Address: down, Interval: 50 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add(Target{Address: up}); err != ErrDuplicateTarget {
+		t.Errorf("expected ErrDuplicateTarget, got: %v", err)
+	}
+
+	updates, unsubscribe := m.Subscribe(16)
+	defer unsubscribe()
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+	if err := m.Start(context.Background()); err != ErrRunning {
+		t.Errorf("expected ErrRunning, got: %v", err)
+	}
+
+	seen := make(map[string]Update)
+	timeout := time.After(2 * time.Second)
+	for len(seen) < 2 {
+		select {
+		case u := <-updates:
+			seen[u.Address] = u
+		case <-timeout:
+			t.Fatalf("timed out waiting for updates, got %v", seen)
+		}
+	}
+
+	if u := seen[up]; !u.Up || u.Response == nil || u.Response.ServerName != "Monitored" {
+		t.Errorf("unexpected update for up server: %+v", u)
+	}
+	if u := seen[down]; u.Up || u.Err == nil {
+		t.Errorf("unexpected update for down server: %+v", u)
+	}
+
+	if status, ok := m.Status(up); !ok || !status.Up {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	if stats, ok := m.Stats(up); !ok || stats.Latency.Count() == 0 || stats.Latency.P99() <= 0 {
+		t.Errorf("expected latency samples for up server, got %+v", stats)
+	}
+	if stats, ok := m.Stats(down); !ok || stats.Latency.Count() != 0 || stats.Loss != 100 {
+		t.Errorf("expected no latency samples and total loss for down server, got %+v", stats)
+	}
+}
+
+func TestMonitorCallbackAndRemove(t *testing.T) {
+	address := pingtest.Server(t, pingtest.Response("Callback"))
+
+	m := New(testClient())
+	calls := make(chan Update, 16)
+	m.OnUpdate(func(u Update) { calls <- u })
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+
+	// Targets added while running start straight away
+	if err := m.Add(Target{Address: address, Interval: 20 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case u := <-calls:
+		if u.Address != address {
+			t.Errorf("unexpected address: %s", u.Address)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for callback")
+	}
+
+	m.Remove(address)
+	if targets := m.Targets(); len(targets) != 0 {
+		t.Errorf("expected no targets, got %v", targets)
+	}
+	if _, ok := m.Status(address); ok {
+		t.Error("expected no status for removed target")
+	}
+}
+
+func TestMonitorEvents(t *testing.T) {
+	up := pingtest.Server(t, pingtest.Response("Up"))
+	down := pingtest.DeadAddress(t)
+
+	m := New(testClient(), WithThresholds(2, 1))
+	m.Add(Target{Address: up, Interval: 20 * time.Millisecond})
+	m.Add(Target{Address: down, Interval: 20 * time.Millisecond, DownAfter: 1})
+
+	events, unsubscribe := m.SubscribeEvents(16)
+	defer unsubscribe()
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+
+	var gotUp, gotDown bool
+	timeout := time.After(2 * time.Second)
+	for !gotUp || !gotDown {
+		select {
+		case e := <-events:
+			switch e := e.(type) {
+			case ServerUp:
+				gotUp = e.Address == up && e.Response.ServerName == "Up"
+			case ServerDown:
+				gotDown = e.Address == down && e.Err != nil
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for events, up=%t down=%t", gotUp, gotDown)
+		}
+	}
+
+	if status, _ := m.Status(down); status.State != StateDown {
+		t.Errorf("expected down state, got %s", status.State)
+	}
+}
+
+func TestMonitorNotifier(t *testing.T) {
+	address := pingtest.Server(t, pingtest.Response("Notified"))
+
+	events := make(chan Event, 16)
+	failed := make(chan error, 16)
+	m := New(testClient(),
+		WithNotifier(NotifierFunc(func(ctx context.Context, e Event) error {
+			events <- e
+			return nil
+		})),
+		WithNotifier(NotifierFunc(func(ctx context.Context, e Event) error {
+			return errors.New("unreachable")
+		})),
+		WithNotifyErrorHandler(func(n Notifier, e Event, err error) {
+			failed <- err
+		}),
+	)
+	m.Add(Target{Address: address, Interval: 20 * time.Millisecond})
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+
+	select {
+	case e := <-events:
+		if _, ok := e.(ServerUp); !ok {
+			t.Errorf("expected ServerUp, got %T", e)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for notification")
+	}
+
+	select {
+	case err := <-failed:
+		if err.Error() != "unreachable" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for notifier error")
+	}
+}
+
+func TestMonitorLatencyThreshold(t *testing.T) {
+	address := pingtest.Server(t, pingtest.Response("Slow"))
+
+	// Every ping to a local server is above a 1ns threshold
+	m := New(testClient(), WithLatencyThreshold(time.Nanosecond))
+	m.Add(Target{Address: address, Interval: 20 * time.Millisecond})
+
+	events, unsubscribe := m.SubscribeEvents(16)
+	defer unsubscribe()
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case e := <-events:
+			if e, ok := e.(LatencyThreshold); ok {
+				if !e.Exceeded || e.Threshold != time.Nanosecond || e.Latency <= e.Threshold {
+					t.Errorf("unexpected event: %+v", e)
+				}
+				return
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for latency event")
+		}
+	}
+}
+
+func TestMonitorZeroLossWindow(t *testing.T) {
+	address := pingtest.Server(t, pingtest.Response("Window"))
+
+	m := New(testClient(), WithLossWindow(0))
+	m.Add(Target{Address: address, Interval: 20 * time.Millisecond})
+
+	updates, unsubscribe := m.Subscribe(16)
+	defer unsubscribe()
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+
+	// A second update means the first ping was recorded in the loss window without panicking
+	for i := 0; i < 2; i++ {
+		select {
+		case <-updates:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for update")
+		}
+	}
+	if stats, ok := m.Stats(address); !ok || stats.PingsSent == 0 {
+		t.Errorf("expected pings in the loss window, got %+v", stats)
+	}
+}
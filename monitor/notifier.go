@@ -0,0 +1,59 @@
+package monitor
+
+import (
+	"context"
+	"time"
+)
+
+const defaultNotifyTimeout = 10 * time.Second
+
+// Notifier delivers state change events to an alert destination.
+type Notifier interface {
+	// Notify delivers e, returning an error if it couldn't be delivered.
+	Notify(ctx context.Context, e Event) error
+}
+
+// NotifierFunc adapts an ordinary function to a Notifier.
+type NotifierFunc func(ctx context.Context, e Event) error
+
+// Notify calls f(ctx, e).
+func (f NotifierFunc) Notify(ctx context.Context, e Event) error {
+	return f(ctx, e)
+}
+
+// WithNotifier adds a Notifier called with every event.
+// Notifiers are called in the order they were added from the goroutine monitoring the target,
+// so events for a target are always delivered in order.
+func WithNotifier(n Notifier) Option {
+	return func(m *Monitor) {
+		m.notifiers = append(m.notifiers, n)
+	}
+}
+
+// WithNotifyTimeout sets how long each Notifier is given to deliver an event, the default is 10 seconds.
+func WithNotifyTimeout(timeout time.Duration) Option {
+	return func(m *Monitor) {
+		m.notifyTimeout = timeout
+	}
+}
+
+// WithNotifyErrorHandler sets a function called when a Notifier fails to deliver an event.
+// By default errors are ignored.
+func WithNotifyErrorHandler(fn func(n Notifier, e Event, err error)) Option {
+	return func(m *Monitor) {
+		m.notifyError = fn
+	}
+}
+
+// notify delivers e to every Notifier.
+func (m *Monitor) notify(ctx context.Context, e Event) {
+	for _, n := range m.notifiers {
+		notifyCtx, cancel := context.WithTimeout(ctx, m.notifyTimeout)
+		err := n.Notify(notifyCtx, e)
+		cancel()
+
+		if err != nil && m.notifyError != nil {
+			m.notifyError(n, e, err)
+		}
+	}
+}
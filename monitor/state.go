@@ -0,0 +1,149 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// State is the damped up/down state of a monitored server.
+type State int
+
+// States a monitored server can be in.
+const (
+	// StateUnknown is the state before enough pings have been made to decide.
+	StateUnknown State = iota
+	StateUp
+	StateDown
+)
+
+func (s State) String() string {
+	switch s {
+	case StateUp:
+		return "up"
+	case StateDown:
+		return "down"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes the state as its name.
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a state from its name.
+func (s *State) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "up":
+		*s = StateUp
+	case "down":
+		*s = StateDown
+	case "unknown":
+		*s = StateUnknown
+	default:
+		return fmt.Errorf("monitor: unknown state %q", text)
+	}
+	return nil
+}
+
+const (
+	defaultDownAfter = 3
+	defaultUpAfter   = 1
+)
+
+// Event is a change in the state of a monitored server,
+// either ServerUp, ServerDown or LatencyThreshold.
+type Event interface {
+	isEvent()
+}
+
+// ServerUp is emitted when a server starts responding after being down, or is first seen up.
+type ServerUp struct {
+	Address  string               `json:"address"`
+	Time     time.Time            `json:"time"`
+	Response bedrockping.Response `json:"response"`
+	// Downtime is how long the server was down for, 0 if it wasn't previously down.
+	Downtime time.Duration `json:"downtime"`
+}
+
+// ServerDown is emitted when a server stops responding after being up, or is first seen down.
+type ServerDown struct {
+	Address string    `json:"address"`
+	Time    time.Time `json:"time"`
+	// Since is the time of the first failed ping.
+	Since time.Time `json:"since"`
+	// Err is the error from the latest failed ping.
+	Err error `json:"-"`
+	// LastResponse is the last response received before the server went down, if any.
+	LastResponse *bedrockping.Response `json:"lastResponse,omitempty"`
+}
+
+// LatencyThreshold is emitted when a server's latency rises above the configured threshold,
+// and again when it falls back below it.
+type LatencyThreshold struct {
+	Address   string               `json:"address"`
+	Time      time.Time            `json:"time"`
+	Latency   time.Duration        `json:"latency"`
+	Threshold time.Duration        `json:"threshold"`
+	Response  bedrockping.Response `json:"response"`
+	// Exceeded is true when the latency rose above the threshold and false when it recovered.
+	Exceeded bool `json:"exceeded"`
+}
+
+func (ServerUp) isEvent()         {}
+func (ServerDown) isEvent()       {}
+func (LatencyThreshold) isEvent() {}
+
+// stateMachine tracks consecutive successes and failures to damp flapping.
+type stateMachine struct {
+	downAfter int
+	upAfter   int
+
+	state        State
+	successes    int
+	failures     int
+	firstFailure time.Time
+	downSince    time.Time
+	lastResponse *bedrockping.Response
+}
+
+// observe records the outcome of a ping and returns an event if the state changed.
+func (s *stateMachine) observe(u Update) Event {
+	if u.Up {
+		s.successes++
+		s.failures = 0
+		s.lastResponse = u.Response
+
+		if s.state != StateUp && s.successes >= s.upAfter {
+			e := ServerUp{Address: u.Address, Time: u.Time, Response: *u.Response}
+			if s.state == StateDown {
+				e.Downtime = u.Time.Sub(s.downSince)
+			}
+			s.state = StateUp
+			return e
+		}
+		return nil
+	}
+
+	if s.failures == 0 {
+		s.firstFailure = u.Time
+	}
+	s.failures++
+	s.successes = 0
+
+	if s.state != StateDown && s.failures >= s.downAfter {
+		s.state = StateDown
+		s.downSince = s.firstFailure
+		return ServerDown{
+			Address:      u.Address,
+			Time:         u.Time,
+			Since:        s.downSince,
+			Err:          u.Err,
+			LastResponse: s.lastResponse,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package monitor
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+func TestStateMachine(t *testing.T) {
+	s := stateMachine{downAfter: 3, upAfter: 2}
+	start := time.Now()
+	resp := &bedrockping.Response{ServerName: "Server"}
+
+	up := func(i int) Update {
+		return Update{Address: "a", Time: start.Add(time.Duration(i) * time.Second), Up: true, Response: resp}
+	}
+	down := func(i int) Update {
+		return Update{Address: "a", Time: start.Add(time.Duration(i) * time.Second), Err: errors.New("timeout")}
+	}
+
+	steps := []struct {
+		update Update
+		state  State
+		event  Event
+	}{
+		{up(0), StateUnknown, nil},
+		{up(1), StateUp, ServerUp{Address: "a", Time: start.Add(time.Second), Response: *resp}},
+		// A single lost ping doesn't take the server down
+		{down(2), StateUp, nil},
+		{up(3), StateUp, nil},
+		{down(4), StateUp, nil},
+		{down(5), StateUp, nil},
+		{down(6), StateDown, nil},
+		{up(7), StateDown, nil},
+		{down(8), StateDown, nil},
+		{up(9), StateDown, nil},
+		{up(10), StateUp, ServerUp{Address: "a", Time: start.Add(10 * time.Second), Response: *resp, Downtime: 6 * time.Second}},
+	}
+	for i, step := range steps {
+		event := s.observe(step.update)
+		if s.state != step.state {
+			t.Errorf("step %d: expected state %s, got %s", i, step.state, s.state)
+		}
+		if step.event != nil && !reflect.DeepEqual(event, step.event) {
+			t.Errorf("step %d: expected event %+v, got %+v", i, step.event, event)
+		}
+		if i == 6 {
+			e, ok := event.(ServerDown)
+			if !ok || !e.Since.Equal(start.Add(4*time.Second)) || e.LastResponse != resp {
+				t.Errorf("step %d: unexpected down event %+v", i, event)
+			}
+			continue
+		}
+		if step.event == nil && event != nil {
+			t.Errorf("step %d: unexpected event %+v", i, event)
+		}
+	}
+}
+
+func TestStateText(t *testing.T) {
+	for _, s := range []State{StateUnknown, StateUp, StateDown} {
+		text, err := s.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded State
+		if err := decoded.UnmarshalText(text); err != nil {
+			t.Fatal(err)
+		}
+		if decoded != s {
+			t.Errorf("%s: decoded as %s", s, decoded)
+		}
+	}
+
+	var s State
+	if err := s.UnmarshalText([]byte("sideways")); err == nil {
+		t.Error("expected error for unknown state")
+	}
+}
@@ -0,0 +1,118 @@
+package monitor
+
+import (
+	"context"
+	"time"
+)
+
+const defaultStoreTimeout = 10 * time.Second
+
+// Sample is the outcome of a single ping, as persisted by a Store.
+type Sample struct {
+	Address string    `json:"address"`
+	Time    time.Time `json:"time"`
+	// Up is whether this ping succeeded, State is the state of the server after it.
+	Up    bool  `json:"up"`
+	State State `json:"state"`
+	// Latency, PlayerCount, MaxPlayers and Version are only set if the ping succeeded.
+	Latency     time.Duration `json:"latency,omitempty"`
+	PlayerCount int           `json:"playerCount,omitempty"`
+	MaxPlayers  int           `json:"maxPlayers,omitempty"`
+	Version     string        `json:"version,omitempty"`
+	// Error is the error message of a failed ping.
+	Error string `json:"error,omitempty"`
+}
+
+// SampleOf returns the Sample recording u.
+func SampleOf(u Update) Sample {
+	s := Sample{
+		Address: u.Address,
+		Time:    u.Time,
+		Up:      u.Up,
+		State:   u.State,
+	}
+	if u.Response != nil {
+		s.Latency = u.Latency
+		s.PlayerCount = u.Response.PlayerCount
+		s.MaxPlayers = u.Response.MaxPlayers
+		s.Version = u.Response.MCPEVersion
+	}
+	if u.Err != nil {
+		s.Error = u.Err.Error()
+	}
+	return s
+}
+
+// Store persists the history of monitored servers, so it survives restarts and can be queried for reports.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// SaveSample persists s.
+	SaveSample(ctx context.Context, s Sample) error
+	// QueryRange returns the samples for address from the time from, inclusive, until to, exclusive,
+	// oldest first.
+	QueryRange(ctx context.Context, address string, from, to time.Time) ([]Sample, error)
+	// Prune deletes every sample older than before.
+	Prune(ctx context.Context, before time.Time) error
+}
+
+// WithStore saves a Sample of every update to store.
+func WithStore(store Store) Option {
+	return func(m *Monitor) {
+		m.store = store
+	}
+}
+
+// WithRetention periodically prunes samples older than retention from the Store while the Monitor is running.
+// By default samples are kept forever.
+func WithRetention(retention time.Duration) Option {
+	return func(m *Monitor) {
+		m.retention = retention
+	}
+}
+
+// WithStoreErrorHandler sets a function called when saving or pruning samples fails.
+// By default errors are ignored.
+func WithStoreErrorHandler(fn func(err error)) Option {
+	return func(m *Monitor) {
+		m.storeError = fn
+	}
+}
+
+// save saves a Sample of u to the Store, if any.
+func (m *Monitor) save(ctx context.Context, u Update) {
+	if m.store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultStoreTimeout)
+	defer cancel()
+	if err := m.store.SaveSample(ctx, SampleOf(u)); err != nil && m.storeError != nil {
+		m.storeError(err)
+	}
+}
+
+// prune deletes samples older than the retention from the Store until ctx is done.
+func (m *Monitor) prune(ctx context.Context) {
+	// Prune often enough that at most a tenth more than the retention is kept
+	interval := m.retention / 10
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		pruneCtx, cancel := context.WithTimeout(ctx, defaultStoreTimeout)
+		err := m.store.Prune(pruneCtx, time.Now().Add(-m.retention))
+		cancel()
+		if err != nil && ctx.Err() == nil && m.storeError != nil {
+			m.storeError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
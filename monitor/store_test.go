@@ -0,0 +1,89 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+// sliceStore is a Store keeping samples in a slice.
+type sliceStore struct {
+	mu      sync.Mutex
+	samples []Sample
+	pruned  chan time.Time
+}
+
+func (s *sliceStore) SaveSample(ctx context.Context, sample Sample) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.samples = append(s.samples, sample)
+	return nil
+}
+
+func (s *sliceStore) QueryRange(ctx context.Context, address string, from, to time.Time) ([]Sample, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *sliceStore) Prune(ctx context.Context, before time.Time) error {
+	select {
+	case s.pruned <- before:
+	default:
+	}
+	return nil
+}
+
+func TestMonitorStore(t *testing.T) {
+	up := pingtest.Server(t, pingtest.Response("Stored"))
+	down := pingtest.DeadAddress(t)
+
+	store := &sliceStore{pruned: make(chan time.Time, 1)}
+	m := New(testClient(), WithStore(store), WithRetention(time.Hour), WithThresholds(1, 1))
+	m.Add(Target{Address: up, Interval: time.Hour})
+	m.Add(Target{Address: down, Interval: time.Hour})
+
+	updates, unsubscribe := m.Subscribe(2)
+	defer unsubscribe()
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-updates:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for update")
+		}
+	}
+
+	select {
+	case before := <-store.pruned:
+		if age := time.Since(before); age < time.Hour || age > time.Hour+time.Minute {
+			t.Errorf("pruned samples before %s", before)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("store wasn't pruned")
+	}
+	m.Stop()
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if len(store.samples) != 2 {
+		t.Fatalf("expected 2 samples, got %+v", store.samples)
+	}
+	for _, s := range store.samples {
+		switch s.Address {
+		case up:
+			if !s.Up || s.State != StateUp || s.Latency <= 0 || s.MaxPlayers != 10 || s.Error != "" {
+				t.Errorf("unexpected sample: %+v", s)
+			}
+		case down:
+			if s.Up || s.State != StateDown || s.Latency != 0 || s.Error == "" {
+				t.Errorf("unexpected sample: %+v", s)
+			}
+		}
+	}
+}
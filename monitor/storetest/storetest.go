@@ -0,0 +1,85 @@
+// Package storetest provides a conformance test for monitor.Store implementations.
+package storetest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+// Run tests the monitor.Store returned by newStore, which must be empty.
+func Run(t *testing.T, newStore func(t *testing.T) monitor.Store) {
+	t.Run("QueryRange", func(t *testing.T) { testQueryRange(t, newStore(t)) })
+	t.Run("Prune", func(t *testing.T) { testPrune(t, newStore(t)) })
+}
+
+var base = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func samples(address string) []monitor.Sample {
+	return []monitor.Sample{
+		{Address: address, Time: base, Up: true, State: monitor.StateUp, Latency: 12 * time.Millisecond, PlayerCount: 3, MaxPlayers: 20, Version: "1.14.60"},
+		{Address: address, Time: base.Add(time.Minute), State: monitor.StateUp, Error: "i/o timeout"},
+		{Address: address, Time: base.Add(2 * time.Minute), State: monitor.StateDown, Error: "i/o timeout"},
+		{Address: address, Time: base.Add(3 * time.Minute), Up: true, State: monitor.StateUp, Latency: 15 * time.Millisecond, PlayerCount: 1, MaxPlayers: 20, Version: "1.14.60"},
+	}
+}
+
+// save saves every sample, interleaving the addresses.
+func save(t *testing.T, store monitor.Store, addresses ...string) {
+	all := make([][]monitor.Sample, len(addresses))
+	for i, address := range addresses {
+		all[i] = samples(address)
+	}
+	for i := range all[0] {
+		for _, s := range all {
+			if err := store.SaveSample(context.Background(), s[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func query(t *testing.T, store monitor.Store, address string, from, to time.Time) []monitor.Sample {
+	got, err := store.QueryRange(context.Background(), address, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range got {
+		// Compare instants regardless of location
+		got[i].Time = got[i].Time.UTC()
+	}
+	return got
+}
+
+func testQueryRange(t *testing.T, store monitor.Store) {
+	save(t, store, "a:19132", "b:19132")
+
+	expect := samples("a:19132")
+	if got := query(t, store, "a:19132", base, base.Add(time.Hour)); !reflect.DeepEqual(got, expect) {
+		t.Errorf("got %+v, expected %+v", got, expect)
+	}
+	// From is inclusive and to is exclusive
+	if got := query(t, store, "a:19132", base.Add(time.Minute), base.Add(3*time.Minute)); !reflect.DeepEqual(got, expect[1:3]) {
+		t.Errorf("got %+v, expected %+v", got, expect[1:3])
+	}
+	if got := query(t, store, "c:19132", base, base.Add(time.Hour)); len(got) != 0 {
+		t.Errorf("expected no samples, got %+v", got)
+	}
+}
+
+func testPrune(t *testing.T, store monitor.Store) {
+	save(t, store, "a:19132", "b:19132")
+
+	if err := store.Prune(context.Background(), base.Add(2*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	for _, address := range []string{"a:19132", "b:19132"} {
+		expect := samples(address)[2:]
+		if got := query(t, store, address, base, base.Add(time.Hour)); !reflect.DeepEqual(got, expect) {
+			t.Errorf("got %+v, expected %+v", got, expect)
+		}
+	}
+}
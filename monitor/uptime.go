@@ -0,0 +1,89 @@
+package monitor
+
+import (
+	"context"
+	"time"
+)
+
+// Uptime is the availability of a server over a window, as shown on status pages.
+type Uptime struct {
+	// Availability is the percentage of the window the server was up, out of the time its state was known.
+	Availability float64 `json:"availability"`
+	// Outages is the number of times the server went down.
+	Outages int `json:"outages"`
+	// MTTR is the mean time to recovery, the mean duration of the outages that ended within the window.
+	MTTR time.Duration `json:"mttr"`
+	// LongestOutage is the duration of the longest outage, including one still ongoing.
+	LongestOutage time.Duration `json:"longestOutage"`
+	// Samples is the number of samples the figures were computed from.
+	Samples int `json:"samples"`
+}
+
+// ComputeUptime computes the Uptime of the server with address over the window ending now from the samples in store.
+// Each sample's damped State is taken to hold until the next sample, so a server counts as down from when
+// the Monitor declared it down until it was declared up again. Time before the first sample in the window,
+// or where the state was unknown, is excluded. If there are no samples the Uptime is zero.
+func ComputeUptime(ctx context.Context, store Store, address string, window time.Duration) (Uptime, error) {
+	to := time.Now()
+	from := to.Add(-window)
+
+	samples, err := store.QueryRange(ctx, address, from, to)
+	if err != nil {
+		return Uptime{}, err
+	}
+	return computeUptime(samples, to), nil
+}
+
+// computeUptime computes the Uptime from samples, oldest first, with the window ending at to.
+func computeUptime(samples []Sample, to time.Time) Uptime {
+	u := Uptime{Samples: len(samples)}
+
+	var up, down, recovered time.Duration
+	var outageStart time.Time
+	inOutage := false
+	for i, s := range samples {
+		end := to
+		if i+1 < len(samples) {
+			end = samples[i+1].Time
+		}
+		held := end.Sub(s.Time)
+
+		switch s.State {
+		case StateUp:
+			up += held
+			if inOutage {
+				inOutage = false
+				outage := s.Time.Sub(outageStart)
+				recovered += outage
+				if outage > u.LongestOutage {
+					u.LongestOutage = outage
+				}
+			}
+		case StateDown:
+			down += held
+			if !inOutage {
+				inOutage = true
+				outageStart = s.Time
+				u.Outages++
+			}
+		}
+	}
+
+	if inOutage {
+		if outage := to.Sub(outageStart); outage > u.LongestOutage {
+			u.LongestOutage = outage
+		}
+	}
+	if finished := u.Outages; finished > 0 {
+		if inOutage {
+			finished--
+		}
+		if finished > 0 {
+			u.MTTR = recovered / time.Duration(finished)
+		}
+	}
+	if known := up + down; known > 0 {
+		u.Availability = float64(up) / float64(known) * 100
+	}
+	return u
+}
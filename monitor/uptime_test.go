@@ -0,0 +1,77 @@
+package monitor
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestComputeUptime(t *testing.T) {
+	start := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	at := func(minutes int, state State) Sample {
+		return Sample{Address: "a", Time: start.Add(time.Duration(minutes) * time.Minute), State: state}
+	}
+
+	samples := []Sample{
+		at(0, StateUnknown),
+		at(10, StateUp),
+		at(40, StateDown),
+		at(45, StateDown),
+		at(50, StateUp),
+		at(70, StateDown),
+		at(85, StateUp),
+	}
+	u := computeUptime(samples, start.Add(100*time.Minute))
+
+	// Up 10-40, 50-70 and 85-100 = 65 minutes, down 40-50 and 70-85 = 25 minutes
+	if math.Abs(u.Availability-65.0/90.0*100) > 1e-9 {
+		t.Errorf("unexpected availability: %f", u.Availability)
+	}
+	u.Availability = 0
+	expect := Uptime{
+		Outages:       2,
+		MTTR:          12*time.Minute + 30*time.Second,
+		LongestOutage: 15 * time.Minute,
+		Samples:       7,
+	}
+	if u != expect {
+		t.Errorf("got %+v, expected %+v", u, expect)
+	}
+}
+
+func TestComputeUptimeOngoingOutage(t *testing.T) {
+	start := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	samples := []Sample{
+		{Time: start, State: StateUp},
+		{Time: start.Add(30 * time.Minute), State: StateDown},
+	}
+	u := computeUptime(samples, start.Add(90*time.Minute))
+
+	if u.Outages != 1 || u.MTTR != 0 || u.LongestOutage != time.Hour {
+		t.Errorf("unexpected uptime: %+v", u)
+	}
+	if u.Availability < 33.3 || u.Availability > 33.4 {
+		t.Errorf("unexpected availability: %f", u.Availability)
+	}
+}
+
+func TestComputeUptimeStore(t *testing.T) {
+	store := NewMemoryStore(10)
+	now := time.Now()
+	store.SaveSample(context.Background(), Sample{Address: "a", Time: now.Add(-2 * time.Hour), State: StateDown})
+	store.SaveSample(context.Background(), Sample{Address: "a", Time: now.Add(-30 * time.Minute), State: StateUp})
+
+	u, err := ComputeUptime(context.Background(), store, "a", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The sample outside the window is ignored
+	if u.Samples != 1 || u.Availability != 100 || u.Outages != 0 {
+		t.Errorf("unexpected uptime: %+v", u)
+	}
+
+	if u, _ := ComputeUptime(context.Background(), store, "b", time.Hour); u != (Uptime{}) {
+		t.Errorf("expected zero uptime, got %+v", u)
+	}
+}
@@ -0,0 +1,417 @@
+package bedrockping
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping/internal/batchio"
+)
+
+// ErrMultiplexerClosed is returned when querying through a Multiplexer that has been closed.
+var ErrMultiplexerClosed = errors.New("bedrockping: multiplexer closed")
+
+// maxPacketSize is the size of the buffer used to read incoming packets.
+const maxPacketSize = 1500
+
+// Result is the outcome of querying a single address as part of a batch.
+// It's encoded to JSON and YAML as a ResultJSON, which can also be used for TOML.
+type Result struct {
+	Address  string        `json:"address"`
+	Response Response      `json:"response"`
+	Latency  time.Duration `json:"latency"`
+	// Attempts is the number of attempts made, including the one that succeeded.
+	Attempts int `json:"attempts"`
+	// Sent is the number of ping packets sent up to and including the one that was answered,
+	// or every packet sent if none were. It is only reported by Client.
+	Sent int `json:"sent,omitempty"`
+	// Resolved is the IP address and port that was pinged, after resolving the host.
+	// It is only reported by Client.
+	Resolved string `json:"resolved,omitempty"`
+	// CheckedAt is when the query started, it's zero if the server wasn't queried.
+	CheckedAt time.Time `json:"checkedAt,omitzero"`
+	// Enrichment holds the values added by Enrichers after a successful ping.
+	Enrichment map[string]string `json:"enrichment,omitempty"`
+	Err        error             `json:"-"`
+}
+
+// BatchOptions configures a QueryMany call.
+// Zero values are replaced with sensible defaults.
+type BatchOptions struct {
+	// Timeout is the maximum time to wait for each attempt at an address, counted from when its first ping
+	// is sent so time spent waiting for RateLimit doesn't count.
+	Timeout time.Duration
+	// Retries is the number of times an address that doesn't respond within Timeout is tried again.
+	Retries int
+	// Resend is the interval that the ping packet is sent in case there is packet loss.
+	Resend time.Duration
+	// RateLimit is the maximum number of pings sent per second across the whole batch,
+	// including resends. 0 means unlimited.
+	RateLimit float64
+	// Burst is the number of pings that may be sent at once before RateLimit applies.
+	Burst int
+	// Progress, if set, is called whenever a target starts or finishes being queried.
+	Progress ProgressFunc
+	// CircuitBreaker, if set, skips targets whose circuit is open, their results fail with ErrCircuitOpen.
+	// Each target counts as a single query however many attempts it takes.
+	CircuitBreaker *CircuitBreaker
+	// Enrichers are run on the result of each target that responds, see Enrich. Their errors are ignored.
+	Enrichers []Enricher
+}
+
+const (
+	defaultBatchTimeout = 5 * time.Second
+	defaultBatchResend  = 150 * time.Millisecond
+)
+
+// Multiplexer sends pings to many servers from a single UDP socket and
+// demultiplexes the pongs by their source address.
+// This allows scanning thousands of servers without exhausting file descriptors or ephemeral ports.
+//
+// A Multiplexer may instead be sharded across several sockets bound to different source ports or IPs,
+// to get around per-flow rate limits and conntrack table pressure during large scans. Each server is
+// always pinged from the same socket.
+//
+// On Linux the pings queued for a socket are sent, and the pongs it has received are read, in batches of
+// one system call each with sendmmsg and recvmmsg.
+//
+// A Multiplexer is safe for concurrent use.
+type Multiplexer struct {
+	conns []net.PacketConn
+	// writes holds the pings waiting to be sent from each socket
+	writes map[net.PacketConn]chan outgoing
+
+	mu      sync.Mutex
+	pending map[string][]pongWaiter
+	closed  bool
+	limiter *RateLimiter
+
+	done chan struct{}
+}
+
+// NewMultiplexer creates a Multiplexer with a socket listening on each of the local addresses laddrs.
+// An empty address uses a random port on all interfaces, as does passing no addresses.
+// Repeat ":0" to shard across several random source ports.
+func NewMultiplexer(laddrs ...string) (*Multiplexer, error) {
+	if len(laddrs) == 0 {
+		laddrs = []string{""}
+	}
+
+	m := &Multiplexer{
+		pending: make(map[string][]pongWaiter),
+		writes:  make(map[net.PacketConn]chan outgoing),
+		done:    make(chan struct{}),
+	}
+
+	for _, laddr := range laddrs {
+		if laddr == "" {
+			laddr = ":0"
+		}
+
+		conn, err := net.ListenPacket("udp", laddr)
+		if err != nil {
+			for _, conn := range m.conns {
+				conn.Close()
+			}
+			return nil, err
+		}
+		m.conns = append(m.conns, conn)
+	}
+
+	var wg sync.WaitGroup
+	for _, conn := range m.conns {
+		writes := make(chan outgoing, batchio.Size)
+		m.writes[conn] = writes
+		go m.writeLoop(conn, writes)
+
+		wg.Add(1)
+		go func(conn net.PacketConn) {
+			defer wg.Done()
+			m.readLoop(conn)
+		}(conn)
+	}
+	go func() {
+		wg.Wait()
+		close(m.done)
+	}()
+
+	return m, nil
+}
+
+// LocalAddr returns the local address of the first underlying socket.
+func (m *Multiplexer) LocalAddr() net.Addr {
+	return m.conns[0].LocalAddr()
+}
+
+// LocalAddrs returns the local addresses of every underlying socket.
+func (m *Multiplexer) LocalAddrs() []net.Addr {
+	addrs := make([]net.Addr, len(m.conns))
+	for i, conn := range m.conns {
+		addrs[i] = conn.LocalAddr()
+	}
+	return addrs
+}
+
+// shard returns the socket used to ping the server with the address key.
+func (m *Multiplexer) shard(key string) net.PacketConn {
+	if len(m.conns) == 1 {
+		return m.conns[0]
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return m.conns[h.Sum32()%uint32(len(m.conns))]
+}
+
+// Close closes the underlying sockets, any in-flight queries return ErrMultiplexerClosed.
+func (m *Multiplexer) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	m.mu.Unlock()
+
+	var err error
+	for _, conn := range m.conns {
+		if closeErr := conn.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	<-m.done
+	return err
+}
+
+func (m *Multiplexer) readLoop(conn net.PacketConn) {
+	batch := batchio.NewConn(conn)
+	ms := batchio.NewMessages(batchio.Size, maxPacketSize)
+	for {
+		n, err := batch.ReadBatch(ms)
+		if err != nil {
+			return
+		}
+
+		for _, msg := range ms[:n] {
+			var resp Response
+			if err := DecodeUnconnectedPong(msg.Buffers[0][:msg.N], &resp); err != nil {
+				// Ignore anything that isn't a valid pong
+				continue
+			}
+
+			key := msg.Addr.String()
+			m.mu.Lock()
+			waiters := m.pending[key]
+			delete(m.pending, key)
+			m.mu.Unlock()
+
+			for _, w := range waiters {
+				select {
+				case w.pongs <- pong{key: key, resp: resp}:
+				case <-w.done:
+				}
+			}
+		}
+	}
+}
+
+// pong is a pong received from the server with the address key.
+type pong struct {
+	key  string
+	resp Response
+}
+
+// pongWaiter receives the pongs from the servers it is registered for on pongs, until done is closed.
+// done is nil if sending to pongs never blocks.
+type pongWaiter struct {
+	pongs chan<- pong
+	done  <-chan struct{}
+}
+
+// outgoing is a ping waiting to be sent to addr, failed is called with the error if it can't be.
+type outgoing struct {
+	ping   []byte
+	addr   *net.UDPAddr
+	failed func(error)
+}
+
+// writeLoop sends the pings queued on writes from conn until the Multiplexer is closed, taking every ping
+// queued at once as a batch.
+func (m *Multiplexer) writeLoop(conn net.PacketConn, writes <-chan outgoing) {
+	batch := batchio.NewConn(conn)
+	queued := make([]outgoing, 0, batchio.Size)
+	ms := make([]batchio.Message, batchio.Size)
+	for i := range ms {
+		ms[i].Buffers = make([][]byte, 1)
+	}
+	for {
+		select {
+		case w := <-writes:
+			queued = append(queued[:0], w)
+		case <-m.done:
+			return
+		}
+	fill:
+		for len(queued) < batchio.Size {
+			select {
+			case w := <-writes:
+				queued = append(queued, w)
+			default:
+				break fill
+			}
+		}
+
+		for i, w := range queued {
+			ms[i].Buffers[0], ms[i].Addr = w.ping, w.addr
+		}
+		batch.WriteBatch(ms[:len(queued)], func(i int, err error) {
+			queued[i].failed(err)
+		})
+	}
+}
+
+// SetRateLimiter sets a limiter applied to every ping sent through the Multiplexer.
+// A nil limiter removes the limit.
+func (m *Multiplexer) SetRateLimiter(l *RateLimiter) {
+	m.mu.Lock()
+	m.limiter = l
+	m.mu.Unlock()
+}
+
+func (m *Multiplexer) rateLimiter() *RateLimiter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.limiter
+}
+
+// register makes w receive the pongs from the server with the address key.
+func (m *Multiplexer) register(key string, w pongWaiter) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return ErrMultiplexerClosed
+	}
+
+	m.pending[key] = append(m.pending[key], w)
+	return nil
+}
+
+// unregister stops w receiving the pongs from the server with the address key.
+func (m *Multiplexer) unregister(key string, w pongWaiter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	waiters := m.pending[key]
+	for i, c := range waiters {
+		if c.pongs == w.pongs {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(waiters) == 0 {
+		delete(m.pending, key)
+	} else {
+		m.pending[key] = waiters
+	}
+}
+
+// Query pings address through the shared socket and waits for the pong until ctx is done.
+// resend is the interval that the ping packet is sent in case there is packet loss.
+// The address is parsed with ParseAddress, so DefaultPort is used if it has no port.
+func (m *Multiplexer) Query(ctx context.Context, address string, resend time.Duration) (Response, error) {
+	var resp Response
+
+	address, err := ParseAddress(address)
+	if err != nil {
+		return resp, err
+	}
+	raddr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return resp, err
+	}
+	key := raddr.String()
+
+	pongs := make(chan pong, 1)
+	w := pongWaiter{pongs: pongs}
+	if err = m.register(key, w); err != nil {
+		return resp, err
+	}
+	defer m.unregister(key, w)
+
+	ping := AppendUnconnectedPing(nil, 0)
+	writes := m.writes[m.shard(key)]
+	errs := make(chan error, 1)
+	failed := func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}
+
+	// Repeat sending ping packet in case there is packet loss
+	ticker := time.NewTicker(resend)
+	defer ticker.Stop()
+
+	for {
+		if err = m.rateLimiter().Wait(ctx); err != nil {
+			return resp, err
+		}
+		select {
+		case writes <- outgoing{ping: ping, addr: raddr, failed: failed}:
+		case <-m.done:
+			return resp, ErrMultiplexerClosed
+		case <-ctx.Done():
+			return resp, ctx.Err()
+		}
+
+		select {
+		case p := <-pongs:
+			return p.resp, nil
+		case err = <-errs:
+			if m.isClosed() {
+				return resp, ErrMultiplexerClosed
+			}
+			return resp, err
+		case <-m.done:
+			return resp, ErrMultiplexerClosed
+		case <-ctx.Done():
+			return resp, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Multiplexer) isClosed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
+
+// QueryMany queries every target concurrently through the shared socket.
+// The returned results are in the same order as targets.
+//
+// The targets are queried by a single event loop rather than a goroutine each, which keeps the state of every
+// target, sending the pings due through the socket writers and matching pongs to targets by their address, so
+// batches of tens of thousands of targets don't put pressure on the scheduler. Hosts that aren't IP addresses
+// are resolved a few at a time.
+func (m *Multiplexer) QueryMany(ctx context.Context, targets []Target, opts BatchOptions) []Result {
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultBatchTimeout
+	}
+	if opts.Resend <= 0 {
+		opts.Resend = defaultBatchResend
+	}
+
+	var limiter *RateLimiter
+	if opts.RateLimit > 0 {
+		limiter = NewRateLimiter(opts.RateLimit, opts.Burst)
+	}
+
+	results := make([]Result, len(targets))
+	newBatch(m, targets, opts, limiter, results).run(ctx)
+	return results
+}
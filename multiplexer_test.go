@@ -0,0 +1,262 @@
+package bedrockping
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// startTestServer starts a Responder on localhost answering every ping with resp, returning its address.
+func startTestServer(t *testing.T, resp Response) string {
+	_, address := startResponder(t, resp)
+	return address
+}
+
+// testResponse returns the response of a test server named name.
+func testResponse(name string) Response {
+	return Response{
+		GameID:          "MCPE",
+		ServerID:        0x1234,
+		ServerName:      name,
+		ProtocolVersion: 390,
+		MCPEVersion:     "1.14.60",
+		PlayerCount:     1,
+		MaxPlayers:      10,
+	}
+}
+
+func TestMultiplexerQuery(t *testing.T) {
+	expect := testResponse("Server")
+	address := startTestServer(t, expect)
+
+	m, err := NewMultiplexer("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	resp, err := m.Query(ctx, address, 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expect, resp) {
+		t.Errorf("incorrect resp: %v", resp)
+	}
+}
+
+func TestMultiplexerQueryMany(t *testing.T) {
+	targets := []Target{
+		{Host: startTestServer(t, testResponse("A"))},
+		{Host: startTestServer(t, testResponse("B"))},
+		{Host: startTestServer(t, testResponse("C"))},
+	}
+
+	m, err := NewMultiplexer("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	results := m.QueryMany(context.Background(), targets, BatchOptions{Timeout: 2 * time.Second})
+	if len(results) != len(targets) {
+		t.Fatalf("expected %d results, got %d", len(targets), len(results))
+	}
+	for i, res := range results {
+		if res.Err != nil {
+			t.Errorf("%s: %v", res.Address, res.Err)
+			continue
+		}
+		if res.Address != targets[i].Host {
+			t.Errorf("result %d has address %s, expected %s", i, res.Address, targets[i].Host)
+		}
+		if name := string(rune('A' + i)); res.Response.ServerName != name {
+			t.Errorf("result %d has server name %s, expected %s", i, res.Response.ServerName, name)
+		}
+	}
+}
+
+func TestMultiplexerTimeout(t *testing.T) {
+	// Nothing is listening on this socket once it's closed
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := conn.LocalAddr().String()
+	conn.Close()
+
+	m, err := NewMultiplexer("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	if _, err := m.Query(ctx, address, 50*time.Millisecond); err != context.DeadlineExceeded {
+		t.Errorf("expected deadline exceeded, got: %v", err)
+	}
+}
+
+func TestMultiplexerClose(t *testing.T) {
+	m, err := NewMultiplexer("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Query(context.Background(), "127.0.0.1:19132", time.Second); err != ErrMultiplexerClosed {
+		t.Errorf("expected ErrMultiplexerClosed, got: %v", err)
+	}
+}
+
+func TestMultiplexerQueryManyRateLimit(t *testing.T) {
+	targets := []Target{
+		{Host: startTestServer(t, testResponse("A"))},
+		{Host: startTestServer(t, testResponse("B"))},
+		{Host: startTestServer(t, testResponse("C"))},
+	}
+
+	m, err := NewMultiplexer("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	start := time.Now()
+	results := m.QueryMany(context.Background(), targets, BatchOptions{
+		Timeout:   2 * time.Second,
+		Resend:    time.Second,
+		RateLimit: 10,
+		Burst:     1,
+	})
+	for _, res := range results {
+		if res.Err != nil {
+			t.Errorf("%s: %v", res.Address, res.Err)
+		}
+	}
+	// One ping is sent straight away, the other two wait for the bucket to refill
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("rate limit not applied, batch took %s", elapsed)
+	}
+}
+
+func TestMultiplexerQueryManyRetries(t *testing.T) {
+	// Nothing is listening on this socket once it's closed
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dead := conn.LocalAddr().String()
+	conn.Close()
+
+	targets := []Target{
+		{Host: startTestServer(t, testResponse("A"))},
+		{Host: dead},
+		{Host: dead, Retries: 2},
+	}
+
+	m, err := NewMultiplexer("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	results := m.QueryMany(context.Background(), targets, BatchOptions{
+		Timeout: 50 * time.Millisecond,
+		Resend:  10 * time.Millisecond,
+		Retries: 1,
+	})
+
+	expect := []struct {
+		attempts int
+		ok       bool
+	}{{1, true}, {2, false}, {3, false}}
+	for i, res := range results {
+		if res.Attempts != expect[i].attempts {
+			t.Errorf("result %d made %d attempts, expected %d", i, res.Attempts, expect[i].attempts)
+		}
+		if (res.Err == nil) != expect[i].ok {
+			t.Errorf("result %d has unexpected error: %v", i, res.Err)
+		}
+	}
+}
+
+func TestMultiplexerQueryManyProgress(t *testing.T) {
+	targets := []Target{
+		{Host: startTestServer(t, testResponse("A"))},
+		{Host: startTestServer(t, testResponse("B"))},
+	}
+
+	m, err := NewMultiplexer("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	var calls, lastDone, maxInflight int
+	m.QueryMany(context.Background(), targets, BatchOptions{
+		Timeout: 2 * time.Second,
+		Progress: func(done, total, inflight int) {
+			calls++
+			if total != len(targets) {
+				t.Errorf("unexpected total: %d", total)
+			}
+			if done < lastDone {
+				t.Errorf("done went backwards from %d to %d", lastDone, done)
+			}
+			lastDone = done
+			if inflight > maxInflight {
+				maxInflight = inflight
+			}
+		},
+	})
+
+	if calls != 2*len(targets) {
+		t.Errorf("expected %d progress calls, got %d", 2*len(targets), calls)
+	}
+	if lastDone != len(targets) {
+		t.Errorf("expected %d done, got %d", len(targets), lastDone)
+	}
+	if maxInflight < 1 || maxInflight > len(targets) {
+		t.Errorf("unexpected max inflight: %d", maxInflight)
+	}
+}
+
+func TestMultiplexerSharded(t *testing.T) {
+	var targets []Target
+	for i := 0; i < 8; i++ {
+		targets = append(targets, Target{Host: startTestServer(t, testResponse("Server"))})
+	}
+
+	m, err := NewMultiplexer("127.0.0.1:0", "127.0.0.1:0", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	if len(m.LocalAddrs()) != 3 {
+		t.Fatalf("expected 3 sockets, got %d", len(m.LocalAddrs()))
+	}
+
+	used := make(map[net.PacketConn]bool)
+	for _, target := range targets {
+		used[m.shard(target.Host)] = true
+	}
+	if len(used) < 2 {
+		t.Error("expected targets to be spread across sockets")
+	}
+
+	for _, res := range m.QueryMany(context.Background(), targets, BatchOptions{Timeout: 2 * time.Second}) {
+		if res.Err != nil {
+			t.Errorf("%s: %v", res.Address, res.Err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+const (
+	discordColorUp   = 0x2ecc71
+	discordColorDown = 0xe74c3c
+	discordColorSlow = 0xf1c40f
+)
+
+// Discord is a monitor.Notifier posting rich embeds to a Discord webhook when a server changes state
+// or crosses its latency threshold.
+type Discord struct {
+	// WebhookURL is the URL of the Discord webhook.
+	WebhookURL string
+	// Username overrides the name the webhook posts as, if set.
+	Username string
+	// AvatarURL overrides the avatar the webhook posts with, if set.
+	AvatarURL string
+	// Client is the HTTP client used to post, http.DefaultClient is used if nil.
+	Client *http.Client
+}
+
+type discordMessage struct {
+	Username  string         `json:"username,omitempty"`
+	AvatarURL string         `json:"avatar_url,omitempty"`
+	Embeds    []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string              `json:"title"`
+	Description string              `json:"description,omitempty"`
+	Color       int                 `json:"color"`
+	Fields      []discordEmbedField `json:"fields,omitempty"`
+	Timestamp   string              `json:"timestamp"`
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// Notify posts an embed describing e to the webhook.
+func (d *Discord) Notify(ctx context.Context, e monitor.Event) error {
+	embed, ok := discordEmbedFor(e)
+	if !ok {
+		return nil
+	}
+
+	return postJSON(ctx, d.Client, d.WebhookURL, discordMessage{
+		Username:  d.Username,
+		AvatarURL: d.AvatarURL,
+		Embeds:    []discordEmbed{embed},
+	})
+}
+
+func discordEmbedFor(e monitor.Event) (discordEmbed, bool) {
+	info, ok := infoOf(e)
+	if !ok {
+		return discordEmbed{}, false
+	}
+
+	embed := discordEmbed{
+		Description: worldName(info.response),
+		Timestamp:   info.time.UTC().Format(time.RFC3339),
+		Fields: []discordEmbedField{
+			{Name: "Address", Value: info.address, Inline: true},
+		},
+	}
+	name := serverName(info.address, info.response)
+
+	switch e := e.(type) {
+	case monitor.ServerUp:
+		embed.Title = fmt.Sprintf("%s is up", name)
+		embed.Color = discordColorUp
+		embed.Fields = append(embed.Fields,
+			discordEmbedField{Name: "Players", Value: fmt.Sprintf("%d/%d", e.Response.PlayerCount, e.Response.MaxPlayers), Inline: true},
+			discordEmbedField{Name: "Version", Value: e.Response.MCPEVersion, Inline: true},
+		)
+		if e.Downtime > 0 {
+			embed.Fields = append(embed.Fields, discordEmbedField{Name: "Downtime", Value: formatDuration(e.Downtime), Inline: true})
+		}
+	case monitor.ServerDown:
+		embed.Title = fmt.Sprintf("%s is down", name)
+		embed.Color = discordColorDown
+		embed.Fields = append(embed.Fields,
+			discordEmbedField{Name: "Down Since", Value: e.Since.UTC().Format(time.RFC3339), Inline: true},
+		)
+		if e.Err != nil {
+			embed.Fields = append(embed.Fields, discordEmbedField{Name: "Error", Value: e.Err.Error()})
+		}
+	case monitor.LatencyThreshold:
+		if e.Exceeded {
+			embed.Title = fmt.Sprintf("%s is slow", name)
+			embed.Color = discordColorSlow
+		} else {
+			embed.Title = fmt.Sprintf("%s latency recovered", name)
+			embed.Color = discordColorUp
+		}
+		embed.Fields = append(embed.Fields,
+			discordEmbedField{Name: "Latency", Value: formatLatency(e.Latency), Inline: true},
+			discordEmbedField{Name: "Threshold", Value: formatLatency(e.Threshold), Inline: true},
+		)
+	}
+
+	return embed, true
+}
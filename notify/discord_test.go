@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+func TestDiscord(t *testing.T) {
+	var got discordMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	d := &Discord{WebhookURL: server.URL, Username: "Status"}
+	e := monitor.ServerUp{
+		Address: "play.example.com:19132",
+		Time:    time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Response: bedrockping.Response{
+			ServerName:  "§aMy Server",
+			MCPEVersion: "1.14.60",
+			PlayerCount: 3,
+			MaxPlayers:  20,
+			Extra:       []string{"123", "§bWorld"},
+		},
+		Downtime: 90 * time.Second,
+	}
+	if err := d.Notify(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Username != "Status" || len(got.Embeds) != 1 {
+		t.Fatalf("unexpected message: %+v", got)
+	}
+	embed := got.Embeds[0]
+	if embed.Title != "My Server is up" || embed.Description != "World" || embed.Color != discordColorUp {
+		t.Errorf("unexpected embed: %+v", embed)
+	}
+	if embed.Timestamp != "2020-01-02T03:04:05Z" {
+		t.Errorf("unexpected timestamp: %s", embed.Timestamp)
+	}
+
+	fields := make(map[string]string)
+	for _, f := range embed.Fields {
+		fields[f.Name] = f.Value
+	}
+	if fields["Players"] != "3/20" || fields["Downtime"] != "1m30s" || fields["Version"] != "1.14.60" {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+}
+
+func TestDiscordDown(t *testing.T) {
+	embed, ok := discordEmbedFor(monitor.ServerDown{
+		Address: "play.example.com:19132",
+		Time:    time.Now(),
+		Since:   time.Now().Add(-time.Minute),
+		Err:     errors.New("i/o timeout"),
+	})
+	if !ok {
+		t.Fatal("expected an embed")
+	}
+	if embed.Title != "play.example.com:19132 is down" || embed.Color != discordColorDown {
+		t.Errorf("unexpected embed: %+v", embed)
+	}
+}
+
+func TestDiscordError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	d := &Discord{WebhookURL: server.URL}
+	if err := d.Notify(context.Background(), monitor.ServerDown{Address: "a"}); err == nil {
+		t.Error("expected error for failed request")
+	}
+}
@@ -0,0 +1,127 @@
+// Package notify provides monitor.Notifier implementations for common alert destinations.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+// postJSON posts body encoded as JSON to url, returning an error for non-2xx responses.
+func postJSON(ctx context.Context, client *http.Client, url string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return post(ctx, client, url, data, nil)
+}
+
+// post posts the JSON data to url with the extra header, returning a *StatusError for non-2xx responses.
+func post(ctx context.Context, client *http.Client, url string, data []byte, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: string(bytes.TrimSpace(msg))}
+	}
+	return nil
+}
+
+// StatusError is returned when a notification is rejected with a non-2xx HTTP status.
+type StatusError struct {
+	StatusCode int
+	Status     string
+	// Body is the start of the response body.
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("notify: unexpected status %s: %s", e.Status, e.Body)
+}
+
+// serverName returns the server name without formatting codes, or the address if it isn't known.
+func serverName(address string, resp *bedrockping.Response) string {
+	if resp == nil || resp.ServerName == "" {
+		return address
+	}
+	return bedrockping.StripFormatting(resp.ServerName)
+}
+
+// worldName returns the level name a server reports after its server ID, if any.
+func worldName(resp *bedrockping.Response) string {
+	if resp == nil || len(resp.Extra) < 2 {
+		return ""
+	}
+	return bedrockping.StripFormatting(resp.Extra[1])
+}
+
+// formatDuration formats d rounded to the second for display.
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
+
+// formatLatency formats d rounded to the millisecond for display.
+func formatLatency(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
+
+// eventInfo holds the fields common to every event.
+type eventInfo struct {
+	address  string
+	time     time.Time
+	up       bool
+	response *bedrockping.Response
+}
+
+// eventKind returns a short name for the type of e.
+func eventKind(e monitor.Event) string {
+	switch e := e.(type) {
+	case monitor.ServerUp:
+		return "up"
+	case monitor.ServerDown:
+		return "down"
+	case monitor.LatencyThreshold:
+		if e.Exceeded {
+			return "slow"
+		}
+		return "recovered"
+	default:
+		return ""
+	}
+}
+
+func infoOf(e monitor.Event) (eventInfo, bool) {
+	switch e := e.(type) {
+	case monitor.ServerUp:
+		return eventInfo{address: e.Address, time: e.Time, up: true, response: &e.Response}, true
+	case monitor.ServerDown:
+		return eventInfo{address: e.Address, time: e.Time, response: e.LastResponse}, true
+	case monitor.LatencyThreshold:
+		return eventInfo{address: e.Address, time: e.Time, up: true, response: &e.Response}, true
+	default:
+		return eventInfo{}, false
+	}
+}
@@ -0,0 +1,108 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+// Slack is a monitor.Notifier posting Block Kit messages to a Slack-compatible incoming webhook
+// when a server changes state or crosses its latency threshold.
+type Slack struct {
+	// WebhookURL is the URL of the incoming webhook.
+	WebhookURL string
+	// Channel overrides the channel the webhook posts to, if set and supported by the webhook.
+	Channel string
+	// Client is the HTTP client used to post, http.DefaultClient is used if nil.
+	Client *http.Client
+}
+
+type slackMessage struct {
+	Channel string       `json:"channel,omitempty"`
+	Text    string       `json:"text"`
+	Blocks  []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func slackPlain(text string) slackText {
+	return slackText{Type: "plain_text", Text: text}
+}
+
+func slackField(name, value string) slackText {
+	return slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", name, value)}
+}
+
+// Notify posts a message describing e to the webhook.
+func (s *Slack) Notify(ctx context.Context, e monitor.Event) error {
+	msg, ok := slackMessageFor(e)
+	if !ok {
+		return nil
+	}
+	msg.Channel = s.Channel
+
+	return postJSON(ctx, s.Client, s.WebhookURL, msg)
+}
+
+func slackMessageFor(e monitor.Event) (slackMessage, bool) {
+	info, ok := infoOf(e)
+	if !ok {
+		return slackMessage{}, false
+	}
+
+	name := serverName(info.address, info.response)
+	fields := []slackText{slackField("Address", info.address)}
+
+	var title string
+	switch e := e.(type) {
+	case monitor.ServerUp:
+		title = fmt.Sprintf(":large_green_circle: %s is up", name)
+		fields = append(fields,
+			slackField("Players", fmt.Sprintf("%d/%d", e.Response.PlayerCount, e.Response.MaxPlayers)),
+			slackField("Version", e.Response.MCPEVersion),
+		)
+		if e.Downtime > 0 {
+			fields = append(fields, slackField("Downtime", formatDuration(e.Downtime)))
+		}
+	case monitor.ServerDown:
+		title = fmt.Sprintf(":red_circle: %s is down", name)
+		fields = append(fields, slackField("Down Since", fmt.Sprintf("<!date^%d^{date_short_pretty} {time_secs}|%s>",
+			e.Since.Unix(), e.Since.UTC().Format("2006-01-02 15:04:05 UTC"))))
+		if e.Err != nil {
+			fields = append(fields, slackField("Error", e.Err.Error()))
+		}
+	case monitor.LatencyThreshold:
+		if e.Exceeded {
+			title = fmt.Sprintf(":large_yellow_circle: %s is slow", name)
+		} else {
+			title = fmt.Sprintf(":large_green_circle: %s latency recovered", name)
+		}
+		fields = append(fields,
+			slackField("Latency", formatLatency(e.Latency)),
+			slackField("Threshold", formatLatency(e.Threshold)),
+		)
+	}
+
+	header := slackPlain(title)
+	blocks := []slackBlock{
+		{Type: "header", Text: &header},
+		{Type: "section", Fields: fields},
+	}
+	if world := worldName(info.response); world != "" {
+		blocks = append(blocks, slackBlock{Type: "context", Elements: []slackText{slackPlain(world)}})
+	}
+
+	return slackMessage{Text: title, Blocks: blocks}, true
+}
@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+func TestSlack(t *testing.T) {
+	var got slackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	s := &Slack{WebhookURL: server.URL, Channel: "#status"}
+	e := monitor.ServerUp{
+		Address: "play.example.com:19132",
+		Time:    time.Now(),
+		Response: bedrockping.Response{
+			ServerName:  "§aMy Server",
+			MCPEVersion: "1.14.60",
+			PlayerCount: 3,
+			MaxPlayers:  20,
+			Extra:       []string{"123", "§bWorld"},
+		},
+		Downtime: 90 * time.Second,
+	}
+	if err := s.Notify(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Channel != "#status" || !strings.Contains(got.Text, "My Server is up") || len(got.Blocks) != 3 {
+		t.Fatalf("unexpected message: %+v", got)
+	}
+	if got.Blocks[0].Type != "header" || got.Blocks[0].Text.Text != got.Text {
+		t.Errorf("unexpected header: %+v", got.Blocks[0])
+	}
+	if got.Blocks[2].Type != "context" || got.Blocks[2].Elements[0].Text != "World" {
+		t.Errorf("unexpected context: %+v", got.Blocks[2])
+	}
+
+	var fields []string
+	for _, f := range got.Blocks[1].Fields {
+		fields = append(fields, f.Text)
+	}
+	if joined := strings.Join(fields, "|"); !strings.Contains(joined, "*Players*\n3/20") || !strings.Contains(joined, "*Downtime*\n1m30s") {
+		t.Errorf("unexpected fields: %q", fields)
+	}
+}
+
+func TestSlackLatency(t *testing.T) {
+	msg, ok := slackMessageFor(monitor.LatencyThreshold{
+		Address:   "play.example.com:19132",
+		Latency:   312 * time.Millisecond,
+		Threshold: 250 * time.Millisecond,
+		Exceeded:  true,
+	})
+	if !ok {
+		t.Fatal("expected a message")
+	}
+	if !strings.Contains(msg.Text, "play.example.com:19132 is slow") {
+		t.Errorf("unexpected text: %s", msg.Text)
+	}
+	if len(msg.Blocks) != 2 || msg.Blocks[1].Fields[1].Text != "*Latency*\n312ms" {
+		t.Errorf("unexpected blocks: %+v", msg.Blocks)
+	}
+}
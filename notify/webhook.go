@@ -0,0 +1,185 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+const (
+	defaultWebhookBackoff = time.Second
+
+	// SignatureHeader is the header Webhook sends the HMAC-SHA256 signature of the body in.
+	SignatureHeader = "X-Bedrockping-Signature"
+)
+
+// Webhook is a monitor.Notifier posting a JSON body to an arbitrary URL, for integration with custom automation.
+//
+// By default the body is an object with the event kind ("up", "down", "slow" or "recovered"), address,
+// server name, time, error and the event itself. Template replaces it with the output of a text/template
+// executed with a WebhookData, which must be valid JSON. The json template function encodes a value as JSON,
+// so strings are escaped correctly:
+//
+//	{"text": {{json (printf "%s is %s" .Name .Kind)}}}
+type Webhook struct {
+	// URL is the URL the body is posted to.
+	URL string
+	// Template, if set, is the text/template used to render the body.
+	Template string
+	// Header holds extra headers sent with every request.
+	Header http.Header
+	// Secret, if set, signs the body with HMAC-SHA256. The hex encoded signature is sent in the
+	// SignatureHeader prefixed with "sha256=".
+	Secret []byte
+	// Retries is the number of times a request is retried after a network error, 5xx or 429 response.
+	Retries int
+	// Backoff is the delay before the first retry, doubling after each one. The default is 1 second.
+	Backoff time.Duration
+	// Client is the HTTP client used to post, http.DefaultClient is used if nil.
+	Client *http.Client
+}
+
+// WebhookData is the value Webhook templates are executed with.
+type WebhookData struct {
+	// Kind is "up", "down", "slow" or "recovered".
+	Kind    string
+	Address string
+	// Name is the server name without formatting codes, or the address if it isn't known.
+	Name string
+	Time time.Time
+	// Response is the latest response from the server, if any.
+	Response *bedrockping.Response
+	// Error is the error from the latest failed ping of a server that went down.
+	Error string
+	Event monitor.Event
+}
+
+type webhookBody struct {
+	Kind    string        `json:"kind"`
+	Address string        `json:"address"`
+	Name    string        `json:"name"`
+	Time    time.Time     `json:"time"`
+	Error   string        `json:"error,omitempty"`
+	Event   monitor.Event `json:"event"`
+}
+
+var webhookFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// Notify posts a body describing e to the URL, retrying failed requests.
+func (w *Webhook) Notify(ctx context.Context, e monitor.Event) error {
+	data, ok := webhookDataFor(e)
+	if !ok {
+		return nil
+	}
+
+	body, err := w.render(data)
+	if err != nil {
+		return err
+	}
+
+	header := make(http.Header, len(w.Header)+1)
+	for name, values := range w.Header {
+		header[name] = values
+	}
+	if len(w.Secret) > 0 {
+		header.Set(SignatureHeader, "sha256="+Sign(w.Secret, body))
+	}
+
+	backoff := w.Backoff
+	if backoff <= 0 {
+		backoff = defaultWebhookBackoff
+	}
+	for attempt := 0; ; attempt++ {
+		err = post(ctx, w.Client, w.URL, body, header)
+		if err == nil || attempt >= w.Retries || !retryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+func (w *Webhook) render(data WebhookData) ([]byte, error) {
+	if w.Template == "" {
+		return json.Marshal(webhookBody{
+			Kind:    data.Kind,
+			Address: data.Address,
+			Name:    data.Name,
+			Time:    data.Time,
+			Error:   data.Error,
+			Event:   data.Event,
+		})
+	}
+
+	tmpl, err := template.New("webhook").Funcs(webhookFuncs).Parse(w.Template)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, errors.New("notify: webhook template rendered invalid JSON")
+	}
+	return buf.Bytes(), nil
+}
+
+func webhookDataFor(e monitor.Event) (WebhookData, bool) {
+	info, ok := infoOf(e)
+	if !ok {
+		return WebhookData{}, false
+	}
+
+	data := WebhookData{
+		Kind:     eventKind(e),
+		Address:  info.address,
+		Name:     serverName(info.address, info.response),
+		Time:     info.time,
+		Response: info.response,
+		Event:    e,
+	}
+	if down, ok := e.(monitor.ServerDown); ok && down.Err != nil {
+		data.Error = down.Err.Error()
+	}
+	return data, true
+}
+
+// retryable reports whether a request failing with err may succeed if it's repeated.
+func retryable(err error) bool {
+	var status *StatusError
+	if errors.As(err, &status) {
+		return status.StatusCode >= 500 || status.StatusCode == http.StatusTooManyRequests
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of body keyed with secret, as sent by Webhook.
+// Receivers should compare it to the signature header with hmac.Equal.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+func TestWebhook(t *testing.T) {
+	secret := []byte("secret")
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		if sig := r.Header.Get(SignatureHeader); sig != "sha256="+Sign(secret, body) {
+			t.Errorf("unexpected signature: %s", sig)
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("missing header: %v", r.Header)
+		}
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	w := &Webhook{URL: server.URL, Secret: secret, Header: http.Header{"Authorization": {"Bearer token"}}}
+	e := monitor.ServerDown{
+		Address: "play.example.com:19132",
+		Time:    time.Now(),
+		Err:     errors.New("i/o timeout"),
+	}
+	if err := w.Notify(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+
+	if got["kind"] != "down" || got["address"] != "play.example.com:19132" || got["error"] != "i/o timeout" {
+		t.Errorf("unexpected body: %v", got)
+	}
+	if _, ok := got["event"].(map[string]any); !ok {
+		t.Errorf("missing event: %v", got)
+	}
+}
+
+func TestWebhookTemplate(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	w := &Webhook{
+		URL:      server.URL,
+		Template: `{"text": {{json (printf "%s is %s" .Name .Kind)}}, "players": "{{.Response.PlayerCount}}"}`,
+	}
+	e := monitor.ServerUp{
+		Address:  "play.example.com:19132",
+		Response: bedrockping.Response{ServerName: `§a"Quoted"`, PlayerCount: 3},
+	}
+	if err := w.Notify(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+
+	if got["text"] != `"Quoted" is up` || got["players"] != "3" {
+		t.Errorf("unexpected body: %v", got)
+	}
+}
+
+func TestWebhookInvalidTemplate(t *testing.T) {
+	w := &Webhook{URL: "http://127.0.0.1:0", Template: `{"text": {{.Name}}}`}
+	if err := w.Notify(context.Background(), monitor.ServerUp{Address: "a"}); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestWebhookRetry(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	w := &Webhook{URL: server.URL, Retries: 2, Backoff: time.Millisecond}
+	if err := w.Notify(context.Background(), monitor.ServerUp{Address: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Errorf("expected 3 requests, got %d", n)
+	}
+}
+
+func TestWebhookNoRetry(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	w := &Webhook{URL: server.URL, Retries: 2, Backoff: time.Millisecond}
+	err := w.Notify(context.Background(), monitor.ServerUp{Address: "a"})
+	var status *StatusError
+	if !errors.As(err, &status) || status.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status error, got %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+}
@@ -0,0 +1,163 @@
+// Package parquetenc writes results as Parquet files, so large scan and survey datasets can be queried directly
+// with DuckDB, Spark or pandas without converting them first:
+//
+//	SELECT server_name, player_count FROM 'scan.parquet' WHERE online ORDER BY player_count DESC
+//
+// Each result is a row with one column per field, see Record. Columns are named like the csvenc package's, and
+// the response columns are null for offline servers.
+package parquetenc
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// DefaultRowGroupSize is the number of rows buffered by a Writer before they're written out as a row group.
+const DefaultRowGroupSize = 10000
+
+// Record is the schema of each row. Optional columns are nil when they don't apply to the result.
+type Record struct {
+	Address   string     `parquet:"address,dict"`
+	Online    bool       `parquet:"online"`
+	Resolved  *string    `parquet:"resolved,optional"`
+	CheckedAt *time.Time `parquet:"checked_at,optional,timestamp(millisecond)"`
+	LatencyMs *float64   `parquet:"latency_ms,optional"`
+	Attempts  int32      `parquet:"attempts"`
+	Sent      int32      `parquet:"sent"`
+	Error     *string    `parquet:"error,optional"`
+	ErrorKind *string    `parquet:"error_kind,optional,dict"`
+
+	GameID          *string  `parquet:"game_id,optional,dict"`
+	ServerName      *string  `parquet:"server_name,optional"`
+	ProtocolVersion *int32   `parquet:"protocol_version,optional"`
+	Version         *string  `parquet:"version,optional,dict"`
+	PlayerCount     *int32   `parquet:"player_count,optional"`
+	MaxPlayers      *int32   `parquet:"max_players,optional"`
+	ServerID        *uint64  `parquet:"server_id,optional"`
+	LevelName       *string  `parquet:"level_name,optional"`
+	GameMode        *string  `parquet:"game_mode,optional,dict"`
+	Extra           []string `parquet:"extra,list"`
+
+	Enrichment map[string]string `parquet:"enrichment"`
+}
+
+// NewRecord returns the row of res. The server name has its formatting codes removed.
+func NewRecord(res bedrockping.Result) Record {
+	r := Record{
+		Address:    res.Address,
+		Online:     res.Err == nil,
+		Attempts:   int32(res.Attempts),
+		Sent:       int32(res.Sent),
+		Enrichment: res.Enrichment,
+	}
+	if res.Resolved != "" {
+		r.Resolved = &res.Resolved
+	}
+	if !res.CheckedAt.IsZero() {
+		r.CheckedAt = &res.CheckedAt
+	}
+	if res.Err != nil {
+		msg, kind := res.Err.Error(), bedrockping.ErrorKind(res.Err)
+		r.Error, r.ErrorKind = &msg, &kind
+		return r
+	}
+
+	resp := res.Response
+	latency := float64(res.Latency) / float64(time.Millisecond)
+	name := bedrockping.StripFormatting(resp.ServerName)
+	protocol, players, maxPlayers := int32(resp.ProtocolVersion), int32(resp.PlayerCount), int32(resp.MaxPlayers)
+	r.LatencyMs = &latency
+	r.GameID = &resp.GameID
+	r.ServerName = &name
+	r.ProtocolVersion = &protocol
+	r.Version = &resp.MCPEVersion
+	r.PlayerCount = &players
+	r.MaxPlayers = &maxPlayers
+	r.ServerID = &resp.ServerID
+	if level := resp.LevelName(); level != "" {
+		r.LevelName = &level
+	}
+	if mode := resp.GameMode(); mode != "" {
+		r.GameMode = &mode
+	}
+	r.Extra = resp.Extra
+	return r
+}
+
+// Writer writes results to a Parquet file as they arrive, buffering them into row groups.
+// The file isn't valid until Close has written its footer.
+type Writer struct {
+	w            *parquet.GenericWriter[Record]
+	rowGroupSize int
+	buffered     int
+}
+
+// NewWriter returns a Writer writing a zstd compressed file to w, with row groups of DefaultRowGroupSize rows.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		w:            parquet.NewGenericWriter[Record](w, parquet.Compression(&parquet.Zstd)),
+		rowGroupSize: DefaultRowGroupSize,
+	}
+}
+
+// Write buffers res as a row, writing out a row group once enough rows are buffered.
+func (w *Writer) Write(res bedrockping.Result) error {
+	if _, err := w.w.Write([]Record{NewRecord(res)}); err != nil {
+		return err
+	}
+	w.buffered++
+	if w.buffered >= w.rowGroupSize {
+		return w.Flush()
+	}
+	return nil
+}
+
+// Flush writes the buffered rows out as a row group.
+func (w *Writer) Flush() error {
+	w.buffered = 0
+	return w.w.Flush()
+}
+
+// Close writes any buffered rows and the file's footer. It doesn't close the underlying writer.
+func (w *Writer) Close() error {
+	return w.w.Close()
+}
+
+// Write writes results to w as a Parquet file.
+func Write(w io.Writer, results []bedrockping.Result) error {
+	pw := NewWriter(w)
+	for _, res := range results {
+		if err := pw.Write(res); err != nil {
+			return err
+		}
+	}
+	return pw.Close()
+}
+
+// WriteAll writes every result received from results, such as the output of a scan, to w as a Parquet file
+// until the channel is closed. It returns the number of results written.
+func WriteAll(w io.Writer, results <-chan bedrockping.Result) (int, error) {
+	pw := NewWriter(w)
+	n := 0
+	for res := range results {
+		if err := pw.Write(res); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, pw.Close()
+}
+
+// Read reads the rows of a Parquet file written by a Writer, of size bytes.
+func Read(r io.ReaderAt, size int64) ([]Record, error) {
+	rows, err := parquet.Read[Record](r, size)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,100 @@
+package parquetenc
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+func TestWriteRead(t *testing.T) {
+	checkedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	results := []bedrockping.Result{
+		{
+			Address: "play.example.com:19132",
+			Response: bedrockping.Response{ServerID: 1<<64 - 1, GameID: "MCPE", ServerName: "§aMy Server",
+				ProtocolVersion: 390, MCPEVersion: "1.14.60", PlayerCount: 3, MaxPlayers: 20,
+				Extra: []string{"1", "world", "Survival"}},
+			Latency:    23500 * time.Microsecond,
+			Attempts:   1,
+			Sent:       2,
+			Resolved:   "203.0.113.7:19132",
+			CheckedAt:  checkedAt,
+			Enrichment: map[string]string{"rdns": "host.example.com"},
+		},
+		{Address: "down.example.com:19132", Attempts: 2, CheckedAt: checkedAt, Err: context.DeadlineExceeded},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != len(results) {
+		t.Fatalf("expected %d rows, got %d", len(results), len(rows))
+	}
+
+	online := rows[0]
+	if online.Address != "play.example.com:19132" || !online.Online || *online.ServerName != "My Server" ||
+		*online.PlayerCount != 3 || *online.ServerID != 1<<64-1 || *online.LatencyMs != 23.5 ||
+		*online.LevelName != "world" || *online.GameMode != "Survival" || *online.Resolved != "203.0.113.7:19132" ||
+		!online.CheckedAt.Equal(checkedAt) || online.Error != nil || online.Sent != 2 {
+		t.Errorf("unexpected online row %+v", online)
+	}
+	if !reflect.DeepEqual(online.Extra, results[0].Response.Extra) || online.Enrichment["rdns"] != "host.example.com" {
+		t.Errorf("unexpected extra %q or enrichment %v", online.Extra, online.Enrichment)
+	}
+
+	offline := rows[1]
+	if offline.Online || offline.Error == nil || *offline.ErrorKind != bedrockping.ErrorKindTimeout ||
+		offline.ServerName != nil || offline.LatencyMs != nil || offline.Resolved != nil || offline.Attempts != 2 {
+		t.Errorf("unexpected offline row %+v", offline)
+	}
+}
+
+func TestWriteAll(t *testing.T) {
+	results := make(chan bedrockping.Result)
+	go func() {
+		defer close(results)
+		for range 3 {
+			results <- bedrockping.Result{Address: "a:1"}
+		}
+	}()
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.rowGroupSize = 2
+	n := 0
+	for res := range results {
+		if err := w.Write(res); err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 || len(rows) != 3 {
+		t.Errorf("expected 3 rows, got %d", len(rows))
+	}
+
+	buf.Reset()
+	empty := make(chan bedrockping.Result)
+	close(empty)
+	if n, err := WriteAll(&buf, empty); n != 0 || err != nil {
+		t.Fatalf("got %d, %v", n, err)
+	}
+	if rows, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len())); err != nil || len(rows) != 0 {
+		t.Errorf("expected no rows, got %d, %v", len(rows), err)
+	}
+}
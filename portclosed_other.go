@@ -0,0 +1,17 @@
+//go:build !windows && !plan9
+
+package bedrockping
+
+import (
+	"errors"
+	"net"
+	"syscall"
+)
+
+// reportPortUnreachable does nothing, connected UDP sockets report ICMP port unreachable messages by default.
+func reportPortUnreachable(conn net.Conn) {}
+
+// isPortUnreachable reports whether err is how the socket reported an ICMP port unreachable message.
+func isPortUnreachable(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
@@ -0,0 +1,12 @@
+package bedrockping
+
+import "net"
+
+// reportPortUnreachable does nothing, Plan 9 doesn't report ICMP port unreachable messages to UDP sockets.
+func reportPortUnreachable(conn net.Conn) {}
+
+// isPortUnreachable reports whether err is how the socket reported an ICMP port unreachable message,
+// which is never on Plan 9.
+func isPortUnreachable(err error) bool {
+	return false
+}
@@ -0,0 +1,32 @@
+package bedrockping
+
+import (
+	"errors"
+	"net"
+	"syscall"
+	"unsafe"
+)
+
+// reportPortUnreachable re-enables SIO_UDP_CONNRESET on conn, which the net package disables for every UDP
+// socket, so an ICMP port unreachable message fails the next read with WSAECONNRESET rather than being dropped.
+// It is best effort, a socket it fails on still works but only times out when the port is closed.
+func reportPortUnreachable(conn net.Conn) {
+	udp, ok := conn.(*net.UDPConn)
+	if !ok {
+		return
+	}
+	raw, err := udp.SyscallConn()
+	if err != nil {
+		return
+	}
+	raw.Control(func(fd uintptr) {
+		flag, ret := uint32(1), uint32(0)
+		syscall.WSAIoctl(syscall.Handle(fd), syscall.SIO_UDP_CONNRESET, (*byte)(unsafe.Pointer(&flag)), uint32(unsafe.Sizeof(flag)), nil, 0, &ret, nil, 0)
+	})
+}
+
+// isPortUnreachable reports whether err is how the socket reported an ICMP port unreachable message,
+// which on Windows is WSAECONNRESET on the read after it arrives.
+func isPortUnreachable(err error) bool {
+	return errors.Is(err, syscall.WSAECONNRESET) || errors.Is(err, syscall.ECONNREFUSED)
+}
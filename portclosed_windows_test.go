@@ -0,0 +1,23 @@
+package bedrockping
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestPortClosedWSAECONNRESET(t *testing.T) {
+	// A read after an ICMP port unreachable message fails like this on Windows
+	err := &net.OpError{Op: "read", Net: "udp", Err: os.NewSyscallError("wsarecv", syscall.WSAECONNRESET)}
+	if !isPortUnreachable(err) {
+		t.Fatal("expected WSAECONNRESET to report the port unreachable")
+	}
+	if wrapped := portClosed(err); !errors.Is(wrapped, ErrPortClosed) || !errors.Is(wrapped, syscall.WSAECONNRESET) {
+		t.Errorf("expected port closed wrapping WSAECONNRESET, got %v", wrapped)
+	}
+	if isPortUnreachable(syscall.WSAECONNABORTED) {
+		t.Error("expected other errors not to report the port unreachable")
+	}
+}
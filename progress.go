@@ -0,0 +1,7 @@
+package bedrockping
+
+// ProgressFunc is called as a batch operation progresses.
+// done is the number of targets finished, total is the number of targets in the batch
+// or -1 if it's too large to count, and inflight is the number of targets currently being queried.
+// Calls are never made concurrently, so implementations don't need to synchronize.
+type ProgressFunc func(done, total, inflight int)
@@ -0,0 +1,51 @@
+package bedrockping
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// QueryAll pings every IP address the host of address resolves to at once, rather than only the first like Ping,
+// returning a result for each in the order the resolver returned them, such as to check every backend behind
+// round-robin DNS is healthy. Each result has the given Address and the address that was pinged in Resolved.
+// Only addresses of the client's address family are pinged and SRV records aren't looked up.
+// The address is parsed with ParseAddress. An address with an IP address is pinged on its own, and if the host
+// doesn't resolve the only result holds the error.
+func (c *Client) QueryAll(ctx context.Context, address string) []Result {
+	parsed, err := ParseAddress(address)
+	if err != nil {
+		return []Result{{Address: address, Attempts: 1, CheckedAt: time.Now(), Err: err}}
+	}
+	address = parsed
+	host, port, _ := net.SplitHostPort(address)
+	if _, err := netip.ParseAddr(host); err == nil {
+		return []Result{c.Ping(ctx, address)}
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	addrs, err := c.lookupIPs(lookupCtx, host)
+	cancel()
+	if err != nil {
+		c.debug(ctx, "resolving failed", "address", address, "error", err)
+		return []Result{{Address: address, Attempts: 1, CheckedAt: time.Now(), Err: err}}
+	}
+	c.debug(ctx, "pinging every address", "address", address, "resolved", len(addrs))
+
+	results := make([]Result, len(addrs))
+	var wg sync.WaitGroup
+	for i, addr := range addrs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resolved := c.joinHostPort(addr, port)
+			results[i] = c.Ping(ctx, resolved)
+			results[i].Address = address
+			results[i].Resolved = resolved
+		}()
+	}
+	wg.Wait()
+	return results
+}
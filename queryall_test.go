@@ -0,0 +1,46 @@
+package bedrockping
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestQueryAll(t *testing.T) {
+	address := startTestServer(t, testResponse("Backend"))
+	_, port, _ := net.SplitHostPort(address)
+	// Nothing listens on 127.0.0.2, so only the first backend is healthy
+	resolver := staticResolver{"play.example.com": {netip.MustParseAddr("127.0.0.1"), netip.MustParseAddr("127.0.0.2"), netip.MustParseAddr("::1")}}
+	c := NewClient(WithResolver(resolver), WithNetwork("udp4"), WithTimeout(500*time.Millisecond))
+
+	results := c.QueryAll(context.Background(), "play.example.com:"+port)
+	if len(results) != 2 {
+		t.Fatalf("expected a result for each IPv4 address, got %+v", results)
+	}
+	if res := results[0]; res.Err != nil || res.Address != "play.example.com:"+port || res.Resolved != address || res.Response.ServerName != "Backend" {
+		t.Errorf("unexpected first result %+v", res)
+	}
+	if res := results[1]; res.Err == nil || res.Address != "play.example.com:"+port || res.Resolved != "127.0.0.2:"+port {
+		t.Errorf("unexpected second result %+v", res)
+	}
+
+	if results := c.QueryAll(context.Background(), address); len(results) != 1 || results[0].Err != nil || results[0].Resolved != address {
+		t.Errorf("expected an IP address to be pinged on its own, got %+v", results)
+	}
+
+	var dnsErr *net.DNSError
+	results = c.QueryAll(context.Background(), "missing.example.com:"+port)
+	if len(results) != 1 || !errors.As(results[0].Err, &dnsErr) || results[0].Address != "missing.example.com:"+port {
+		t.Errorf("expected a single result with the resolving error, got %+v", results)
+	}
+
+	if results := c.QueryAll(context.Background(), "play.example.com"); len(results) != 2 || results[0].Resolved != "127.0.0.1:19132" {
+		t.Errorf("expected the default port for an address without one, got %+v", results)
+	}
+	if results := c.QueryAll(context.Background(), "play.example.com:port"); len(results) != 1 || results[0].Err == nil {
+		t.Errorf("expected an error for an invalid address, got %+v", results)
+	}
+}
@@ -0,0 +1,156 @@
+package bedrockping
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiting the rate that pings are sent.
+// It allows bursts of up to burst packets and refills at rate packets per second.
+// When the bucket is empty waiting packets are released highest priority first,
+// and in the order they started waiting for equal priorities.
+// A nil *RateLimiter imposes no limit. A RateLimiter is safe for concurrent use.
+type RateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time
+	waiters waiterQueue
+	seq     uint64
+	running bool
+}
+
+// NewRateLimiter returns a RateLimiter allowing rate packets per second with bursts of up to burst packets.
+// If burst is less than 1 it is set to 1.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// refill adds the tokens accumulated since the last refill, l.mu must be held.
+func (l *RateLimiter) refill() {
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+}
+
+// Wait blocks until a packet may be sent or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	return l.WaitPriority(ctx, 0)
+}
+
+// WaitPriority is like Wait but packets with a higher priority are released first.
+func (l *RateLimiter) WaitPriority(ctx context.Context, priority int) error {
+	if l == nil || l.rate <= 0 {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	l.refill()
+	if len(l.waiters) == 0 && l.tokens >= 1 {
+		l.tokens--
+		l.mu.Unlock()
+		return nil
+	}
+
+	w := &waiter{priority: priority, seq: l.seq, ready: make(chan struct{})}
+	l.seq++
+	heap.Push(&l.waiters, w)
+	if !l.running {
+		l.running = true
+		go l.dispatch()
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if w.index < 0 {
+			// Already released, return the token for someone else
+			l.tokens++
+		} else {
+			heap.Remove(&l.waiters, w.index)
+		}
+		return ctx.Err()
+	}
+}
+
+// dispatch releases waiters as tokens become available until none are left.
+func (l *RateLimiter) dispatch() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for len(l.waiters) > 0 {
+		l.refill()
+		if l.tokens >= 1 {
+			l.tokens--
+			w := heap.Pop(&l.waiters).(*waiter)
+			close(w.ready)
+			continue
+		}
+
+		delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+		time.Sleep(delay)
+		l.mu.Lock()
+	}
+	l.running = false
+}
+
+// waiter is a packet waiting for a token.
+type waiter struct {
+	priority int
+	seq      uint64
+	ready    chan struct{}
+	index    int
+}
+
+// waiterQueue is a heap of waiters ordered by priority then arrival.
+type waiterQueue []*waiter
+
+func (q waiterQueue) Len() int { return len(q) }
+
+func (q waiterQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q waiterQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *waiterQueue) Push(x any) {
+	w := x.(*waiter)
+	w.index = len(*q)
+	*q = append(*q, w)
+}
+
+func (q *waiterQueue) Pop() any {
+	old := *q
+	w := old[len(old)-1]
+	old[len(old)-1] = nil
+	w.index = -1
+	*q = old[:len(old)-1]
+	return w
+}
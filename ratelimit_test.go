@@ -0,0 +1,69 @@
+package bedrockping
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := NewRateLimiter(100, 5)
+
+	start := time.Now()
+	for i := 0; i < 15; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first 5 packets are a burst, the remaining 10 take at least 100ms at 100/s
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("rate limiter allowed 15 packets in %s", elapsed)
+	}
+}
+
+func TestRateLimiterNil(t *testing.T) {
+	var l *RateLimiter
+	if err := l.Wait(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected deadline exceeded, got: %v", err)
+	}
+}
+
+func TestRateLimiterPriority(t *testing.T) {
+	l := NewRateLimiter(20, 1)
+	// Empty the bucket so everyone has to queue
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	order := make(chan int, 3)
+	for _, priority := range []int{0, 1, 2} {
+		go func(priority int) {
+			if err := l.WaitPriority(context.Background(), priority); err != nil {
+				t.Error(err)
+			}
+			order <- priority
+		}(priority)
+		// Make sure the waiters queue in a known order
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	// Everyone queued before the bucket refilled, so the highest priority goes first
+	first, second, third := <-order, <-order, <-order
+	if first != 2 || second != 1 || third != 0 {
+		t.Errorf("released out of priority order: %d, %d, %d", first, second, third)
+	}
+}
@@ -0,0 +1,264 @@
+// Package report renders status reports of batch results with Go templates, such as an HTML status page or a
+// Markdown table for a wiki or chat message. Templates can use either text/template or html/template, with the
+// helper functions in Funcs:
+//
+//	tmpl := template.Must(template.New("report").Funcs(report.Funcs).Parse(text))
+//	err := report.Render(w, tmpl, m.QueryMany(ctx, targets, bedrockping.BatchOptions{}))
+//
+// Markdown and HTML are ready-made reports.
+package report
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// Template is a parsed template from text/template or html/template.
+type Template interface {
+	Execute(w io.Writer, data any) error
+}
+
+// Data is the value templates are executed with.
+type Data struct {
+	// Servers holds a Server for each result, in the order given.
+	Servers []Server
+	// Summary holds the statistics of every result.
+	Summary bedrockping.Summary
+	// GeneratedAt is when the report was rendered.
+	GeneratedAt time.Time
+}
+
+// Online returns the servers that responded.
+func (d Data) Online() []Server {
+	return d.filter(true)
+}
+
+// Offline returns the servers that didn't respond.
+func (d Data) Offline() []Server {
+	return d.filter(false)
+}
+
+func (d Data) filter(online bool) []Server {
+	var servers []Server
+	for _, s := range d.Servers {
+		if s.Online == online {
+			servers = append(servers, s)
+		}
+	}
+	return servers
+}
+
+// Server is the result of querying a server. The fields of the response are promoted, so templates can use
+// {{.ServerName}} and {{.PlayerCount}} directly, and are empty for servers that didn't respond.
+type Server struct {
+	bedrockping.Response
+	Address string
+	// Resolved is the IP address and port that was pinged.
+	Resolved  string
+	Online    bool
+	Latency   time.Duration
+	CheckedAt time.Time
+	// Error is the error of a failed ping and ErrorKind its kind, see bedrockping.ErrorKind.
+	// Both are empty if the ping succeeded.
+	Error      string
+	ErrorKind  string
+	Enrichment map[string]string
+}
+
+// NewServer returns the Server for res.
+func NewServer(res bedrockping.Result) Server {
+	s := Server{
+		Address:    res.Address,
+		Resolved:   res.Resolved,
+		Online:     res.Err == nil,
+		CheckedAt:  res.CheckedAt,
+		Enrichment: res.Enrichment,
+	}
+	if res.Err != nil {
+		s.Error = res.Err.Error()
+		s.ErrorKind = bedrockping.ErrorKind(res.Err)
+	} else {
+		s.Response = res.Response
+		s.Latency = res.Latency
+	}
+	return s
+}
+
+// Render executes tmpl with the Data of results, writing the report to w.
+func Render(w io.Writer, tmpl Template, results []bedrockping.Result) error {
+	data := Data{
+		Servers:     make([]Server, len(results)),
+		Summary:     bedrockping.Summarize(results),
+		GeneratedAt: time.Now(),
+	}
+	for i, res := range results {
+		data.Servers[i] = NewServer(res)
+	}
+	return tmpl.Execute(w, data)
+}
+
+// Funcs are the helper functions available to report templates, add them with the template's Funcs method:
+//
+//	strip       removes formatting codes, {{strip .ServerName}}
+//	ms          converts a duration to fractional milliseconds, {{ms .Latency}}
+//	duration    formats a duration rounded for display, {{duration .Latency}} gives "23.5ms"
+//	sortBy      returns servers sorted by "name", "version", "players", "latency" or "address", or in reverse with
+//	            a "-" prefix, servers that didn't respond always being last, {{range sortBy "players" .Servers}}
+//	percent     returns n as a percentage of total, {{percent .Summary.Reachable .Summary.Total}}
+//	json        encodes a value as JSON
+var Funcs = template.FuncMap{
+	"strip":    bedrockping.StripFormatting,
+	"ms":       milliseconds,
+	"duration": formatDuration,
+	"sortBy":   sortBy,
+	"percent":  percent,
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// formatDuration rounds d to a precision that's useful to read.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < 10*time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	case d < time.Second:
+		return d.Round(100 * time.Microsecond).String()
+	}
+	return d.Round(time.Millisecond).String()
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total) * 100
+}
+
+// sortBy returns a copy of servers sorted by column.
+func sortBy(column string, servers []Server) ([]Server, error) {
+	reverse := strings.HasPrefix(column, "-")
+	column = strings.TrimPrefix(column, "-")
+
+	var compare func(a, b Server) int
+	switch column {
+	case "name":
+		compare = func(a, b Server) int {
+			return strings.Compare(strings.ToLower(bedrockping.StripFormatting(a.ServerName)),
+				strings.ToLower(bedrockping.StripFormatting(b.ServerName)))
+		}
+	case "version":
+		compare = func(a, b Server) int { return compareVersions(a.MCPEVersion, b.MCPEVersion) }
+	case "players":
+		compare = func(a, b Server) int { return cmp.Compare(b.PlayerCount, a.PlayerCount) }
+	case "latency":
+		compare = func(a, b Server) int { return cmp.Compare(a.Latency, b.Latency) }
+	case "address":
+		compare = func(a, b Server) int { return strings.Compare(a.Address, b.Address) }
+	default:
+		return nil, fmt.Errorf("unknown sort column %q", column)
+	}
+
+	sorted := slices.Clone(servers)
+	slices.SortStableFunc(sorted, func(a, b Server) int {
+		if a.Online != b.Online {
+			if a.Online {
+				return -1
+			}
+			return 1
+		}
+		if reverse {
+			return compare(b, a)
+		}
+		return compare(a, b)
+	})
+	return sorted, nil
+}
+
+// compareVersions compares dotted version numbers numerically, so 1.9 is before 1.14.
+// Parts that aren't numbers are compared as strings.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil && an != bn:
+			return cmp.Compare(an, bn)
+		case (aErr != nil || bErr != nil) && as[i] != bs[i]:
+			return strings.Compare(as[i], bs[i])
+		}
+	}
+	return len(as) - len(bs)
+}
+
+// Markdown is a report with a table of the servers, sorted by players, followed by those that didn't respond.
+var Markdown = template.Must(template.New("markdown").Funcs(Funcs).Parse(markdownText))
+
+const markdownText = `# Server Status
+
+{{.Summary.Reachable}} of {{.Summary.Total}} servers online, {{.Summary.PlayerCount}} players.
+
+| Server | Address | Version | Players | Latency |
+| ------ | ------- | ------- | ------- | ------- |
+{{range sortBy "players" .Online -}}
+| {{strip .ServerName}} | {{.Address}} | {{.MCPEVersion}} | {{.PlayerCount}}/{{.MaxPlayers}} | {{duration .Latency}} |
+{{end}}
+{{with .Offline -}}
+## Offline
+
+{{range .}}- {{.Address}}: {{.Error}}
+{{end}}
+{{end -}}
+_Generated {{.GeneratedAt.UTC.Format "2006-01-02 15:04:05 MST"}}_
+`
+
+// HTML is a standalone HTML page with a table of the servers, sorted by players with those that didn't
+// respond last.
+var HTML = htmltemplate.Must(htmltemplate.New("html").Funcs(Funcs).Parse(htmlText))
+
+const htmlText = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Server Status</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { padding: 4px 12px; text-align: left; border-bottom: 1px solid #ddd; }
+.online { color: #2a2; }
+.offline { color: #c22; }
+</style>
+</head>
+<body>
+<h1>Server Status</h1>
+<p>{{.Summary.Reachable}} of {{.Summary.Total}} servers online, {{.Summary.PlayerCount}} players.</p>
+<table>
+<tr><th>Server</th><th>Address</th><th>Status</th><th>Version</th><th>Players</th><th>Latency</th></tr>
+{{range sortBy "players" .Servers -}}
+{{if .Online -}}
+<tr><td>{{strip .ServerName}}</td><td>{{.Address}}</td><td class="online">Online</td><td>{{.MCPEVersion}}</td><td>{{.PlayerCount}}/{{.MaxPlayers}}</td><td>{{duration .Latency}}</td></tr>
+{{else -}}
+<tr><td></td><td>{{.Address}}</td><td class="offline" title="{{.Error}}">Offline</td><td></td><td></td><td></td></tr>
+{{end -}}
+{{end -}}
+</table>
+<p><small>Generated {{.GeneratedAt.UTC.Format "2006-01-02 15:04:05 MST"}}</small></p>
+</body>
+</html>
+`
@@ -0,0 +1,107 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+var testResults = []bedrockping.Result{
+	{Address: "small.example.com:19132", Latency: 40 * time.Millisecond,
+		Response: bedrockping.Response{ServerName: "§aSmall", MCPEVersion: "1.9.0", PlayerCount: 1, MaxPlayers: 10}},
+	{Address: "down.example.com:19132", Err: context.DeadlineExceeded},
+	{Address: "big.example.com:19132", Latency: 23500 * time.Microsecond,
+		Response: bedrockping.Response{ServerName: "Big <Server>", MCPEVersion: "1.14.60", PlayerCount: 30, MaxPlayers: 100}},
+}
+
+func TestRender(t *testing.T) {
+	tmpl := template.Must(template.New("test").Funcs(Funcs).Parse(
+		`{{range sortBy "-players" .Servers}}{{.Address}} {{strip .ServerName}} {{ms .Latency}}|{{end}}` +
+			`{{len .Online}}/{{len .Offline}} {{printf "%.0f" (percent .Summary.Reachable .Summary.Total)}}%`))
+
+	var buf bytes.Buffer
+	if err := Render(&buf, tmpl, testResults); err != nil {
+		t.Fatal(err)
+	}
+	expected := "small.example.com:19132 Small 40|big.example.com:19132 Big <Server> 23.5|down.example.com:19132  0|2/1 67%"
+	if buf.String() != expected {
+		t.Errorf("got %q, expected %q", buf.String(), expected)
+	}
+
+	tmpl = template.Must(template.New("test").Funcs(Funcs).Parse(`{{sortBy "color" .Servers}}`))
+	if err := Render(&buf, tmpl, testResults); err == nil || !strings.Contains(err.Error(), `unknown sort column "color"`) {
+		t.Errorf("expected an unknown column error, got %v", err)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, Markdown, testResults); err != nil {
+		t.Fatal(err)
+	}
+	expected := "# Server Status\n\n2 of 3 servers online, 31 players.\n\n" +
+		"| Server | Address | Version | Players | Latency |\n" +
+		"| ------ | ------- | ------- | ------- | ------- |\n" +
+		"| Big <Server> | big.example.com:19132 | 1.14.60 | 30/100 | 23.5ms |\n" +
+		"| Small | small.example.com:19132 | 1.9.0 | 1/10 | 40ms |\n" +
+		"\n## Offline\n\n- down.example.com:19132: context deadline exceeded\n\n_Generated "
+	if !strings.HasPrefix(buf.String(), expected) {
+		t.Errorf("got:\n%s\nexpected to start with:\n%s", buf.String(), expected)
+	}
+
+	buf.Reset()
+	if err := Render(&buf, Markdown, testResults[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "| 1/10 | 40ms |\n\n_Generated ") {
+		t.Errorf("expected the table to end before the footer:\n%s", buf.String())
+	}
+}
+
+func TestHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, HTML, testResults); err != nil {
+		t.Fatal(err)
+	}
+	html := buf.String()
+	big := strings.Index(html, "<td>Big &lt;Server&gt;</td>")
+	small := strings.Index(html, "<td>Small</td>")
+	down := strings.Index(html, `<td>down.example.com:19132</td><td class="offline" title="context deadline exceeded">`)
+	if big < 0 || small < big || down < small {
+		t.Errorf("unexpected HTML:\n%s", html)
+	}
+}
+
+func TestSortBy(t *testing.T) {
+	servers := []Server{
+		{Address: "c", Online: true, Response: bedrockping.Response{MCPEVersion: "1.14.0"}},
+		{Address: "a", Online: false},
+		{Address: "b", Online: true, Response: bedrockping.Response{MCPEVersion: "1.9.0"}},
+	}
+	for column, expected := range map[string]string{
+		"address":  "bca",
+		"-address": "cba",
+		"version":  "bca",
+		"-version": "cba",
+	} {
+		sorted, err := sortBy(column, servers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got string
+		for _, s := range sorted {
+			got += s.Address
+		}
+		if got != expected {
+			t.Errorf("%s: got %s, expected %s", column, got, expected)
+		}
+	}
+	if servers[0].Address != "c" {
+		t.Error("expected the servers to be left unchanged")
+	}
+}
@@ -0,0 +1,36 @@
+package bedrockping
+
+import (
+	"context"
+	"net"
+	"net/netip"
+)
+
+// Resolver looks up the addresses hosts are pinged at. *net.Resolver implements it, so a client can use
+// a particular DNS server, and other implementations can resolve names over DNS over HTTPS or TLS,
+// from split-horizon zones or from fixed addresses in tests.
+type Resolver interface {
+	// LookupNetIP returns the IP addresses of host, network being "ip", "ip4" or "ip6".
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+	// LookupSRV returns the SRV records of _{service}._{proto}.{name}, see WithSRV.
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+// WithResolver sets the resolver hosts are looked up with, the default is net.DefaultResolver.
+// A nil resolver restores the default.
+//
+//	client := bedrockping.NewClient(bedrockping.WithResolver(&net.Resolver{
+//		PreferGo: true,
+//		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+//			var d net.Dialer
+//			return d.DialContext(ctx, network, "10.0.0.53:53")
+//		},
+//	}))
+func WithResolver(r Resolver) Option {
+	return func(c *Client) {
+		if r == nil {
+			r = net.DefaultResolver
+		}
+		c.resolver = r
+	}
+}
@@ -0,0 +1,56 @@
+package bedrockping
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+// staticResolver resolves names from a map, without SRV records.
+type staticResolver map[string][]netip.Addr
+
+func (r staticResolver) LookupNetIP(_ context.Context, network, host string) ([]netip.Addr, error) {
+	var addrs []netip.Addr
+	for _, addr := range r[host] {
+		if network == "ip" || (network == "ip4") == addr.Is4() {
+			addrs = append(addrs, addr)
+		}
+	}
+	if len(addrs) == 0 {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addrs, nil
+}
+
+func (r staticResolver) LookupSRV(_ context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	return "", nil, &net.DNSError{Err: "no such host", Name: "_" + service + "._" + proto + "." + name, IsNotFound: true}
+}
+
+func TestWithResolver(t *testing.T) {
+	address := startTestServer(t, testResponse("Resolver Server"))
+	_, port, _ := net.SplitHostPort(address)
+	resolver := staticResolver{"play.example.com": {netip.MustParseAddr("::1"), netip.MustParseAddr("127.0.0.1")}}
+
+	c := NewClient(WithResolver(resolver), WithNetwork("udp4"), WithTimeout(time.Second), WithSRV(DefaultSRVService))
+	res := c.Ping(context.Background(), "play.example.com:"+port)
+	if res.Err != nil || res.Resolved != address || res.Response.ServerName != "Resolver Server" {
+		t.Errorf("unexpected result %+v", res)
+	}
+
+	resolved, err := c.Resolve(context.Background(), "play.example.com:19132")
+	if err != nil || resolved != "127.0.0.1:19132" {
+		t.Errorf("expected the SRV lookup to fall back to the host, got %s %v", resolved, err)
+	}
+
+	var dnsErr *net.DNSError
+	if _, err := c.Resolve(context.Background(), "missing.example.com:19132"); !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+
+	if c := NewClient(WithResolver(resolver), WithResolver(nil)); c.resolver != net.DefaultResolver {
+		t.Errorf("expected a nil resolver to restore the default, got %v", c.resolver)
+	}
+}
@@ -0,0 +1,102 @@
+package bedrockping
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+)
+
+// Responder answers pings like a Bedrock server, without accepting connections.
+// It is useful as a placeholder while a server is down for maintenance, and for testing clients.
+// A Responder is safe for concurrent use, the response may be changed while it is serving.
+type Responder struct {
+	mu   sync.RWMutex
+	resp Response
+}
+
+// NewResponder creates a Responder answering with resp. Timestamp is replaced with the one from each ping,
+// and if ServerID is zero a random one is used.
+func NewResponder(resp Response) *Responder {
+	if resp.ServerID == 0 {
+		var id [8]byte
+		rand.Read(id[:])
+		resp.ServerID = binary.BigEndian.Uint64(id[:])
+	}
+	return &Responder{resp: resp}
+}
+
+// Response returns the response currently sent.
+func (r *Responder) Response() Response {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.resp
+}
+
+// SetResponse changes the response sent to later pings.
+func (r *Responder) SetResponse(resp Response) {
+	r.mu.Lock()
+	r.resp = resp
+	r.mu.Unlock()
+}
+
+// ListenAndServe listens on the UDP address and answers pings until ctx is done.
+func (r *Responder) ListenAndServe(ctx context.Context, address string) error {
+	conn, err := net.ListenPacket("udp", address)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+	if err := r.Serve(conn); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// Serve answers pings received on conn until it's closed, when it returns nil.
+func (r *Responder) Serve(conn net.PacketConn) error {
+	buf := make([]byte, maxPacketSize)
+	pong := new(bytes.Buffer)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		timestamp, ok := parseUnconnectedPing(buf[:n])
+		if !ok {
+			continue
+		}
+
+		resp := r.Response()
+		resp.Timestamp = timestamp
+		pong.Reset()
+		if err := WriteUnconnectedPong(pong, resp); err != nil {
+			return err
+		}
+		// A failure to answer one client shouldn't stop answering the others
+		conn.WriteTo(pong.Bytes(), addr)
+	}
+}
+
+// parseUnconnectedPing returns the timestamp of an 'Unconnected Ping (0x01)' or
+// 'Unconnected Ping Open Connections (0x02)' packet.
+func parseUnconnectedPing(packet []byte) (uint64, bool) {
+	if len(packet) < 1+8+len(offlineMessageDataID) || (packet[0] != 0x01 && packet[0] != 0x02) {
+		return 0, false
+	}
+	if !bytes.Equal(packet[9:9+len(offlineMessageDataID)], offlineMessageDataID) {
+		return 0, false
+	}
+	return binary.BigEndian.Uint64(packet[1:9]), true
+}
@@ -0,0 +1,56 @@
+package bedrockping
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestResponder(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewResponder(testResponse("Maintenance"))
+	if r.Response().ServerID == 0 {
+		t.Error("expected a random server ID")
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- r.Serve(conn)
+	}()
+
+	client := NewClient(WithTimeout(time.Second))
+	res := client.Ping(context.Background(), conn.LocalAddr().String())
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if res.Response.ServerName != "Maintenance" || res.Response.ServerID != r.Response().ServerID {
+		t.Errorf("unexpected response: %+v", res.Response)
+	}
+	// The timestamp is echoed, so the latency is measured from the ping that was answered
+	if res.Latency <= 0 || res.Latency > time.Second {
+		t.Errorf("unexpected latency: %s", res.Latency)
+	}
+
+	r.SetResponse(testResponse("Back"))
+	if resp, err := client.Query(context.Background(), conn.LocalAddr().String()); err != nil || resp.ServerName != "Back" {
+		t.Errorf("unexpected response: %+v, %v", resp, err)
+	}
+
+	conn.Close()
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestParseUnconnectedPing(t *testing.T) {
+	if _, ok := parseUnconnectedPing([]byte{0x01, 0, 0}); ok {
+		t.Error("accepted a short packet")
+	}
+	if _, ok := parseUnconnectedPing(append([]byte{0x01, 0, 0, 0, 0, 0, 0, 0, 7}, make([]byte, 16)...)); ok {
+		t.Error("accepted a packet without the magic")
+	}
+}
@@ -0,0 +1,46 @@
+package bedrockping
+
+import "time"
+
+// rttEstimator tracks a smoothed round trip time and derives a retransmission timeout from it
+// in the same way as TCP, see RFC 6298.
+type rttEstimator struct {
+	srtt   time.Duration
+	rttvar time.Duration
+	rto    time.Duration
+	// last is when the last sample was taken
+	last time.Time
+}
+
+const (
+	rttAlpha = 8 // 1/alpha is the gain for the smoothed RTT
+	rttBeta  = 4 // 1/beta is the gain for the RTT variation
+	rttK     = 4
+	// rttGranularity is the minimum variance added to the smoothed RTT.
+	rttGranularity = time.Millisecond
+	// rttIdle is how long an estimate is kept without new samples, so clients pinging many addresses
+	// once, like a scan, don't keep an estimate for each of them forever.
+	rttIdle = 10 * time.Minute
+)
+
+// sample updates the estimate with a newly measured round trip time.
+func (e *rttEstimator) sample(rtt time.Duration, now time.Time) {
+	e.last = now
+	if e.srtt == 0 {
+		e.srtt = rtt
+		e.rttvar = rtt / 2
+	} else {
+		delta := e.srtt - rtt
+		if delta < 0 {
+			delta = -delta
+		}
+		e.rttvar += (delta - e.rttvar) / rttBeta
+		e.srtt += (rtt - e.srtt) / rttAlpha
+	}
+
+	variance := rttK * e.rttvar
+	if variance < rttGranularity {
+		variance = rttGranularity
+	}
+	e.rto = e.srtt + variance
+}
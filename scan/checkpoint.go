@@ -0,0 +1,170 @@
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Checkpoint records how far a scan has progressed so it can be resumed.
+type Checkpoint struct {
+	CIDR  string `json:"cidr"`
+	Ports []int  `json:"ports"`
+	// Last is the last address handed out for probing, enumeration resumes after it.
+	Last string `json:"last,omitempty"`
+	// Pending lists addresses that were being probed and hadn't finished, they are probed again on resume.
+	Pending []string `json:"pending,omitempty"`
+	// Complete is set once every address in the range has been probed.
+	Complete bool `json:"complete"`
+}
+
+// CheckpointStore persists scan checkpoints.
+// Implementations must be safe for concurrent use.
+type CheckpointStore interface {
+	// Load returns the last saved checkpoint, or nil if there isn't one.
+	Load(ctx context.Context) (*Checkpoint, error)
+	// Save replaces the saved checkpoint.
+	Save(ctx context.Context, cp Checkpoint) error
+}
+
+// FileCheckpointStore is a CheckpointStore that keeps the checkpoint as JSON in a file.
+type FileCheckpointStore struct {
+	Path string
+}
+
+// Load reads the checkpoint from the file, a missing file is not an error.
+func (f FileCheckpointStore) Load(ctx context.Context) (*Checkpoint, error) {
+	data, err := os.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// Save atomically replaces the file with cp.
+func (f FileCheckpointStore) Save(ctx context.Context, cp Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), filepath.Base(f.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
+
+// progress tracks the state of a running scan for checkpointing.
+type progress struct {
+	mu       sync.Mutex
+	cidr     string
+	ports    []int
+	last     string
+	pending  map[string]struct{}
+	complete bool
+}
+
+func newProgress(cidr string, ports []int) *progress {
+	return &progress{cidr: cidr, ports: ports, pending: make(map[string]struct{})}
+}
+
+// resumes reports whether cp was saved by a scan of the same range and ports.
+func (p *progress) resumes(cp *Checkpoint) bool {
+	return cp != nil && cp.CIDR == p.cidr && reflect.DeepEqual(cp.Ports, p.ports)
+}
+
+// dispatch records that address is about to be probed, returning the previous last address for undispatch.
+func (p *progress) dispatch(address string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	last := p.last
+	p.pending[address] = struct{}{}
+	p.last = address
+	return last
+}
+
+// undispatch reverts dispatching address when it wasn't probed after all.
+func (p *progress) undispatch(address, last string) {
+	p.mu.Lock()
+	delete(p.pending, address)
+	p.last = last
+	p.mu.Unlock()
+}
+
+func (p *progress) finish(address string) {
+	p.mu.Lock()
+	delete(p.pending, address)
+	p.mu.Unlock()
+}
+
+func (p *progress) checkpoint() Checkpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cp := Checkpoint{CIDR: p.cidr, Ports: p.ports, Last: p.last, Complete: p.complete}
+	for address := range p.pending {
+		cp.Pending = append(cp.Pending, address)
+	}
+	sort.Strings(cp.Pending)
+	return cp
+}
+
+// resumePosition returns where enumeration should restart after last, and whether anything is left.
+func resumePosition(ipNet *net.IPNet, ports []int, last string) (net.IP, int, bool) {
+	start := ipNet.IP.Mask(ipNet.Mask)
+	if last == "" {
+		return start, 0, true
+	}
+
+	host, portStr, err := net.SplitHostPort(last)
+	if err != nil {
+		return start, 0, true
+	}
+	ip := net.ParseIP(host)
+	port, err := strconv.Atoi(portStr)
+	if ip == nil || err != nil || !ipNet.Contains(ip) {
+		return start, 0, true
+	}
+	if v4 := ip.To4(); v4 != nil && len(start) == net.IPv4len {
+		ip = v4
+	}
+
+	for i, p := range ports {
+		if p != port {
+			continue
+		}
+		if i+1 < len(ports) {
+			return ip, i + 1, true
+		}
+		if isLastIP(ip) {
+			return nil, 0, false
+		}
+		next := nextIP(ip)
+		return next, 0, ipNet.Contains(next)
+	}
+	return start, 0, true
+}
@@ -0,0 +1,138 @@
+package scan
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memoryCheckpointStore struct {
+	mu sync.Mutex
+	cp *Checkpoint
+}
+
+func (m *memoryCheckpointStore) Load(ctx context.Context) (*Checkpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cp, nil
+}
+
+func (m *memoryCheckpointStore) Save(ctx context.Context, cp Checkpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cp = &cp
+	return nil
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	store := FileCheckpointStore{Path: filepath.Join(t.TempDir(), "scan.json")}
+
+	cp, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp != nil {
+		t.Fatalf("expected no checkpoint, got %+v", cp)
+	}
+
+	expect := Checkpoint{CIDR: "10.0.0.0/8", Ports: []int{19132}, Last: "10.0.1.2:19132", Pending: []string{"10.0.1.1:19132"}}
+	if err := store.Save(context.Background(), expect); err != nil {
+		t.Fatal(err)
+	}
+
+	cp, err = store.Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&expect, cp) {
+		t.Errorf("got %+v", cp)
+	}
+}
+
+func TestScanCheckpointResume(t *testing.T) {
+	port := startTestServer(t, "Resumed")
+	address := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+
+	// The server was being probed when the previous scan stopped and was the last address enumerated
+	store := &memoryCheckpointStore{cp: &Checkpoint{
+		CIDR:    "127.0.0.1/32",
+		Ports:   []int{port},
+		Last:    address,
+		Pending: []string{address},
+	}}
+
+	s := Scanner{Timeout: time.Second, Resend: 100 * time.Millisecond, LocalAddr: "127.0.0.1:0", Checkpoints: store}
+	results, err := s.Scan(context.Background(), "127.0.0.1/32", []int{port})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found int
+	for range results {
+		found++
+	}
+	if found != 1 {
+		t.Errorf("expected 1 server, found %d", found)
+	}
+
+	cp, _ := store.Load(context.Background())
+	if !cp.Complete || len(cp.Pending) != 0 {
+		t.Errorf("expected complete checkpoint, got %+v", cp)
+	}
+
+	// Scanning again does nothing since the range is complete
+	results, err = s.Scan(context.Background(), "127.0.0.1/32", []int{port})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for res := range results {
+		t.Errorf("unexpected result: %v", res)
+	}
+}
+
+func TestProgressDispatch(t *testing.T) {
+	p := newProgress("10.0.0.0/30", []int{19132})
+
+	// A worker may finish an address as soon as it's dispatched
+	p.dispatch("10.0.0.0:19132")
+	p.finish("10.0.0.0:19132")
+	last := p.dispatch("10.0.0.1:19132")
+	if cp := p.checkpoint(); cp.Last != "10.0.0.1:19132" || !reflect.DeepEqual(cp.Pending, []string{"10.0.0.1:19132"}) {
+		t.Errorf("unexpected checkpoint: %+v", cp)
+	}
+
+	// An address dispatched as the scan stops was never probed
+	p.undispatch("10.0.0.1:19132", last)
+	if cp := p.checkpoint(); cp.Last != "10.0.0.0:19132" || len(cp.Pending) != 0 {
+		t.Errorf("unexpected checkpoint: %+v", cp)
+	}
+}
+
+func TestResumePosition(t *testing.T) {
+	_, ipNet, _ := net.ParseCIDR("10.0.0.0/30")
+	ports := []int{1, 2}
+
+	tests := []struct {
+		last string
+		ip   string
+		port int
+		ok   bool
+	}{
+		{"", "10.0.0.0", 0, true},
+		{"10.0.0.1:1", "10.0.0.1", 1, true},
+		{"10.0.0.1:2", "10.0.0.2", 0, true},
+		{"10.0.0.3:2", "", 0, false},
+		{"192.168.0.1:1", "10.0.0.0", 0, true},
+	}
+	for _, test := range tests {
+		ip, port, ok := resumePosition(ipNet, ports, test.last)
+		if ok != test.ok || (ok && (ip.String() != test.ip || port != test.port)) {
+			t.Errorf("resumePosition(%q) = %s, %d, %t", test.last, ip, port, ok)
+		}
+	}
+}
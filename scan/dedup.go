@@ -0,0 +1,61 @@
+package scan
+
+import (
+	"encoding/json"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// Server is a single server discovered by a scan, possibly reachable on several addresses
+// such as anycast or dual-stack deployments.
+type Server struct {
+	// Result is the first response received from the server.
+	bedrockping.Result
+	// Addresses lists every address the server responded on, in the order they were discovered.
+	Addresses []string `json:"addresses"`
+}
+
+// MarshalJSON encodes s as a bedrockping.ResultJSON with an "addresses" field.
+func (s Server) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		bedrockping.ResultJSON
+		Addresses []string `json:"addresses"`
+	}{s.Result.JSON(), s.Addresses})
+}
+
+// Dedup collapses scan results from the same server, identified by its ServerID, into a single Server
+// with all observed addresses attached. Results with a ServerID of 0 are never collapsed since some
+// servers don't set it.
+//
+// A server can't be known to be complete until every result has been seen, so the returned channel
+// only emits once results is closed. Memory use is proportional to the number of unique servers.
+func Dedup(results <-chan bedrockping.Result) <-chan Server {
+	out := make(chan Server)
+
+	go func() {
+		defer close(out)
+
+		var servers []*Server
+		byID := make(map[uint64]*Server)
+
+		for res := range results {
+			id := res.Response.ServerID
+			if server, ok := byID[id]; ok && id != 0 {
+				server.Addresses = append(server.Addresses, res.Address)
+				continue
+			}
+
+			server := &Server{Result: res, Addresses: []string{res.Address}}
+			servers = append(servers, server)
+			if id != 0 {
+				byID[id] = server
+			}
+		}
+
+		for _, server := range servers {
+			out <- *server
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,62 @@
+package scan
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+func TestDedup(t *testing.T) {
+	results := make(chan bedrockping.Result)
+	go func() {
+		defer close(results)
+		for _, res := range []bedrockping.Result{
+			{Address: "10.0.0.1:19132", Response: bedrockping.Response{ServerID: 1}},
+			{Address: "10.0.0.2:19132", Response: bedrockping.Response{ServerID: 2}},
+			{Address: "[fd00::1]:19132", Response: bedrockping.Response{ServerID: 1}},
+			{Address: "10.0.0.3:19132"},
+			{Address: "10.0.0.4:19132"},
+		} {
+			results <- res
+		}
+	}()
+
+	var addresses [][]string
+	for server := range Dedup(results) {
+		addresses = append(addresses, server.Addresses)
+	}
+
+	expect := [][]string{
+		{"10.0.0.1:19132", "[fd00::1]:19132"},
+		{"10.0.0.2:19132"},
+		{"10.0.0.3:19132"},
+		{"10.0.0.4:19132"},
+	}
+	if !reflect.DeepEqual(expect, addresses) {
+		t.Errorf("got %v", addresses)
+	}
+}
+
+func TestServerJSON(t *testing.T) {
+	s := Server{Result: bedrockping.Result{Address: "10.0.0.1:19132", Response: bedrockping.Response{ServerID: 1}},
+		Addresses: []string{"10.0.0.1:19132", "[fd00::1]:19132"}}
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		SchemaVersion int
+		Address       string
+		Response      struct{ ServerID uint64 }
+		Addresses     []string
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.SchemaVersion != bedrockping.JSONSchemaVersion || decoded.Address != s.Address ||
+		decoded.Response.ServerID != 1 || !reflect.DeepEqual(decoded.Addresses, s.Addresses) {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+}
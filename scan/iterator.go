@@ -0,0 +1,52 @@
+package scan
+
+import (
+	"context"
+
+	"github.com/ZeroErrors/go-bedrockping"
+)
+
+// Iterator steps through the servers discovered by a scan one at a time.
+// It is an alternative to ranging over the channel returned by Scan that
+// can be abandoned part way through without leaking the scan.
+type Iterator struct {
+	results <-chan bedrockping.Result
+	cancel  context.CancelFunc
+	current bedrockping.Result
+}
+
+// Iterate starts scanning cidr on each of ports and returns an Iterator over the discovered servers.
+// See Scanner.Scan for details. The Iterator must be closed once it is no longer needed.
+func (s *Scanner) Iterate(ctx context.Context, cidr string, ports []int) (*Iterator, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	results, err := s.Scan(ctx, cidr, ports)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return &Iterator{results: results, cancel: cancel}, nil
+}
+
+// Next waits for the next discovered server, it returns false once the scan has finished.
+func (it *Iterator) Next() bool {
+	res, ok := <-it.results
+	if !ok {
+		return false
+	}
+	it.current = res
+	return true
+}
+
+// Result returns the server found by the last call to Next.
+func (it *Iterator) Result() bedrockping.Result {
+	return it.current
+}
+
+// Close stops the scan and waits for it to release its resources.
+func (it *Iterator) Close() {
+	it.cancel()
+	for range it.results {
+	}
+}
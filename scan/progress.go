@@ -0,0 +1,25 @@
+package scan
+
+import (
+	"math/big"
+	"net"
+)
+
+// maxCountableHostBits limits the size of ranges that progress totals are calculated for.
+const maxCountableHostBits = 48
+
+// rangeSize returns the number of probes needed to scan ipNet on len(ports) ports, or -1 if it's too large.
+func rangeSize(ipNet *net.IPNet, ports int) int {
+	ones, bits := ipNet.Mask.Size()
+	if bits-ones > maxCountableHostBits {
+		return -1
+	}
+	return (1 << uint(bits-ones)) * ports
+}
+
+// rangeOffset returns the number of probes enumerated before the port index portIndex of ip.
+func rangeOffset(ipNet *net.IPNet, ports int, ip net.IP, portIndex int) int {
+	base := new(big.Int).SetBytes(ipNet.IP.Mask(ipNet.Mask))
+	offset := new(big.Int).SetBytes(ip)
+	return int(offset.Sub(offset, base).Int64())*ports + portIndex
+}
@@ -0,0 +1,314 @@
+// Package scan enumerates network ranges looking for Minecraft Bedrock/MCPE servers.
+package scan
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/internal/tracker"
+)
+
+const (
+	defaultConcurrency = 256
+	defaultTimeout     = 2 * time.Second
+	defaultResend      = 500 * time.Millisecond
+
+	defaultCheckpointInterval = 5 * time.Second
+)
+
+// Scanner holds the configuration used to scan a network range.
+// The zero value is a usable Scanner with default settings.
+type Scanner struct {
+	// Rate is the maximum number of pings sent per second, including resends. 0 means unlimited.
+	Rate float64
+	// Burst is the number of pings that may be sent at once before Rate applies.
+	Burst int
+	// Concurrency is the maximum number of addresses probed at once.
+	Concurrency int
+	// Timeout is how long to wait for each address to respond.
+	Timeout time.Duration
+	// Resend is the interval that the ping packet is sent in case there is packet loss.
+	Resend time.Duration
+	// LocalAddr is the local address the scanning socket is bound to, if empty a random port is used.
+	LocalAddr string
+	// LocalAddrs, if set, overrides LocalAddr and spreads the scan across a socket bound to each address.
+	// Scan shards targets between the sockets, ScanStateless only uses the first.
+	LocalAddrs []string
+	// Buffer is the number of discovered servers held in the results channel before the scan
+	// waits for them to be consumed. 0 means every result is handed off directly.
+	Buffer int
+	// Checkpoints, if set, persists the progress of Scan so it can resume after a crash.
+	// A saved checkpoint is only used if it's for the same range and ports.
+	Checkpoints CheckpointStore
+	// CheckpointInterval is how often progress is saved, the default is 5 seconds.
+	CheckpointInterval time.Duration
+	// Progress, if set, is called whenever an address starts or finishes being probed.
+	// The total is -1 for ranges too large to count.
+	Progress bedrockping.ProgressFunc
+	// Enrichers are run by Scan on each server found, such as to look up its reverse DNS name, see
+	// bedrockping.Enrich. Their errors are ignored. ScanStateless doesn't run them.
+	Enrichers []bedrockping.Enricher
+}
+
+// Scan enumerates every address in cidr on each of ports using a default Scanner.
+// See Scanner.Scan for details.
+func Scan(ctx context.Context, cidr string, ports []int) (<-chan bedrockping.Result, error) {
+	var s Scanner
+	return s.Scan(ctx, cidr, ports)
+}
+
+// Scan enumerates every address in cidr on each of ports looking for Bedrock servers.
+// If ports is empty bedrockping.DefaultPort is used.
+// Discovered servers are streamed on the returned channel which is closed once the scan
+// has completed or ctx is done. Addresses that don't respond are not reported.
+//
+// Results are never accumulated, if the consumer falls behind the scan stops enumerating
+// new addresses until it catches up, so memory use is bounded by Concurrency and Buffer
+// regardless of the size of the range. The channel must be drained or ctx cancelled,
+// otherwise the scan never finishes.
+//
+// If Checkpoints is set progress is saved periodically and when the scan ends,
+// a later Scan of the same range continues where the previous one left off.
+// Errors saving checkpoints don't stop the scan, saving is tried again at the next interval.
+func (s *Scanner) Scan(ctx context.Context, cidr string, ports []int) (<-chan bedrockping.Result, error) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+	if len(ports) == 0 {
+		ports = []int{bedrockping.DefaultPort}
+	}
+
+	concurrency := s.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	resend := s.Resend
+	if resend <= 0 {
+		resend = defaultResend
+	}
+
+	p := newProgress(cidr, ports)
+	var resume *Checkpoint
+	if s.Checkpoints != nil {
+		cp, err := s.Checkpoints.Load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if p.resumes(cp) {
+			resume = cp
+			p.last = cp.Last
+			for _, address := range cp.Pending {
+				p.pending[address] = struct{}{}
+			}
+		}
+	}
+
+	m, err := bedrockping.NewMultiplexer(s.localAddrs()...)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Rate > 0 {
+		m.SetRateLimiter(bedrockping.NewRateLimiter(s.Rate, s.Burst))
+	}
+
+	addresses := make(chan string)
+	results := make(chan bedrockping.Result, s.Buffer)
+	c := tracker.New(s.Progress, rangeSize(ipNet, len(ports)))
+
+	var enumerated bool
+	go func() {
+		defer close(addresses)
+
+		// Addresses are pending before a worker can finish them. The pending addresses of a resumed
+		// scan already are, and stay so until they are probed.
+		send := func(address string, retry bool) bool {
+			var last string
+			if !retry {
+				last = p.dispatch(address)
+			}
+			select {
+			case addresses <- address:
+				return true
+			case <-ctx.Done():
+				if !retry {
+					p.undispatch(address, last)
+				}
+				return false
+			}
+		}
+
+		start, startPort := ipNet.IP.Mask(ipNet.Mask), 0
+		if resume != nil {
+			if resume.Complete {
+				enumerated = true
+				return
+			}
+
+			for _, address := range resume.Pending {
+				if !send(address, true) {
+					return
+				}
+			}
+
+			var ok bool
+			if start, startPort, ok = resumePosition(ipNet, ports, resume.Last); !ok {
+				enumerated = true
+				return
+			}
+			if c.Total() >= 0 {
+				c.Resume(rangeOffset(ipNet, len(ports), start, startPort) - len(resume.Pending))
+			}
+		}
+
+		enumerated = enumerateFrom(ipNet, ports, start, startPort, func(ip net.IP, port int) bool {
+			return send(net.JoinHostPort(ip.String(), strconv.Itoa(port)), false)
+		})
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for address := range addresses {
+				c.Start()
+				if probe(ctx, m, address, timeout, resend, s.Enrichers, results) {
+					p.finish(address)
+				}
+				c.Finish()
+			}
+		}()
+	}
+
+	stopSaving := make(chan struct{})
+	savingDone := make(chan struct{})
+	go func() {
+		defer close(savingDone)
+		if s.Checkpoints != nil {
+			s.saveCheckpoints(ctx, p, stopSaving)
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		m.Close()
+
+		close(stopSaving)
+		<-savingDone
+		if s.Checkpoints != nil {
+			p.mu.Lock()
+			p.complete = enumerated && len(p.pending) == 0
+			p.mu.Unlock()
+			// Use a fresh context so the final checkpoint is saved even if ctx was cancelled
+			s.Checkpoints.Save(context.Background(), p.checkpoint())
+		}
+
+		close(results)
+	}()
+
+	return results, nil
+}
+
+func (s *Scanner) localAddrs() []string {
+	if len(s.LocalAddrs) > 0 {
+		return s.LocalAddrs
+	}
+	return []string{s.LocalAddr}
+}
+
+func (s *Scanner) saveCheckpoints(ctx context.Context, p *progress, stop <-chan struct{}) {
+	interval := s.CheckpointInterval
+	if interval <= 0 {
+		interval = defaultCheckpointInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.Checkpoints.Save(ctx, p.checkpoint())
+		case <-stop:
+			return
+		}
+	}
+}
+
+// probe queries address and sends it to results if it responds.
+// It returns false if the probe was interrupted by ctx and needs to be repeated.
+func probe(ctx context.Context, m *bedrockping.Multiplexer, address string, timeout, resend time.Duration,
+	enrichers []bedrockping.Enricher, results chan<- bedrockping.Result) bool {
+	queryCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := m.Query(queryCtx, address, resend)
+	if err != nil {
+		return ctx.Err() == nil
+	}
+
+	res := bedrockping.Result{Address: address, Response: resp, Latency: time.Since(start), CheckedAt: start}
+	bedrockping.Enrich(ctx, &res, enrichers...)
+	select {
+	case results <- res:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// enumerate calls fn for every port of every address in ipNet until fn returns false.
+func enumerate(ipNet *net.IPNet, ports []int, fn func(ip net.IP, port int) bool) bool {
+	return enumerateFrom(ipNet, ports, ipNet.IP.Mask(ipNet.Mask), 0, fn)
+}
+
+// enumerateFrom is like enumerate but starts at the port index startPort of the address start.
+// It returns true if every address was enumerated.
+func enumerateFrom(ipNet *net.IPNet, ports []int, start net.IP, startPort int, fn func(ip net.IP, port int) bool) bool {
+	for ip := start; ipNet.Contains(ip); ip = nextIP(ip) {
+		for _, port := range ports[startPort:] {
+			if !fn(ip, port) {
+				return false
+			}
+		}
+		startPort = 0
+		if isLastIP(ip) {
+			break
+		}
+	}
+	return true
+}
+
+// nextIP returns a copy of ip incremented by one.
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+// isLastIP reports whether ip is the last address of its family, incrementing it would wrap around.
+func isLastIP(ip net.IP) bool {
+	for _, b := range ip {
+		if b != 0xff {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,230 @@
+package scan
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/internal/pingtest"
+)
+
+// startTestServer starts a server on localhost answering pings with a response named name, returning its port.
+func startTestServer(t *testing.T, name string) int {
+	_, port, err := net.SplitHostPort(pingtest.Server(t, pingtest.Response(name)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, _ := strconv.Atoi(port)
+	return n
+}
+
+// forgingConn is a net.PacketConn that zeroes the timestamp of the pongs written to it, like a server that
+// doesn't echo the timestamp of the ping it answers.
+type forgingConn struct {
+	net.PacketConn
+}
+
+func (c forgingConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	if len(p) >= 9 {
+		copy(p[1:9], make([]byte, 8))
+	}
+	return c.PacketConn.WriteTo(p, addr)
+}
+
+func TestScan(t *testing.T) {
+	port := startTestServer(t, "Scanned")
+
+	s := Scanner{Timeout: time.Second, Resend: 100 * time.Millisecond, LocalAddr: "127.0.0.1:0"}
+	results, err := s.Scan(context.Background(), "127.0.0.1/32", []int{port})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found int
+	for res := range results {
+		found++
+		if res.Address != net.JoinHostPort("127.0.0.1", strconv.Itoa(port)) {
+			t.Errorf("unexpected address: %s", res.Address)
+		}
+		if res.Response.ServerName != "Scanned" {
+			t.Errorf("unexpected server name: %s", res.Response.ServerName)
+		}
+	}
+	if found != 1 {
+		t.Errorf("expected 1 server, found %d", found)
+	}
+}
+
+func TestScanInvalidCIDR(t *testing.T) {
+	if _, err := Scan(context.Background(), "not-a-cidr", nil); err == nil {
+		t.Error("expected error for invalid cidr")
+	}
+}
+
+func TestNextIP(t *testing.T) {
+	tests := []struct {
+		ip, next string
+	}{
+		{"10.0.0.1", "10.0.0.2"},
+		{"10.0.0.255", "10.0.1.0"},
+		{"::1", "::2"},
+		{"::ffff", "::1:0"},
+	}
+	for _, test := range tests {
+		ip := net.ParseIP(test.ip)
+		if v4 := ip.To4(); v4 != nil {
+			ip = v4
+		}
+		if next := nextIP(ip).String(); next != test.next {
+			t.Errorf("nextIP(%s) = %s, expected %s", test.ip, next, test.next)
+		}
+	}
+}
+
+func TestIterator(t *testing.T) {
+	port := startTestServer(t, "Iterated")
+
+	s := Scanner{Timeout: time.Second, Resend: 100 * time.Millisecond, LocalAddr: "127.0.0.1:0"}
+	it, err := s.Iterate(context.Background(), "127.0.0.1/32", []int{port})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+
+	if !it.Next() {
+		t.Fatal("expected a result")
+	}
+	if name := it.Result().Response.ServerName; name != "Iterated" {
+		t.Errorf("unexpected server name: %s", name)
+	}
+	if it.Next() {
+		t.Errorf("unexpected result: %v", it.Result())
+	}
+}
+
+func TestIteratorCloseEarly(t *testing.T) {
+	s := Scanner{Timeout: time.Minute, LocalAddr: "127.0.0.1:0"}
+	it, err := s.Iterate(context.Background(), "127.0.0.0/16", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		it.Close()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("closing the iterator didn't stop the scan")
+	}
+}
+
+func TestScanStateless(t *testing.T) {
+	port := startTestServer(t, "Stateless")
+
+	s := Scanner{Timeout: 500 * time.Millisecond, LocalAddr: "127.0.0.1:0"}
+	results, err := s.ScanStateless(context.Background(), "127.0.0.1/32", []int{port})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found int
+	for res := range results {
+		found++
+		if res.Address != net.JoinHostPort("127.0.0.1", strconv.Itoa(port)) {
+			t.Errorf("unexpected address: %s", res.Address)
+		}
+		if res.Response.ServerName != "Stateless" {
+			t.Errorf("unexpected server name: %s", res.Response.ServerName)
+		}
+	}
+	if found != 1 {
+		t.Errorf("expected 1 server, found %d", found)
+	}
+}
+
+func TestScanStatelessIgnoresForgedCookie(t *testing.T) {
+	// The server replies with a zero timestamp instead of echoing the cookie
+	conn := pingtest.Listen(t)
+	go bedrockping.NewResponder(pingtest.Response("Forged")).Serve(forgingConn{conn})
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+
+	s := Scanner{Timeout: 200 * time.Millisecond, LocalAddr: "127.0.0.1:0"}
+	results, err := s.ScanStateless(context.Background(), "127.0.0.1/32", []int{port})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for res := range results {
+		t.Errorf("unexpected result: %v", res)
+	}
+}
+
+func TestCookieJar(t *testing.T) {
+	c, err := newCookieJar()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 19132}
+	ts := c.timestamp(addr, time.Now())
+
+	if _, ok := c.verify(addr, ts); !ok {
+		t.Error("failed to verify cookie")
+	}
+	if _, ok := c.verify(&net.UDPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 19132}, ts); ok {
+		t.Error("verified cookie for a different address")
+	}
+	if _, ok := c.verify(addr, ts^1); ok {
+		t.Error("verified tampered cookie")
+	}
+}
+
+func TestScanProgress(t *testing.T) {
+	port := startTestServer(t, "Progress")
+
+	var lastDone, lastTotal int
+	s := Scanner{
+		Timeout:   100 * time.Millisecond,
+		Resend:    50 * time.Millisecond,
+		LocalAddr: "127.0.0.1:0",
+		Progress: func(done, total, inflight int) {
+			lastDone, lastTotal = done, total
+		},
+	}
+	results, err := s.Scan(context.Background(), "127.0.0.0/30", []int{port})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range results {
+	}
+
+	if lastDone != 4 || lastTotal != 4 {
+		t.Errorf("expected 4/4 done, got %d/%d", lastDone, lastTotal)
+	}
+}
+
+func TestRangeSize(t *testing.T) {
+	tests := []struct {
+		cidr  string
+		ports int
+		size  int
+	}{
+		{"10.0.0.0/24", 1, 256},
+		{"10.0.0.0/24", 2, 512},
+		{"10.0.0.1/32", 1, 1},
+		{"::/0", 1, -1},
+	}
+	for _, test := range tests {
+		_, ipNet, _ := net.ParseCIDR(test.cidr)
+		if size := rangeSize(ipNet, test.ports); size != test.size {
+			t.Errorf("rangeSize(%s, %d) = %d, expected %d", test.cidr, test.ports, size, test.size)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package scan
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"net"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/internal/batchio"
+	"github.com/ZeroErrors/go-bedrockping/internal/tracker"
+)
+
+// ScanStateless enumerates every address in cidr on each of ports like Scan, but without keeping
+// any per-target state. Pings are fired continuously, each carrying a cookie in its timestamp field
+// derived from the target address, a per-scan secret and the time it was sent. Servers echo the
+// timestamp in their pong, so replies are matched by recomputing the cookie for the source address.
+// This makes it suitable for surveying very large address spaces.
+//
+// Each address is pinged once, Concurrency and Resend are ignored. Progress counts an address as done
+// once its ping has been sent, with nothing ever in flight. After the last ping is sent the
+// scan keeps listening for Timeout before the returned channel is closed. On Linux pings are sent and pongs
+// read in batches with a single sendmmsg or recvmmsg system call each, rate limited pings in batches no
+// bigger than Burst.
+// If the results channel isn't drained quickly enough pongs may be dropped by the operating system.
+func (s *Scanner) ScanStateless(ctx context.Context, cidr string, ports []int) (<-chan bedrockping.Result, error) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+	if len(ports) == 0 {
+		ports = []int{bedrockping.DefaultPort}
+	}
+
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	laddr := s.localAddrs()[0]
+	if laddr == "" {
+		laddr = ":0"
+	}
+	conn, err := net.ListenPacket("udp", laddr)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := newCookieJar()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	var limiter *bedrockping.RateLimiter
+	if s.Rate > 0 {
+		limiter = bedrockping.NewRateLimiter(s.Rate, s.Burst)
+	}
+
+	results := make(chan bedrockping.Result, s.Buffer)
+	sent := make(chan struct{})
+	count := tracker.New(s.Progress, rangeSize(ipNet, len(ports)))
+
+	// Send pings
+	go func() {
+		defer close(sent)
+
+		// Rate limited pings are sent in batches no bigger than the burst, so none waits long for the rest
+		size := batchio.Size
+		if limiter != nil && s.Burst < size {
+			size = max(s.Burst, 1)
+		}
+		batch := batchio.NewConn(conn)
+		ms := batchio.NewMessages(size, len(bedrockping.AppendUnconnectedPing(nil, 0)))
+		addrs := make([]*net.UDPAddr, 0, size)
+		flush := func() {
+			// The cookies are made as the batch is sent, so latencies don't include the time spent batching
+			now := time.Now()
+			for i, raddr := range addrs {
+				ms[i].Buffers[0] = bedrockping.AppendUnconnectedPing(ms[i].Buffers[0][:0], c.timestamp(raddr, now))
+				ms[i].Addr = raddr
+			}
+			// Errors for individual targets such as unreachable networks are ignored
+			batch.WriteBatch(ms[:len(addrs)], nil)
+
+			count.Add(len(addrs))
+			addrs = addrs[:0]
+		}
+
+		enumerate(ipNet, ports, func(ip net.IP, port int) bool {
+			if err := limiter.Wait(ctx); err != nil {
+				return false
+			}
+			if addrs = append(addrs, &net.UDPAddr{IP: ip, Port: port}); len(addrs) == size {
+				flush()
+			}
+			return true
+		})
+		if len(addrs) > 0 && ctx.Err() == nil {
+			flush()
+		}
+	}()
+
+	// Stop listening once every ping has been sent and the stragglers had time to reply
+	go func() {
+		<-sent
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+		conn.Close()
+	}()
+
+	// Receive pongs
+	go func() {
+		defer close(results)
+
+		batch := batchio.NewConn(conn)
+		ms := batchio.NewMessages(batchio.Size, 1500)
+		for {
+			n, err := batch.ReadBatch(ms)
+			if err != nil {
+				return
+			}
+			for _, msg := range ms[:n] {
+				raddr, ok := msg.Addr.(*net.UDPAddr)
+				if !ok {
+					continue
+				}
+
+				var resp bedrockping.Response
+				if err := bedrockping.DecodeUnconnectedPong(msg.Buffers[0][:msg.N], &resp); err != nil {
+					continue
+				}
+				sentAt, ok := c.verify(raddr, resp.Timestamp)
+				if !ok {
+					continue
+				}
+
+				res := bedrockping.Result{Address: raddr.String(), Response: resp, Latency: time.Since(sentAt), CheckedAt: sentAt}
+				select {
+				case results <- res:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return results, nil
+}
+
+// cookieJar creates and verifies the cookies sent in the timestamp field of stateless pings.
+// The upper 32 bits of a timestamp are milliseconds since the scan started and the lower 32 bits
+// are a MAC of the target address and send time, keyed by a secret random per scan.
+type cookieJar struct {
+	secret []byte
+	start  time.Time
+}
+
+func newCookieJar() (*cookieJar, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return &cookieJar{secret: secret, start: time.Now()}, nil
+}
+
+func (c *cookieJar) mac(addr *net.UDPAddr, millis uint32) uint32 {
+	h := hmac.New(sha256.New, c.secret)
+
+	var buf [6]byte
+	binary.BigEndian.PutUint32(buf[:4], millis)
+	binary.BigEndian.PutUint16(buf[4:], uint16(addr.Port))
+	h.Write(buf[:])
+	if ip4 := addr.IP.To4(); ip4 != nil {
+		h.Write(ip4)
+	} else {
+		h.Write(addr.IP.To16())
+	}
+
+	return binary.BigEndian.Uint32(h.Sum(nil))
+}
+
+func (c *cookieJar) timestamp(addr *net.UDPAddr, now time.Time) uint64 {
+	millis := uint32(now.Sub(c.start) / time.Millisecond)
+	return uint64(millis)<<32 | uint64(c.mac(addr, millis))
+}
+
+// verify checks a timestamp echoed back by addr, returning the time the ping was sent.
+func (c *cookieJar) verify(addr *net.UDPAddr, timestamp uint64) (time.Time, bool) {
+	millis := uint32(timestamp >> 32)
+	if !hmac.Equal(uint32Bytes(c.mac(addr, millis)), uint32Bytes(uint32(timestamp))) {
+		return time.Time{}, false
+	}
+	return c.start.Add(time.Duration(millis) * time.Millisecond), true
+}
+
+func uint32Bytes(v uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	return buf[:]
+}
@@ -0,0 +1,50 @@
+package bedrockping
+
+import (
+	"context"
+	"time"
+)
+
+// flight is a query shared by concurrent Ping calls for an address.
+type flight struct {
+	done chan struct{}
+	res  Result
+}
+
+// WithSingleflight makes concurrent Ping calls for the same address share a single query rather than each
+// sending pings, such as when many HTTP requests ask for the same server at once. Every call receives the
+// result of the query, a call whose context is done first returns early with its error. The query isn't
+// cancelled with the call that started it, as others may be waiting for it. It is disabled by default.
+func WithSingleflight(enabled bool) Option {
+	return func(c *Client) {
+		c.singleflight = enabled
+	}
+}
+
+// sharedPing queries address, or waits for the query already in flight for it.
+func (c *Client) sharedPing(ctx context.Context, address string) Result {
+	c.flightsMu.Lock()
+	f, ok := c.flights[address]
+	if ok {
+		c.sharedPings.Add(1)
+	} else {
+		f = &flight{done: make(chan struct{})}
+		c.flights[address] = f
+		go func() {
+			f.res = c.tracedPing(context.WithoutCancel(ctx), address)
+
+			c.flightsMu.Lock()
+			delete(c.flights, address)
+			c.flightsMu.Unlock()
+			close(f.done)
+		}()
+	}
+	c.flightsMu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.res
+	case <-ctx.Done():
+		return Result{Address: address, Attempts: 1, CheckedAt: time.Now(), Err: ctx.Err()}
+	}
+}
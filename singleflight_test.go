@@ -0,0 +1,87 @@
+package bedrockping
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slowConn delays every packet written to it.
+type slowConn struct {
+	net.PacketConn
+	delay time.Duration
+}
+
+func (c slowConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	time.Sleep(c.delay)
+	return c.PacketConn.WriteTo(p, addr)
+}
+
+// startSlowResponder starts a Responder on localhost answering after delay, returning its address.
+func startSlowResponder(t *testing.T, delay time.Duration) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go NewResponder(testResponse("Slow")).Serve(slowConn{conn, delay})
+	return conn.LocalAddr().String()
+}
+
+func TestClientSingleflight(t *testing.T) {
+	address := startSlowResponder(t, 100*time.Millisecond)
+	client := NewClient(WithSingleflight(true), WithResend(time.Second), WithAdaptiveResend(false))
+
+	// One caller gives up early, without cancelling the query the others are waiting for
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if res := client.Ping(context.Background(), address); res.Err != nil || res.Response.ServerName != "Slow" {
+				t.Errorf("unexpected result: %+v", res)
+			}
+		}()
+	}
+	if res := client.Ping(ctx, address); !errors.Is(res.Err, context.DeadlineExceeded) || res.Attempts != 1 || res.CheckedAt.IsZero() {
+		t.Errorf("expected the cancelled call to return early, got %+v", res)
+	}
+	wg.Wait()
+
+	stats := client.Stats()
+	if stats.PingsSent != 1 || stats.SharedPings != 10 {
+		t.Errorf("expected 1 ping shared by 10 calls, got %+v", stats)
+	}
+
+	// Once the query has finished the next call sends a new one
+	if res := client.Ping(context.Background(), address); res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if n := client.Stats().PingsSent; n != 2 {
+		t.Errorf("expected a second ping, got %d", n)
+	}
+}
+
+func TestClientWithoutSingleflight(t *testing.T) {
+	address := startSlowResponder(t, 50*time.Millisecond)
+	client := NewClient(WithResend(time.Second), WithAdaptiveResend(false))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.Ping(context.Background(), address)
+		}()
+	}
+	wg.Wait()
+	if stats := client.Stats(); stats.PingsSent != 3 || stats.SharedPings != 0 {
+		t.Errorf("expected every call to ping, got %+v", stats)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package bedrockping
+
+import (
+	"errors"
+	"syscall"
+)
+
+// setDSCP isn't supported on this platform.
+func setDSCP(conn syscall.RawConn, network string, dscp uint8) error {
+	return errors.New("bedrockping: DSCP marking is only supported on Linux, macOS and the BSDs")
+}
+
+// setReusePort isn't supported on this platform.
+func setReusePort(conn syscall.RawConn) error {
+	return errors.New("bedrockping: SO_REUSEPORT is only supported on Linux, macOS and the BSDs")
+}
+
+// setHopLimit isn't supported on this platform.
+func setHopLimit(conn syscall.RawConn, network string, ttl int) error {
+	return errors.New("bedrockping: setting the TTL is only supported on Linux, macOS and the BSDs")
+}
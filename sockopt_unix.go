@@ -0,0 +1,61 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package bedrockping
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// setDSCP marks the packets sent by the socket conn of network with dscp, in the IP TOS byte for IPv4
+// and the traffic class for IPv6.
+func setDSCP(conn syscall.RawConn, network string, dscp uint8) error {
+	if dscp > 63 {
+		return fmt.Errorf("bedrockping: invalid DSCP %d, it must be from 0 to 63", dscp)
+	}
+	level, opt := syscall.IPPROTO_IP, syscall.IP_TOS
+	if strings.HasSuffix(network, "6") {
+		level, opt = syscall.IPPROTO_IPV6, syscall.IPV6_TCLASS
+	}
+
+	var err error
+	controlErr := conn.Control(func(fd uintptr) {
+		err = syscall.SetsockoptInt(int(fd), level, opt, int(dscp)<<2)
+	})
+	if controlErr != nil {
+		return controlErr
+	}
+	return err
+}
+
+// setReusePort enables SO_REUSEPORT on the socket conn.
+func setReusePort(conn syscall.RawConn) error {
+	var err error
+	controlErr := conn.Control(func(fd uintptr) {
+		err = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+	})
+	if controlErr != nil {
+		return controlErr
+	}
+	return err
+}
+
+// setHopLimit sets the IP TTL, or the IPv6 hop limit, of the packets sent by the socket conn of network.
+func setHopLimit(conn syscall.RawConn, network string, ttl int) error {
+	level, opt := syscall.IPPROTO_IP, syscall.IP_TTL
+	if strings.HasSuffix(network, "6") {
+		level, opt = syscall.IPPROTO_IPV6, syscall.IPV6_UNICAST_HOPS
+	}
+
+	var err error
+	controlErr := conn.Control(func(fd uintptr) {
+		err = syscall.SetsockoptInt(int(fd), level, opt, ttl)
+	})
+	if controlErr != nil {
+		return controlErr
+	}
+	return err
+}
@@ -0,0 +1,106 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package bedrockping
+
+import (
+	"context"
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSetDSCP(t *testing.T) {
+	for _, test := range []struct {
+		network, address string
+		level, opt       int
+	}{
+		{"udp4", "127.0.0.1:0", syscall.IPPROTO_IP, syscall.IP_TOS},
+		{"udp6", "[::1]:0", syscall.IPPROTO_IPV6, syscall.IPV6_TCLASS},
+	} {
+		conn, err := net.ListenPacket(test.network, test.address)
+		if err != nil {
+			t.Logf("%s isn't available: %v", test.network, err)
+			continue
+		}
+		defer conn.Close()
+		raw, err := conn.(*net.UDPConn).SyscallConn()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := setDSCP(raw, test.network, 46); err != nil {
+			t.Fatal(err)
+		}
+		var tos int
+		raw.Control(func(fd uintptr) {
+			tos, err = syscall.GetsockoptInt(int(fd), test.level, test.opt)
+		})
+		if err != nil || tos != 46<<2 {
+			t.Errorf("%s: expected TOS %d, got %d %v", test.network, 46<<2, tos, err)
+		}
+
+		if err := setDSCP(raw, test.network, 64); err == nil {
+			t.Errorf("%s: expected an error for an invalid DSCP", test.network)
+		}
+	}
+}
+
+func TestReusePort(t *testing.T) {
+	address := startTestServer(t, testResponse("Server"))
+
+	// Hold the port with SO_REUSEPORT like another probe process would
+	lc := net.ListenConfig{Control: func(network, address string, conn syscall.RawConn) error {
+		return setReusePort(conn)
+	}}
+	held, err := lc.ListenPacket(context.Background(), "udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer held.Close()
+	port := held.LocalAddr().(*net.UDPAddr).Port
+
+	c := NewClient(WithTimeout(time.Second), WithLocalAddr(net.IPv4(127, 0, 0, 1)), WithLocalPort(port), WithReusePort(true))
+	res := c.Ping(context.Background(), address)
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	c = NewClient(WithTimeout(time.Second), WithLocalAddr(net.IPv4(127, 0, 0, 1)), WithLocalPort(port))
+	if res := c.Ping(context.Background(), address); !errors.Is(res.Err, syscall.EADDRINUSE) {
+		t.Errorf("expected the port to be in use without SO_REUSEPORT, got %v", res.Err)
+	}
+}
+
+func TestSetHopLimit(t *testing.T) {
+	for _, test := range []struct {
+		network, address string
+		level, opt       int
+	}{
+		{"udp4", "127.0.0.1:0", syscall.IPPROTO_IP, syscall.IP_TTL},
+		{"udp6", "[::1]:0", syscall.IPPROTO_IPV6, syscall.IPV6_UNICAST_HOPS},
+	} {
+		conn, err := net.ListenPacket(test.network, test.address)
+		if err != nil {
+			t.Logf("%s isn't available: %v", test.network, err)
+			continue
+		}
+		defer conn.Close()
+		raw, err := conn.(*net.UDPConn).SyscallConn()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := setHopLimit(raw, test.network, 7); err != nil {
+			t.Fatal(err)
+		}
+		var ttl int
+		raw.Control(func(fd uintptr) {
+			ttl, err = syscall.GetsockoptInt(int(fd), test.level, test.opt)
+		})
+		if err != nil || ttl != 7 {
+			t.Errorf("%s: expected TTL 7, got %d %v", test.network, ttl, err)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package bedrockping
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SOCKS5 constants from RFC 1928 and RFC 1929.
+const (
+	socksVersion        = 0x05
+	socksAuthNone       = 0x00
+	socksAuthPassword   = 0x02
+	socksAuthNoAccept   = 0xff
+	socksCmdUDPAssoc    = 0x03
+	socksAddrIPv4       = 0x01
+	socksAddrDomain     = 0x03
+	socksAddrIPv6       = 0x04
+	socksPasswordStatus = 0x01
+)
+
+// dialSOCKS5 asks the SOCKS5 proxy at proxyURL to relay datagrams with UDP ASSOCIATE and returns a
+// connection sending them to address, which must be an IP address and port, through the relay.
+// localIP, if set, is the address the datagrams are sent from, which some proxies require in advance.
+func dialSOCKS5(ctx context.Context, d *net.Dialer, proxyURL *url.URL, address string, localIP net.IP) (net.Conn, error) {
+	if proxyURL.Scheme != "socks5" && proxyURL.Scheme != "socks5h" {
+		return nil, fmt.Errorf("bedrockping: unsupported proxy scheme %q", proxyURL.Scheme)
+	}
+	header, err := socksAddr(address)
+	if err != nil {
+		return nil, err
+	}
+
+	ctrl, err := d.DialContext(ctx, "tcp", proxyURL.Host)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		ctrl.SetDeadline(deadline)
+	}
+
+	relay, err := socksAssociate(ctrl, proxyURL.User, localIP)
+	if err != nil {
+		ctrl.Close()
+		return nil, fmt.Errorf("bedrockping: proxy: %w", err)
+	}
+	ctrl.SetDeadline(time.Time{})
+
+	// Proxies commonly reply with an unspecified address, meaning the relay is on the proxy's host
+	if relay.IP.IsUnspecified() {
+		relay.IP = ctrl.RemoteAddr().(*net.TCPAddr).IP
+	}
+	udpDialer := *d
+	udpDialer.LocalAddr = nil
+	if localIP != nil {
+		udpDialer.LocalAddr = &net.UDPAddr{IP: localIP}
+	}
+	conn, err := udpDialer.DialContext(ctx, "udp", relay.String())
+	if err != nil {
+		ctrl.Close()
+		return nil, err
+	}
+
+	// The relay prefixes every datagram with a reserved field, fragment number and the address
+	return &socksConn{Conn: conn, ctrl: ctrl, header: append([]byte{0, 0, 0}, header...)}, nil
+}
+
+// socksAssociate negotiates authentication and a UDP association on the control connection ctrl,
+// returning the address of the relay.
+func socksAssociate(ctrl net.Conn, user *url.Userinfo, localIP net.IP) (*net.UDPAddr, error) {
+	methods := []byte{socksAuthNone}
+	if user != nil {
+		methods = []byte{socksAuthNone, socksAuthPassword}
+	}
+	if _, err := ctrl.Write(append([]byte{socksVersion, byte(len(methods))}, methods...)); err != nil {
+		return nil, err
+	}
+
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(ctrl, reply); err != nil {
+		return nil, err
+	}
+	if reply[0] != socksVersion {
+		return nil, fmt.Errorf("unexpected version %d", reply[0])
+	}
+	switch reply[1] {
+	case socksAuthNone:
+	case socksAuthPassword:
+		if user == nil {
+			return nil, errors.New("password authentication required")
+		}
+		password, _ := user.Password()
+		if len(user.Username()) > 255 || len(password) > 255 {
+			return nil, errors.New("username or password too long")
+		}
+		req := []byte{socksPasswordStatus, byte(len(user.Username()))}
+		req = append(req, user.Username()...)
+		req = append(req, byte(len(password)))
+		req = append(req, password...)
+		if _, err := ctrl.Write(req); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(ctrl, reply); err != nil {
+			return nil, err
+		}
+		if reply[1] != 0 {
+			return nil, errors.New("authentication failed")
+		}
+	case socksAuthNoAccept:
+		return nil, errors.New("no acceptable authentication method")
+	default:
+		return nil, fmt.Errorf("unsupported authentication method %d", reply[1])
+	}
+
+	// The address datagrams will be sent from, unknown until the UDP socket is dialed
+	from := net.JoinHostPort("0.0.0.0", "0")
+	if localIP != nil {
+		from = net.JoinHostPort(localIP.String(), "0")
+	}
+	addr, err := socksAddr(from)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ctrl.Write(append([]byte{socksVersion, socksCmdUDPAssoc, 0}, addr...)); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 3)
+	if _, err := io.ReadFull(ctrl, head); err != nil {
+		return nil, err
+	}
+	if head[1] != 0 {
+		return nil, fmt.Errorf("UDP associate failed with code %d", head[1])
+	}
+	host, port, err := readSOCKSAddr(ctrl)
+	if err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, fmt.Errorf("relay address %q isn't an IP address", host)
+	}
+	return &net.UDPAddr{IP: ip, Port: port}, nil
+}
+
+// socksAddr encodes the IP address and port address in the SOCKS5 address format.
+func socksAddr(address string) ([]byte, error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, err
+	}
+
+	var b []byte
+	ip := net.ParseIP(host)
+	switch {
+	case ip == nil:
+		if len(host) > 255 {
+			return nil, fmt.Errorf("bedrockping: host too long: %s", host)
+		}
+		b = append([]byte{socksAddrDomain, byte(len(host))}, host...)
+	case ip.To4() != nil:
+		b = append([]byte{socksAddrIPv4}, ip.To4()...)
+	default:
+		b = append([]byte{socksAddrIPv6}, ip.To16()...)
+	}
+	return binary.BigEndian.AppendUint16(b, uint16(port)), nil
+}
+
+// readSOCKSAddr reads an address in the SOCKS5 address format.
+func readSOCKSAddr(r io.Reader) (string, int, error) {
+	atyp := make([]byte, 1)
+	if _, err := io.ReadFull(r, atyp); err != nil {
+		return "", 0, err
+	}
+
+	var host string
+	switch atyp[0] {
+	case socksAddrIPv4, socksAddrIPv6:
+		ip := make(net.IP, net.IPv4len)
+		if atyp[0] == socksAddrIPv6 {
+			ip = make(net.IP, net.IPv6len)
+		}
+		if _, err := io.ReadFull(r, ip); err != nil {
+			return "", 0, err
+		}
+		host = ip.String()
+	case socksAddrDomain:
+		n := make([]byte, 1)
+		if _, err := io.ReadFull(r, n); err != nil {
+			return "", 0, err
+		}
+		domain := make([]byte, n[0])
+		if _, err := io.ReadFull(r, domain); err != nil {
+			return "", 0, err
+		}
+		host = string(domain)
+	default:
+		return "", 0, fmt.Errorf("unknown address type %d", atyp[0])
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(r, port); err != nil {
+		return "", 0, err
+	}
+	return host, int(binary.BigEndian.Uint16(port)), nil
+}
+
+// socksConn is a connection to a SOCKS5 UDP relay that encapsulates datagrams to and from a single address.
+type socksConn struct {
+	net.Conn
+	// ctrl is the TCP connection the association lasts as long as
+	ctrl   net.Conn
+	header []byte
+}
+
+func (c *socksConn) Write(b []byte) (int, error) {
+	if _, err := c.Conn.Write(append(c.header[:len(c.header):len(c.header)], b...)); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (c *socksConn) Read(b []byte) (int, error) {
+	buf := make([]byte, len(b)+len(c.header)+256)
+	for {
+		n, err := c.Conn.Read(buf)
+		if err != nil {
+			return 0, err
+		}
+		// Skip the reserved field and fragment number, fragmented datagrams aren't supported
+		if n < 4 || buf[2] != 0 {
+			continue
+		}
+		r := bytes.NewReader(buf[3:n])
+		if _, _, err := readSOCKSAddr(r); err != nil {
+			continue
+		}
+		return copy(b, buf[n-r.Len():n]), nil
+	}
+}
+
+func (c *socksConn) Close() error {
+	err := c.Conn.Close()
+	c.ctrl.Close()
+	return err
+}
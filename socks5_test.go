@@ -0,0 +1,168 @@
+package bedrockping
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// startSOCKS5Server starts a SOCKS5 proxy on localhost supporting only UDP ASSOCIATE,
+// requiring the username and password if user is set.
+func startSOCKS5Server(t *testing.T, user *url.Userinfo) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			ctrl, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go serveSOCKS5(t, ctrl, user)
+		}
+	}()
+	return l.Addr().String()
+}
+
+func serveSOCKS5(t *testing.T, ctrl net.Conn, user *url.Userinfo) {
+	defer ctrl.Close()
+
+	head := make([]byte, 2)
+	if _, err := io.ReadFull(ctrl, head); err != nil {
+		return
+	}
+	methods := make([]byte, head[1])
+	if _, err := io.ReadFull(ctrl, methods); err != nil {
+		return
+	}
+	if user == nil {
+		ctrl.Write([]byte{socksVersion, socksAuthNone})
+	} else {
+		if !bytes.Contains(methods, []byte{socksAuthPassword}) {
+			ctrl.Write([]byte{socksVersion, socksAuthNoAccept})
+			return
+		}
+		ctrl.Write([]byte{socksVersion, socksAuthPassword})
+
+		buf := make([]byte, 512)
+		n, err := ctrl.Read(buf)
+		if err != nil {
+			return
+		}
+		password, _ := user.Password()
+		expect := append([]byte{socksPasswordStatus, byte(len(user.Username()))}, user.Username()...)
+		expect = append(append(expect, byte(len(password))), password...)
+		if !bytes.Equal(buf[:n], expect) {
+			ctrl.Write([]byte{socksPasswordStatus, 1})
+			return
+		}
+		ctrl.Write([]byte{socksPasswordStatus, 0})
+	}
+
+	req := make([]byte, 3)
+	if _, err := io.ReadFull(ctrl, req); err != nil || req[1] != socksCmdUDPAssoc {
+		return
+	}
+	if _, _, err := readSOCKSAddr(ctrl); err != nil {
+		return
+	}
+
+	relay, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer relay.Close()
+	// Reply with an unspecified address, meaning the proxy's own
+	bound, _ := socksAddr(net.JoinHostPort("0.0.0.0", strconv.Itoa(relay.LocalAddr().(*net.UDPAddr).Port)))
+	ctrl.Write(append([]byte{socksVersion, 0, 0}, bound...))
+
+	go func() {
+		var client net.Addr
+		buf := make([]byte, maxPacketSize)
+		for {
+			n, from, err := relay.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if client == nil || from.String() == client.String() {
+				// From the client, forward to the destination in the header
+				client = from
+				r := bytes.NewReader(buf[3:n])
+				host, port, err := readSOCKSAddr(r)
+				if err != nil {
+					continue
+				}
+				dst, _ := net.ResolveUDPAddr("udp", net.JoinHostPort(host, strconv.Itoa(port)))
+				relay.WriteTo(buf[n-r.Len():n], dst)
+				continue
+			}
+			// From a server, encapsulate and return to the client
+			header, _ := socksAddr(from.String())
+			relay.WriteTo(append(append([]byte{0, 0, 0}, header...), buf[:n]...), client)
+		}
+	}()
+
+	// The association lasts until the control connection closes
+	io.Copy(io.Discard, ctrl)
+}
+
+func TestClientProxy(t *testing.T) {
+	expect := testResponse("Proxied")
+	address := startTestServer(t, expect)
+
+	proxy := &url.URL{Scheme: "socks5", Host: startSOCKS5Server(t, nil)}
+	c := NewClient(WithTimeout(2*time.Second), WithProxy(proxy))
+	resp, err := c.Query(context.Background(), address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ServerName != "Proxied" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestClientProxyAuth(t *testing.T) {
+	address := startTestServer(t, testResponse("Proxied"))
+	user := url.UserPassword("user", "secret")
+	host := startSOCKS5Server(t, user)
+
+	c := NewClient(WithTimeout(2*time.Second), WithProxy(&url.URL{Scheme: "socks5", Host: host, User: user}))
+	if _, err := c.Query(context.Background(), address); err != nil {
+		t.Fatal(err)
+	}
+
+	c = NewClient(WithTimeout(2*time.Second), WithProxy(&url.URL{Scheme: "socks5", Host: host, User: url.UserPassword("user", "wrong")}))
+	if _, err := c.Query(context.Background(), address); err == nil {
+		t.Error("expected error with the wrong password")
+	}
+
+	c = NewClient(WithTimeout(2*time.Second), WithProxy(&url.URL{Scheme: "http", Host: host}))
+	if _, err := c.Query(context.Background(), address); err == nil {
+		t.Error("expected error for an unsupported scheme")
+	}
+}
+
+func TestSOCKSAddr(t *testing.T) {
+	for _, address := range []string{"127.0.0.1:19132", "[::1]:19133", "play.example.com:80"} {
+		b, err := socksAddr(address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		host, port, err := readSOCKSAddr(bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := net.JoinHostPort(host, strconv.Itoa(port)); got != address {
+			t.Errorf("round trip of %s gave %s", address, got)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package bedrockping
+
+import (
+	"net"
+	"strconv"
+)
+
+// SourcePortPolicy controls which local port queries send pings from, see WithSourcePortPolicy.
+type SourcePortPolicy int
+
+const (
+	// SourcePortRandom sends each query from a fresh port picked by the system, which randomizes it on most.
+	SourcePortRandom SourcePortPolicy = iota
+	// SourcePortPinned sends every query from the same port, the one the system picked for the first query.
+	SourcePortPinned
+)
+
+// String returns the name of the policy, "random" or "pinned".
+func (p SourcePortPolicy) String() string {
+	switch p {
+	case SourcePortRandom:
+		return "random"
+	case SourcePortPinned:
+		return "pinned"
+	}
+	return "SourcePortPolicy(" + strconv.Itoa(int(p)) + ")"
+}
+
+// WithSourcePortPolicy sets whether each query uses a fresh random source port or they all use the same one,
+// as some NATs and anti-DDoS appliances treat a stream of pings from one port differently to pings from many.
+// The default is SourcePortRandom. Concurrent queries can only share a pinned port with WithReusePort enabled
+// too, otherwise all but one fail. A port set by WithLocalPort is always used, whatever the policy.
+func WithSourcePortPolicy(policy SourcePortPolicy) Option {
+	return func(c *Client) {
+		c.sourcePortPolicy = policy
+	}
+}
+
+// sourcePort returns the local port for the next query's socket, 0 for the system to pick one.
+func (c *Client) sourcePort() int {
+	if c.localPort != 0 || c.sourcePortPolicy != SourcePortPinned {
+		return c.localPort
+	}
+	return int(c.pinnedPort.Load())
+}
+
+// pinSourcePort records the local port of conn as the port to pin queries to, if the policy pins one
+// and it isn't known yet. Queries through a proxy aren't pinned.
+func (c *Client) pinSourcePort(conn net.Conn) {
+	if c.localPort != 0 || c.sourcePortPolicy != SourcePortPinned {
+		return
+	}
+	if addr, ok := conn.LocalAddr().(*net.UDPAddr); ok {
+		c.pinnedPort.CompareAndSwap(0, int32(addr.Port))
+	}
+}
@@ -0,0 +1,71 @@
+package bedrockping
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+// startPortRecorder starts a server answering every ping, sending the source port of each to the returned channel.
+func startPortRecorder(t *testing.T) (string, <-chan int) {
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	pong := new(bytes.Buffer)
+	if err := WriteUnconnectedPong(pong, testResponse("Recorder")); err != nil {
+		t.Fatal(err)
+	}
+	ports := make(chan int, 16)
+	go func() {
+		buf := make([]byte, maxPacketSize)
+		for {
+			_, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			ports <- addr.(*net.UDPAddr).Port
+			conn.WriteTo(pong.Bytes(), addr)
+		}
+	}()
+	return conn.LocalAddr().String(), ports
+}
+
+func TestSourcePortPolicy(t *testing.T) {
+	for _, test := range []struct {
+		policy SourcePortPolicy
+		same   bool
+	}{
+		{SourcePortRandom, false},
+		{SourcePortPinned, true},
+	} {
+		address, ports := startPortRecorder(t)
+		c := NewClient(WithTimeout(time.Second), WithSourcePortPolicy(test.policy))
+		seen := make(map[int]bool)
+		for range 3 {
+			if res := c.Ping(context.Background(), address); res.Err != nil {
+				t.Fatalf("%s: %v", test.policy, res.Err)
+			}
+			seen[<-ports] = true
+		}
+		if (len(seen) == 1) != test.same {
+			t.Errorf("%s: pings sent from ports %v", test.policy, seen)
+		}
+	}
+}
+
+func TestSourcePortPolicyString(t *testing.T) {
+	for policy, expected := range map[SourcePortPolicy]string{
+		SourcePortRandom:    "random",
+		SourcePortPinned:    "pinned",
+		SourcePortPolicy(5): "SourcePortPolicy(5)",
+	} {
+		if s := policy.String(); s != expected {
+			t.Errorf("got %q, expected %q", s, expected)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package bedrockping
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultSRVService is the service name of the SRV records hosting providers publish Bedrock servers under,
+// _minecraft._udp.{host}.
+const DefaultSRVService = "minecraft"
+
+// WithSRV makes the client look up the _{service}._udp SRV records of the hosts of addresses on DefaultPort,
+// such as _minecraft._udp.play.example.com for DefaultSRVService, and ping the host and port of the first record
+// whose target resolves, in the order of their priority and weight. Hosts without SRV records are resolved
+// as usual with the address's port. An address with another port is always pinged on that port.
+// An empty service disables SRV lookups, which is the default.
+func WithSRV(service string) Option {
+	return func(c *Client) {
+		c.srvService = service
+	}
+}
+
+// resolveSRV returns the address of the first SRV record of the client's service for host whose target
+// resolves, or false if host has none.
+func (c *Client) resolveSRV(ctx context.Context, host string) (string, bool) {
+	ctx, span := c.tracer.Start(ctx, "bedrockping.resolveSRV",
+		trace.WithAttributes(attribute.String("dns.question.name", "_"+c.srvService+"._udp."+host)))
+	_, srvs, err := c.resolver.LookupSRV(ctx, c.srvService, "udp", host)
+	endSpan(span, err)
+	if err != nil {
+		c.debug(ctx, "no SRV records", "host", host, "error", err)
+		return "", false
+	}
+
+	for _, srv := range srvs {
+		// A target of "." means the service isn't available at the host
+		target := strings.TrimSuffix(srv.Target, ".")
+		if target == "" {
+			continue
+		}
+		ip, err := c.lookupIP(ctx, target)
+		if err != nil {
+			c.debug(ctx, "resolving SRV target failed", "host", host, "target", target, "error", err)
+			continue
+		}
+		c.debug(ctx, "using SRV record", "host", host, "target", target, "port", srv.Port)
+		return c.joinHostPort(ip, strconv.Itoa(int(srv.Port))), true
+	}
+	return "", false
+}
@@ -0,0 +1,181 @@
+package bedrockping
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// testZone holds the DNS records served by startDNSServer, keyed by name without the trailing dot.
+type testZone struct {
+	a   map[string]netip.Addr
+	srv map[string][]net.SRV
+	// ttl is the TTL of every record, 60 seconds if it's 0
+	ttl uint32
+	// truncate makes answers over UDP truncated, so they have to be asked for again over TCP
+	truncate bool
+}
+
+// startDNSServer starts a DNS server answering queries from zone, returning a resolver that uses it.
+func startDNSServer(t *testing.T, zone testZone) *net.Resolver {
+	address := serveDNS(t, zone)
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, address)
+		},
+	}
+}
+
+// serveDNS serves the records of zone over UDP and TCP on localhost, returning the address.
+func serveDNS(t *testing.T, zone testZone) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	l, err := net.Listen("tcp", conn.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var query dnsmessage.Message
+			if err := query.Unpack(buf[:n]); err != nil || len(query.Questions) != 1 {
+				continue
+			}
+			answer := zone.answer(query)
+			if zone.truncate {
+				answer.Truncated = true
+				answer.Answers = nil
+			}
+			if reply, err := answer.Pack(); err == nil {
+				conn.WriteTo(reply, addr)
+			}
+		}
+	}()
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer c.Close()
+				var length [2]byte
+				if _, err := io.ReadFull(c, length[:]); err != nil {
+					return
+				}
+				buf := make([]byte, binary.BigEndian.Uint16(length[:]))
+				if _, err := io.ReadFull(c, buf); err != nil {
+					return
+				}
+				var query dnsmessage.Message
+				if err := query.Unpack(buf); err != nil || len(query.Questions) != 1 {
+					return
+				}
+				answer := zone.answer(query)
+				if reply, err := answer.Pack(); err == nil {
+					c.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(reply))), reply...))
+				}
+			}()
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+// answer returns the reply to query.
+func (z testZone) answer(query dnsmessage.Message) dnsmessage.Message {
+	q := query.Questions[0]
+	name := strings.TrimSuffix(q.Name.String(), ".")
+	reply := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: query.ID, Response: true, Authoritative: true},
+		Questions: query.Questions,
+	}
+	ttl := z.ttl
+	if ttl == 0 {
+		ttl = 60
+	}
+	header := dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: dnsmessage.ClassINET, TTL: ttl}
+
+	ip, hasA := z.a[name]
+	srvs, hasSRV := z.srv[name]
+	switch {
+	case q.Type == dnsmessage.TypeA && hasA:
+		reply.Answers = append(reply.Answers, dnsmessage.Resource{Header: header, Body: &dnsmessage.AResource{A: ip.As4()}})
+	case q.Type == dnsmessage.TypeSRV && hasSRV:
+		for _, srv := range srvs {
+			reply.Answers = append(reply.Answers, dnsmessage.Resource{Header: header, Body: &dnsmessage.SRVResource{
+				Priority: srv.Priority, Weight: srv.Weight, Port: srv.Port, Target: dnsmessage.MustNewName(srv.Target),
+			}})
+		}
+	case !hasA && !hasSRV:
+		reply.RCode = dnsmessage.RCodeNameError
+	}
+	return reply
+}
+
+func TestResolveSRV(t *testing.T) {
+	resolver := startDNSServer(t, testZone{
+		a: map[string]netip.Addr{
+			"play.example.com": netip.MustParseAddr("127.0.0.2"),
+			"srv.example.com":  netip.MustParseAddr("127.0.0.3"),
+		},
+		srv: map[string][]net.SRV{
+			"_minecraft._udp.play.example.com": {
+				{Target: "missing.example.com.", Port: 19134, Priority: 1},
+				{Target: "srv.example.com.", Port: 19133, Priority: 2},
+			},
+			"_bedrock._udp.play.example.com": {{Target: ".", Port: 0}},
+		},
+	})
+
+	for _, test := range []struct {
+		service, address, expected string
+	}{
+		{DefaultSRVService, "play.example.com:19132", "127.0.0.3:19133"},
+		{DefaultSRVService, "play.example.com:19135", "127.0.0.2:19135"},
+		{DefaultSRVService, "srv.example.com:19132", "127.0.0.3:19132"},
+		{"bedrock", "play.example.com:19132", "127.0.0.2:19132"},
+		{"", "play.example.com:19132", "127.0.0.2:19132"},
+	} {
+		c := NewClient(WithSRV(test.service), WithNetwork("udp4"), WithResolver(resolver))
+		resolved, err := c.Resolve(context.Background(), test.address)
+		if err != nil || resolved != test.expected {
+			t.Errorf("%q %s: expected %s, got %s %v", test.service, test.address, test.expected, resolved, err)
+		}
+	}
+}
+
+func TestPingSRV(t *testing.T) {
+	address := startTestServer(t, testResponse("SRV Server"))
+	_, port, _ := net.SplitHostPort(address)
+	n, _ := strconv.Atoi(port)
+	resolver := startDNSServer(t, testZone{
+		a:   map[string]netip.Addr{"srv.example.com": netip.MustParseAddr("127.0.0.1")},
+		srv: map[string][]net.SRV{"_minecraft._udp.play.example.com": {{Target: "srv.example.com.", Port: uint16(n)}}},
+	})
+
+	c := NewClient(WithSRV(DefaultSRVService), WithNetwork("udp4"), WithTimeout(time.Second), WithResolver(resolver))
+	res := c.Ping(context.Background(), "play.example.com:19132")
+	if res.Err != nil || res.Response.ServerName != "SRV Server" || res.Resolved != address {
+		t.Errorf("unexpected result %+v", res)
+	}
+}
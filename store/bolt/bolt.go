@@ -0,0 +1,128 @@
+// Package bolt provides a monitor.Store backed by a bbolt database, for persistence without cgo
+// or an external database.
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+// samplesBucket holds a nested bucket of samples for each address.
+var samplesBucket = []byte("samples")
+
+// Store is a monitor.Store keeping samples in a bbolt database.
+// Samples are keyed by their time and a sequence number, so samples of an address are stored
+// in time order and range queries are a single cursor scan.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens the bbolt database at path, creating it if it doesn't exist.
+// Only one process may have the database open at a time.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	s, err := New(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// New creates a Store using db, creating the samples bucket if needed.
+func New(db *bolt.DB) (*Store, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(samplesBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// key returns the key a sample at t with the sequence number seq is stored at.
+func key(t time.Time, seq uint64) []byte {
+	k := make([]byte, 16)
+	binary.BigEndian.PutUint64(k, uint64(t.UnixNano()))
+	binary.BigEndian.PutUint64(k[8:], seq)
+	return k
+}
+
+// SaveSample implements monitor.Store.
+func (s *Store) SaveSample(ctx context.Context, sample monitor.Sample) error {
+	value, err := json.Marshal(sample)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.Bucket(samplesBucket).CreateBucketIfNotExists([]byte(sample.Address))
+		if err != nil {
+			return err
+		}
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		return b.Put(key(sample.Time, seq), value)
+	})
+}
+
+// QueryRange implements monitor.Store.
+func (s *Store) QueryRange(ctx context.Context, address string, from, to time.Time) ([]monitor.Sample, error) {
+	var samples []monitor.Sample
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(samplesBucket).Bucket([]byte(address))
+		if b == nil {
+			return nil
+		}
+
+		end := key(to, 0)
+		c := b.Cursor()
+		for k, v := c.Seek(key(from, 0)); k != nil && bytes.Compare(k, end) < 0; k, v = c.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			var sample monitor.Sample
+			if err := json.Unmarshal(v, &sample); err != nil {
+				return err
+			}
+			samples = append(samples, sample)
+		}
+		return nil
+	})
+	return samples, err
+}
+
+// Prune implements monitor.Store.
+func (s *Store) Prune(ctx context.Context, before time.Time) error {
+	end := key(before, 0)
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(samplesBucket).ForEachBucket(func(address []byte) error {
+			c := tx.Bucket(samplesBucket).Bucket(address).Cursor()
+			for k, _ := c.First(); k != nil && bytes.Compare(k, end) < 0; k, _ = c.First() {
+				if err := c.Delete(); err != nil {
+					return err
+				}
+			}
+			return ctx.Err()
+		})
+	})
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
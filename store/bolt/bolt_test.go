@@ -0,0 +1,20 @@
+package bolt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+	"github.com/ZeroErrors/go-bedrockping/monitor/storetest"
+)
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) monitor.Store {
+		s, err := Open(filepath.Join(t.TempDir(), "history.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { s.Close() })
+		return s
+	})
+}
@@ -0,0 +1,103 @@
+// Package sqlite provides a monitor.Store backed by a SQLite database.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	// Registers the sqlite3 driver
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS samples (
+	address      TEXT    NOT NULL,
+	time         INTEGER NOT NULL,
+	up           INTEGER NOT NULL,
+	state        INTEGER NOT NULL,
+	latency      INTEGER NOT NULL,
+	player_count INTEGER NOT NULL,
+	max_players  INTEGER NOT NULL,
+	version      TEXT    NOT NULL,
+	error        TEXT    NOT NULL
+);
+CREATE INDEX IF NOT EXISTS samples_address_time ON samples (address, time);
+CREATE INDEX IF NOT EXISTS samples_time ON samples (time);
+`
+
+// Store is a monitor.Store keeping samples in a SQLite database.
+// Times are stored as nanoseconds since the Unix epoch and latencies in nanoseconds.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens the SQLite database at path, creating it if it doesn't exist.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, err
+	}
+	s, err := New(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// New creates a Store using db, which must be a SQLite database, creating the samples table if needed.
+func New(db *sql.DB) (*Store, error) {
+	if _, err := db.Exec(schema); err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// SaveSample implements monitor.Store.
+func (s *Store) SaveSample(ctx context.Context, sample monitor.Sample) error {
+	_, err := s.db.ExecContext(ctx,
+		"INSERT INTO samples (address, time, up, state, latency, player_count, max_players, version, error) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		sample.Address, sample.Time.UnixNano(), sample.Up, int(sample.State), int64(sample.Latency),
+		sample.PlayerCount, sample.MaxPlayers, sample.Version, sample.Error)
+	return err
+}
+
+// QueryRange implements monitor.Store.
+func (s *Store) QueryRange(ctx context.Context, address string, from, to time.Time) ([]monitor.Sample, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT time, up, state, latency, player_count, max_players, version, error FROM samples WHERE address = ? AND time >= ? AND time < ? ORDER BY time",
+		address, from.UnixNano(), to.UnixNano())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var samples []monitor.Sample
+	for rows.Next() {
+		sample := monitor.Sample{Address: address}
+		var t, latency int64
+		var state int
+		if err := rows.Scan(&t, &sample.Up, &state, &latency, &sample.PlayerCount, &sample.MaxPlayers, &sample.Version, &sample.Error); err != nil {
+			return nil, err
+		}
+		sample.Time = time.Unix(0, t)
+		sample.State = monitor.State(state)
+		sample.Latency = time.Duration(latency)
+		samples = append(samples, sample)
+	}
+	return samples, rows.Err()
+}
+
+// Prune implements monitor.Store.
+func (s *Store) Prune(ctx context.Context, before time.Time) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM samples WHERE time < ?", before.UnixNano())
+	return err
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
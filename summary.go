@@ -0,0 +1,112 @@
+package bedrockping
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"time"
+)
+
+// Summary holds aggregate statistics for a batch of results.
+type Summary struct {
+	Total     int `json:"total"`
+	Reachable int `json:"reachable"`
+	// Errors counts the unreachable results by the kind of error, see ErrorKind.
+	Errors map[string]int `json:"errors"`
+
+	// Latency percentiles of the reachable results.
+	LatencyMin  time.Duration `json:"latencyMin"`
+	LatencyMean time.Duration `json:"latencyMean"`
+	LatencyP50  time.Duration `json:"latencyP50"`
+	LatencyP90  time.Duration `json:"latencyP90"`
+	LatencyP99  time.Duration `json:"latencyP99"`
+	LatencyMax  time.Duration `json:"latencyMax"`
+
+	// PlayerCount and MaxPlayers are totals across the reachable servers.
+	PlayerCount int `json:"playerCount"`
+	MaxPlayers  int `json:"maxPlayers"`
+	// Versions counts the reachable servers by MCPEVersion.
+	Versions map[string]int `json:"versions"`
+}
+
+// Summarize computes aggregate statistics for results.
+func Summarize(results []Result) Summary {
+	summary := Summary{
+		Total:    len(results),
+		Errors:   make(map[string]int),
+		Versions: make(map[string]int),
+	}
+
+	var latencies []time.Duration
+	var latencySum time.Duration
+	for _, res := range results {
+		if res.Err != nil {
+			summary.Errors[ErrorKind(res.Err)]++
+			continue
+		}
+
+		summary.Reachable++
+		summary.PlayerCount += res.Response.PlayerCount
+		summary.MaxPlayers += res.Response.MaxPlayers
+		summary.Versions[res.Response.MCPEVersion]++
+
+		latencies = append(latencies, res.Latency)
+		latencySum += res.Latency
+	}
+
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		summary.LatencyMin = latencies[0]
+		summary.LatencyMax = latencies[len(latencies)-1]
+		summary.LatencyMean = latencySum / time.Duration(len(latencies))
+		summary.LatencyP50 = percentile(latencies, 50)
+		summary.LatencyP90 = percentile(latencies, 90)
+		summary.LatencyP99 = percentile(latencies, 99)
+	}
+
+	return summary
+}
+
+// percentile returns the nearest-rank percentile p of the sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Kinds of error returned by ErrorKind.
+const (
+	ErrorKindTimeout  = "timeout"
+	ErrorKindDNS      = "dns"
+	ErrorKindRefused  = "refused"
+	ErrorKindCanceled = "canceled"
+	// ErrorKindCircuitOpen is for servers that weren't pinged as they'd failed repeatedly, see CircuitBreaker.
+	ErrorKindCircuitOpen = "circuit-open"
+	ErrorKindOther       = "other"
+)
+
+// ErrorKind classifies an error returned while querying a server,
+// for grouping failures in reports. It returns one of the ErrorKind constants.
+func ErrorKind(err error) string {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, ErrCircuitOpen):
+		return ErrorKindCircuitOpen
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorKindTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorKindCanceled
+	case errors.As(err, &dnsErr):
+		return ErrorKindDNS
+	case errors.Is(err, ErrPortClosed):
+		return ErrorKindRefused
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorKindTimeout
+	default:
+		return ErrorKindOther
+	}
+}
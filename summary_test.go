@@ -0,0 +1,57 @@
+package bedrockping
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	var results []Result
+	for i := 1; i <= 10; i++ {
+		version := "1.14.60"
+		if i > 7 {
+			version = "1.16.0"
+		}
+		results = append(results, Result{
+			Latency:  time.Duration(i) * time.Millisecond,
+			Response: Response{PlayerCount: i, MaxPlayers: 20, MCPEVersion: version},
+		})
+	}
+	results = append(results,
+		Result{Err: context.DeadlineExceeded},
+		Result{Err: context.DeadlineExceeded},
+		Result{Err: &net.DNSError{Err: "no such host", Name: "example.invalid", IsNotFound: true}},
+		Result{Err: errors.New("invalid payload")},
+	)
+
+	summary := Summarize(results)
+
+	expect := Summary{
+		Total:       14,
+		Reachable:   10,
+		Errors:      map[string]int{ErrorKindTimeout: 2, ErrorKindDNS: 1, ErrorKindOther: 1},
+		LatencyMin:  time.Millisecond,
+		LatencyMean: 5500 * time.Microsecond,
+		LatencyP50:  5 * time.Millisecond,
+		LatencyP90:  9 * time.Millisecond,
+		LatencyP99:  10 * time.Millisecond,
+		LatencyMax:  10 * time.Millisecond,
+		PlayerCount: 55,
+		MaxPlayers:  200,
+		Versions:    map[string]int{"1.14.60": 7, "1.16.0": 3},
+	}
+	if !reflect.DeepEqual(expect, summary) {
+		t.Errorf("got %+v", summary)
+	}
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	summary := Summarize(nil)
+	if summary.Total != 0 || summary.Reachable != 0 || summary.LatencyMax != 0 {
+		t.Errorf("got %+v", summary)
+	}
+}
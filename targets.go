@@ -0,0 +1,246 @@
+package bedrockping
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Target is a server to be queried as part of a batch.
+type Target struct {
+	// Host is a hostname or IP address, optionally including a port.
+	Host string `json:"host"`
+	// Port overrides any port in Host, if 0 the port in Host or DefaultPort is used.
+	Port int `json:"port,omitempty"`
+	// Timeout overrides the batch timeout for each attempt at this target if non-zero.
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// Retries overrides the number of batch retries for this target if non-zero.
+	Retries int `json:"retries,omitempty"`
+	// Priority orders pings when the batch is rate limited, higher priority targets are pinged first.
+	Priority int `json:"priority,omitempty"`
+}
+
+// Address returns the host:port address of the target, normalized by ParseAddress. A Host ParseAddress rejects
+// is returned as is, for the ping to report the error.
+func (t Target) Address() string {
+	address, err := ParseAddress(t.Host)
+	if err != nil {
+		return t.Host
+	}
+	if t.Port != 0 {
+		host, _, _ := net.SplitHostPort(address)
+		address = net.JoinHostPort(host, strconv.Itoa(t.Port))
+	}
+	return address
+}
+
+// UnmarshalJSON accepts either a plain address string or an object.
+// Timeouts may be a duration string such as "2s" or a number of milliseconds.
+func (t *Target) UnmarshalJSON(data []byte) error {
+	var host string
+	if err := json.Unmarshal(data, &host); err == nil {
+		*t = Target{Host: host}
+		return nil
+	}
+
+	var raw struct {
+		Host     string          `json:"host"`
+		Port     int             `json:"port"`
+		Timeout  json.RawMessage `json:"timeout"`
+		Retries  int             `json:"retries"`
+		Priority int             `json:"priority"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*t = Target{Host: raw.Host, Port: raw.Port, Retries: raw.Retries, Priority: raw.Priority}
+	if len(raw.Timeout) == 0 || string(raw.Timeout) == "null" {
+		return nil
+	}
+
+	var timeout string
+	if err := json.Unmarshal(raw.Timeout, &timeout); err != nil {
+		timeout = string(raw.Timeout)
+	}
+	var err error
+	t.Timeout, err = parseTimeout(timeout)
+	return err
+}
+
+// MarshalJSON encodes the target as an object with the timeout as a duration string such as "2s",
+// which UnmarshalJSON decodes back to the same target.
+func (t Target) MarshalJSON() ([]byte, error) {
+	raw := struct {
+		Host     string `json:"host"`
+		Port     int    `json:"port,omitempty"`
+		Timeout  string `json:"timeout,omitempty"`
+		Retries  int    `json:"retries,omitempty"`
+		Priority int    `json:"priority,omitempty"`
+	}{Host: t.Host, Port: t.Port, Retries: t.Retries, Priority: t.Priority}
+	if t.Timeout != 0 {
+		raw.Timeout = t.Timeout.String()
+	}
+	return json.Marshal(raw)
+}
+
+// parseTimeout parses a duration string, a bare number is treated as milliseconds.
+func parseTimeout(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+	timeout, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout: %q", s)
+	}
+	return timeout, nil
+}
+
+// LoadTargets reads a list of targets from r. The format is detected from the content:
+//
+//   - A JSON array of address strings or objects with "host", "port", "timeout", "retries" and "priority" fields.
+//   - CSV with host, port, timeout, retries and priority columns, either in that order or named by a header row.
+//   - One host or host:port per line.
+//
+// In CSV and line formats blank lines and lines starting with '#' are ignored.
+// Timeouts are either duration strings such as "2s" or a number of milliseconds.
+func LoadTargets(r io.Reader) ([]Target, error) {
+	br := bufio.NewReader(r)
+
+	first, err := peekNonSpace(br)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if first == '[' {
+		var targets []Target
+		if err := json.NewDecoder(br).Decode(&targets); err != nil {
+			return nil, err
+		}
+		for i, t := range targets {
+			if t.Host == "" {
+				return nil, fmt.Errorf("target %d: missing host", i)
+			}
+		}
+		return targets, nil
+	}
+
+	data, err := io.ReadAll(br)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.ContainsRune(data, ',') {
+		return loadCSVTargets(bytes.NewReader(data))
+	}
+	return loadLineTargets(bytes.NewReader(data))
+}
+
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			return b, br.UnreadByte()
+		}
+	}
+}
+
+func loadLineTargets(r io.Reader) ([]Target, error) {
+	var targets []Target
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		targets = append(targets, Target{Host: line})
+	}
+	return targets, scanner.Err()
+}
+
+func loadCSVTargets(r io.Reader) ([]Target, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	columns := map[string]int{"host": 0, "port": 1, "timeout": 2, "retries": 3, "priority": 4}
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var targets []Target
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return targets, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		if targets == nil && isTargetsHeader(record) {
+			columns = make(map[string]int)
+			for i, name := range record {
+				columns[strings.ToLower(strings.TrimSpace(name))] = i
+			}
+			targets = []Target{}
+			continue
+		}
+
+		t := Target{Host: field(record, "host")}
+		if t.Host == "" {
+			return nil, fmt.Errorf("line %d: missing host", line)
+		}
+		if port := field(record, "port"); port != "" {
+			if t.Port, err = strconv.Atoi(port); err != nil {
+				return nil, fmt.Errorf("line %d: invalid port: %q", line, port)
+			}
+		}
+		if t.Timeout, err = parseTimeout(field(record, "timeout")); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		if retries := field(record, "retries"); retries != "" {
+			if t.Retries, err = strconv.Atoi(retries); err != nil {
+				return nil, fmt.Errorf("line %d: invalid retries: %q", line, retries)
+			}
+		}
+		if priority := field(record, "priority"); priority != "" {
+			if t.Priority, err = strconv.Atoi(priority); err != nil {
+				return nil, fmt.Errorf("line %d: invalid priority: %q", line, priority)
+			}
+		}
+		targets = append(targets, t)
+	}
+}
+
+// isTargetsHeader reports whether a CSV record is a header row naming the columns.
+func isTargetsHeader(record []string) bool {
+	for _, name := range record {
+		if strings.EqualFold(strings.TrimSpace(name), "host") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package bedrockping
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTargetAddress(t *testing.T) {
+	tests := []struct {
+		target  Target
+		address string
+	}{
+		{Target{Host: "example.com"}, "example.com:19132"},
+		{Target{Host: "example.com:1234"}, "example.com:1234"},
+		{Target{Host: "example.com:1234", Port: 4321}, "example.com:4321"},
+		{Target{Host: "example.com", Port: 4321}, "example.com:4321"},
+		{Target{Host: "::1"}, "[::1]:19132"},
+		{Target{Host: "[::1]"}, "[::1]:19132"},
+		{Target{Host: "[::1]:1234"}, "[::1]:1234"},
+		{Target{Host: "fe80::1%eth0", Port: 1234}, "[fe80::1%eth0]:1234"},
+		{Target{Host: "Example.com."}, "Example.com.:19132"},
+		{Target{Host: "bad host", Port: 1234}, "bad host"},
+	}
+	for _, test := range tests {
+		if address := test.target.Address(); address != test.address {
+			t.Errorf("%+v: got %s, expected %s", test.target, address, test.address)
+		}
+	}
+}
+
+func TestLoadTargets(t *testing.T) {
+	expect := []Target{
+		{Host: "a.example.com"},
+		{Host: "b.example.com", Port: 19133},
+		{Host: "c.example.com", Timeout: 2 * time.Second, Retries: 3, Priority: 1},
+	}
+
+	tests := map[string]string{
+		"json": `[
+			"a.example.com",
+			{"host": "b.example.com", "port": 19133},
+			{"host": "c.example.com", "timeout": "2s", "retries": 3, "priority": 1}
+		]`,
+		"json-millis": `["a.example.com", {"host": "b.example.com", "port": 19133}, {"host": "c.example.com", "timeout": 2000, "retries": 3, "priority": 1}]`,
+		"csv":         "a.example.com\nb.example.com,19133\nc.example.com,,2s,3,1\n",
+		"csv-header":  "# servers\npriority,retries,timeout,host,port\n,,,a.example.com,\n,,,b.example.com,19133\n1,3,2000,c.example.com,\n",
+	}
+	for name, input := range tests {
+		targets, err := LoadTargets(strings.NewReader(input))
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(expect, targets) {
+			t.Errorf("%s: got %+v", name, targets)
+		}
+	}
+}
+
+func TestLoadTargetsLines(t *testing.T) {
+	input := "\n# comment\na.example.com\n  b.example.com:19133  \n\n[::1]:19132\n"
+	expect := []Target{{Host: "a.example.com"}, {Host: "b.example.com:19133"}, {Host: "[::1]:19132"}}
+
+	targets, err := LoadTargets(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expect, targets) {
+		t.Errorf("got %+v", targets)
+	}
+}
+
+func TestLoadTargetsErrors(t *testing.T) {
+	tests := map[string]string{
+		"json-missing-host": `[{"port": 19132}]`,
+		"json-bad-timeout":  `[{"host": "a", "timeout": "soon"}]`,
+		"csv-bad-port":      "a.example.com,port\n",
+		"csv-bad-timeout":   "a.example.com,19132,soon\n",
+		"csv-missing-host":  ",19132\n",
+		"csv-bad-retries":   "a.example.com,19132,1s,many\n",
+	}
+	for name, input := range tests {
+		if _, err := LoadTargets(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoadTargetsEmpty(t *testing.T) {
+	targets, err := LoadTargets(strings.NewReader("  \n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 0 {
+		t.Errorf("expected no targets, got %+v", targets)
+	}
+}
+
+func TestTargetJSONRoundTrip(t *testing.T) {
+	targets := []Target{
+		{Host: "a.example.com"},
+		{Host: "b.example.com", Port: 19133, Timeout: 2 * time.Second, Retries: 3, Priority: 1},
+		{Host: "c.example.com", Timeout: 1500 * time.Millisecond},
+	}
+	data, err := json.Marshal(targets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"timeout":"2s"`) {
+		t.Errorf("timeout not encoded as a duration string: %s", data)
+	}
+
+	var decoded []Target
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(targets, decoded) {
+		t.Errorf("got %+v, expected %+v", decoded, targets)
+	}
+}
@@ -0,0 +1,150 @@
+// Package textenc reads and writes results and monitor targets as YAML and TOML documents, for
+// configuration-driven tooling and human-readable reports, and results as XML for monitoring systems
+// that consume it. Results use the same schema as their JSON, bedrockping.ResultJSON, and targets the
+// keys of the bedrockping command's configuration file.
+//
+// TOML documents hold results as a [[results]] array of tables and targets as [[targets]], as a TOML
+// document can't be an array. YAML documents are plain sequences, and XML documents a results element
+// holding a result element for each.
+package textenc
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+// resultsDocument is a TOML document of results.
+type resultsDocument struct {
+	Results []bedrockping.ResultJSON `toml:"results"`
+}
+
+// resultsXML is an XML document of results.
+type resultsXML struct {
+	XMLName xml.Name             `xml:"results"`
+	Results []bedrockping.Result `xml:"result"`
+}
+
+// targetsDocument is a TOML document of targets.
+type targetsDocument struct {
+	Targets []monitor.Target `toml:"targets"`
+}
+
+// WriteYAML writes results to w as a YAML sequence.
+func WriteYAML(w io.Writer, results []bedrockping.Result) error {
+	if results == nil {
+		results = []bedrockping.Result{}
+	}
+	return encodeYAML(w, results)
+}
+
+// ReadYAML reads results written by WriteYAML from r.
+func ReadYAML(r io.Reader) ([]bedrockping.Result, error) {
+	var results []bedrockping.Result
+	if err := decodeYAML(r, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// WriteTOML writes results to w as a TOML array of tables named results.
+func WriteTOML(w io.Writer, results []bedrockping.Result) error {
+	doc := resultsDocument{Results: make([]bedrockping.ResultJSON, len(results))}
+	for i, res := range results {
+		doc.Results[i] = res.JSON()
+	}
+	return toml.NewEncoder(w).Encode(doc)
+}
+
+// ReadTOML reads results written by WriteTOML from r.
+func ReadTOML(r io.Reader) ([]bedrockping.Result, error) {
+	var doc resultsDocument
+	if _, err := toml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	var results []bedrockping.Result
+	for _, j := range doc.Results {
+		res, err := j.Result()
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// WriteXML writes results to w as an indented XML document, see bedrockping.ResultJSON.MarshalXML.
+func WriteXML(w io.Writer, results []bedrockping.Result) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(resultsXML{Results: results}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ReadXML reads results written by WriteXML from r.
+func ReadXML(r io.Reader) ([]bedrockping.Result, error) {
+	var doc resultsXML
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc.Results, nil
+}
+
+// WriteTargetsYAML writes targets to w as a YAML sequence.
+func WriteTargetsYAML(w io.Writer, targets []monitor.Target) error {
+	if targets == nil {
+		targets = []monitor.Target{}
+	}
+	return encodeYAML(w, targets)
+}
+
+// ReadTargetsYAML reads targets written by WriteTargetsYAML from r.
+func ReadTargetsYAML(r io.Reader) ([]monitor.Target, error) {
+	var targets []monitor.Target
+	if err := decodeYAML(r, &targets); err != nil {
+		return nil, err
+	}
+	return targets, nil
+}
+
+// WriteTargetsTOML writes targets to w as a TOML array of tables named targets.
+func WriteTargetsTOML(w io.Writer, targets []monitor.Target) error {
+	return toml.NewEncoder(w).Encode(targetsDocument{Targets: targets})
+}
+
+// ReadTargetsTOML reads targets written by WriteTargetsTOML from r.
+func ReadTargetsTOML(r io.Reader) ([]monitor.Target, error) {
+	var doc targetsDocument
+	if _, err := toml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc.Targets, nil
+}
+
+func encodeYAML(w io.Writer, v any) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// decodeYAML decodes the YAML document in r into v, an empty document leaving it unchanged.
+func decodeYAML(r io.Reader, v any) error {
+	if err := yaml.NewDecoder(r).Decode(v); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package textenc
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZeroErrors/go-bedrockping"
+	"github.com/ZeroErrors/go-bedrockping/monitor"
+)
+
+var testResults = []bedrockping.Result{
+	{
+		Address:  "play.example.com:19132",
+		Resolved: "203.0.113.7:19132",
+		Response: bedrockping.Response{Timestamp: 1, ServerID: 1<<64 - 1, GameID: "MCPE", ServerName: "§aMy Server",
+			ProtocolVersion: 390, MCPEVersion: "1.14.60", PlayerCount: 3, MaxPlayers: 20,
+			Extra: []string{"1", "world", "Survival", "0", "19132", "19133"}},
+		Latency:    23500 * time.Microsecond,
+		Attempts:   1,
+		Sent:       1,
+		CheckedAt:  time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Enrichment: map[string]string{"rdns": "host.example.com"},
+	},
+	{Address: "down.example.com:19132", Attempts: 2, Sent: 4, Err: context.DeadlineExceeded},
+}
+
+// checkResults fails the test unless got are the testResults, whose errors only keep their message.
+func checkResults(t *testing.T, got []bedrockping.Result) {
+	t.Helper()
+	if len(got) != len(testResults) {
+		t.Fatalf("expected %d results, got %d", len(testResults), len(got))
+	}
+	if !reflect.DeepEqual(got[0], testResults[0]) {
+		t.Errorf("unexpected result:\n%+v\nexpected:\n%+v", got[0], testResults[0])
+	}
+	offline := got[1]
+	if offline.Err == nil || offline.Err.Error() != testResults[1].Err.Error() {
+		t.Errorf("unexpected error: %v", offline.Err)
+	}
+	offline.Err = testResults[1].Err
+	if !reflect.DeepEqual(offline, testResults[1]) {
+		t.Errorf("unexpected result:\n%+v\nexpected:\n%+v", offline, testResults[1])
+	}
+}
+
+func TestYAML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteYAML(&buf, testResults); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"- schemaVersion: 1", "    serverId: 18446744073709551615", "    gameMode: Survival",
+		"  latencyMs: 23.5", "  errorKind: timeout"} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("expected %q in:\n%s", line, buf.String())
+		}
+	}
+	results, err := ReadYAML(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkResults(t, results)
+}
+
+func TestTOML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTOML(&buf, testResults); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"[[results]]", `serverId = "18446744073709551615"`, `gameMode = "Survival"`, "latencyMs = 23.5"} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("expected %q in:\n%s", line, buf.String())
+		}
+	}
+	results, err := ReadTOML(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkResults(t, results)
+}
+
+func TestXML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteXML(&buf, testResults); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{xml.Header + "<results>", `  <result schemaVersion="1">`,
+		"      <serverId>18446744073709551615</serverId>", "    <latencyMs>23.5</latencyMs>",
+		`      <value key="rdns">host.example.com</value>`, "    <errorKind>timeout</errorKind>", "</results>"} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("expected %q in:\n%s", line, buf.String())
+		}
+	}
+	results, err := ReadXML(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkResults(t, results)
+}
+
+func TestEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteYAML(&buf, nil); err != nil || buf.String() != "[]\n" {
+		t.Errorf("unexpected YAML %q %v", buf.String(), err)
+	}
+	if results, err := ReadYAML(strings.NewReader("")); err != nil || len(results) != 0 {
+		t.Errorf("unexpected results %v %v", results, err)
+	}
+	if results, err := ReadTOML(strings.NewReader("")); err != nil || len(results) != 0 {
+		t.Errorf("unexpected results %v %v", results, err)
+	}
+	buf.Reset()
+	if err := WriteXML(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if results, err := ReadXML(&buf); err != nil || len(results) != 0 {
+		t.Errorf("unexpected results %v %v", results, err)
+	}
+	if _, err := ReadYAML(strings.NewReader("- schemaVersion: 2\n")); err == nil {
+		t.Error("expected a newer schema to fail")
+	}
+}
+
+func TestTargets(t *testing.T) {
+	targets := []monitor.Target{
+		{Address: "play.example.com:19132"},
+		{Address: "other.example.com:19133", Interval: 10 * time.Second, DownAfter: 3, UpAfter: 2,
+			LatencyThreshold: 150 * time.Millisecond},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTargetsYAML(&buf, targets); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "  latency_threshold: 150ms\n") {
+		t.Errorf("unexpected YAML:\n%s", buf.String())
+	}
+	got, err := ReadTargetsYAML(&buf)
+	if err != nil || !reflect.DeepEqual(got, targets) {
+		t.Errorf("expected the targets to round trip through YAML, got %+v %v", got, err)
+	}
+
+	buf.Reset()
+	if err := WriteTargetsTOML(&buf, targets); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `down_after = 3`) {
+		t.Errorf("unexpected TOML:\n%s", buf.String())
+	}
+	got, err = ReadTargetsTOML(&buf)
+	if err != nil || !reflect.DeepEqual(got, targets) {
+		t.Errorf("expected the targets to round trip through TOML, got %+v %v", got, err)
+	}
+}
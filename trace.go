@@ -0,0 +1,45 @@
+package bedrockping
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const instrumentationName = "github.com/ZeroErrors/go-bedrockping"
+
+// WithTracerProvider records an OpenTelemetry span for every query made by the Client,
+// with child spans for the resolve, dial, send and receive phases.
+// Spans aren't recorded by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *Client) {
+		c.tracer = provider.Tracer(instrumentationName)
+	}
+}
+
+// defaultTracer discards spans.
+var defaultTracer trace.Tracer = noop.NewTracerProvider().Tracer(instrumentationName)
+
+// endPingSpan records the outcome of res on span and ends it.
+func endPingSpan(span trace.Span, res Result) {
+	span.SetAttributes(attribute.Int("bedrockping.pings_sent", res.Sent))
+	if res.Err != nil {
+		span.SetAttributes(attribute.String("bedrockping.result", ErrorKind(res.Err)))
+	} else {
+		span.SetAttributes(
+			attribute.String("bedrockping.result", "ok"),
+			attribute.Int64("bedrockping.latency_us", res.Latency.Microseconds()),
+		)
+	}
+	endSpan(span, res.Err)
+}
+
+// endSpan marks span as failed if err is non-nil and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
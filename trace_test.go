@@ -0,0 +1,89 @@
+package bedrockping
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestClientTracing(t *testing.T) {
+	_, port, _ := net.SplitHostPort(startTestServer(t, testResponse("Traced")))
+	address := net.JoinHostPort("localhost", port)
+
+	recorder := tracetest.NewSpanRecorder()
+	c := NewClient(WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+	if res := c.Ping(context.Background(), address); res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	root, ok := spans["bedrockping.Ping"]
+	if !ok {
+		t.Fatalf("missing root span: %v", spans)
+	}
+	for _, name := range []string{"bedrockping.resolve", "bedrockping.dial", "bedrockping.send", "bedrockping.receive"} {
+		span, ok := spans[name]
+		if !ok {
+			t.Errorf("missing %s span", name)
+			continue
+		}
+		if span.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("%s isn't a child of the root span", name)
+		}
+	}
+
+	attrs := spanAttributes(root)
+	if attrs["server.address"].AsString() != address || attrs["bedrockping.result"].AsString() != "ok" {
+		t.Errorf("unexpected attributes: %v", attrs)
+	}
+	if attrs["bedrockping.latency_us"].AsInt64() <= 0 {
+		t.Errorf("missing latency: %v", attrs)
+	}
+	if peer := attrs["network.peer.address"].AsString(); peer == "" || peer == address {
+		t.Errorf("expected resolved address, got %q", peer)
+	}
+}
+
+func TestClientTracingTimeout(t *testing.T) {
+	// Nothing reads from this socket so pings are never answered
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	c := NewClient(WithTimeout(50*time.Millisecond), WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+	c.Ping(context.Background(), conn.LocalAddr().String())
+
+	for _, span := range recorder.Ended() {
+		if span.Name() == "bedrockping.resolve" {
+			t.Error("IP addresses shouldn't be resolved")
+		}
+		if span.Name() != "bedrockping.Ping" {
+			continue
+		}
+		if span.Status().Code != codes.Error || spanAttributes(span)["bedrockping.result"].AsString() != ErrorKindTimeout {
+			t.Errorf("unexpected span: %v %v", span.Status(), span.Attributes())
+		}
+		return
+	}
+	t.Error("missing root span")
+}
@@ -0,0 +1,211 @@
+package bedrockping
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// TracerouteOptions configures Client.Traceroute.
+type TracerouteOptions struct {
+	// MaxHops is the highest TTL pings are sent with, the default is 30.
+	MaxHops int
+	// HopTimeout is how long to wait for an answer at each TTL before moving on to the next, the default
+	// is 1 second. Pings are resent every resend interval while waiting.
+	HopTimeout time.Duration
+}
+
+// Hop is what answered pings sent with one TTL during a traceroute.
+type Hop struct {
+	// TTL is the IP TTL, or IPv6 hop limit, the pings were sent with.
+	TTL int
+	// Router is the address of the router that reported the pings expired, or that reported an error, if one did.
+	// Routers are only reported on Linux.
+	Router netip.Addr
+	// Reached is set if the server answered, so it is at most TTL hops away.
+	Reached bool
+	// Response is the server's pong if it was reached.
+	Response Response
+	// RTT is how long the server or router took to answer.
+	RTT time.Duration
+	// Err is why the hop failed: context.DeadlineExceeded if nothing answered in time, ErrPortClosed if the server's
+	// host reported the port closed, or the error reported by a router that rejected the pings.
+	Err error
+}
+
+// Route is the result of a traceroute to a server.
+type Route struct {
+	// Address is the address given to Traceroute.
+	Address string
+	// Resolved is the IP address and port the pings were sent to.
+	Resolved string
+	// Hops are the hops tried, in order of TTL.
+	Hops []Hop
+}
+
+// Reached reports whether the server answered at any hop.
+func (r Route) Reached() bool {
+	return len(r.Hops) > 0 && r.Hops[len(r.Hops)-1].Reached
+}
+
+// LastResponse returns the furthest hop that a router or the server answered at, past which pings are being
+// dropped if the server wasn't reached. It returns false if nothing answered at all.
+func (r Route) LastResponse() (Hop, bool) {
+	for i := len(r.Hops) - 1; i >= 0; i-- {
+		if hop := r.Hops[i]; hop.Reached || hop.Router.IsValid() {
+			return hop, true
+		}
+	}
+	return Hop{}, false
+}
+
+// Traceroute pings the server at address with an increasing IP TTL, or IPv6 hop limit, starting from 1 until it
+// answers, a router rejects the pings or opts.MaxHops is reached, to localize where its traffic is being dropped.
+// The hop the server first answers at is how many hops away it is. On Linux the routers the pings expired at are
+// reported too, from the ICMP time exceeded messages they send, so a route that stops being answered shows the
+// last router the pings got through. Elsewhere only whether the server was reached is known.
+// The address is parsed with ParseAddress and resolved like Ping, but it can't be used through a proxy and isn't
+// supported on Windows. An error is only returned if the address can't be resolved or pinged at all, or ctx ends.
+func (c *Client) Traceroute(ctx context.Context, address string, opts TracerouteOptions) (Route, error) {
+	if c.proxy != nil {
+		return Route{}, errors.New("bedrockping: traceroute isn't supported through a proxy")
+	}
+	if opts.MaxHops <= 0 {
+		opts.MaxHops = 30
+	}
+	if opts.HopTimeout <= 0 {
+		opts.HopTimeout = time.Second
+	}
+	parsed, err := ParseAddress(address)
+	if err != nil {
+		return Route{}, err
+	}
+
+	route := Route{Address: address}
+	resolveCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	route.Resolved, err = c.Resolve(resolveCtx, parsed)
+	cancel()
+	if err != nil {
+		return route, err
+	}
+
+	for ttl := 1; ttl <= opts.MaxHops; ttl++ {
+		hop, err := c.traceHop(ctx, address, route.Resolved, ttl, opts.HopTimeout)
+		if err != nil {
+			return route, err
+		}
+		c.debug(ctx, "traced hop", "address", address, "ttl", ttl, "router", hop.Router, "reached", hop.Reached, "error", hop.Err)
+		route.Hops = append(route.Hops, hop)
+		if err := ctx.Err(); err != nil {
+			return route, err
+		}
+		if hop.Reached || (hop.Err != nil && !errors.Is(hop.Err, context.DeadlineExceeded)) {
+			break
+		}
+	}
+	return route, nil
+}
+
+// traceHop pings resolved, what address resolved to, with the IP TTL set to ttl until it or a router answers,
+// or timeout passes. Pings are resent at the interval for address, which round trip times are recorded under.
+// An error is returned if the pings couldn't be sent at all.
+func (c *Client) traceHop(ctx context.Context, address, resolved string, ttl int, timeout time.Duration) (Hop, error) {
+	hop := Hop{TTL: ttl}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	d := c.dialer()
+	control := d.Control
+	d.Control = func(network, address string, conn syscall.RawConn) error {
+		if control != nil {
+			if err := control(network, address, conn); err != nil {
+				return err
+			}
+		}
+		if err := setHopLimit(conn, network, ttl); err != nil {
+			return err
+		}
+		return enableICMPErrors(conn, network)
+	}
+	conn, err := d.DialContext(ctx, c.network, resolved)
+	if err != nil {
+		return hop, err
+	}
+	defer conn.Close()
+	reportPortUnreachable(conn)
+	raw, err := conn.(*net.UDPConn).SyscallConn()
+	if err != nil {
+		return hop, err
+	}
+
+	pongs := make(chan Response, 1)
+	errs := make(chan error, 1)
+	go func() {
+		pooled := c.buffers.get()
+		defer c.buffers.put(pooled)
+		buf := *pooled
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				errs <- err
+				return
+			}
+			var resp Response
+			if DecodeUnconnectedPong(buf[:n], &resp) == nil {
+				pongs <- resp
+				return
+			}
+		}
+	}()
+
+	// icmp fills in the hop from an error reported by reading or writing, clearing it if the ICMP message
+	// behind it only reported the pings expired on the way
+	icmp := func(err error) {
+		hop.Err = portClosed(err)
+		if router, expired, ok := icmpError(raw); ok {
+			hop.Router = router
+			if expired {
+				hop.Err = nil
+			}
+		}
+	}
+
+	resend := c.ResendInterval(address)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	var first time.Time
+	pooled := c.buffers.get()
+	defer c.buffers.put(pooled)
+	for {
+		select {
+		case <-timer.C:
+			timestamp := c.timestamp(time.Now())
+			if first.IsZero() {
+				first = c.sentAt(timestamp)
+			}
+			if _, err := conn.Write(AppendUnconnectedPing((*pooled)[:0], timestamp)); err != nil {
+				icmp(err)
+				hop.RTT = time.Since(first)
+				return hop, nil
+			}
+			timer.Reset(resend)
+		case hop.Response = <-pongs:
+			hop.Reached = true
+			hop.RTT = time.Since(c.sentAt(hop.Response.Timestamp))
+			if elapsed := time.Since(first); hop.RTT <= 0 || hop.RTT > elapsed {
+				hop.RTT = elapsed
+			}
+			return hop, nil
+		case err := <-errs:
+			icmp(err)
+			hop.RTT = time.Since(first)
+			return hop, nil
+		case <-ctx.Done():
+			hop.Err = ctx.Err()
+			return hop, nil
+		}
+	}
+}
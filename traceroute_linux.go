@@ -0,0 +1,87 @@
+package bedrockping
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// enableICMPErrors enables IP_RECVERR, or IPV6_RECVERR, on the socket conn of network, so the ICMP errors it
+// receives are queued with the address of the router that sent them for icmpError to read.
+func enableICMPErrors(conn syscall.RawConn, network string) error {
+	level, opt := unix.IPPROTO_IP, unix.IP_RECVERR
+	if strings.HasSuffix(network, "6") {
+		level, opt = unix.IPPROTO_IPV6, unix.IPV6_RECVERR
+	}
+
+	var err error
+	controlErr := conn.Control(func(fd uintptr) {
+		err = unix.SetsockoptInt(int(fd), level, opt, 1)
+	})
+	if controlErr != nil {
+		return controlErr
+	}
+	return err
+}
+
+// icmpError reads the oldest ICMP error queued on the socket conn, returning the address of the router or host
+// that sent it and whether it reported the packet's TTL expired. It returns false if none is queued.
+func icmpError(conn syscall.RawConn) (from netip.Addr, expired, ok bool) {
+	oob := make([]byte, 512)
+	var oobn int
+	var err error
+	controlErr := conn.Control(func(fd uintptr) {
+		_, oobn, _, _, err = unix.Recvmsg(int(fd), nil, oob, unix.MSG_ERRQUEUE)
+	})
+	if controlErr != nil || err != nil {
+		return netip.Addr{}, false, false
+	}
+	msgs, err := unix.ParseSocketControlMessage(oob[:oobn])
+	if err != nil {
+		return netip.Addr{}, false, false
+	}
+	for _, msg := range msgs {
+		if (msg.Header.Level == unix.IPPROTO_IP && msg.Header.Type == unix.IP_RECVERR) ||
+			(msg.Header.Level == unix.IPPROTO_IPV6 && msg.Header.Type == unix.IPV6_RECVERR) {
+			return parseExtendedErr(msg.Data)
+		}
+	}
+	return netip.Addr{}, false, false
+}
+
+// parseExtendedErr parses the sock_extended_err of an IP_RECVERR or IPV6_RECVERR control message and the
+// address of the sender that follows it, see ip(7).
+func parseExtendedErr(b []byte) (from netip.Addr, expired, ok bool) {
+	// struct sock_extended_err { u32 ee_errno; u8 ee_origin, ee_type, ee_code, ee_pad; u32 ee_info, ee_data; }
+	const size = 16
+	if len(b) < size+2 {
+		return netip.Addr{}, false, false
+	}
+	origin, icmpType := b[4], b[5]
+	switch origin {
+	case unix.SO_EE_ORIGIN_ICMP:
+		expired = icmpType == 11 // Time Exceeded
+	case unix.SO_EE_ORIGIN_ICMP6:
+		expired = icmpType == 3 // Time Exceeded
+	default:
+		return netip.Addr{}, false, false
+	}
+
+	sa := b[size:]
+	switch binary.NativeEndian.Uint16(sa) {
+	case unix.AF_INET:
+		// struct sockaddr_in { u16 family; be16 port; u8 addr[4]; ... }
+		if len(sa) >= 8 {
+			from = netip.AddrFrom4([4]byte(sa[4:8]))
+		}
+	case unix.AF_INET6:
+		// struct sockaddr_in6 { u16 family; be16 port; u32 flowinfo; u8 addr[16]; u32 scope_id; }
+		if len(sa) >= 24 {
+			from = netip.AddrFrom16([16]byte(sa[8:24]))
+		}
+	}
+	return from, expired, true
+}
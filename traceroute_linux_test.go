@@ -0,0 +1,48 @@
+package bedrockping
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestParseExtendedErr(t *testing.T) {
+	extendedErr := func(origin, icmpType uint8, from netip.Addr) []byte {
+		b := make([]byte, 16, 44)
+		binary.NativeEndian.PutUint32(b, uint32(unix.EHOSTUNREACH))
+		b[4], b[5] = origin, icmpType
+		if from.Is4() {
+			sa := make([]byte, 16)
+			binary.NativeEndian.PutUint16(sa, unix.AF_INET)
+			ip := from.As4()
+			copy(sa[4:], ip[:])
+			return append(b, sa...)
+		}
+		sa := make([]byte, 28)
+		binary.NativeEndian.PutUint16(sa, unix.AF_INET6)
+		ip := from.As16()
+		copy(sa[8:], ip[:])
+		return append(b, sa...)
+	}
+
+	for _, test := range []struct {
+		data    []byte
+		from    netip.Addr
+		expired bool
+		ok      bool
+	}{
+		{extendedErr(unix.SO_EE_ORIGIN_ICMP, 11, netip.MustParseAddr("192.0.2.1")), netip.MustParseAddr("192.0.2.1"), true, true},
+		{extendedErr(unix.SO_EE_ORIGIN_ICMP, 3, netip.MustParseAddr("192.0.2.2")), netip.MustParseAddr("192.0.2.2"), false, true},
+		{extendedErr(unix.SO_EE_ORIGIN_ICMP6, 3, netip.MustParseAddr("2001:db8::1")), netip.MustParseAddr("2001:db8::1"), true, true},
+		{extendedErr(unix.SO_EE_ORIGIN_ICMP6, 1, netip.MustParseAddr("2001:db8::2")), netip.MustParseAddr("2001:db8::2"), false, true},
+		{extendedErr(unix.SO_EE_ORIGIN_LOCAL, 0, netip.MustParseAddr("192.0.2.1")), netip.Addr{}, false, false},
+		{[]byte{1, 2, 3}, netip.Addr{}, false, false},
+	} {
+		from, expired, ok := parseExtendedErr(test.data)
+		if from != test.from || expired != test.expired || ok != test.ok {
+			t.Errorf("expected %s %t %t, got %s %t %t", test.from, test.expired, test.ok, from, expired, ok)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package bedrockping
+
+import (
+	"net/netip"
+	"syscall"
+)
+
+// enableICMPErrors does nothing on this platform, which doesn't report the routers that send ICMP errors.
+func enableICMPErrors(conn syscall.RawConn, network string) error {
+	return nil
+}
+
+// icmpError always returns false on this platform.
+func icmpError(conn syscall.RawConn) (from netip.Addr, expired, ok bool) {
+	return netip.Addr{}, false, false
+}
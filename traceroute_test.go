@@ -0,0 +1,118 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package bedrockping
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"net/url"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTraceroute(t *testing.T) {
+	address := startTestServer(t, testResponse("Traced Server"))
+
+	c := NewClient(WithTimeout(time.Second))
+	route, err := c.Traceroute(context.Background(), address, TracerouteOptions{MaxHops: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The server is on the same host, so the first hop reaches it
+	if len(route.Hops) != 1 || !route.Reached() || route.Resolved != address {
+		t.Fatalf("unexpected route %+v", route)
+	}
+	hop, ok := route.LastResponse()
+	if !ok || hop.TTL != 1 || hop.Response.ServerName != "Traced Server" || hop.Err != nil {
+		t.Errorf("unexpected hop %+v", hop)
+	}
+}
+
+func TestTraceroutePortClosed(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := conn.LocalAddr().String()
+	conn.Close()
+
+	c := NewClient(WithTimeout(time.Second))
+	route, err := c.Traceroute(context.Background(), address, TracerouteOptions{MaxHops: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(route.Hops) != 1 || route.Reached() || !errors.Is(route.Hops[0].Err, ErrPortClosed) {
+		t.Fatalf("unexpected route %+v", route)
+	}
+	if runtime.GOOS == "linux" && route.Hops[0].Router != netip.MustParseAddr("127.0.0.1") {
+		t.Errorf("expected the host to be reported, got %s", route.Hops[0].Router)
+	}
+}
+
+func TestTracerouteUnanswered(t *testing.T) {
+	// Nothing answers pings sent to a socket that doesn't read them
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	c := NewClient(WithTimeout(time.Second))
+	route, err := c.Traceroute(context.Background(), conn.LocalAddr().String(), TracerouteOptions{MaxHops: 2, HopTimeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(route.Hops) != 2 || route.Reached() || !errors.Is(route.Hops[1].Err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected route %+v", route)
+	}
+	if _, ok := route.LastResponse(); ok {
+		t.Error("expected no hop to have answered")
+	}
+}
+
+func TestTracerouteResendInterval(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	address := "play.example.com:" + strconv.Itoa(conn.LocalAddr().(*net.UDPAddr).Port)
+
+	c := NewClient(WithResolver(staticResolver{"play.example.com": {netip.MustParseAddr("127.0.0.1")}}),
+		WithResend(time.Second), WithResendBounds(20*time.Millisecond, time.Second))
+	// Round trip times are recorded under the address pinged, not what it resolved to
+	c.observe(address, time.Millisecond)
+
+	received := make(chan int, 1)
+	go func() {
+		n := 0
+		buf := make([]byte, 1500)
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		for {
+			if _, _, err := conn.ReadFrom(buf); err != nil {
+				received <- n
+				return
+			}
+			n++
+		}
+	}()
+
+	if _, err := c.Traceroute(context.Background(), address, TracerouteOptions{MaxHops: 1, HopTimeout: 200 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	// The default resend interval of a second would only send one ping
+	if n := <-received; n < 3 {
+		t.Errorf("expected pings to be resent at the adaptive interval, got %d pings", n)
+	}
+}
+
+func TestTracerouteProxy(t *testing.T) {
+	c := NewClient(WithProxy(&url.URL{Scheme: "socks5", Host: "127.0.0.1:1080"}))
+	if _, err := c.Traceroute(context.Background(), "127.0.0.1:19132", TracerouteOptions{}); err == nil {
+		t.Error("expected an error through a proxy")
+	}
+}
@@ -0,0 +1,15 @@
+package bedrockping
+
+import "syscall"
+
+// bindToInterface binds the socket conn to the network interface name with SO_BINDTODEVICE.
+func bindToInterface(conn syscall.RawConn, name string) error {
+	var err error
+	controlErr := conn.Control(func(fd uintptr) {
+		err = syscall.SetsockoptString(int(fd), syscall.SOL_SOCKET, syscall.SO_BINDTODEVICE, name)
+	})
+	if controlErr != nil {
+		return controlErr
+	}
+	return err
+}
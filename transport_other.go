@@ -0,0 +1,13 @@
+//go:build !linux
+
+package bedrockping
+
+import (
+	"errors"
+	"syscall"
+)
+
+// bindToInterface isn't supported on this platform.
+func bindToInterface(conn syscall.RawConn, name string) error {
+	return errors.New("bedrockping: binding to an interface is only supported on Linux")
+}
@@ -0,0 +1,156 @@
+package bedrockping
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestClientLocalAddr(t *testing.T) {
+	address := startTestServer(t, testResponse("Server"))
+
+	c := NewClient(WithTimeout(time.Second), WithLocalAddr(net.IPv4(127, 0, 0, 1)))
+	if res := c.Ping(context.Background(), address); res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	// An address that isn't assigned to the host can't be bound to
+	c = NewClient(WithTimeout(time.Second), WithLocalAddr(net.IPv4(192, 0, 2, 1)))
+	if res := c.Ping(context.Background(), address); res.Err == nil {
+		t.Error("expected error binding to an unassigned address")
+	}
+}
+
+func TestClientLocalPort(t *testing.T) {
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	// Answer the first ping, reporting the port it came from
+	from := make(chan int, 1)
+	go func() {
+		buf := make([]byte, maxPacketSize)
+		_, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		from <- addr.(*net.UDPAddr).Port
+		pong := new(bytes.Buffer)
+		WriteUnconnectedPong(pong, testResponse("Server"))
+		conn.WriteTo(pong.Bytes(), addr)
+	}()
+
+	// Find a free port to send from
+	free, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := free.LocalAddr().(*net.UDPAddr).Port
+	free.Close()
+
+	c := NewClient(WithTimeout(time.Second), WithLocalPort(port))
+	if res := c.Ping(context.Background(), conn.LocalAddr().String()); res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if got := <-from; got != port {
+		t.Errorf("ping sent from port %d, expected %d", got, port)
+	}
+}
+
+func TestClientInterface(t *testing.T) {
+	address := startTestServer(t, testResponse("Server"))
+
+	c := NewClient(WithTimeout(time.Second), WithInterface("lo"))
+	res := c.Ping(context.Background(), address)
+	if runtime.GOOS != "linux" {
+		if res.Err == nil {
+			t.Error("expected error on a platform without interface binding")
+		}
+		return
+	}
+	if errors.Is(res.Err, syscall.EPERM) {
+		t.Skip("binding to an interface isn't permitted")
+	}
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	c = NewClient(WithTimeout(time.Second), WithInterface("nonexistent0"))
+	if res := c.Ping(context.Background(), address); res.Err == nil {
+		t.Error("expected error binding to a missing interface")
+	}
+}
+
+func TestClientDSCP(t *testing.T) {
+	address := startTestServer(t, testResponse("Server"))
+
+	c := NewClient(WithTimeout(time.Second), WithDSCP(46))
+	res := c.Ping(context.Background(), address)
+	switch runtime.GOOS {
+	case "linux", "darwin", "freebsd", "netbsd", "openbsd", "dragonfly":
+		if res.Err != nil {
+			t.Fatal(res.Err)
+		}
+	default:
+		if res.Err == nil {
+			t.Error("expected error on a platform without DSCP marking")
+		}
+		return
+	}
+
+	c = NewClient(WithTimeout(time.Second), WithDSCP(64))
+	if res := c.Ping(context.Background(), address); res.Err == nil {
+		t.Error("expected error for an invalid DSCP")
+	}
+}
+
+func TestClientNetwork(t *testing.T) {
+	address := startTestServer(t, testResponse("Server"))
+	_, port, _ := net.SplitHostPort(address)
+
+	c := NewClient(WithTimeout(time.Second), WithNetwork("udp4"))
+	res := c.Ping(context.Background(), net.JoinHostPort("localhost", port))
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if res.Resolved != address {
+		t.Errorf("resolved %q, expected %q", res.Resolved, address)
+	}
+
+	c = NewClient(WithTimeout(time.Second), WithNetwork("udp6"))
+	res = c.Ping(context.Background(), address)
+	var addrErr *net.AddrError
+	if !errors.As(res.Err, &addrErr) {
+		t.Errorf("expected address error pinging IPv4 over IPv6, got %v", res.Err)
+	}
+}
+
+func TestClientInterfaceZone(t *testing.T) {
+	resolver := staticResolver{"link.example.com": {netip.MustParseAddr("fe80::1")}}
+	c := NewClient(WithInterface("eth0"), WithResolver(resolver))
+	for address, expected := range map[string]string{
+		"[fe80::1]:19132":     "[fe80::1%eth0]:19132",
+		"[fe80::1%lo]:19132":  "[fe80::1%lo]:19132",
+		"[ff02::1]:19132":     "[ff02::1%eth0]:19132",
+		"[2001:db8::1]:19132": "[2001:db8::1]:19132",
+		"192.0.2.1:19132":     "192.0.2.1:19132",
+		"link.example.com:1":  "[fe80::1%eth0]:1",
+	} {
+		if resolved, err := c.Resolve(context.Background(), address); err != nil || resolved != expected {
+			t.Errorf("Resolve(%s) = %s, %v, expected %s", address, resolved, err, expected)
+		}
+	}
+
+	// Without an interface there's no zone to add
+	c = NewClient(WithResolver(resolver))
+	if resolved, err := c.Resolve(context.Background(), "link.example.com:1"); err != nil || resolved != "[fe80::1]:1" {
+		t.Errorf("Resolve(link.example.com:1) = %s, %v", resolved, err)
+	}
+}
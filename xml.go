@@ -0,0 +1,118 @@
+package bedrockping
+
+import (
+	"encoding/xml"
+	"maps"
+	"slices"
+	"time"
+)
+
+// resultFields is ResultJSON without its methods, so it can be embedded in resultXML.
+type resultFields ResultJSON
+
+// resultXML is the XML representation of a ResultJSON. XML has no maps or empty times,
+// so CheckedAt is left out if it's zero and the enrichment is a list of values with keys.
+type resultXML struct {
+	resultFields
+	CheckedAt  *time.Time     `xml:"checkedAt,omitempty"`
+	Enrichment *enrichmentXML `xml:"enrichment,omitempty"`
+}
+
+type enrichmentXML struct {
+	Values []enrichmentValueXML `xml:"value"`
+}
+
+type enrichmentValueXML struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// MarshalXML encodes j as a result element, with the same fields as its JSON:
+//
+//	<result schemaVersion="1">
+//	  <address>play.example.com:19132</address>
+//	  <online>true</online>
+//	  <checkedAt>2024-05-01T12:00:00Z</checkedAt>
+//	  <latencyMs>23.5</latencyMs>
+//	  <response>
+//	    <serverName>My Server</serverName>
+//	    <extra><value>1</value><value>world</value></extra>
+//	    ...
+//	  </response>
+//	  <enrichment><value key="rdns">host.example.com</value></enrichment>
+//	</result>
+func (j ResultJSON) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	x := resultXML{resultFields: resultFields(j)}
+	if !j.CheckedAt.IsZero() {
+		x.CheckedAt = &j.CheckedAt
+	}
+	if len(j.Enrichment) > 0 {
+		x.Enrichment = new(enrichmentXML)
+		for _, key := range slices.Sorted(maps.Keys(j.Enrichment)) {
+			x.Enrichment.Values = append(x.Enrichment.Values, enrichmentValueXML{Key: key, Value: j.Enrichment[key]})
+		}
+	}
+	return e.EncodeElement(x, elementName(start, "ResultJSON", "result"))
+}
+
+// UnmarshalXML decodes an element written by MarshalXML into j.
+func (j *ResultJSON) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var x resultXML
+	if err := d.DecodeElement(&x, &start); err != nil {
+		return err
+	}
+	*j = ResultJSON(x.resultFields)
+	if x.CheckedAt != nil {
+		j.CheckedAt = *x.CheckedAt
+	}
+	if x.Enrichment != nil {
+		j.Enrichment = make(map[string]string, len(x.Enrichment.Values))
+		for _, v := range x.Enrichment.Values {
+			j.Enrichment[v.Key] = v.Value
+		}
+	}
+	return nil
+}
+
+// MarshalXML encodes r as a ResultJSON.
+func (r Result) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return r.JSON().MarshalXML(e, elementName(start, "Result", "result"))
+}
+
+// UnmarshalXML decodes a ResultJSON into r, see ResultJSON.Result.
+func (r *Result) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var j ResultJSON
+	if err := j.UnmarshalXML(d, start); err != nil {
+		return err
+	}
+	res, err := j.Result()
+	if err != nil {
+		return err
+	}
+	*r = res
+	return nil
+}
+
+// MarshalXML encodes r as a response element with the fields of ResponseJSON.
+func (r Response) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(r.JSON(), elementName(start, "Response", "response"))
+}
+
+// UnmarshalXML decodes a ResponseJSON into r.
+func (r *Response) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var j ResponseJSON
+	if err := d.DecodeElement(&j, &start); err != nil {
+		return err
+	}
+	*r = j.Response()
+	return nil
+}
+
+// elementName returns start named name if it has the name of the Go type, as it does when the value
+// isn't a field with its own name.
+func elementName(start xml.StartElement, typeName, name string) xml.StartElement {
+	if start.Name.Space == "" && start.Name.Local == typeName {
+		start.Name.Local = name
+	}
+	return start
+}
@@ -0,0 +1,90 @@
+package bedrockping
+
+import (
+	"context"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResultXML(t *testing.T) {
+	res := Result{
+		Address:  "play.example.com:19132",
+		Resolved: "203.0.113.7:19132",
+		Response: Response{ServerID: 1<<64 - 1, GameID: "MCPE", ServerName: "My <Server>", ProtocolVersion: 390,
+			MCPEVersion: "1.14.60", PlayerCount: 3, MaxPlayers: 20, Extra: []string{"1", "world", "Survival"}},
+		Latency:    23500 * time.Microsecond,
+		Attempts:   1,
+		CheckedAt:  time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Enrichment: map[string]string{"rdns": "host.example.com", "region": "eu"},
+	}
+	data, err := xml.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<result schemaVersion="1"><address>play.example.com:19132</address><online>true</online>` +
+		`<resolved>203.0.113.7:19132</resolved><latencyMs>23.5</latencyMs><attempts>1</attempts>` +
+		`<response><timestamp>0</timestamp><serverId>18446744073709551615</serverId><gameId>MCPE</gameId>` +
+		`<serverName>My &lt;Server&gt;</serverName><protocolVersion>390</protocolVersion>` +
+		`<mcpeVersion>1.14.60</mcpeVersion><playerCount>3</playerCount><maxPlayers>20</maxPlayers>` +
+		`<levelName>world</levelName><gameMode>Survival</gameMode>` +
+		`<extra><value>1</value><value>world</value><value>Survival</value></extra></response>` +
+		`<checkedAt>2024-05-01T12:00:00Z</checkedAt><enrichment><value key="rdns">host.example.com</value>` +
+		`<value key="region">eu</value></enrichment></result>`
+	if string(data) != expected {
+		t.Errorf("unexpected XML:\n%s\nexpected:\n%s", data, expected)
+	}
+
+	var decoded Result
+	if err := xml.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, res) {
+		t.Errorf("expected the result to round trip, got %+v", decoded)
+	}
+}
+
+func TestResultXMLOffline(t *testing.T) {
+	data, err := xml.Marshal(Result{Address: "play.example.com:19132", Err: context.DeadlineExceeded})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<result schemaVersion="1"><address>play.example.com:19132</address><online>false</online>` +
+		`<error>context deadline exceeded</error><errorKind>timeout</errorKind></result>`
+	if string(data) != expected {
+		t.Errorf("unexpected XML:\n%s\nexpected:\n%s", data, expected)
+	}
+
+	var res Result
+	if err := xml.Unmarshal(data, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Err == nil || res.Err.Error() != "context deadline exceeded" || !res.CheckedAt.IsZero() {
+		t.Errorf("unexpected result %+v", res)
+	}
+
+	if err := xml.Unmarshal([]byte(`<result schemaVersion="2"></result>`), &res); err == nil {
+		t.Error("expected an error for a newer schema version")
+	}
+}
+
+func TestResponseXML(t *testing.T) {
+	resp := Response{ServerName: "Test", PlayerCount: 3, Extra: []string{"1"}}
+	data, err := xml.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "<response><timestamp>0</timestamp>") {
+		t.Errorf("unexpected XML: %s", data)
+	}
+
+	var decoded Response
+	if err := xml.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, resp) {
+		t.Errorf("expected %+v, got %+v", resp, decoded)
+	}
+}